@@ -0,0 +1,141 @@
+package main
+
+// Compares the pool state written by the lp_consumer command (lp_pool_depths) with the depths
+// written by the Midgard writer (block_pool_depths).
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"gitlab.com/thorchain/midgard/config"
+	"gitlab.com/thorchain/midgard/internal/db"
+	"gitlab.com/thorchain/midgard/internal/util/midlog"
+)
+
+const usageStr = `Compares the lp_consumer pool state with block_pool_depths.
+Usage:
+$ go run ./cmd/checks/lpconsumer [--history] config
+`
+
+var history = flag.Bool("history", false,
+	"Check every block written by lp_consumer and report the first difference per pool, "+
+		"not only the latest state.")
+
+func init() {
+	flag.Usage = func() {
+		fmt.Print(usageStr)
+		flag.PrintDefaults()
+	}
+}
+
+type depths struct {
+	AssetE8 int64
+	RuneE8  int64
+	SynthE8 int64
+	Units   int64
+}
+
+func (d depths) String() string {
+	return fmt.Sprintf("[Asset: %d, Rune: %d, Synth: %d, Units: %d]",
+		d.AssetE8, d.RuneE8, d.SynthE8, d.Units)
+}
+
+type comparison struct {
+	Pool      string
+	Height    int64
+	Timestamp int64
+	LP        depths
+	Midgard   depths
+}
+
+func main() {
+	midlog.LogCommandLine()
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	config.ReadGlobalFrom(flag.Arg(0))
+	db.SetupWithoutUpdate()
+
+	ctx := context.Background()
+	comparisons, err := readComparisons(ctx, *history)
+	if err != nil {
+		midlog.FatalE(err, "Reading the pool depths failed")
+	}
+
+	checked := map[string]bool{}
+	var differences int
+	for _, c := range comparisons {
+		if checked[c.Pool] {
+			continue
+		}
+		if c.LP != c.Midgard {
+			// Only the first difference of the pool is interesting, the later ones follow from it.
+			checked[c.Pool] = true
+			differences++
+			fmt.Printf("%s height %d (%s):\n  lp_consumer %s\n  midgard     %s\n",
+				c.Pool, c.Height, db.Nano(c.Timestamp).ToTime().UTC(), c.LP, c.Midgard)
+		}
+	}
+
+	pools := map[string]bool{}
+	for _, c := range comparisons {
+		pools[c.Pool] = true
+	}
+	fmt.Printf("Compared %d rows of %d pools, %d pools differ\n",
+		len(comparisons), len(pools), differences)
+	if differences != 0 {
+		os.Exit(1)
+	}
+}
+
+// Returns the lp_consumer depths with the Midgard depths at the same block, in block order per
+// pool. Blocks which Midgard didn't commit yet are left out. Without allBlocks only the last
+// block of every pool is returned.
+func readComparisons(ctx context.Context, allBlocks bool) ([]comparison, error) {
+	lpDepths := `
+		SELECT DISTINCT ON (pool) * FROM lp_pool_depths
+		WHERE block_timestamp <= (SELECT MAX(timestamp) FROM block_log)
+		ORDER BY pool, block_timestamp DESC`
+	if allBlocks {
+		lpDepths = `
+			SELECT * FROM lp_pool_depths
+			WHERE block_timestamp <= (SELECT MAX(timestamp) FROM block_log)`
+	}
+
+	q := `
+		SELECT l.pool, l.height, l.block_timestamp,
+			l.asset_e8, l.rune_e8, l.synth_e8, l.units,
+			COALESCE(d.asset_e8, 0), COALESCE(d.rune_e8, 0),
+			COALESCE(d.synth_e8, 0), COALESCE(d.units, 0)
+		FROM (` + lpDepths + `) l
+		LEFT JOIN LATERAL (
+			SELECT asset_e8, rune_e8, synth_e8, units FROM block_pool_depths b
+			WHERE b.pool = l.pool AND b.block_timestamp <= l.block_timestamp
+			ORDER BY b.block_timestamp DESC
+			LIMIT 1) d ON true
+		ORDER BY l.pool, l.block_timestamp`
+
+	rows, err := db.Query(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ret []comparison
+	for rows.Next() {
+		var c comparison
+		err := rows.Scan(&c.Pool, &c.Height, &c.Timestamp,
+			&c.LP.AssetE8, &c.LP.RuneE8, &c.LP.SynthE8, &c.LP.Units,
+			&c.Midgard.AssetE8, &c.Midgard.RuneE8, &c.Midgard.SynthE8, &c.Midgard.Units)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, c)
+	}
+	return ret, rows.Err()
+}
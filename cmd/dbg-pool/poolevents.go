@@ -21,6 +21,8 @@ func emitPoolEvents(ctx context.Context) chan error {
 
 	gConfig := sarama.NewConfig()
 	gConfig.Consumer.Offsets.Initial = sarama.OffsetOldest
+	// The producer writes the blocks in transactions, aborted ones are skipped.
+	gConfig.Consumer.IsolationLevel = sarama.ReadCommitted
 
 	poolEmitterG := goka.DefineGroup(poolEmitterGroup,
 		goka.Input(blockStream, new(kafka.IndexedEventCodec), blockEventHandler),
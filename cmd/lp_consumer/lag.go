@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/lovoo/goka"
+	"github.com/pascaldekloe/metrics"
+	"gitlab.com/thorchain/midgard/config"
+	"gitlab.com/thorchain/midgard/internal/util/kafka"
+	"gitlab.com/thorchain/midgard/internal/util/midlog"
+	"gitlab.com/thorchain/midgard/internal/util/timer"
+)
+
+var (
+	consumerLag = metrics.Must2LabelInteger("midgard_lp_consumer_lag_messages", "group", "topic")
+	lagErrors   = metrics.MustCounter("midgard_lp_consumer_lag_errors_total",
+		"Number of failed consumer lag measurements.")
+	eventHeight = metrics.Must1LabelInteger("midgard_lp_consumer_height", "group")
+	eventAge    = metrics.Must1LabelReal("midgard_lp_consumer_event_age_seconds", "group")
+)
+
+func init() {
+	metrics.MustHelp("midgard_lp_consumer_lag_messages",
+		"Messages of the input topic not committed by the consumer group yet.")
+	metrics.MustHelp("midgard_lp_consumer_height", "Block height of the last event processed.")
+	metrics.MustHelp("midgard_lp_consumer_event_age_seconds",
+		"Time between the block of the last event processed and its processing.")
+}
+
+// Records the progress of a processor, group is the name without the prefix.
+func observeEvent(group string, idx kafka.EventIdx, blockTimestamp time.Time) {
+	eventHeight(group).Set(idx.Height)
+	eventAge(group).Set(time.Since(blockTimestamp).Seconds())
+}
+
+func serveMetrics(port int) {
+	if port == 0 {
+		return
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", timer.ServeMetrics)
+	go func() {
+		err := http.ListenAndServe(fmt.Sprintf(":%d", port), mux)
+		midlog.FatalE(err, "Metrics server failed")
+	}()
+}
+
+// Updates the consumer lag of the groups (input topic by group) periodically.
+func monitorLag(ctx context.Context, groups map[goka.Group]string) {
+	client, err := sarama.NewClient(brokers, sarama.NewConfig())
+	if err != nil {
+		midlog.ErrorE(err, "Kafka client for the consumer lag failed, lag is not measured")
+		return
+	}
+	defer client.Close()
+	admin, err := sarama.NewClusterAdminFromClient(client)
+	if err != nil {
+		midlog.ErrorE(err, "Kafka admin for the consumer lag failed, lag is not measured")
+		return
+	}
+
+	ticker := time.NewTicker(config.Global.Kafka.LPConsumer.LagInterval.Value())
+	defer ticker.Stop()
+	for {
+		for group, topic := range groups {
+			lag, err := measureLag(client, admin, string(group), topic)
+			if err != nil {
+				lagErrors.Add(1)
+				midlog.WarnF("Measuring the lag of %s on %s failed: %v", group, topic, err)
+				continue
+			}
+			consumerLag(string(group), topic).Set(lag)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sums the messages after the committed offset over the partitions of the topic. Partitions
+// without a committed offset count from the oldest message, the groups start from there.
+func measureLag(client sarama.Client, admin sarama.ClusterAdmin, group, topic string) (int64, error) {
+	partitions, err := client.Partitions(topic)
+	if err != nil {
+		return 0, err
+	}
+	offsets, err := admin.ListConsumerGroupOffsets(group, map[string][]int32{topic: partitions})
+	if err != nil {
+		return 0, err
+	}
+
+	var lag int64
+	for _, partition := range partitions {
+		newest, err := client.GetOffset(topic, partition, sarama.OffsetNewest)
+		if err != nil {
+			return 0, err
+		}
+		committed := int64(-1)
+		if block := offsets.GetBlock(topic, partition); block != nil {
+			if block.Err != sarama.ErrNoError {
+				return 0, block.Err
+			}
+			committed = block.Offset
+		}
+		if committed < 0 {
+			committed, err = client.GetOffset(topic, partition, sarama.OffsetOldest)
+			if err != nil {
+				return 0, err
+			}
+		}
+		if newest > committed {
+			lag += newest - committed
+		}
+	}
+	return lag, nil
+}
@@ -2,14 +2,14 @@ package main
 
 import (
 	"context"
-	"github.com/lovoo/goka"
-	"gitlab.com/thorchain/midgard/config"
-	"gitlab.com/thorchain/midgard/internal/util/midlog"
 	"os"
 	"os/signal"
 	"syscall"
 
-	_ "github.com/lib/pq"
+	"github.com/lovoo/goka"
+	"gitlab.com/thorchain/midgard/config"
+	"gitlab.com/thorchain/midgard/internal/db"
+	"gitlab.com/thorchain/midgard/internal/util/midlog"
 )
 
 var (
@@ -24,6 +24,11 @@ func init() {
 	tmc.Stream.Replication = 1
 }
 
+// Consumer group names, with the configured prefix.
+func groupName(name string) goka.Group {
+	return goka.Group(config.Global.Kafka.LPConsumer.GroupPrefix + name)
+}
+
 func main() {
 	mainCtx, done := context.WithCancel(context.Background())
 
@@ -32,6 +37,16 @@ func main() {
 
 	brokers = config.Global.Kafka.Brokers
 
+	db.SetupWithoutUpdate()
+	mustHaveTables()
+
+	serveMetrics(config.Global.Kafka.LPConsumer.MetricsPort)
+	go monitorLag(mainCtx, map[goka.Group]string{
+		groupName(poolEmitterGroup):    config.Global.Kafka.BlockTopic,
+		groupName(poolStatsGroup):      config.Global.Kafka.PoolTopic,
+		groupName(poolAggregatorGroup): config.Global.Kafka.PoolStatsTopic,
+	})
+
 	midlog.Info("Starting pool emitter")
 	pe := emitPoolEvents(mainCtx)
 
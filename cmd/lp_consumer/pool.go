@@ -6,6 +6,7 @@ import (
 	"gitlab.com/thorchain/midgard/internal/fetch/record"
 	"gitlab.com/thorchain/midgard/internal/util/kafka"
 	"gitlab.com/thorchain/midgard/internal/util/midlog"
+	"strings"
 )
 
 const withdrawCoinKeptHeight = 1970000
@@ -96,13 +97,14 @@ func (p *pool) WithdrawLiquidity(idx kafka.EventIdx, unstake record.Unstake) int
 	return assetInRune
 }
 
-func (p *pool) Swap(swap record.Swap) {
+// Swap returns false if the swap was skipped.
+func (p *pool) Swap(swap record.Swap) bool {
 	fromCoin := record.GetCoinType(swap.FromAsset)
 	toCoin := record.GetCoinType(swap.ToAsset)
 
 	if fromCoin == record.UnknownCoin || toCoin == record.UnknownCoin {
 		midlog.Warn("Unknown coin in swap, skipping")
-		return
+		return false
 	}
 
 	p.SwapCount++
@@ -128,6 +130,15 @@ func (p *pool) Swap(swap record.Swap) {
 			p.SynthE8Depth += swap.ToE8
 		}
 	}
+	return true
+}
+
+// Status zeroes the depths of suspended pools, like the Midgard writer does.
+func (p *pool) Status(poolEvent record.Pool) {
+	if strings.ToLower(string(poolEvent.Status)) == "suspended" {
+		p.AssetE8Depth = 0
+		p.RuneE8Depth = 0
+	}
 }
 
 func (p *pool) Donate(add record.Add) {
@@ -11,14 +11,15 @@ import (
 	"gitlab.com/thorchain/midgard/internal/util/midlog"
 )
 
+const poolAggregatorGroup = "pool-agg-stats"
+
 func calculateAggregates(ctx context.Context) chan error {
-	poolAggregatorGroup := goka.Group("pool-agg-stats")
 	poolStatsStream := goka.Stream(config.Global.Kafka.PoolStatsTopic)
 
 	gConfig := sarama.NewConfig()
 	gConfig.Consumer.Offsets.Initial = sarama.OffsetOldest
 
-	poolAggG := goka.DefineGroup(poolAggregatorGroup,
+	poolAggG := goka.DefineGroup(groupName(poolAggregatorGroup),
 		goka.Input(poolStatsStream, new(kafka.ParsedEventCodec), poolStatsEventHandler),
 		goka.Persist(new(pool)),
 	)
@@ -60,6 +61,7 @@ func poolStatsEventHandler(ctx goka.Context, msg interface{}) {
 	}
 
 	iEvent := msg.(kafka.ParsedEvent)
+	observeEvent(poolAggregatorGroup, iEvent.EventIndex, iEvent.BlockTimestamp)
 
 	// We are reducing partitions, so we need to track heights of the partition used in the
 	// original, more partitioned, topic
@@ -13,17 +13,19 @@ import (
 	"strings"
 )
 
-func emitPoolEvents(ctx context.Context) chan error {
+const poolEmitterGroup = "pool-emitter"
 
-	poolEmitterGroup := goka.Group("pool-emitter")
+func emitPoolEvents(ctx context.Context) chan error {
 
 	blockStream := goka.Stream(config.Global.Kafka.BlockTopic)
 	poolStream := goka.Stream(config.Global.Kafka.PoolTopic)
 
 	gConfig := sarama.NewConfig()
 	gConfig.Consumer.Offsets.Initial = sarama.OffsetOldest
+	// The producer writes the blocks in transactions, aborted ones are skipped.
+	gConfig.Consumer.IsolationLevel = sarama.ReadCommitted
 
-	poolEmitterG := goka.DefineGroup(poolEmitterGroup,
+	poolEmitterG := goka.DefineGroup(groupName(poolEmitterGroup),
 		goka.Input(blockStream, new(kafka.IndexedEventCodec), blockEventHandler),
 		goka.Output(poolStream, new(kafka.ParsedEventCodec)),
 	)
@@ -59,6 +61,7 @@ func blockEventHandler(ctx goka.Context, msg interface{}) {
 	}
 
 	iEvent := msg.(kafka.IndexedEvent)
+	observeEvent(poolEmitterGroup, iEvent.EventIndex, iEvent.BlockTimestamp)
 
 	// NOTE: we don't do any processing, we just emit events, so there is
 	// no duplicate handling here. It is done by the consumers.
@@ -99,6 +102,16 @@ func blockEventHandler(ctx goka.Context, msg interface{}) {
 			ctx.Emit(poolStream, string(gas.Asset), pE)
 		}
 
+	case "pool":
+		var poolEvent record.Pool
+		if err := poolEvent.LoadTendermint(event.Attributes); err != nil {
+			midlog.FatalF("Failed to load pool event: %v", err)
+		} else {
+			pE := kafka.NewParsedEventFromIndexedEvent(iEvent)
+			pE.Event = poolEvent
+			ctx.Emit(poolStream, string(poolEvent.Asset), pE)
+		}
+
 	case "pool_balance_change":
 		var poolBalChange record.PoolBalanceChange
 		if err := poolBalChange.LoadTendermint(event.Attributes); err != nil {
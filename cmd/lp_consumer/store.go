@@ -0,0 +1,186 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"gitlab.com/thorchain/midgard/internal/db"
+	"gitlab.com/thorchain/midgard/internal/util/kafka"
+	"gitlab.com/thorchain/midgard/internal/util/midlog"
+)
+
+// The pool state is written into the lp_* tables of the Midgard schema, they are created by the
+// 0002_lp_consumer core migration.
+
+const statsBucketSize = 5 * time.Minute
+
+// Activity of a pool in one event, added to the bucket of the event in lp_pool_stats.
+type poolStats struct {
+	AddCount         int64
+	AddAssetE8       int64
+	AddRuneE8        int64
+	AddAssetInRuneE8 int64
+	WithdrawCount    int64
+	WithdrawAssetE8  int64
+	WithdrawRuneE8   int64
+	SwapCount        int64
+	SwapVolumeRuneE8 int64
+	SwapFeesRuneE8   int64
+	RewardsE8        int64
+}
+
+// Change of a liquidity provider position.
+type memberChange struct {
+	// RUNE address, or the asset address for asset only providers. For withdraws it is the
+	// sender, which may be either of them.
+	ID        string
+	AssetAddr string
+	Withdraw  bool
+	Units     int64
+	AssetE8   int64
+	RuneE8    int64
+}
+
+func mustHaveTables() {
+	_, err := db.TheDB.Exec("SELECT 1 FROM lp_pools LIMIT 1")
+	if err != nil {
+		midlog.FatalE(err,
+			"lp_consumer tables are missing, apply the migrations with `go run ./cmd/migrate config up`")
+	}
+}
+
+// Writes the state of the pool after the event in one transaction. Events which were already
+// written (when Kafka replays messages after a restart) are skipped.
+func persistEvent(ctx context.Context, poolName string, p *pool, ev kafka.ParsedEvent,
+	stats poolStats, member *memberChange) error {
+	tx, err := db.TheDB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var last kafka.EventIdx
+	err = tx.QueryRowContext(ctx,
+		"SELECT height, event_offset FROM lp_pools WHERE pool = $1 FOR UPDATE", poolName,
+	).Scan(&last.Height, &last.Offset)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+	case err != nil:
+		return err
+	case ev.EventIndex.LessOrEqual(last):
+		return nil
+	}
+
+	timestamp := ev.BlockTimestamp.UnixNano()
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO lp_pools (pool, asset_e8, rune_e8, synth_e8, units,
+			add_count, withdraw_count, swap_count, height, event_offset, block_timestamp)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (pool) DO UPDATE SET
+			asset_e8 = EXCLUDED.asset_e8, rune_e8 = EXCLUDED.rune_e8,
+			synth_e8 = EXCLUDED.synth_e8, units = EXCLUDED.units,
+			add_count = EXCLUDED.add_count, withdraw_count = EXCLUDED.withdraw_count,
+			swap_count = EXCLUDED.swap_count, height = EXCLUDED.height,
+			event_offset = EXCLUDED.event_offset, block_timestamp = EXCLUDED.block_timestamp`,
+		poolName, p.AssetE8Depth, p.RuneE8Depth, p.SynthE8Depth, p.StakeUnits,
+		p.AddCount, p.WithdrawCount, p.SwapCount,
+		ev.EventIndex.Height, ev.EventIndex.Offset, timestamp)
+	if err != nil {
+		return err
+	}
+
+	// The last event of the block wins.
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO lp_pool_depths (pool, asset_e8, rune_e8, synth_e8, units, height, block_timestamp)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (pool, block_timestamp) DO UPDATE SET
+			asset_e8 = EXCLUDED.asset_e8, rune_e8 = EXCLUDED.rune_e8,
+			synth_e8 = EXCLUDED.synth_e8, units = EXCLUDED.units`,
+		poolName, p.AssetE8Depth, p.RuneE8Depth, p.SynthE8Depth, p.StakeUnits,
+		ev.EventIndex.Height, timestamp)
+	if err != nil {
+		return err
+	}
+
+	if stats != (poolStats{}) {
+		if err := addStats(ctx, tx, poolName, ev.BlockTimestamp, stats); err != nil {
+			return err
+		}
+	}
+	if member != nil {
+		if err := changeMember(ctx, tx, poolName, timestamp, member); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func addStats(ctx context.Context, tx *sql.Tx, poolName string, t time.Time, s poolStats) error {
+	bucketStart := t.Truncate(statsBucketSize).UnixNano()
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO lp_pool_stats AS s (pool, bucket_start,
+			add_count, add_asset_e8, add_rune_e8, add_asset_in_rune_e8,
+			withdraw_count, withdraw_asset_e8, withdraw_rune_e8,
+			swap_count, swap_volume_rune_e8, swap_fees_rune_e8, rewards_e8)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		ON CONFLICT (pool, bucket_start) DO UPDATE SET
+			add_count = s.add_count + EXCLUDED.add_count,
+			add_asset_e8 = s.add_asset_e8 + EXCLUDED.add_asset_e8,
+			add_rune_e8 = s.add_rune_e8 + EXCLUDED.add_rune_e8,
+			add_asset_in_rune_e8 = s.add_asset_in_rune_e8 + EXCLUDED.add_asset_in_rune_e8,
+			withdraw_count = s.withdraw_count + EXCLUDED.withdraw_count,
+			withdraw_asset_e8 = s.withdraw_asset_e8 + EXCLUDED.withdraw_asset_e8,
+			withdraw_rune_e8 = s.withdraw_rune_e8 + EXCLUDED.withdraw_rune_e8,
+			swap_count = s.swap_count + EXCLUDED.swap_count,
+			swap_volume_rune_e8 = s.swap_volume_rune_e8 + EXCLUDED.swap_volume_rune_e8,
+			swap_fees_rune_e8 = s.swap_fees_rune_e8 + EXCLUDED.swap_fees_rune_e8,
+			rewards_e8 = s.rewards_e8 + EXCLUDED.rewards_e8`,
+		poolName, bucketStart,
+		s.AddCount, s.AddAssetE8, s.AddRuneE8, s.AddAssetInRuneE8,
+		s.WithdrawCount, s.WithdrawAssetE8, s.WithdrawRuneE8,
+		s.SwapCount, s.SwapVolumeRuneE8, s.SwapFeesRuneE8, s.RewardsE8)
+	return err
+}
+
+func changeMember(ctx context.Context, tx *sql.Tx, poolName string, timestamp int64,
+	m *memberChange) error {
+	if !m.Withdraw {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO lp_members AS m (pool, member_id, asset_addr, units,
+				asset_added_e8, rune_added_e8, asset_withdrawn_e8, rune_withdrawn_e8,
+				first_added_timestamp, last_change_timestamp)
+			VALUES ($1, $2, NULLIF($3, ''), $4, $5, $6, 0, 0, $7, $7)
+			ON CONFLICT (pool, member_id) DO UPDATE SET
+				asset_addr = COALESCE(EXCLUDED.asset_addr, m.asset_addr),
+				units = m.units + EXCLUDED.units,
+				asset_added_e8 = m.asset_added_e8 + EXCLUDED.asset_added_e8,
+				rune_added_e8 = m.rune_added_e8 + EXCLUDED.rune_added_e8,
+				last_change_timestamp = EXCLUDED.last_change_timestamp`,
+			poolName, m.ID, m.AssetAddr, m.Units, m.AssetE8, m.RuneE8, timestamp)
+		return err
+	}
+
+	// The sender of the withdraw is the RUNE address or the asset address of the member.
+	res, err := tx.ExecContext(ctx, `
+		UPDATE lp_members SET
+			units = units - $3,
+			asset_withdrawn_e8 = asset_withdrawn_e8 + $4,
+			rune_withdrawn_e8 = rune_withdrawn_e8 + $5,
+			last_change_timestamp = $6
+		WHERE pool = $1 AND member_id = (
+			SELECT member_id FROM lp_members
+			WHERE pool = $1 AND (member_id = $2 OR asset_addr = $2)
+			ORDER BY member_id = $2 DESC
+			LIMIT 1)`,
+		poolName, m.ID, m.Units, m.AssetE8, m.RuneE8, timestamp)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		midlog.WarnF("Withdraw of unknown member %s from pool %s", m.ID, poolName)
+	}
+	return nil
+}
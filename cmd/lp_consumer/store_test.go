@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gitlab.com/thorchain/midgard/internal/db"
+	"gitlab.com/thorchain/midgard/internal/db/testdb"
+	"gitlab.com/thorchain/midgard/internal/util/kafka"
+)
+
+func clearLPTables(t *testing.T) {
+	testdb.MustExec(t, "DELETE FROM lp_pools")
+	testdb.MustExec(t, "DELETE FROM lp_pool_depths")
+	testdb.MustExec(t, "DELETE FROM lp_members")
+	testdb.MustExec(t, "DELETE FROM lp_pool_stats")
+}
+
+func lpEvent(height int64, offset int16, timestamp time.Time) kafka.ParsedEvent {
+	return kafka.ParsedEvent{
+		EventIndex:     kafka.EventIdx{Height: height, Offset: offset},
+		BlockTimestamp: timestamp,
+	}
+}
+
+// The lp_* tables are created by the core migrations.
+func TestPersistEvent(t *testing.T) {
+	testdb.InitTest(t)
+	clearLPTables(t)
+	mustHaveTables()
+	ctx := context.Background()
+	t0 := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	p := NewPool()
+	p.AddCount = 1
+	p.AssetE8Depth, p.RuneE8Depth, p.StakeUnits = 10, 20, 5
+	add := &memberChange{ID: "thoraddr", AssetAddr: "btcaddr", Units: 5, AssetE8: 10, RuneE8: 20}
+	require.NoError(t, persistEvent(ctx, "BTC.BTC", p, lpEvent(1, 3, t0),
+		poolStats{AddCount: 1, AddAssetE8: 10, AddRuneE8: 20}, add))
+
+	// Replayed events are skipped.
+	require.NoError(t, persistEvent(ctx, "BTC.BTC", p, lpEvent(1, 3, t0),
+		poolStats{AddCount: 1, AddAssetE8: 10, AddRuneE8: 20}, add))
+
+	p.WithdrawCount = 1
+	p.AssetE8Depth, p.RuneE8Depth, p.StakeUnits = 8, 16, 4
+	withdraw := &memberChange{ID: "btcaddr", Withdraw: true, Units: 1, AssetE8: 2, RuneE8: 4}
+	require.NoError(t, persistEvent(ctx, "BTC.BTC", p, lpEvent(2, 0, t0.Add(time.Minute)),
+		poolStats{WithdrawCount: 1, WithdrawAssetE8: 2, WithdrawRuneE8: 4}, withdraw))
+
+	var units, height, addCount, withdrawCount int64
+	var offset int16
+	require.NoError(t, db.TheDB.QueryRow(
+		"SELECT units, height, event_offset FROM lp_pools WHERE pool = 'BTC.BTC'",
+	).Scan(&units, &height, &offset))
+	require.Equal(t, int64(4), units)
+	require.Equal(t, int64(2), height)
+	require.Equal(t, int16(0), offset)
+
+	var depths int
+	require.NoError(t, db.TheDB.QueryRow(
+		"SELECT COUNT(*) FROM lp_pool_depths WHERE pool = 'BTC.BTC'").Scan(&depths))
+	require.Equal(t, 2, depths)
+
+	require.NoError(t, db.TheDB.QueryRow(`
+		SELECT add_count, withdraw_count FROM lp_pool_stats WHERE pool = 'BTC.BTC'`,
+	).Scan(&addCount, &withdrawCount))
+	require.Equal(t, int64(1), addCount)
+	require.Equal(t, int64(1), withdrawCount)
+
+	var memberUnits, assetWithdrawn int64
+	require.NoError(t, db.TheDB.QueryRow(`
+		SELECT units, asset_withdrawn_e8 FROM lp_members
+		WHERE pool = 'BTC.BTC' AND member_id = 'thoraddr'`,
+	).Scan(&memberUnits, &assetWithdrawn))
+	require.Equal(t, int64(4), memberUnits)
+	require.Equal(t, int64(2), assetWithdrawn)
+}
@@ -7,23 +7,16 @@ import (
 
 	"gitlab.com/thorchain/midgard/config"
 	"gitlab.com/thorchain/midgard/internal/db"
-	"gitlab.com/thorchain/midgard/internal/fetch/sync/chain"
+	"gitlab.com/thorchain/midgard/internal/fetch/record"
 	"gitlab.com/thorchain/midgard/internal/timeseries"
 	"gitlab.com/thorchain/midgard/internal/util/jobs"
 	"gitlab.com/thorchain/midgard/internal/util/midlog"
 )
 
 type blockWriter struct {
-	ctx    context.Context
-	blocks <-chan chain.Block
-}
-
-func (x *blockWriter) Do() {
-	err := x.loop()
-	if err != nil {
-		midlog.ErrorE(err, "Unrecoverable error in BlockWriter, terminating")
-		jobs.InitiateShutdown()
-	}
+	ctx      context.Context
+	blocks   <-chan *record.ParsedBlock
+	onFenced func()
 }
 
 func (x *blockWriter) loop() error {
@@ -32,7 +25,7 @@ func (x *blockWriter) loop() error {
 
 	hardForkHeight := db.CurrentChain.Get().HardForkHeight
 	heightBeforeStart := db.LastCommittedBlock.Get().Height
-	if hardForkHeight != 0 && hardForkHeight <= heightBeforeStart {
+	if hardForkHeight != 0 && hardForkHeight <= heightBeforeStart && !db.ContinuesAfterFork() {
 		x.waitAtForkAndExit(heightBeforeStart)
 	}
 
@@ -45,12 +38,20 @@ func (x *blockWriter) loop() error {
 		case <-x.ctx.Done():
 			x.logBlockWriteShutdown(lastHeightWritten)
 			return nil
-		case block := <-x.blocks:
+		case parsed, ok := <-x.blocks:
+			if !ok {
+				// Parsing stops only on shutdown.
+				x.logBlockWriteShutdown(lastHeightWritten)
+				return nil
+			}
+			block := parsed.Block
 			if block.Height == 0 {
 				// Default constructed block, height should be at least 1.
 				return errors.New("Block height of 0 is invalid")
 			}
 
+			// The fetcher switches to the child chain after the fork, if there is one.
+			hardForkHeight = db.CurrentChain.Get().HardForkHeight
 			lastBlockBeforeStop := false
 			if hardForkHeight != 0 {
 				if block.Height == hardForkHeight {
@@ -72,8 +73,16 @@ func (x *blockWriter) loop() error {
 			_, immediate := db.Inserter.(*db.ImmediateInserter)
 
 			synced := db.LastThorNodeBlock.Get().Height <= block.Height+1
-			commit := immediate || synced || block.Height%blockBatch == 0 || lastBlockBeforeStop
-			err := timeseries.ProcessBlock(&block, commit)
+			paused := db.IngestionPaused()
+			commit := immediate || synced || block.Height%blockBatch == 0 || lastBlockBeforeStop ||
+				paused
+			err := timeseries.ProcessParsedBlock(parsed, commit)
+			if errors.Is(err, db.ErrWriterFenced) && x.onFenced != nil {
+				// An other instance is the writer now, this one switches to standby.
+				midlog.WarnF("Block %d not committed, the writer lock was lost", block.Height)
+				x.onFenced()
+				return nil
+			}
 			if err != nil {
 				return err
 			}
@@ -85,12 +94,33 @@ func (x *blockWriter) loop() error {
 			lastHeightWritten = block.Height
 			t()
 
-			if hardForkHeight != 0 && hardForkHeight <= lastHeightWritten {
+			if hardForkHeight != 0 && hardForkHeight <= lastHeightWritten &&
+				!db.ContinuesAfterFork() {
 				x.waitAtForkAndExit(lastHeightWritten)
 				return nil
 			}
+
+			if paused && !x.waitWhilePaused(lastHeightWritten) {
+				return nil
+			}
+		}
+	}
+}
+
+// Blocks until ingestion is resumed, returns false on shutdown.
+// The last block before the pause is always committed, so the DB doesn't lag behind.
+func (x *blockWriter) waitWhilePaused(lastHeightWritten int64) bool {
+	midlog.InfoF("Block writing paused at height %d", lastHeightWritten)
+	for db.IngestionPaused() {
+		select {
+		case <-x.ctx.Done():
+			x.logBlockWriteShutdown(lastHeightWritten)
+			return false
+		case <-time.After(time.Second):
 		}
 	}
+	midlog.InfoF("Block writing resumed at height %d", lastHeightWritten)
+	return true
 }
 
 func (x *blockWriter) waitAtForkAndExit(lastHeightWritten int64) {
@@ -21,6 +21,7 @@ import (
 	"gitlab.com/thorchain/midgard/internal/util/jobs"
 	"gitlab.com/thorchain/midgard/internal/util/midlog"
 	"gitlab.com/thorchain/midgard/internal/util/timer"
+	"gitlab.com/thorchain/midgard/internal/webhooks"
 	"gitlab.com/thorchain/midgard/internal/websockets"
 )
 
@@ -36,17 +37,24 @@ func main() {
 
 	waitingJobs := []jobs.NamedFunction{}
 
-	blocks, fetchJob := sync.InitBlockFetch(mainContext)
+	sync.InitGlobalSync(mainContext)
 
-	// InitBlockFetch may take some time to copy remote blockstore to local.
+	// InitGlobalSync may take some time to copy remote blockstore to local.
 	// If it was cancelled, we don't create anything else.
 	jobs.StopIfCanceled()
 
-	waitingJobs = append(waitingJobs, fetchJob)
+	setupBlockWrite()
 
-	waitingJobs = append(waitingJobs, initBlockWrite(mainContext, blocks))
+	err := webhooks.Setup()
+	if err != nil {
+		midlog.FatalE(err, "Webhooks config")
+	}
 
-	waitingJobs = append(waitingJobs, db.InitAggregatesRefresh(mainContext))
+	if config.Global.WriterLock.Enabled {
+		waitingJobs = append(waitingJobs, initWriterElection(mainContext))
+	} else {
+		waitingJobs = append(waitingJobs, initWriterJobs(mainContext, nil)...)
+	}
 
 	waitingJobs = append(waitingJobs, initHTTPServer(mainContext))
 
@@ -104,22 +112,27 @@ func initHTTPServer(ctx context.Context) jobs.NamedFunction {
 		WriteTimeout: c.WriteTimeout.Value(),
 	}
 
-	// launch HTTP server
-	go func() {
-		err := srv.ListenAndServe()
-		midlog.ErrorE(err, "HTTP stopped")
-		jobs.InitiateShutdown()
-	}()
-
-	return jobs.Later("HTTPserver", func() {
-		<-ctx.Done()
-		if err := srv.Shutdown(context.Background()); err != nil {
-			midlog.ErrorE(err, "HTTP failed shutdown")
+	return jobs.Supervised(ctx, "HTTPserver", jobs.RestartNever, func() error {
+		// launch HTTP server
+		served := make(chan error, 1)
+		go func() {
+			served <- srv.ListenAndServe()
+		}()
+
+		select {
+		case err := <-served:
+			return fmt.Errorf("HTTP stopped: %w", err)
+		case <-ctx.Done():
+			if err := srv.Shutdown(context.Background()); err != nil {
+				midlog.ErrorE(err, "HTTP failed shutdown")
+			}
+			return nil
 		}
 	})
 }
 
-func initBlockWrite(ctx context.Context, blocks <-chan chain.Block) jobs.NamedFunction {
+// Called once, also by the standby instances.
+func setupBlockWrite() {
 	db.EnsureDBMatchesChain()
 	record.LoadCorrections(db.RootChain.Get().Name)
 
@@ -127,11 +140,17 @@ func initBlockWrite(ctx context.Context, blocks <-chan chain.Block) jobs.NamedFu
 	if err != nil {
 		midlog.FatalE(err, "Failed to read constants")
 	}
+}
+
+// onFenced is called when a block can't be committed because the writer lock was lost.
+func initBlockWrite(ctx context.Context, blocks <-chan chain.Block, onFenced func()) jobs.NamedFunction {
 	writer := blockWriter{
-		ctx:    ctx,
-		blocks: blocks,
+		ctx:      ctx,
+		blocks:   record.ParseBlocks(ctx, blocks, config.Global.EventRecorder.ParseParallelism),
+		onFenced: onFenced,
 	}
-	return jobs.Later("BlockWrite", writer.Do)
+	// Errors are unrecoverable, Midgard shuts down.
+	return jobs.Supervised(ctx, "BlockWrite", jobs.RestartNever, writer.loop)
 }
 
 func setupDB() {
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gitlab.com/thorchain/midgard/config"
+	"gitlab.com/thorchain/midgard/internal/db"
+	"gitlab.com/thorchain/midgard/internal/fetch/sync"
+	"gitlab.com/thorchain/midgard/internal/kafkasink"
+	"gitlab.com/thorchain/midgard/internal/timeseries"
+	"gitlab.com/thorchain/midgard/internal/util/jobs"
+	"gitlab.com/thorchain/midgard/internal/util/midlog"
+	"gitlab.com/thorchain/midgard/internal/webhooks"
+)
+
+// Standby mode: the instance which holds the writer lock runs the fetch, write and aggregate
+// jobs, the others serve the API and follow the DB. When the lock is lost the writer jobs are
+// stopped and the instance becomes a standby again.
+type writerElection struct {
+	ctx      context.Context
+	key      int64
+	interval time.Duration
+}
+
+func initWriterElection(ctx context.Context) jobs.NamedFunction {
+	c := config.Global.WriterLock
+	e := writerElection{ctx: ctx, key: c.Key, interval: c.CheckInterval.Value()}
+	if e.interval <= 0 {
+		e.interval = 5 * time.Second
+	}
+	midlog.InfoF("Standby mode, writer lock key: %d", e.key)
+	return jobs.Supervised(ctx, "WriterElection", jobs.RestartNever, e.loop)
+}
+
+func (e *writerElection) loop() error {
+	for {
+		lock := e.waitForLock()
+		if lock == nil {
+			midlog.Info("Shutdown writer election")
+			return nil
+		}
+		err := e.write(lock)
+		lock.Release()
+		if err != nil {
+			return err
+		}
+		if e.ctx.Err() != nil {
+			midlog.Info("Shutdown writer election")
+			return nil
+		}
+	}
+}
+
+// Returns nil on shutdown.
+func (e *writerElection) waitForLock() *db.WriterLock {
+	logged := false
+	for {
+		lock, err := db.TryAcquireWriterLock(e.ctx, e.key)
+		if err != nil && e.ctx.Err() == nil {
+			midlog.WarnF("Failed to try the writer lock: %v", err)
+		}
+		if lock != nil {
+			return lock
+		}
+		if !logged {
+			midlog.Info("Writer lock is held by an other instance, waiting in standby")
+			logged = true
+		}
+		followWriter()
+
+		select {
+		case <-e.ctx.Done():
+			return nil
+		case <-time.After(e.interval):
+		}
+	}
+}
+
+// Updates the last block and the aggregates watermark written by the writer instance.
+func followWriter() {
+	err := timeseries.ReloadIfChanged(config.Global.UsdPools)
+	if err != nil {
+		midlog.WarnF("Failed to read last block: %v", err)
+	}
+	err = db.LoadAggregatesWatermark()
+	if err != nil {
+		midlog.WarnF("Failed to read aggregates watermark: %v", err)
+	}
+}
+
+// Runs the writer jobs until the lock is lost or shutdown.
+func (e *writerElection) write(lock *db.WriterLock) error {
+	midlog.Info("Acquired writer lock, starting to write blocks")
+
+	// Rows of an earlier term which were not committed are dropped, writing continues from
+	// block_log.
+	db.DiscardPendingInserts()
+	err := timeseries.Reload(config.Global.UsdPools)
+	if err != nil {
+		return fmt.Errorf("error during reading last block from DB: %w", err)
+	}
+
+	db.SetWriterFence(lock)
+	defer db.SetWriterFence(nil)
+
+	ctx, cancel := context.WithCancel(e.ctx)
+	defer cancel()
+	runningJobs := []*jobs.RunningJob{}
+	for _, waiting := range initWriterJobs(ctx, cancel) {
+		runningJobs = append(runningJobs, waiting.Start())
+	}
+
+	for ctx.Err() == nil {
+		select {
+		case <-ctx.Done():
+		case <-time.After(e.interval):
+			err := lock.Check(ctx)
+			if err != nil && ctx.Err() == nil {
+				midlog.ErrorE(err, "Writer lock lost, switching to standby")
+				cancel()
+			}
+		}
+	}
+
+	timeout := config.Global.ShutdownTimeout.Value()
+	finishCTX, finishCancel := context.WithTimeout(context.Background(), timeout)
+	defer finishCancel()
+	jobs.WaitAll(finishCTX, runningJobs...)
+	return nil
+}
+
+// Jobs run only by the instance which writes to the DB. onFenced is called when the writer lock
+// turns out to be lost at a block commit, nil if there is no writer lock.
+func initWriterJobs(ctx context.Context, onFenced func()) []jobs.NamedFunction {
+	blocks, fetchJob := sync.StartBlockFetch(ctx)
+	return []jobs.NamedFunction{
+		fetchJob,
+		initBlockWrite(ctx, blocks, onFenced),
+		db.InitAggregatesRefresh(ctx),
+		db.InitRetention(ctx),
+		kafkasink.Init(ctx),
+		webhooks.Init(ctx),
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+// Shows or applies the pending schema migrations.
+//
+// Usage: $ migrate config [status|up]
+
+import (
+	"fmt"
+	"os"
+
+	"gitlab.com/thorchain/midgard/config"
+	"gitlab.com/thorchain/midgard/internal/db"
+	"gitlab.com/thorchain/midgard/internal/util/midlog"
+
+	// Registers the aggregates, they are part of the aggregates DDL.
+	_ "gitlab.com/thorchain/midgard/internal/db/dbinit"
+	_ "gitlab.com/thorchain/midgard/internal/globalinit"
+)
+
+func main() {
+	midlog.LogCommandLine()
+
+	if len(os.Args) != 3 || (os.Args[2] != "status" && os.Args[2] != "up") {
+		midlog.FatalF("Provide 2 arguments, %d provided\nUsage: $ migrate config [status|up]",
+			len(os.Args)-1)
+	}
+
+	config.ReadGlobalFrom(os.Args[1])
+	db.SetupWithoutUpdate()
+
+	switch os.Args[2] {
+	case "status":
+		printStatus()
+	case "up":
+		midlog.Warn("If Midgard is running, stop it and rerun this tool!")
+		db.MigrateUp(db.TheDB)
+		midlog.Info("Schema is up to date")
+		printStatus()
+	}
+}
+
+func printStatus() {
+	statuses, err := db.MigrationStatuses(db.TheDB)
+	if err != nil {
+		midlog.FatalE(err, "Failed to read migration status")
+	}
+	for _, status := range statuses {
+		fmt.Printf("%s:\n", status.Tag)
+		if status.BaselineChanged {
+			fmt.Println("  baseline DDL changed, the schema will be recreated")
+		} else if status.NeedsReset() {
+			fmt.Println("  a pending migration needs the schema to be recreated")
+		}
+		for _, m := range status.Applied {
+			fmt.Printf("  applied  %04d_%s  %s\n", m.Version, m.Name, m.AppliedAt.UTC().Format("2006-01-02 15:04:05"))
+		}
+		for _, m := range status.Pending {
+			flags := ""
+			if m.NeedsReset {
+				flags = "  (needs reset)"
+			}
+			fmt.Printf("  pending  %04d_%s%s\n", m.Version, m.Name, flags)
+		}
+		if len(status.Applied) == 0 && len(status.Pending) == 0 {
+			fmt.Println("  no migrations")
+		}
+	}
+}
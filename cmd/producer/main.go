@@ -2,20 +2,17 @@ package main
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"github.com/Shopify/sarama"
-	"github.com/lovoo/goka"
+	"github.com/tendermint/tendermint/abci/types"
 	"gitlab.com/thorchain/midgard/config"
 	"gitlab.com/thorchain/midgard/internal/fetch/record"
 	"gitlab.com/thorchain/midgard/internal/fetch/sync"
 	"gitlab.com/thorchain/midgard/internal/fetch/sync/blockstore"
 	"gitlab.com/thorchain/midgard/internal/fetch/sync/chain"
+	"gitlab.com/thorchain/midgard/internal/util/jobs"
 	"gitlab.com/thorchain/midgard/internal/util/kafka"
 	"gitlab.com/thorchain/midgard/internal/util/midlog"
-	"os"
-	"os/signal"
-	"syscall"
 	"time"
 
 	_ "gitlab.com/thorchain/midgard/internal/globalinit"
@@ -27,11 +24,11 @@ var (
 )
 
 func main() {
-	mainCtx, done := context.WithCancel(context.Background())
-
 	midlog.LogCommandLine()
 	config.ReadGlobal()
 
+	mainCtx := jobs.InitSignals()
+
 	blockstore.NewBlockStore(
 		mainCtx, config.Global.BlockStore, "thorchain")
 
@@ -40,244 +37,159 @@ func main() {
 
 	loadAllCorrections(extraEvents, correctEvents)
 
-	go func() {
-		inSync := false
-
-		brokers := config.Global.Kafka.Brokers
-		topic := config.Global.Kafka.BlockTopic
-
-		for {
-
-			lastHeight, lastIndex, err := GetLastHeight(brokers, topic)
-			if err != nil {
-				midlog.FatalE(err, "Unable to get last stored height")
-			}
-
-			if !inSync {
-				midlog.InfoF("Resuming sync from: block %v, offset %v", lastHeight, lastIndex)
-			}
-
-			blocks := make(chan chain.Block)
-			events := make(chan kafka.IndexedEvent)
+	// On failures the producer is recreated, it continues after the last committed block.
+	producerJob := jobs.Supervised(mainCtx, "Producer", jobs.RestartOnFailure, func() error {
+		return produce(mainCtx, s)
+	}).Start()
 
-			loopCtx, loopCancel := context.WithCancel(mainCtx)
-
-			go processEvents(loopCtx, events, lastHeight, lastIndex)
-			go processBlocks(loopCtx, blocks, events)
-
-			// The docs for this function mention errquit and errnodata but these don't seem to exist
-			_, inSync, err = s.CatchUp(blocks, lastHeight)
-			if err != nil {
-				midlog.WarnF("Resuming sync due to error: %v", err)
-			}
-
-			loopCancel()
+	jobs.WaitUntilSignal()
+	jobs.ShutdownWait(producerJob)
+}
 
-			select {
-			case <-mainCtx.Done():
-				break
-			default:
-				// Give it a few secs to drain the kafka queues
-				time.Sleep(time.Second * 5)
-			}
+// Publishes the blocks until ctx is cancelled or an error occurs.
+func produce(ctx context.Context, s *sync.Sync) error {
+	brokers := config.Global.Kafka.Brokers
+	topic := config.Global.Kafka.BlockTopic
 
-			midlog.Info("Terminating chain sync routine")
+	p, err := openPublisher(brokers, topic)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := p.Close(); err != nil {
+			midlog.WarnF("Error trying to close the producer: %v", err)
 		}
 	}()
+	midlog.InfoF("Resuming sync from block %d", p.NextHeight())
 
-	sigs := make(chan os.Signal)
-	go func() {
-		signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM, syscall.SIGKILL)
-	}()
-
-	<-sigs
-	done()
-
-	midlog.Info("Signal received, exiting")
-}
-
-func processBlocks(ctx context.Context, in chan chain.Block, out chan kafka.IndexedEvent) {
 	for {
-		select {
-		case <-ctx.Done():
-			return
-		case block := <-in:
-			idx := int16(0)
-
-			for i := range block.Results.BeginBlockEvents {
-				iEvent := kafka.IndexedEvent{
-					EventIndex: kafka.EventIdx{
-						Height: block.Height,
-						Offset: idx,
-					},
-					BlockTimestamp: block.Time,
-					Event:          &block.Results.BeginBlockEvents[i],
-				}
-				idx++
-				out <- iEvent
-			}
-
-			for _, tx := range block.Results.TxsResults {
-				for i := range tx.Events {
-					iEvent := kafka.IndexedEvent{
-						EventIndex: kafka.EventIdx{
-							Height: block.Height,
-							Offset: idx,
-						},
-						BlockTimestamp: block.Time,
-						Event:          &tx.Events[i],
-					}
-					idx++
-					out <- iEvent
-				}
-			}
-
-			for i := range block.Results.EndBlockEvents {
-				iEvent := kafka.IndexedEvent{
-					EventIndex: kafka.EventIdx{
-						Height: block.Height,
-						Offset: idx,
-					},
-					BlockTimestamp: block.Time,
-					Event:          &block.Results.EndBlockEvents[i],
-				}
-				idx++
-				out <- iEvent
-			}
-
-			// Add any corrections that are supposed to be in this block
-			if extras, ok := extraEvents[block.Height]; ok {
-				for _, v := range extras {
-					midlog.WarnF("Sending extra event: %v", v.Type)
-					iEvent := kafka.IndexedEvent{
-						EventIndex: kafka.EventIdx{
-							Height: block.Height,
-							Offset: idx,
-						},
-						BlockTimestamp: block.Time,
-						Event:          v,
-					}
-					idx++
-					out <- iEvent
-				}
-			}
+		inSync, err := publishCatchUp(ctx, s, p)
+		if err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+		if inSync {
+			jobs.Sleep(ctx, time.Second)
 		}
 	}
 }
 
-func processEvents(ctx context.Context, in chan kafka.IndexedEvent, lastHeight int64, lastIndex int16) {
-	brokers := config.Global.Kafka.Brokers
-	topic := config.Global.Kafka.BlockTopic
+// Creates the transactional producer and resumes after the last checkpoint.
+func openPublisher(brokers []string, topic string) (*Publisher, error) {
+	client, err := sarama.NewClient(brokers, kafka.NewConfig())
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+	consumer, err := sarama.NewConsumerFromClient(client)
+	if err != nil {
+		return nil, err
+	}
+	defer consumer.Close()
 
-	eventEmitter, err := goka.NewEmitter(brokers, goka.Stream(topic), new(kafka.IndexedEventCodec))
+	last, found, err := ReadCheckpoint(client, consumer, checkpointTopic())
 	if err != nil {
-		midlog.FatalE(err, "Failed to create event emitter")
+		return nil, fmt.Errorf("reading the checkpoint: %w", err)
 	}
-	defer func(eventEmitter *goka.Emitter) {
-		ierr := eventEmitter.Finish()
-		if ierr != nil {
-			midlog.WarnF("Error trying to gracefully close emitter: %v", ierr)
-		}
-	}(eventEmitter)
 
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		case iEvent := <-in:
-			if iEvent.EventIndex.Height == lastHeight && iEvent.EventIndex.Offset <= lastIndex {
-				continue
-			}
+	producer, err := newTransactionalProducer(brokers)
+	if err != nil {
+		return nil, err
+	}
+	p := NewPublisher(producer, topic, checkpointTopic(), last)
+	if found {
+		return p, nil
+	}
 
-			if mainnetFilter(&iEvent) == record.Discard {
-				iEvent.Event = nil
-			}
+	lastEvent, found, err := lastPublishedEvent(client, consumer, topic)
+	if err != nil {
+		p.Close()
+		return nil, fmt.Errorf("reading the last event: %w", err)
+	}
+	if found {
+		midlog.InfoF("No checkpoint yet, continuing after event %d of block %d",
+			lastEvent.Offset, lastEvent.Height)
+		p.ContinueAfter(lastEvent)
+	}
+	return p, nil
+}
 
-			if corrections, ok := correctEvents[iEvent.EventIndex.Height]; ok {
-				for _, correctFunc := range corrections {
-					if correctFunc(&iEvent) == record.Discard {
-						iEvent.Event = nil
-					}
-				}
-			}
+// Publishes the blocks fetched by one CatchUp. Fetching is stopped when publishing fails.
+func publishCatchUp(ctx context.Context, s *sync.Sync, p *Publisher) (inSync bool, err error) {
+	blocks := make(chan chain.Block)
+	loopCtx, loopCancel := context.WithCancel(ctx)
+	defer loopCancel()
 
-			keyS, err := iEvent.KeyAsString()
-			if err != nil {
-				midlog.ErrorE(err, "Error getting key to emit event message")
-			}
-
-			_, err = eventEmitter.Emit(keyS, iEvent)
+	published := make(chan error, 1)
+	go func() {
+		for block := range blocks {
+			err := p.PublishBlock(block.Height, block.Time, blockEvents(block))
 			if err != nil {
-				midlog.ErrorE(err, "Error trying to emit message")
+				loopCancel()
+				// Unblock CatchUp until it notices the cancellation.
+				for range blocks {
+				}
+				published <- err
+				return
 			}
 		}
+		published <- nil
+	}()
+
+	// The docs for this function mention errquit and errnodata but these don't seem to exist
+	_, inSync, err = s.CatchUpContext(loopCtx, blocks, p.NextHeight())
+	close(blocks)
+	if publishErr := <-published; publishErr != nil {
+		return false, publishErr
 	}
+	return inSync, err
 }
 
-var consumer sarama.Consumer // replaceable in tests
-func GetLastHeight(brokers []string, topic string) (int64, int16, error) {
-	if consumer == nil {
-		c, err := sarama.NewConsumer(brokers, sarama.NewConfig())
-		if err != nil {
-			return 0, 0, err
-		}
-		defer func(c sarama.Consumer) {
-			ierr := c.Close()
-			if ierr != nil {
-				midlog.WarnF("Error trying to close kafka client %v", ierr)
-			}
-		}(c)
-
-		consumer = c
+// Returns the events of the block with the corrections applied. Discarded events are kept with
+// a nil Event, so the offsets stay the same.
+func blockEvents(block chain.Block) []kafka.IndexedEvent {
+	var events []kafka.IndexedEvent
+	add := func(event *types.Event) {
+		events = append(events, kafka.IndexedEvent{
+			EventIndex: kafka.EventIdx{
+				Height: block.Height,
+				Offset: int16(len(events)),
+			},
+			BlockTimestamp: block.Time,
+			Event:          event,
+		})
 	}
 
-	hwmall := consumer.HighWaterMarks()
-	hwm, ok := hwmall[topic]
-	if !ok {
-		return 0, 0, errors.New("unable to find topic in high water mark map")
+	for i := range block.Results.BeginBlockEvents {
+		add(&block.Results.BeginBlockEvents[i])
 	}
-
-	var (
-		partition int32
-		high      int64
-	)
-
-	for k, v := range hwm {
-		if v > high {
-			partition = k
+	for _, tx := range block.Results.TxsResults {
+		for i := range tx.Events {
+			add(&tx.Events[i])
 		}
 	}
-
-	pc, err := consumer.ConsumePartition(topic, partition, high)
-	if err != nil {
-		return 0, 0, err
+	for i := range block.Results.EndBlockEvents {
+		add(&block.Results.EndBlockEvents[i])
 	}
 
-	var (
-		lastHeight int64
-		lastOffset int16
-	)
+	// Add any corrections that are supposed to be in this block
+	for _, v := range extraEvents[block.Height] {
+		midlog.WarnF("Sending extra event: %v", v.Type)
+		add(v)
+	}
 
-	select {
-	case msg := <-pc.Messages():
-		val := msg.Value
-		decoder := kafka.IndexedEventCodec{}
-		ieD, err := decoder.Decode(val)
-		if err != nil {
-			return 0, 0, err
+	for i := range events {
+		iEvent := &events[i]
+		if mainnetFilter(iEvent) == record.Discard {
+			iEvent.Event = nil
 		}
-
-		if _, isEvent := ieD.(kafka.IndexedEvent); !isEvent {
-			return 0, 0, errors.New(fmt.Sprintf("message should be type kafka.IndexedEvent, got %T", ieD))
+		for _, correctFunc := range correctEvents[block.Height] {
+			if correctFunc(iEvent) == record.Discard {
+				iEvent.Event = nil
+			}
 		}
-
-		ie := ieD.(kafka.IndexedEvent)
-		lastHeight = ie.EventIndex.Height
-		lastOffset = ie.EventIndex.Offset
-
-	case <-time.After(3 * time.Second):
-		return 0, 0, errors.New("timeout getting last message in topic")
 	}
-
-	return lastHeight, lastOffset, nil
+	return events
 }
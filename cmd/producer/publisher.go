@@ -0,0 +1,204 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/pascaldekloe/metrics"
+	"gitlab.com/thorchain/midgard/config"
+	"gitlab.com/thorchain/midgard/internal/util/kafka"
+	"gitlab.com/thorchain/midgard/internal/util/midlog"
+)
+
+// The events of a block are published in one Kafka transaction together with a checkpoint
+// message on the checkpoint topic, so a block is either published completely or not at all,
+// and the checkpoint is the last block published. Consumers have to read with the read_committed
+// isolation level to not see the events of aborted transactions.
+
+const checkpointKey = "producer"
+
+var (
+	publishedBlocks = metrics.MustCounter("midgard_producer_blocks_total",
+		"Number of blocks published.")
+	publishedEvents = metrics.MustCounter("midgard_producer_events_total",
+		"Number of events published.")
+	skippedBlocks = metrics.MustCounter("midgard_producer_duplicate_blocks_total",
+		"Number of blocks skipped because they were published before.")
+	abortedTxns = metrics.MustCounter("midgard_producer_aborted_transactions_total",
+		"Number of block transactions aborted.")
+	checkpointHeight = metrics.MustInteger("midgard_producer_checkpoint_height",
+		"Height of the last block published.")
+)
+
+// Checkpoint is the last block published.
+type Checkpoint struct {
+	Height         int64     `json:"height"`
+	BlockTimestamp time.Time `json:"blockTimestamp"`
+	// Number of events of the block published.
+	Events int `json:"events"`
+}
+
+func checkpointTopic() string {
+	if t := config.Global.Kafka.Producer.CheckpointTopic; t != "" {
+		return t
+	}
+	return config.Global.Kafka.BlockTopic + "-checkpoint"
+}
+
+func newTransactionalProducer(brokers []string) (sarama.SyncProducer, error) {
+	return sarama.NewSyncProducer(brokers,
+		kafka.NewTransactionalConfig(config.Global.Kafka.Producer.TransactionalID))
+}
+
+// Publisher writes the blocks into the block topic, each in a transaction.
+type Publisher struct {
+	producer        sarama.SyncProducer
+	topic           string
+	checkpointTopic string
+
+	// Last block published, blocks up to it are skipped.
+	last Checkpoint
+	// Last event published without a checkpoint, see ContinueAfter.
+	published kafka.EventIdx
+}
+
+func NewPublisher(producer sarama.SyncProducer, topic, checkpointTopic string, last Checkpoint) *Publisher {
+	checkpointHeight.Set(last.Height)
+	return &Publisher{
+		producer:        producer,
+		topic:           topic,
+		checkpointTopic: checkpointTopic,
+		last:            last,
+	}
+}
+
+// ContinueAfter resumes a block topic written without checkpoints. The block of the last event
+// may have been published partially, its events up to the last one are skipped.
+func (p *Publisher) ContinueAfter(last kafka.EventIdx) {
+	p.last = Checkpoint{Height: last.Height - 1}
+	p.published = last
+	checkpointHeight.Set(p.last.Height)
+}
+
+// NextHeight is the first height not published yet.
+func (p *Publisher) NextHeight() int64 {
+	return p.last.Height + 1
+}
+
+// PublishBlock publishes the events of the block atomically. Blocks which were published before
+// are skipped. After an error the producer can't be used anymore.
+func (p *Publisher) PublishBlock(height int64, blockTimestamp time.Time,
+	events []kafka.IndexedEvent) error {
+	if height <= p.last.Height {
+		skippedBlocks.Add(1)
+		return nil
+	}
+
+	var codec kafka.IndexedEventCodec
+	msgs := make([]*sarama.ProducerMessage, 0, len(events)+1)
+	for _, ev := range events {
+		if ev.EventIndex.LessOrEqual(p.published) {
+			continue
+		}
+		key, err := ev.KeyAsString()
+		if err != nil {
+			return err
+		}
+		value, err := codec.Encode(ev)
+		if err != nil {
+			return fmt.Errorf("encoding event %s: %w", key, err)
+		}
+		msgs = append(msgs, &sarama.ProducerMessage{
+			Topic: p.topic,
+			Key:   sarama.StringEncoder(key),
+			Value: sarama.ByteEncoder(value),
+		})
+	}
+
+	checkpoint := Checkpoint{Height: height, BlockTimestamp: blockTimestamp, Events: len(events)}
+	value, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+	msgs = append(msgs, &sarama.ProducerMessage{
+		Topic: p.checkpointTopic,
+		Key:   sarama.StringEncoder(checkpointKey),
+		Value: sarama.ByteEncoder(value),
+	})
+
+	if err := p.producer.BeginTxn(); err != nil {
+		return fmt.Errorf("begin transaction of block %d: %w", height, err)
+	}
+	if err := p.producer.SendMessages(msgs); err != nil {
+		p.abort(height)
+		return fmt.Errorf("sending block %d: %w", height, err)
+	}
+	if err := p.producer.CommitTxn(); err != nil {
+		p.abort(height)
+		return fmt.Errorf("commit transaction of block %d: %w", height, err)
+	}
+
+	p.last = checkpoint
+	publishedBlocks.Add(1)
+	publishedEvents.Add(uint64(len(msgs) - 1))
+	checkpointHeight.Set(height)
+	return nil
+}
+
+func (p *Publisher) abort(height int64) {
+	abortedTxns.Add(1)
+	if err := p.producer.AbortTxn(); err != nil {
+		midlog.WarnF("Aborting the transaction of block %d failed: %v", height, err)
+	}
+}
+
+func (p *Publisher) Close() error {
+	return p.producer.Close()
+}
+
+// ReadCheckpoint returns the last checkpoint written in the topic, found is false if the topic
+// is empty. The consumer has to read committed messages only.
+func ReadCheckpoint(offsets kafka.OffsetGetter, consumer sarama.Consumer, topic string) (
+	last Checkpoint, found bool, err error) {
+	err = kafka.ReadTails(offsets, consumer, topic, func(msg *sarama.ConsumerMessage) error {
+		var c Checkpoint
+		if err := json.Unmarshal(msg.Value, &c); err != nil {
+			return fmt.Errorf("malformed checkpoint at offset %d: %w", msg.Offset, err)
+		}
+		if !found || last.Height < c.Height {
+			last, found = c, true
+		}
+		return nil
+	})
+	if err != nil {
+		return Checkpoint{}, false, err
+	}
+	return last, found, nil
+}
+
+// Returns the last event in a block topic written without transactions, before the checkpoints
+// were introduced. The last block may have been published partially.
+func lastPublishedEvent(offsets kafka.OffsetGetter, consumer sarama.Consumer, topic string) (
+	last kafka.EventIdx, found bool, err error) {
+	var codec kafka.IndexedEventCodec
+	err = kafka.ReadTails(offsets, consumer, topic, func(msg *sarama.ConsumerMessage) error {
+		v, err := codec.Decode(msg.Value)
+		if err != nil {
+			return fmt.Errorf("malformed event at offset %d: %w", msg.Offset, err)
+		}
+		ev, ok := v.(kafka.IndexedEvent)
+		if !ok {
+			return fmt.Errorf("message should be type kafka.IndexedEvent, got %T", v)
+		}
+		if !found || !ev.EventIndex.LessOrEqual(last) {
+			last, found = ev.EventIndex, true
+		}
+		return nil
+	})
+	if err != nil {
+		return kafka.EventIdx{}, false, err
+	}
+	return last, found, nil
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/Shopify/sarama/mocks"
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/abci/types"
+	"gitlab.com/thorchain/midgard/internal/util/kafka"
+)
+
+const (
+	testTopic           = "test.blocks"
+	testCheckpointTopic = "test.blocks-checkpoint"
+)
+
+var errKilled = errors.New("producer killed")
+
+// Offsets of single partition topics starting at 0, by topic.
+type fakeOffsets map[string]int64
+
+func (o fakeOffsets) GetOffset(topic string, partitionID int32, time int64) (int64, error) {
+	if time == sarama.OffsetOldest {
+		return 0, nil
+	}
+	return o[topic], nil
+}
+
+// Stand-in for the brokers, the messages of a transaction become visible when it's committed.
+type standInKafka struct {
+	committed map[string][]*sarama.ProducerMessage
+	pending   []*sarama.ProducerMessage
+}
+
+func (k *standInKafka) send(msg *sarama.ProducerMessage) error {
+	k.pending = append(k.pending, msg)
+	return nil
+}
+
+// Returns a transactional producer which expects n messages. The one at failAt fails, like a
+// producer killed while sending it.
+func (k *standInKafka) producer(t *testing.T, n, failAt int) sarama.SyncProducer {
+	mock := mocks.NewSyncProducer(t, kafka.NewTransactionalConfig("test"))
+	for i := 0; i < n; i++ {
+		if i == failAt {
+			mock.ExpectSendMessageWithMessageCheckerFunctionAndFail(k.send, errKilled)
+		} else {
+			mock.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(k.send)
+		}
+	}
+	return &standInProducer{mock, k}
+}
+
+// Returns a consumer for the committed messages of the topic, as the brokers would return them
+// from the offset ReadCheckpoint starts at.
+func (k *standInKafka) consumer(t *testing.T, topic string) (sarama.Consumer, fakeOffsets) {
+	msgs := k.committed[topic]
+	start := len(msgs) - kafka.TailLookback
+	if start < 0 {
+		start = 0
+	}
+
+	consumer := mocks.NewConsumer(t, nil)
+	consumer.SetTopicMetadata(map[string][]int32{topic: {0}})
+	pc := consumer.ExpectConsumePartition(topic, 0, int64(start))
+	for _, msg := range msgs[start:] {
+		value, err := msg.Value.Encode()
+		require.NoError(t, err)
+		pc.YieldMessage(&sarama.ConsumerMessage{Value: value})
+	}
+	return consumer, fakeOffsets{topic: int64(len(msgs))}
+}
+
+func (k *standInKafka) events(t *testing.T) []kafka.EventIdx {
+	var codec kafka.IndexedEventCodec
+	var ret []kafka.EventIdx
+	for _, msg := range k.committed[testTopic] {
+		value, err := msg.Value.Encode()
+		require.NoError(t, err)
+		v, err := codec.Decode(value)
+		require.NoError(t, err)
+		ret = append(ret, v.(kafka.IndexedEvent).EventIndex)
+	}
+	return ret
+}
+
+type standInProducer struct {
+	*mocks.SyncProducer
+	kafka *standInKafka
+}
+
+func (p *standInProducer) CommitTxn() error {
+	for _, msg := range p.kafka.pending {
+		p.kafka.committed[msg.Topic] = append(p.kafka.committed[msg.Topic], msg)
+	}
+	p.kafka.pending = nil
+	return p.SyncProducer.CommitTxn()
+}
+
+func (p *standInProducer) AbortTxn() error {
+	p.kafka.pending = nil
+	return p.SyncProducer.AbortTxn()
+}
+
+const testBlockEvents = 3
+
+func testBlock(height int64) (time.Time, []kafka.IndexedEvent) {
+	blockTimestamp := time.Unix(1600000000+height*5, 0).UTC()
+	var events []kafka.IndexedEvent
+	for i := 0; i < testBlockEvents; i++ {
+		events = append(events, kafka.IndexedEvent{
+			EventIndex:     kafka.EventIdx{Height: height, Offset: int16(i)},
+			BlockTimestamp: blockTimestamp,
+			Event: &types.Event{Type: "message", Attributes: []types.EventAttribute{
+				{Key: []byte("action"), Value: []byte(fmt.Sprint("event ", i))}}},
+		})
+	}
+	return blockTimestamp, events
+}
+
+func publish(t *testing.T, p *Publisher, height int64) error {
+	blockTimestamp, events := testBlock(height)
+	return p.PublishBlock(height, blockTimestamp, events)
+}
+
+func TestResumeAfterKilledMidBlock(t *testing.T) {
+	k := &standInKafka{committed: map[string][]*sarama.ProducerMessage{}}
+	msgsPerBlock := testBlockEvents + 1
+
+	// Blocks 1 and 2 are published, the producer is killed while sending the second event of
+	// block 3.
+	p := NewPublisher(k.producer(t, 3*msgsPerBlock, 2*msgsPerBlock+1),
+		testTopic, testCheckpointTopic, Checkpoint{})
+	require.NoError(t, publish(t, p, 1))
+	require.NoError(t, publish(t, p, 2))
+	require.ErrorIs(t, publish(t, p, 3), errKilled)
+	require.NoError(t, p.Close())
+	require.Len(t, k.committed[testTopic], 2*testBlockEvents)
+
+	consumer, offsets := k.consumer(t, testCheckpointTopic)
+	last, found, err := ReadCheckpoint(offsets, consumer, testCheckpointTopic)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, int64(2), last.Height)
+	require.Equal(t, testBlockEvents, last.Events)
+
+	// The restarted producer gets block 2 again, it's not published twice.
+	p = NewPublisher(k.producer(t, 2*msgsPerBlock, -1),
+		testTopic, testCheckpointTopic, last)
+	require.Equal(t, int64(3), p.NextHeight())
+	for height := int64(2); height <= 4; height++ {
+		require.NoError(t, publish(t, p, height))
+	}
+	require.NoError(t, p.Close())
+
+	var expected []kafka.EventIdx
+	for height := int64(1); height <= 4; height++ {
+		for i := 0; i < testBlockEvents; i++ {
+			expected = append(expected, kafka.EventIdx{Height: height, Offset: int16(i)})
+		}
+	}
+	require.Equal(t, expected, k.events(t))
+
+	checkpoints := k.committed[testCheckpointTopic]
+	require.Len(t, checkpoints, 4)
+	value, err := checkpoints[3].Value.Encode()
+	require.NoError(t, err)
+	var c Checkpoint
+	require.NoError(t, json.Unmarshal(value, &c))
+	require.Equal(t, int64(4), c.Height)
+}
+
+func TestContinuePartialBlock(t *testing.T) {
+	k := &standInKafka{committed: map[string][]*sarama.ProducerMessage{}}
+
+	// Written without checkpoints up to the first event of block 3.
+	p := NewPublisher(k.producer(t, testBlockEvents, -1),
+		testTopic, testCheckpointTopic, Checkpoint{})
+	p.ContinueAfter(kafka.EventIdx{Height: 3, Offset: 0})
+	require.Equal(t, int64(3), p.NextHeight())
+	require.NoError(t, publish(t, p, 3))
+	require.NoError(t, p.Close())
+
+	require.Equal(t, []kafka.EventIdx{{Height: 3, Offset: 1}, {Height: 3, Offset: 2}},
+		k.events(t))
+}
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"gitlab.com/thorchain/midgard/internal/db"
+	"gitlab.com/thorchain/midgard/internal/util/midlog"
+)
+
+// Tables of the midgard schema which are not exported.
+var skippedCoreTables = map[string]bool{
+	"constants":         true,
+	"schema_migrations": true,
+	"writer_epoch":      true,
+}
+
+// Materialized tables of the watermarks of midgard_agg.watermarks which are not watermarked
+// materialized views. A new watermark has to be added here, otherwise the export fails.
+var watermarkTables = map[string]string{
+	"actions":          "actions",
+	"balances":         "balances",
+	"members":          "members_log",
+	"first_swaps":      "first_swaps",
+	"bond_totals":      "bond_totals",
+	"node_bond_totals": "node_bond_totals",
+}
+
+// Materialized table of the watermark.
+func watermarkTable(name string) (string, error) {
+	if table, ok := watermarkTables[name]; ok {
+		return table, nil
+	}
+	for _, table := range db.WatermarkedMaterializedTables() {
+		if table == "midgard_agg."+name+"_materialized" {
+			return name + "_materialized", nil
+		}
+	}
+	return "", fmt.Errorf("unknown table for the %s watermark", name)
+}
+
+// Within a block the members_log rows have to be loaded in the same order as they were
+// created, see midgard_agg.update_members_interval.
+var exportOrder = map[string]string{
+	"midgard_agg.members_log": "block_timestamp, change_type",
+}
+
+func exportSnapshot(ctx context.Context, conn *pgx.Conn, dir string, height int64) error {
+	statuses, err := db.MigrationStatuses(db.TheDB)
+	if err != nil {
+		return err
+	}
+	for _, status := range statuses {
+		if status.BaselineChanged || len(status.Pending) != 0 {
+			return fmt.Errorf("the %s schema is not up to date, run cmd/migrate first", status.Tag)
+		}
+	}
+	fingerprint, err := db.SchemaFingerprint()
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(filepath.Join(dir, manifestFile)); err == nil {
+		return fmt.Errorf("%s already contains a snapshot", dir)
+	}
+
+	// All the tables are read from the same database snapshot.
+	_, err = conn.Exec(ctx, "BEGIN ISOLATION LEVEL REPEATABLE READ READ ONLY")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_, _ = conn.Exec(ctx, "ROLLBACK")
+	}()
+
+	m := manifest{
+		Version:    manifestVersion,
+		CreatedAt:  time.Now().UTC().Format(time.RFC3339),
+		Schema:     fingerprint,
+		Watermarks: map[string]int64{},
+	}
+	q := "SELECT height, timestamp, encode(hash, 'hex') FROM block_log WHERE height = $1"
+	args := []interface{}{height}
+	if height < 0 {
+		q = "SELECT height, timestamp, encode(hash, 'hex') FROM block_log ORDER BY height DESC LIMIT 1"
+		args = nil
+	}
+	err = conn.QueryRow(ctx, q, args...).Scan(&m.Height, &m.Timestamp, &m.BlockHash)
+	if err == pgx.ErrNoRows {
+		return fmt.Errorf("block %d not found", height)
+	}
+	if err != nil {
+		return err
+	}
+	midlog.InfoF("Exporting snapshot at height %d", m.Height)
+
+	// Rows after the snapshot are not exported, so the watermarks can't be after it either.
+	cutoff := m.Timestamp + 1
+	rows, err := conn.Query(ctx, "SELECT materialized_table, watermark FROM midgard_agg.watermarks")
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var name string
+		var watermark int64
+		err = rows.Scan(&name, &watermark)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		if cutoff < watermark {
+			watermark = cutoff
+		}
+		m.Watermarks[name] = watermark
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	coreTables, err := listTables(ctx, conn)
+	if err != nil {
+		return err
+	}
+	for _, table := range coreTables {
+		filter := fmt.Sprintf("block_timestamp <= %d", m.Timestamp)
+		if table == "block_log" {
+			filter = fmt.Sprintf("height <= %d", m.Height)
+		}
+		tf, err := exportTable(ctx, conn, dir, "midgard", table, filter)
+		if err != nil {
+			return err
+		}
+		m.Tables = append(m.Tables, tf)
+	}
+
+	watermarkNames := make([]string, 0, len(m.Watermarks))
+	for name := range m.Watermarks {
+		watermarkNames = append(watermarkNames, name)
+	}
+	sort.Strings(watermarkNames)
+	for _, name := range watermarkNames {
+		table, err := watermarkTable(name)
+		if err != nil {
+			return err
+		}
+		filter := fmt.Sprintf("block_timestamp < %d", m.Watermarks[name])
+		tf, err := exportTable(ctx, conn, dir, "midgard_agg", table, filter)
+		if err != nil {
+			return err
+		}
+		m.Tables = append(m.Tables, tf)
+	}
+
+	err = writeManifest(dir, m)
+	if err != nil {
+		return err
+	}
+	midlog.InfoF("Exported %d tables into %s", len(m.Tables), dir)
+	return nil
+}
+
+// Returns the tables of the midgard schema which have a block_timestamp column (and block_log).
+func listTables(ctx context.Context, conn *pgx.Conn) (ret []string, err error) {
+	rows, err := conn.Query(ctx, `
+		SELECT t.table_name, bool_or(c.column_name = 'block_timestamp')
+		FROM information_schema.tables t
+		JOIN information_schema.columns c USING (table_schema, table_name)
+		WHERE t.table_schema = 'midgard' AND t.table_type = 'BASE TABLE'
+		GROUP BY t.table_name
+		ORDER BY t.table_name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var table string
+		var hasTimestamp bool
+		err = rows.Scan(&table, &hasTimestamp)
+		if err != nil {
+			return nil, err
+		}
+		if skippedCoreTables[table] {
+			continue
+		}
+		if !hasTimestamp && table != "block_log" {
+			midlog.WarnF("Skipping table %s without block_timestamp", table)
+			continue
+		}
+		ret = append(ret, table)
+	}
+	return ret, rows.Err()
+}
+
+func exportTable(ctx context.Context, conn *pgx.Conn, dir, schema, table, filter string) (
+	tf tableFile, err error,
+) {
+	tf = tableFile{
+		Schema: schema,
+		Table:  table,
+		File:   schema + "." + table + ".csv.gz",
+	}
+	path := filepath.Join(dir, tf.File)
+	f, err := os.Create(path)
+	if err != nil {
+		return tf, err
+	}
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+
+	q := "SELECT * FROM " + tableIdentifier(schema, table) + " WHERE " + filter
+	if order, ok := exportOrder[schema+"."+table]; ok {
+		q += " ORDER BY " + order
+	}
+	tag, err := conn.PgConn().CopyTo(ctx, gz, "COPY ("+q+") TO STDOUT WITH (FORMAT csv)")
+	if err != nil {
+		return tf, fmt.Errorf("exporting %s.%s: %w", schema, table, err)
+	}
+	err = gz.Close()
+	if err != nil {
+		return tf, err
+	}
+	err = f.Close()
+	if err != nil {
+		return tf, err
+	}
+
+	tf.Rows = tag.RowsAffected()
+	tf.Sha256, err = fileSha256(path)
+	if err != nil {
+		return tf, err
+	}
+	midlog.InfoF("Exported %s.%s: %d rows", schema, table, tf.Rows)
+	return tf, nil
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jackc/pgx/v4"
+	"gitlab.com/thorchain/midgard/internal/util/midlog"
+)
+
+func importSnapshot(ctx context.Context, conn *pgx.Conn, dir string) error {
+	m, err := readManifest(dir)
+	if err != nil {
+		return err
+	}
+	err = checkSchema(m)
+	if err != nil {
+		return err
+	}
+	err = checkEmpty(ctx)
+	if err != nil {
+		return err
+	}
+
+	midlog.Info("Verifying checksums")
+	for _, tf := range m.Tables {
+		sum, err := fileSha256(filepath.Join(dir, tf.File))
+		if err != nil {
+			return err
+		}
+		if sum != tf.Sha256 {
+			return fmt.Errorf("checksum mismatch for %s, the snapshot is corrupted", tf.File)
+		}
+	}
+
+	midlog.InfoF("Importing snapshot at height %d", m.Height)
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	// members_log is loaded through its trigger, which recreates the members table.
+	for _, tf := range m.Tables {
+		err = importTable(ctx, tx, dir, tf)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO midgard_agg.current_balances (
+			SELECT DISTINCT ON (addr, asset) addr, asset, amount_e8
+			FROM midgard_agg.balances
+			ORDER BY addr, asset, block_timestamp DESC
+		)`)
+	if err != nil {
+		return fmt.Errorf("rebuilding current balances: %w", err)
+	}
+
+	for name, watermark := range m.Watermarks {
+		_, err = tx.Exec(ctx,
+			"UPDATE midgard_agg.watermarks SET watermark = $1 WHERE materialized_table = $2",
+			watermark, name)
+		if err != nil {
+			return fmt.Errorf("setting watermark of %s: %w", name, err)
+		}
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+	midlog.InfoF("Imported snapshot, Midgard will continue syncing from height %d", m.Height+1)
+	return nil
+}
+
+func importTable(ctx context.Context, tx pgx.Tx, dir string, tf tableFile) error {
+	f, err := os.Open(filepath.Join(dir, tf.File))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", tf.File, err)
+	}
+	defer gz.Close()
+
+	tag, err := tx.Conn().PgConn().CopyFrom(ctx, gz,
+		"COPY "+tableIdentifier(tf.Schema, tf.Table)+" FROM STDIN WITH (FORMAT csv)")
+	if err != nil {
+		return fmt.Errorf("importing %s.%s: %w", tf.Schema, tf.Table, err)
+	}
+	if tag.RowsAffected() != tf.Rows {
+		return fmt.Errorf("importing %s.%s: expected %d rows, got %d",
+			tf.Schema, tf.Table, tf.Rows, tag.RowsAffected())
+	}
+	midlog.InfoF("Imported %s.%s: %d rows", tf.Schema, tf.Table, tf.Rows)
+	return nil
+}
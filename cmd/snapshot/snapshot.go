@@ -0,0 +1,204 @@
+package main
+
+// Exports the database at a given height into a directory and loads it into an empty database,
+// so a new instance doesn't need to replay all the blocks. After an import Midgard continues
+// syncing from the height of the snapshot.
+//
+// Usage:
+//   $ snapshot config export DIR [HEIGHT]
+//   $ snapshot config import DIR
+//
+// If HEIGHT is not given the last block is exported.
+//
+// Exported: the event tables and block_log (with the aggregation state) up to the height, the
+// materialized aggregate tables (actions, balances, members_log, first_swaps, bond_totals,
+// node_bond_totals, watermarked views) and their watermarks. Not exported, rebuilt on import: members and
+// current_balances. The TimescaleDB continuous aggregates are refreshed by Midgard after the
+// import.
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/stdlib"
+	"gitlab.com/thorchain/midgard/config"
+	"gitlab.com/thorchain/midgard/internal/db"
+	"gitlab.com/thorchain/midgard/internal/db/dbinit"
+	"gitlab.com/thorchain/midgard/internal/util/midlog"
+
+	_ "gitlab.com/thorchain/midgard/internal/globalinit"
+)
+
+const (
+	manifestFile    = "manifest.json"
+	manifestVersion = 1
+)
+
+type tableFile struct {
+	Schema string `json:"schema"`
+	Table  string `json:"table"`
+	File   string `json:"file"`
+	Rows   int64  `json:"rows"`
+	// Hex sha256 of the compressed file.
+	Sha256 string `json:"sha256"`
+}
+
+type manifest struct {
+	Version   int    `json:"version"`
+	Height    int64  `json:"height"`
+	Timestamp int64  `json:"timestamp"`
+	BlockHash string `json:"block_hash"`
+	CreatedAt string `json:"created_at"`
+	// db.SchemaFingerprint of the exporting Midgard, has to match on import.
+	Schema     map[string]string `json:"schema"`
+	Watermarks map[string]int64  `json:"watermarks"`
+	Tables     []tableFile       `json:"tables"`
+}
+
+func main() {
+	midlog.LogCommandLine()
+
+	if len(os.Args) < 4 {
+		midlog.FatalF("Not enough arguments\n" +
+			"Usage: $ snapshot config export DIR [HEIGHT]\n" +
+			"       $ snapshot config import DIR")
+	}
+	config.ReadGlobalFrom(os.Args[1])
+	dir := os.Args[3]
+	ctx := context.Background()
+
+	switch os.Args[2] {
+	case "export":
+		height := int64(-1)
+		if len(os.Args) == 5 {
+			var err error
+			height, err = strconv.ParseInt(os.Args[4], 10, 64)
+			if err != nil {
+				midlog.FatalF("Couldn't parse height: %s", os.Args[4])
+			}
+		}
+		db.SetupWithoutUpdate()
+		err := withConn(ctx, func(conn *pgx.Conn) error {
+			return exportSnapshot(ctx, conn, dir, height)
+		})
+		if err != nil {
+			midlog.FatalE(err, "Export failed")
+		}
+	case "import":
+		midlog.Warn("If Midgard is running, stop it and rerun this tool!")
+		dbinit.Setup()
+		err := withConn(ctx, func(conn *pgx.Conn) error {
+			return importSnapshot(ctx, conn, dir)
+		})
+		if err != nil {
+			midlog.FatalE(err, "Import failed")
+		}
+	default:
+		midlog.FatalF("Unknown command: %s, should be export or import", os.Args[2])
+	}
+}
+
+// Runs f with the pgx connection underlying a database/sql connection, COPY needs the native
+// connection.
+func withConn(ctx context.Context, f func(conn *pgx.Conn) error) error {
+	sqlConn, err := db.TheDB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer sqlConn.Close()
+	return sqlConn.Raw(func(driverConn interface{}) error {
+		conn, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return fmt.Errorf("unexpected driver connection: %T", driverConn)
+		}
+		return f(conn.Conn())
+	})
+}
+
+func readManifest(dir string) (m manifest, err error) {
+	data, err := os.ReadFile(filepath.Join(dir, manifestFile))
+	if err != nil {
+		return m, err
+	}
+	err = json.Unmarshal(data, &m)
+	if err != nil {
+		return m, fmt.Errorf("malformed manifest: %w", err)
+	}
+	if m.Version != manifestVersion {
+		return m, fmt.Errorf("unsupported manifest version %d", m.Version)
+	}
+	return m, nil
+}
+
+func writeManifest(dir string, m manifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	// Written last and renamed, a directory without a manifest is an incomplete export.
+	tmp := filepath.Join(dir, manifestFile+".tmp")
+	err = os.WriteFile(tmp, data, 0o644)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(dir, manifestFile))
+}
+
+func fileSha256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	_, err = io.Copy(h, f)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func checkSchema(m manifest) error {
+	fingerprint, err := db.SchemaFingerprint()
+	if err != nil {
+		return err
+	}
+	for tag, value := range fingerprint {
+		if m.Schema[tag] != value {
+			return fmt.Errorf(
+				"snapshot was made with a different %s schema (%s), this Midgard has %s",
+				tag, m.Schema[tag], value)
+		}
+	}
+	return nil
+}
+
+func tableIdentifier(schema, table string) string {
+	return pgx.Identifier{schema, table}.Sanitize()
+}
+
+var errNotEmpty = errors.New(
+	"the database already contains blocks, import only works into an empty database " +
+		"(use cmd/nukedb to reset it)")
+
+func checkEmpty(ctx context.Context) error {
+	var height int64
+	err := db.TheDB.QueryRowContext(ctx, "SELECT height FROM block_log LIMIT 1").Scan(&height)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return errNotEmpty
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/require"
+	"gitlab.com/thorchain/midgard/internal/db"
+	"gitlab.com/thorchain/midgard/internal/db/testdb"
+)
+
+func watermarks(t *testing.T) map[string]int64 {
+	rows, err := db.TheDB.Query("SELECT materialized_table, watermark FROM midgard_agg.watermarks")
+	require.NoError(t, err)
+	defer rows.Close()
+	ret := map[string]int64{}
+	for rows.Next() {
+		var name string
+		var watermark int64
+		require.NoError(t, rows.Scan(&name, &watermark))
+		ret[name] = watermark
+	}
+	require.NoError(t, rows.Err())
+	return ret
+}
+
+func TestWatermarkTables(t *testing.T) {
+	testdb.InitTest(t)
+	for name := range watermarks(t) {
+		_, err := watermarkTable(name)
+		require.NoError(t, err)
+	}
+	_, err := watermarkTable("missing")
+	require.Error(t, err)
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	testdb.InitTest(t)
+	ctx := context.Background()
+
+	testdb.InsertBlockLog(t, 1, "2020-09-01 00:00:00")
+	testdb.InsertBlockLog(t, 2, "2020-09-01 00:10:00")
+	timestamp := testdb.StrToNano("2020-09-01 00:10:00")
+	testdb.MustExec(t, `INSERT INTO midgard_agg.first_swaps (from_addr, block_timestamp)
+		VALUES ('thoraddr1', $1)`, testdb.StrToNano("2020-09-01 00:00:00"))
+	testdb.MustExec(t, "UPDATE midgard_agg.watermarks SET watermark = $1", timestamp)
+	exported := watermarks(t)
+
+	dir := t.TempDir()
+	require.NoError(t, withConn(ctx, func(conn *pgx.Conn) error {
+		return exportSnapshot(ctx, conn, dir, 2)
+	}))
+	m, err := readManifest(dir)
+	require.NoError(t, err)
+	require.Equal(t, exported, m.Watermarks)
+	tables := map[string]bool{}
+	for _, tf := range m.Tables {
+		tables[tf.Schema+"."+tf.Table] = true
+	}
+	for name := range exported {
+		table, err := watermarkTable(name)
+		require.NoError(t, err)
+		require.True(t, tables["midgard_agg."+table], "%s is not exported", table)
+	}
+
+	testdb.DeleteTables(t)
+	for _, tf := range m.Tables {
+		testdb.MustExec(t, "DELETE FROM "+tableIdentifier(tf.Schema, tf.Table))
+	}
+	require.NoError(t, withConn(ctx, func(conn *pgx.Conn) error {
+		return importSnapshot(ctx, conn, dir)
+	}))
+
+	require.Equal(t, exported, watermarks(t))
+	var firstSwap int64
+	require.NoError(t, db.TheDB.QueryRow(
+		"SELECT block_timestamp FROM midgard_agg.first_swaps WHERE from_addr = 'thoraddr1'",
+	).Scan(&firstSwap))
+	require.Equal(t, int64(testdb.StrToNano("2020-09-01 00:00:00")), firstSwap)
+}
@@ -43,8 +43,12 @@ type Config struct {
 
 	Websockets Websockets `json:"websockets" split_words:"true"`
 
+	Webhooks Webhooks `json:"webhooks"`
+
 	UsdPools []string `json:"usdpools" split_words:"true"`
 
+	UsdPrice UsdPrice `json:"usd_price" split_words:"true"`
+
 	EventRecorder EventRecorder `json:"event_recorder" split_words:"true"`
 
 	CaseInsensitiveChains map[string]bool `json:"case_insensitive_chains" split_words:"true"`
@@ -52,6 +56,14 @@ type Config struct {
 	Logs midlog.LogConfig `json:"logs" split_words:"true"`
 
 	Kafka Kafka `json:"kafka"`
+
+	Retention Retention `json:"retention" split_words:"true"`
+
+	WriterLock WriterLock `json:"writer_lock" split_words:"true"`
+
+	Admin Admin `json:"admin"`
+
+	Health Health `json:"health"`
 }
 
 type Kafka struct {
@@ -59,6 +71,50 @@ type Kafka struct {
 	BlockTopic     string   `json:"block_topic" split_words:"true"`
 	PoolTopic      string   `json:"pool_topic" split_words:"true"`
 	PoolStatsTopic string   `json:"pool_stats_topic" split_words:"true"`
+
+	Producer Producer `json:"producer"`
+
+	Sink KafkaSink `json:"sink"`
+
+	LPConsumer LPConsumer `json:"lp_consumer" split_words:"true"`
+}
+
+// Settings of the producer command.
+type Producer struct {
+	// Transactional id of the producer. Only one producer may run with an id, a new one fences
+	// off the old one.
+	TransactionalID string `json:"transactional_id" split_words:"true"`
+	// Compacted topic with the last published block, it's written in the transaction of the
+	// block. Defaults to the block topic with a "-checkpoint" suffix.
+	CheckpointTopic string `json:"checkpoint_topic" split_words:"true"`
+}
+
+// The Midgard writer publishes the events it recorded and the pool depths of every committed
+// block. Uses the Brokers above.
+type KafkaSink struct {
+	Enabled bool `json:"enabled"`
+	// Recorded events, after the corrections, in the parsed event schema.
+	EventTopic string `json:"event_topic" split_words:"true"`
+	// Pool depths and depth changes of the blocks which changed a pool.
+	DepthTopic string `json:"depth_topic" split_words:"true"`
+	// Compacted topic with the last outbox row published. Defaults to the event topic with a
+	// "-checkpoint" suffix.
+	CheckpointTopic string `json:"checkpoint_topic" split_words:"true"`
+	TransactionalID string `json:"transactional_id" split_words:"true"`
+	// How often the outbox is checked for new messages when it was empty.
+	PollInterval Duration `json:"poll_interval" split_words:"true"`
+	// Messages published in one transaction at most.
+	BatchSize int `json:"batch_size" split_words:"true"`
+}
+
+// Settings of the lp_consumer command, it uses the TimeScale settings for the database.
+type LPConsumer struct {
+	// Consumer group names are prefixed with this, to run several instances on the same brokers.
+	GroupPrefix string `json:"group_prefix" split_words:"true"`
+	// Port of the /metrics endpoint, disabled when 0.
+	MetricsPort int `json:"metrics_port" split_words:"true"`
+	// How often the consumer group lag is read from the brokers.
+	LagInterval Duration `json:"lag_interval" split_words:"true"`
 }
 
 type BlockStore struct {
@@ -73,6 +129,9 @@ type BlockStore struct {
 type EventRecorder struct {
 	OnTransferEnabled bool `json:"on_transfer_enabled" split_words:"true"`
 	OnMessageEnabled  bool `json:"on_message_enabled" split_words:"true"`
+	// Number of blocks parsed in parallel ahead of the block writer.
+	// The events are recorded sequentially regardless.
+	ParseParallelism int `json:"parse_parallelism" split_words:"true"`
 }
 
 type ThorChain struct {
@@ -82,6 +141,19 @@ type ThorChain struct {
 	FetchBatchSize              int      `json:"fetch_batch_size" split_words:"true"`
 	Parallelism                 int      `json:"parallelism" split_words:"true"`
 
+	// Endpoints of other nodes used together with TendermintURL and ThorNodeURL.
+	// Requests fail over between the endpoints, parallel block fetches are spread over them and
+	// the hashes of the fetched blocks are cross-checked between them.
+	ExtraTendermintURLs []string `json:"extra_tendermint_urls" split_words:"true"`
+	ExtraThorNodeURLs   []string `json:"extra_thornode_urls" split_words:"true"`
+	// An endpoint is skipped for EndpointRetryAfter after this many consecutive failures.
+	EndpointMaxFailures int      `json:"endpoint_max_failures" split_words:"true"`
+	EndpointRetryAfter  Duration `json:"endpoint_retry_after" split_words:"true"`
+
+	// When in sync, new blocks are fetched as soon as the Tendermint websocket notifies about
+	// them. With this set, or while the websocket is not available, ThorNode is polled.
+	NoNewBlockSubscription bool `json:"no_new_block_subscription" split_words:"true"`
+
 	// Timeout for fetch requests to ThorNode
 	ReadTimeout Duration `json:"read_timeout" split_words:"true"`
 	// If fetch from ThorNode fails, wait this much before retrying
@@ -108,12 +180,22 @@ type ThorChain struct {
 //
 // When a fork is coming up it's useful to prevent Midgard from writing out data from the old chain
 // beyond the fork height.
+//
+// If a child of the current chain is defined, Midgard switches to it at the fork: it waits until
+// the child chain is served on its endpoints, checks `EarliestBlockHash` and continues writing.
+// The endpoints and the blockstore of the child chain default to the ThorChain and BlockStore
+// config.
 type ForkInfo struct {
 	ChainId             string `json:"chain_id" split_words:"true"`
 	ParentChainId       string `json:"parent_chain_id" split_words:"true"`
 	EarliestBlockHash   string `json:"earliest_block_hash" split_words:"true"`
 	EarliestBlockHeight int64  `json:"earliest_block_height" split_words:"true"`
 	HardForkHeight      int64  `json:"hard_fork_height" split_words:"true"`
+
+	TendermintURL    string `json:"tendermint_url" split_words:"true"`
+	ThorNodeURL      string `json:"thornode_url" split_words:"true"`
+	BlockStoreLocal  string `json:"blockstore_local" split_words:"true"`
+	BlockStoreRemote string `json:"blockstore_remote" split_words:"true"`
 }
 
 type TimeScale struct {
@@ -128,16 +210,124 @@ type TimeScale struct {
 	MaxOpenConns    int `json:"max_open_conns"`
 	CommitBatchSize int `json:"commit_batch_size"`
 
-	// If DDL mismatch is detected exit with error instead of resetting the schema
+	// If DDL mismatch is detected exit with error instead of resetting the schema.
+	// Pending migrations which don't need a reset are applied regardless.
 	NoAutoUpdateDDL bool `json:"no_auto_update_ddl"`
 	// If DDL mismatch for aggregates is detected exit with error instead of resetting
 	// the aggregates. Implies `NoAutoUpdateDDL`
 	NoAutoUpdateAggregatesDDL bool `json:"no_auto_update_aggregates_ddl"`
 }
 
+// UsdPrice configures how the RUNE/USD price is derived from the `UsdPools`.
+type UsdPrice struct {
+	// Aggregation method, one of:
+	//   "deepest" (default): price of the single deepest pool
+	//   "median": rune depth weighted median of the pool prices
+	//   "mean": rune depth weighted mean of the pool prices
+	Method string `json:"method" split_words:"true"`
+	// Pools with less rune depth (in e8) than this are not considered.
+	MinRuneDepth int64 `json:"min_rune_depth" split_words:"true"`
+	// Pools whose price deviates from the weighted median by more than this ratio
+	// (e.g. 0.05 for 5%) are rejected as outliers. 0 disables outlier rejection.
+	MaxDeviation float64 `json:"max_deviation" split_words:"true"`
+}
+
+// Retention configures the "lite" mode, where only recent raw events are kept.
+// The continuous aggregates, members, balances and the last state rows (e.g. pool status, mimir)
+// are kept regardless, see db/retention.go.
+type Retention struct {
+	// Raw event rows older than this many days are deleted. 0 keeps everything.
+	Days int `json:"days" split_words:"true"`
+	// How often the old rows are deleted.
+	PruneInterval Duration `json:"prune_interval" split_words:"true"`
+}
+
+// WriterLock configures the standby mode: multiple instances share a DB, the one holding a
+// Postgres advisory lock fetches and writes blocks and refreshes the aggregates, the others only
+// serve the API. When the writer goes away one of the others takes over.
+type WriterLock struct {
+	Enabled bool `json:"enabled" split_words:"true"`
+	// Advisory lock key, instances writing to the same DB have to use the same key.
+	Key int64 `json:"key" split_words:"true"`
+	// How often the standby instances try to take the lock and the writer checks that it
+	// still holds it.
+	CheckInterval Duration `json:"check_interval" split_words:"true"`
+}
+
+// Health configures the readiness checks of /v2/health/ready. Zero values disable the check.
+type Health struct {
+	// Blocks the fetching may be behind the chain tip.
+	ReadyMaxFetchLag int64 `json:"ready_max_fetch_lag" split_words:"true"`
+	// Age of the last committed block.
+	ReadyMaxCommitAge Duration `json:"ready_max_commit_age" split_words:"true"`
+	// Age of the last aggregated block.
+	ReadyMaxAggregatesLag Duration `json:"ready_max_aggregates_lag" split_words:"true"`
+	// Readiness requires the DB to answer within this time.
+	DBTimeout Duration `json:"db_timeout" split_words:"true"`
+	// Readiness requires a working ThorNode and Tendermint endpoint.
+	ReadyRequireThorNode bool `json:"ready_require_thor_node" split_words:"true"`
+}
+
+// Admin configures the /admin API for changing Midgard's behaviour at runtime.
+type Admin struct {
+	// Requests have to send "Authorization: Bearer <token>". The API is disabled when empty.
+	Token string `json:"token"`
+}
+
 type Websockets struct {
 	Enable          bool `json:"enable" split_words:"true"`
 	ConnectionLimit int  `json:"connection_limit" split_words:"true"`
+	// Connections from one remote address at most, no limit when 0.
+	PerIPLimit int `json:"per_ip_limit" split_words:"true"`
+	// Header the remote address is read from when Midgard runs behind a proxy, e.g.
+	// X-Forwarded-For or X-Real-IP. The last address of the header is used, the one added by the
+	// proxy. When empty the address of the connection is used, behind a proxy all clients share
+	// it and per_ip_limit applies to all of them together.
+	RemoteIPHeader string `json:"remote_ip_header" split_words:"true"`
+	// Messages waiting to be written to a connection at most, clients which don't read fast
+	// enough are disconnected.
+	SendQueueSize int      `json:"send_queue_size" split_words:"true"`
+	WriteTimeout  Duration `json:"write_timeout" split_words:"true"`
+	// Protocol v2 heartbeats, disabled when 0.
+	HeartbeatInterval Duration `json:"heartbeat_interval" split_words:"true"`
+	// Updates of the last blocks kept in memory for v2 clients resuming after a reconnect.
+	ResumeBlocks int `json:"resume_blocks" split_words:"true"`
+}
+
+// Webhooks are POSTed the actions and pool status changes of the new blocks which match their
+// filter, after the aggregates caught up with the blocks. More webhooks can be registered with
+// the admin API.
+type Webhooks struct {
+	Enabled bool      `json:"enabled"`
+	Hooks   []Webhook `json:"hooks"`
+	// Failed deliveries are retried with exponential backoff, after MaxAttempts they are moved
+	// to the webhook_dead_letters table.
+	MaxAttempts int      `json:"max_attempts" split_words:"true"`
+	MinBackoff  Duration `json:"min_backoff" split_words:"true"`
+	MaxBackoff  Duration `json:"max_backoff" split_words:"true"`
+	Timeout     Duration `json:"timeout"`
+}
+
+type Webhook struct {
+	ID  string `json:"id"`
+	URL string `json:"url"`
+	// Payloads are signed with HMAC-SHA256 of the secret, see the README.
+	Secret string        `json:"secret"`
+	Filter WebhookFilter `json:"filter"`
+}
+
+// Empty lists match everything.
+type WebhookFilter struct {
+	// Action and pool status change, "action" and "pool_status".
+	Events []string `json:"events"`
+	// Actions which involve one of the addresses.
+	Addresses []string `json:"addresses"`
+	// Actions and pool status changes of one of the pools.
+	Pools []string `json:"pools"`
+	// Action types, as in /v2/actions (swap, addLiquidity, withdraw, donate, refund, switch).
+	ActionTypes []string `json:"action_types"`
+	// Swaps with a smaller input value in RUNE are not sent.
+	MinSwapRuneE8 int64 `json:"min_swap_rune_e8"`
 }
 
 var defaultConfig = Config{
@@ -148,6 +338,9 @@ var defaultConfig = Config{
 		ReadTimeout:      Duration(8 * time.Second),
 		LastChainBackoff: Duration(7 * time.Second),
 
+		EndpointMaxFailures: 3,
+		EndpointRetryAfter:  Duration(30 * time.Second),
+
 		// NOTE(huginn): numbers are chosen to give a good performance on an "average" desktop
 		// machine with a 4 core CPU. With more cores it might make sense to increase the
 		// parallelism, though care should be taken to not overload the Thornode.
@@ -167,6 +360,47 @@ var defaultConfig = Config{
 	ReadTimeout:     Duration(20 * time.Second),
 	WriteTimeout:    Duration(20 * time.Second),
 	MaxBlockAge:     Duration(60 * time.Second),
+	Retention: Retention{
+		PruneInterval: Duration(time.Hour),
+	},
+	WriterLock: WriterLock{
+		Key:           0x4d494447, // "MIDG"
+		CheckInterval: Duration(5 * time.Second),
+	},
+	Websockets: Websockets{
+		SendQueueSize:     256,
+		WriteTimeout:      Duration(10 * time.Second),
+		HeartbeatInterval: Duration(15 * time.Second),
+		ResumeBlocks:      100,
+	},
+	Webhooks: Webhooks{
+		MaxAttempts: 10,
+		MinBackoff:  Duration(5 * time.Second),
+		MaxBackoff:  Duration(time.Hour),
+		Timeout:     Duration(10 * time.Second),
+	},
+	Kafka: Kafka{
+		Producer: Producer{
+			TransactionalID: "midgard-producer",
+		},
+		Sink: KafkaSink{
+			EventTopic:      "midgard-events",
+			DepthTopic:      "midgard-pool-depths",
+			TransactionalID: "midgard-sink",
+			PollInterval:    Duration(time.Second),
+			BatchSize:       1000,
+		},
+		LPConsumer: LPConsumer{
+			MetricsPort: 8081,
+			LagInterval: Duration(15 * time.Second),
+		},
+	},
+	Health: Health{
+		ReadyMaxFetchLag:      10,
+		ReadyMaxCommitAge:     Duration(60 * time.Second),
+		ReadyMaxAggregatesLag: Duration(60 * time.Second),
+		DBTimeout:             Duration(2 * time.Second),
+	},
 	UsdPools: []string{
 		"BNB.BUSD-BD1",
 		"ETH.USDT-0XDAC17F958D2EE523A2206206994597C13D831EC7",
@@ -178,6 +412,7 @@ var defaultConfig = Config{
 	EventRecorder: EventRecorder{
 		OnTransferEnabled: false,
 		OnMessageEnabled:  false,
+		ParseParallelism:  4,
 	},
 }
 
@@ -268,6 +503,12 @@ func LogAndcheckUrls(c *Config) {
 		{c.ThorChain.TendermintURL, "Tendermint RPC URL"},
 		{c.BlockStore.Remote, "BlockStore Remote URL"},
 	}
+	for _, u := range c.ThorChain.ExtraThorNodeURLs {
+		urls = append(urls, struct{ url, name string }{u, "Extra THORNode REST URL"})
+	}
+	for _, u := range c.ThorChain.ExtraTendermintURLs {
+		urls = append(urls, struct{ url, name string }{u, "Extra Tendermint RPC URL"})
+	}
 	for _, v := range urls {
 		logger.InfoF("%s: %q", v.name, v.url)
 		if _, err := url.Parse(v.url); err != nil {
@@ -276,6 +517,17 @@ func LogAndcheckUrls(c *Config) {
 	}
 }
 
+func checkUsdPrice(c *Config) {
+	switch c.UsdPrice.Method {
+	case "", "deepest", "median", "mean":
+	default:
+		logger.FatalF("Exit on unknown usd_price method %q", c.UsdPrice.Method)
+	}
+	if c.UsdPrice.MaxDeviation < 0 {
+		logger.Fatal("Exit on negative usd_price max_deviation")
+	}
+}
+
 // Not thread safe, it is written once, then only read
 var Global Config = defaultConfig
 
@@ -290,6 +542,7 @@ func readConfigFrom(filenames string) Config {
 	}
 
 	LogAndcheckUrls(&ret)
+	checkUsdPrice(&ret)
 
 	setDefaultCacheLifetime(&ret)
 	return ret
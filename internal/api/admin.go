@@ -0,0 +1,274 @@
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/rs/zerolog"
+	"gitlab.com/thorchain/midgard/config"
+	"gitlab.com/thorchain/midgard/internal/db"
+	"gitlab.com/thorchain/midgard/internal/util/jobs"
+	"gitlab.com/thorchain/midgard/internal/util/miderr"
+	"gitlab.com/thorchain/midgard/internal/util/midlog"
+	"gitlab.com/thorchain/midgard/internal/webhooks"
+)
+
+var adminLogger = midlog.LoggerForModule("admin")
+
+// The admin API changes Midgard's behaviour at runtime, it's only registered when a token is
+// configured.
+func addAdmin(router *httprouter.Router, token string) {
+	handle := func(method, url string, handler httprouter.Handle) {
+		router.Handle(method, url, adminAuth(token, handler))
+	}
+	handle(http.MethodGet, "/admin/log_levels", adminLogLevels)
+	handle(http.MethodPost, "/admin/log_levels", adminSetLogLevel)
+	handle(http.MethodPost, "/admin/aggregates/refresh", adminRefreshAggregates)
+	handle(http.MethodGet, "/admin/caches", adminCaches)
+	handle(http.MethodPost, "/admin/caches/refresh", adminRefreshCaches)
+	handle(http.MethodPost, "/admin/caches/flush", adminFlushCaches)
+	handle(http.MethodGet, "/admin/jobs", adminJobs)
+	handle(http.MethodGet, "/admin/ingestion", adminIngestion)
+	handle(http.MethodPost, "/admin/ingestion/pause", adminSetIngestionPaused(true))
+	handle(http.MethodPost, "/admin/ingestion/resume", adminSetIngestionPaused(false))
+	handle(http.MethodGet, "/admin/disabled_endpoints", adminDisabledEndpoints)
+	handle(http.MethodPost, "/admin/disabled_endpoints", adminSetEndpointDisabled)
+	handle(http.MethodGet, "/admin/webhooks", adminWebhooks)
+	handle(http.MethodPost, "/admin/webhooks", adminRegisterWebhook)
+	handle(http.MethodDelete, "/admin/webhooks/:id", adminRemoveWebhook)
+	handle(http.MethodGet, "/admin/webhooks/:id/dead_letters", adminWebhookDeadLetters)
+	handle(http.MethodPost, "/admin/webhooks/:id/dead_letters/retry", adminRetryWebhookDeadLetters)
+}
+
+func adminAuth(token string, handler httprouter.Handle) httprouter.Handle {
+	expected := []byte("Bearer " + token)
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		got := []byte(r.Header.Get("Authorization"))
+		if subtle.ConstantTimeCompare(got, expected) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		adminLogger.InfoT(
+			midlog.Tags(midlog.Str("method", r.Method), midlog.Str("path", r.URL.Path)),
+			"Admin request")
+		handler(w, r, ps)
+	}
+}
+
+func readAdminBody(r *http.Request, body interface{}) miderr.Err {
+	err := json.NewDecoder(r.Body).Decode(body)
+	if err != nil {
+		return miderr.BadRequestF("Invalid request body: %v", err)
+	}
+	return nil
+}
+
+type logLevelsResponse struct {
+	Default midlog.Level            `json:"default"`
+	Modules map[string]midlog.Level `json:"modules"`
+}
+
+func adminLogLevels(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	defaultLevel, levels := midlog.Levels()
+	ret := logLevelsResponse{
+		Default: midlog.Level(defaultLevel),
+		Modules: map[string]midlog.Level{},
+	}
+	for module, level := range levels {
+		ret.Modules[module] = midlog.Level(level)
+	}
+	respJSON(w, ret)
+}
+
+// Without a module the default level is set. Without a level the module goes back to the
+// default level.
+type setLogLevelRequest struct {
+	Module string        `json:"module"`
+	Level  *midlog.Level `json:"level"`
+}
+
+func adminSetLogLevel(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	var req setLogLevelRequest
+	merr := readAdminBody(r, &req)
+	if merr != nil {
+		merr.ReportHTTP(w)
+		return
+	}
+	switch {
+	case req.Module == "" && req.Level == nil:
+		miderr.BadRequestF("Either module or level has to be given").ReportHTTP(w)
+		return
+	case req.Module == "":
+		midlog.SetLevel(zerolog.Level(*req.Level))
+	case req.Level == nil:
+		midlog.ResetModuleLevel(req.Module)
+	default:
+		midlog.SetModuleLevel(req.Module, zerolog.Level(*req.Level))
+	}
+	adminLogLevels(w, r, ps)
+}
+
+func adminRefreshAggregates(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	db.RequestAggregatesRefresh()
+	w.WriteHeader(http.StatusAccepted)
+}
+
+type cachesResponse struct {
+	Background []CacheStatus    `json:"background"`
+	Responses  []ApiCacheStatus `json:"responses"`
+}
+
+func adminCaches(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	respJSON(w, cachesResponse{
+		Background: GlobalCacheStore.Status(),
+		Responses:  GlobalApiCacheStore.Status(),
+	})
+}
+
+// Refreshes the background cache given by the name parameter, or all of them.
+// Refreshing may take minutes, so it's done in the background.
+func adminRefreshCaches(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		go GlobalCacheStore.RefreshAll(context.Background())
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	found := false
+	for _, status := range GlobalCacheStore.Status() {
+		if status.Name == name {
+			found = true
+		}
+	}
+	if !found {
+		miderr.BadRequestF("Unknown cache: %s", name).ReportHTTP(w)
+		return
+	}
+	go GlobalCacheStore.RefreshByName(context.Background(), name)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func adminFlushCaches(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	GlobalApiCacheStore.Flush()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func adminJobs(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	respJSON(w, jobs.Running())
+}
+
+type ingestionResponse struct {
+	Paused            bool  `json:"paused"`
+	LastFetchedHeight int64 `json:"lastFetchedHeight"`
+	LastCommitted     int64 `json:"lastCommittedHeight"`
+}
+
+func adminIngestion(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	respJSON(w, ingestionResponse{
+		Paused:            db.IngestionPaused(),
+		LastFetchedHeight: db.LastFetchedBlock.Get().Height,
+		LastCommitted:     db.LastCommittedBlock.Get().Height,
+	})
+}
+
+func adminSetIngestionPaused(paused bool) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		db.SetIngestionPaused(paused)
+		adminIngestion(w, r, ps)
+	}
+}
+
+func adminDisabledEndpoints(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	respJSON(w, disabledEndpoints())
+}
+
+type setEndpointDisabledRequest struct {
+	// As registered in the router, e.g. "/v2/pool/:pool".
+	Endpoint string `json:"endpoint"`
+	Disabled bool   `json:"disabled"`
+}
+
+func adminSetEndpointDisabled(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	var req setEndpointDisabledRequest
+	merr := readAdminBody(r, &req)
+	if merr != nil {
+		merr.ReportHTTP(w)
+		return
+	}
+	if strings.HasPrefix(req.Endpoint, "/admin/") {
+		miderr.BadRequestF("Admin endpoints can't be disabled").ReportHTTP(w)
+		return
+	}
+	if !setEndpointDisabled(req.Endpoint, req.Disabled) {
+		miderr.BadRequestF("Unknown endpoint: %s", req.Endpoint).ReportHTTP(w)
+		return
+	}
+	adminDisabledEndpoints(w, r, ps)
+}
+
+func adminWebhooks(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	hooks, err := webhooks.List(r.Context())
+	if err != nil {
+		respError(w, err)
+		return
+	}
+	respJSON(w, hooks)
+}
+
+// Webhooks registered with the admin API are stored in the DB, they are sent the events while
+// webhooks.enabled is set in the config.
+func adminRegisterWebhook(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	var req config.Webhook
+	merr := readAdminBody(r, &req)
+	if merr != nil {
+		merr.ReportHTTP(w)
+		return
+	}
+	merr = webhooks.Register(r.Context(), req)
+	if merr != nil {
+		merr.ReportHTTP(w)
+		return
+	}
+	adminWebhooks(w, r, ps)
+}
+
+func adminRemoveWebhook(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	found, merr := webhooks.Remove(r.Context(), ps.ByName("id"))
+	if merr != nil {
+		merr.ReportHTTP(w)
+		return
+	}
+	if !found {
+		http.Error(w, "Unknown webhook", http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func adminWebhookDeadLetters(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	deadLetters, err := webhooks.DeadLetters(r.Context(), ps.ByName("id"), 100)
+	if err != nil {
+		respError(w, err)
+		return
+	}
+	respJSON(w, deadLetters)
+}
+
+type retryDeadLettersResponse struct {
+	Queued int64 `json:"queued"`
+}
+
+func adminRetryWebhookDeadLetters(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	n, err := webhooks.RetryDeadLetters(r.Context(), ps.ByName("id"))
+	if err != nil {
+		respError(w, err)
+		return
+	}
+	respJSON(w, retryDeadLettersResponse{Queued: n})
+}
@@ -7,7 +7,9 @@ import (
 	"net/http/httputil"
 	"net/url"
 	"regexp"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"gitlab.com/thorchain/midgard/internal/util/midlog"
@@ -37,11 +39,51 @@ import (
 
 // Handler serves the entire API.
 var (
-	Handler           http.Handler
-	whiteListIPs      []string
-	disabledEndpoints []string
+	Handler      http.Handler
+	whiteListIPs []string
 )
 
+// Endpoints registered with addMeasured, they can be disabled at runtime through the admin API.
+var measuredEndpoints struct {
+	sync.RWMutex
+	all      []string
+	disabled map[string]bool
+}
+
+func endpointDisabled(url string) bool {
+	measuredEndpoints.RLock()
+	defer measuredEndpoints.RUnlock()
+	return measuredEndpoints.disabled[url]
+}
+
+// Returns false if url is not a measured endpoint.
+func setEndpointDisabled(url string, disabled bool) bool {
+	measuredEndpoints.Lock()
+	defer measuredEndpoints.Unlock()
+	for _, endpoint := range measuredEndpoints.all {
+		if endpoint == url {
+			if disabled {
+				measuredEndpoints.disabled[url] = true
+			} else {
+				delete(measuredEndpoints.disabled, url)
+			}
+			return true
+		}
+	}
+	return false
+}
+
+func disabledEndpoints() []string {
+	measuredEndpoints.RLock()
+	defer measuredEndpoints.RUnlock()
+	ret := make([]string, 0, len(measuredEndpoints.disabled))
+	for url := range measuredEndpoints.disabled {
+		ret = append(ret, url)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
 // RateLimit is a rate limiting middleware
 func LimitHandler(handler httprouter.Handle, lmt *limiter.Limiter) httprouter.Handle {
 	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
@@ -75,51 +117,43 @@ func addMeasured(router *httprouter.Router, url string, handler httprouter.Handl
 	}
 	simplifiedURL := reg.ReplaceAllString(url, "_")
 	t := timer.NewTimer("serving" + simplifiedURL)
-	for _, endpoint := range disabledEndpoints {
-		if url == endpoint {
-			router.Handle(
-				http.MethodGet, url, func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
-					w.WriteHeader(503)
-					_, err := w.Write([]byte("Service Unavailable"))
-					if err != nil {
-						log.Error().Interface("error", err).Str("path", r.URL.Path)
-					}
-				})
-			return
+
+	measuredEndpoints.Lock()
+	measuredEndpoints.all = append(measuredEndpoints.all, url)
+	measuredEndpoints.Unlock()
+
+	measured := func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		m := t.One()
+		if !strings.HasPrefix(r.URL.Path, "/v2/health") && config.Global.RedirectOnOutOfSync {
+			synced := db.FullyCaughtUp()
+			if !synced {
+				time.Sleep(5 * time.Second)
+				http.Redirect(w, r, r.URL.Path, http.StatusTemporaryRedirect)
+				return
+			}
 		}
+		handler(w, r, ps)
+		m()
 	}
 	if httpLimiter != nil {
-		router.Handle(
-			http.MethodGet, url,
-			LimitHandler(func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
-				m := t.One()
-				if r.RequestURI != "/v2/health" && config.Global.RedirectOnOutOfSync {
-					synced := db.FullyCaughtUp()
-					if !synced {
-						time.Sleep(5 * time.Second)
-						http.Redirect(w, r, r.URL.Path, http.StatusTemporaryRedirect)
-						return
-					}
-				}
-				handler(w, r, ps)
-				m()
-			}, httpLimiter))
-	} else {
-		router.Handle(
-			http.MethodGet, url, func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
-				m := t.One()
-				if r.RequestURI != "/v2/health" && config.Global.RedirectOnOutOfSync {
-					synced := db.FullyCaughtUp()
-					if !synced {
-						time.Sleep(5 * time.Second)
-						http.Redirect(w, r, r.URL.Path, http.StatusTemporaryRedirect)
-						return
-					}
-				}
-				handler(w, r, ps)
-				m()
-			})
+		measured = LimitHandler(measured, httpLimiter)
 	}
+	router.Handle(
+		http.MethodGet, url, func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+			recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			defer observeRequest(url, time.Now(), recorder)
+			w = recorder
+
+			if endpointDisabled(url) {
+				w.WriteHeader(503)
+				_, err := w.Write([]byte("Service Unavailable"))
+				if err != nil {
+					log.Error().Interface("error", err).Str("path", r.URL.Path)
+				}
+				return
+			}
+			measured(w, r, ps)
+		})
 }
 
 const proxiedPrefix = "/v2/thorchain/"
@@ -137,7 +171,13 @@ func InitHandler(nodeURL string, proxiedWhitelistedEndpoints []string, maxReqPer
 	ohlcvCount = ohlcvCnt
 
 	whiteListIPs = whiteList
-	disabledEndpoints = disabledUrls
+	measuredEndpoints.Lock()
+	measuredEndpoints.all = nil
+	measuredEndpoints.disabled = make(map[string]bool)
+	for _, url := range disabledUrls {
+		measuredEndpoints.disabled[url] = true
+	}
+	measuredEndpoints.Unlock()
 	router := httprouter.New()
 
 	Handler = loggerHandler(corsHandler(router))
@@ -147,11 +187,16 @@ func InitHandler(nodeURL string, proxiedWhitelistedEndpoints []string, maxReqPer
 	router.HandleOPTIONS = true
 	router.HandlerFunc(http.MethodGet, "/", serveRoot)
 
+	router.HandlerFunc(http.MethodGet, "/v2/metrics", serveMetrics)
 	router.HandlerFunc(http.MethodGet, "/v2/debug/metrics", metrics.ServeHTTP)
 	router.HandlerFunc(http.MethodGet, "/v2/debug/timers", timer.ServeHTTP)
 	router.HandlerFunc(http.MethodGet, "/v2/debug/usd", stat.ServeUSDDebug)
 	router.Handle(http.MethodGet, "/v2/debug/block/:id", debugBlock)
 
+	if config.Global.Admin.Token != "" {
+		addAdmin(router, config.Global.Admin.Token)
+	}
+
 	for _, endpoint := range proxiedWhitelistedEndpoints {
 		midgardPath := proxiedPrefix + endpoint
 		addMeasured(router, midgardPath, proxyHandler(nodeURL))
@@ -162,15 +207,29 @@ func InitHandler(nodeURL string, proxiedWhitelistedEndpoints []string, maxReqPer
 	// version 1
 	addMeasured(router, "/v2/actions", jsonActions)
 	addMeasured(router, "/v2/health", jsonHealth)
+	addMeasured(router, "/v2/health/live", jsonHealthLive)
+	addMeasured(router, "/v2/health/ready", jsonHealthReady)
 	addMeasured(router, "/v2/history/swaps", jsonSwapHistory)
 	addMeasured(router, "/v2/history/ts-swaps", jsonTsSwapHistory)
 	addMeasured(router, "/v2/history/depths/:pool", jsonDepths)
+	addMeasured(router, "/v2/history/depth_changes/:pool", jsonDepthChanges)
 	addMeasured(router, "/v2/history/ohlcv/:pool", jsonohlcv)
 	addMeasured(router, "/v2/history/earnings", jsonEarningsHistory)
 	addMeasured(router, "/v2/history/liquidity_changes", jsonLiquidityHistory)
 	addMeasured(router, "/v2/history/tvl", jsonTVLHistory)
+	addMeasured(router, "/v2/history/rune_price", jsonRunePriceHistory)
+	addMeasured(router, "/v2/history/affiliates", jsonAffiliateHistory)
+	addMeasured(router, "/v2/affiliates", jsonAffiliates)
+	addMeasured(router, "/v2/history/users", jsonUsersHistory)
+	addMeasured(router, "/v2/traders", jsonTraders)
+	addMeasured(router, "/v2/history/node/:addr", jsonNodeHistory)
 	addMeasured(router, "/v2/network", jsonNetwork)
 	addMeasured(router, "/v2/nodes", jsonNodes)
+	addMeasured(router, "/v2/node/:addr/history", jsonNodeTimeline)
+	addMeasured(router, "/v2/churns", jsonChurns)
+	addMeasured(router, "/v2/mimir", jsonMimir)
+	addMeasured(router, "/v2/mimir/:key/history", jsonMimirHistory)
+	addMeasured(router, "/v2/node_mimir", jsonNodeMimir)
 	addMeasured(router, "/v2/members", jsonMembers)
 	addMeasured(router, "/v2/member/:addr", jsonMemberDetails)
 	addMeasured(router, "/v2/full_member", jsonFullMemberDetails)
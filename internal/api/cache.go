@@ -31,8 +31,9 @@ const CacheRefreshSleepPerRoundDurringCatchup = time.Second * 60 * 10
 type RefreshFunc func(ctx context.Context, w io.Writer) error
 
 type cachedResponse struct {
-	buf bytes.Buffer
-	err error
+	buf       bytes.Buffer
+	err       error
+	refreshed time.Time
 }
 
 type cache struct {
@@ -67,6 +68,11 @@ func CreateAndRegisterCache(f RefreshFunc, name string) *cache {
 
 func (c *cache) ServeHTTP(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
 	response := c.getResponse()
+	if response.refreshed.IsZero() {
+		cacheRequests("background", "miss").Add(1)
+	} else {
+		cacheRequests("background", "hit").Add(1)
+	}
 
 	if response.err != nil {
 		respError(w, response.err)
@@ -84,6 +90,7 @@ func (c *cache) Refresh(ctx context.Context) {
 	stop := c.timer.One()
 	response.err = c.f(ctx, &response.buf)
 	stop()
+	response.refreshed = time.Now()
 
 	c.responseMutex.Lock()
 	c.response = response
@@ -104,17 +111,7 @@ func (cs *cacheStore) RefreshAll(ctx context.Context) {
 	cs.RUnlock()
 
 	for _, cache := range caches {
-		ctx2, cancel := context.WithTimeout(ctx, BackgroundCalculationTotalTimeout)
-		CacheLogger.InfoT(midlog.Str("cache", cache.name), "Refreshing cache")
-		start := timer.MilliCounter()
-		cache.Refresh(ctx2)
-		CacheLogger.InfoT(
-			midlog.Tags(
-				midlog.Str("cache", cache.name),
-				midlog.Float32("duration", start.SecondsElapsed())),
-			"Refreshed cache.")
-
-		cancel()
+		cache.refreshLogged(ctx)
 		if ctx.Err() != nil {
 			// Cancelled
 			return
@@ -122,15 +119,69 @@ func (cs *cacheStore) RefreshAll(ctx context.Context) {
 	}
 }
 
+// RefreshByName refreshes the caches with the given name.
+// Returns false if there is no such cache.
+func (cs *cacheStore) RefreshByName(ctx context.Context, name string) bool {
+	cs.RLock()
+	caches := cs.caches
+	cs.RUnlock()
+
+	found := false
+	for _, cache := range caches {
+		if cache.name == name {
+			found = true
+			cache.refreshLogged(ctx)
+		}
+	}
+	return found
+}
+
+func (c *cache) refreshLogged(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, BackgroundCalculationTotalTimeout)
+	defer cancel()
+	CacheLogger.InfoT(midlog.Str("cache", c.name), "Refreshing cache")
+	start := timer.MilliCounter()
+	c.Refresh(ctx)
+	CacheLogger.InfoT(
+		midlog.Tags(
+			midlog.Str("cache", c.name),
+			midlog.Float32("duration", start.SecondsElapsed())),
+		"Refreshed cache.")
+}
+
+type CacheStatus struct {
+	Name string `json:"name"`
+	// Zero if the cache wasn't calculated yet.
+	Refreshed time.Time `json:"refreshed"`
+	Error     string    `json:"error,omitempty"`
+}
+
+func (cs *cacheStore) Status() []CacheStatus {
+	cs.RLock()
+	caches := cs.caches
+	cs.RUnlock()
+
+	ret := make([]CacheStatus, 0, len(caches))
+	for _, cache := range caches {
+		response := cache.getResponse()
+		status := CacheStatus{Name: cache.name, Refreshed: response.refreshed}
+		if response.err != nil {
+			status.Error = response.err.Error()
+		}
+		ret = append(ret, status)
+	}
+	return ret
+}
+
 func (cs *cacheStore) InitBackgroundRefresh(ctx context.Context) jobs.NamedFunction {
 	// TODO(muninn): add more logs once we have log levels
-	return jobs.Later("CacheRefresh", func() {
+	return jobs.Supervised(ctx, "CacheRefresh", jobs.RestartOnFailure, func() error {
 		jobs.Sleep(ctx, CacheRefreshStartupSleep)
 		CacheLogger.Info("Starting background cache population")
 		for {
 			if ctx.Err() != nil {
 				CacheLogger.Info("Shutdown background cache population")
-				return
+				return nil
 			}
 			cs.RefreshAll(ctx)
 			sleepTime := CacheRefreshSleepPerRound
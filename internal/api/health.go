@@ -0,0 +1,139 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"gitlab.com/thorchain/midgard/config"
+	"gitlab.com/thorchain/midgard/internal/db"
+	"gitlab.com/thorchain/midgard/internal/fetch/sync"
+	"gitlab.com/thorchain/midgard/internal/util"
+	"gitlab.com/thorchain/midgard/internal/websockets"
+	"gitlab.com/thorchain/midgard/openapi/generated/oapigen"
+)
+
+// Liveness only tells that the process serves HTTP, it should be restarted otherwise.
+func jsonHealthLive(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	respJSON(w, oapigen.Liveness{Alive: true})
+}
+
+// Readiness tells if the data served is up to date, it fails during the catch up too.
+func jsonHealthReady(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	merr := util.CheckUrlEmpty(r.URL.Query())
+	if merr != nil {
+		merr.ReportHTTP(w)
+		return
+	}
+
+	report := healthReport(r.Context(), time.Now())
+	w.Header().Set("Content-Type", "application/json")
+	if !report.Ready {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	writeJSON(w, report)
+}
+
+func healthReport(ctx context.Context, now time.Time) oapigen.HealthReport {
+	c := config.Global.Health
+	tip := db.LastThorNodeBlock.Get()
+	fetched := db.LastFetchedBlock.Get()
+	committed := db.LastCommittedBlock.Get()
+	aggregated := db.LastAggregatedBlock.Get()
+
+	// Standby instances don't fetch, they have no fetch lag.
+	var fetchLag int64
+	if fetched.Height != 0 && fetched.Height < tip.Height {
+		fetchLag = tip.Height - fetched.Height
+	}
+	commitAge := now.Sub(committed.Timestamp.ToTime())
+	aggregatesLag := now.Sub(aggregated.Timestamp.ToTime())
+
+	report := oapigen.HealthReport{
+		ChainTip:             db.LastThorNodeBlock.AsHeightTS(),
+		FetchLag:             int(fetchLag),
+		LastCommitAge:        int(commitAge.Seconds()),
+		AggregatesLag:        int(aggregatesLag.Seconds()),
+		Caches:               cachesHealth(now),
+		WebsocketConnections: websockets.ConnectionCount(),
+		Database:             databaseHealth(ctx, c.DBTimeout.Value()),
+		BlockStoreHeight:     util.IntStr(0),
+		ThorNodeReachable:    true,
+	}
+	if sync.GlobalSync != nil {
+		report.BlockStoreHeight = util.IntStr(sync.GlobalSync.BlockStoreHeight())
+		report.ThorNodeReachable = sync.GlobalSync.ThorNodeReachable()
+	}
+
+	addCheck := func(name string, ok bool, format string, a ...interface{}) {
+		check := oapigen.HealthCheck{Name: name, Ok: ok}
+		if !ok {
+			message := fmt.Sprintf(format, a...)
+			check.Message = &message
+		}
+		report.Checks = append(report.Checks, check)
+	}
+	addCheck("database", report.Database.Reachable, "Database is not reachable")
+	if c.ReadyMaxFetchLag != 0 {
+		addCheck("fetch_lag", fetchLag <= c.ReadyMaxFetchLag,
+			"Fetching is %d blocks behind the chain tip, max %d", fetchLag, c.ReadyMaxFetchLag)
+	}
+	if c.ReadyMaxCommitAge != 0 {
+		maxAge := c.ReadyMaxCommitAge.Value()
+		addCheck("commit_age", commitAge <= maxAge,
+			"Last committed block is %v old, max %v", commitAge.Truncate(time.Second), maxAge)
+	}
+	if c.ReadyMaxAggregatesLag != 0 {
+		maxLag := c.ReadyMaxAggregatesLag.Value()
+		addCheck("aggregates_lag", aggregatesLag <= maxLag,
+			"Last aggregated block is %v old, max %v", aggregatesLag.Truncate(time.Second), maxLag)
+	}
+	if c.ReadyRequireThorNode {
+		addCheck("thornode", report.ThorNodeReachable, "All ThorNode endpoints are failing")
+	}
+
+	report.Ready = true
+	for _, check := range report.Checks {
+		report.Ready = report.Ready && check.Ok
+	}
+	return report
+}
+
+func cachesHealth(now time.Time) []oapigen.CacheHealth {
+	statuses := GlobalCacheStore.Status()
+	ret := make([]oapigen.CacheHealth, len(statuses))
+	for i, status := range statuses {
+		ret[i].Name = status.Name
+		if !status.Refreshed.IsZero() {
+			age := int(now.Sub(status.Refreshed).Seconds())
+			ret[i].Age = &age
+		}
+		if status.Error != "" {
+			err := status.Error
+			ret[i].Error = &err
+		}
+	}
+	return ret
+}
+
+func databaseHealth(ctx context.Context, timeout time.Duration) oapigen.DatabaseHealth {
+	if db.TheDB == nil {
+		return oapigen.DatabaseHealth{WaitCount: "0", WaitDurationMs: "0"}
+	}
+	if timeout != 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	stats := db.TheDB.Stats()
+	return oapigen.DatabaseHealth{
+		Reachable:       db.TheDB.PingContext(ctx) == nil,
+		OpenConnections: stats.OpenConnections,
+		InUse:           stats.InUse,
+		Idle:            stats.Idle,
+		WaitCount:       util.IntStr(stats.WaitCount),
+		WaitDurationMs:  util.IntStr(stats.WaitDuration.Milliseconds()),
+	}
+}
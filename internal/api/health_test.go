@@ -0,0 +1,55 @@
+package api_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gitlab.com/thorchain/midgard/config"
+	"gitlab.com/thorchain/midgard/internal/db"
+	"gitlab.com/thorchain/midgard/internal/db/testdb"
+	"gitlab.com/thorchain/midgard/openapi/generated/oapigen"
+)
+
+func setHeights(tip, fetched int64, age time.Duration) {
+	ts := db.TimeToNano(time.Now().Add(-age))
+	db.LastThorNodeBlock.Set(tip, ts)
+	db.LastFetchedBlock.Set(fetched, ts)
+	db.LastCommittedBlock.Set(fetched, ts)
+	db.LastAggregatedBlock.Set(fetched, ts)
+}
+
+func TestHealthLive(t *testing.T) {
+	testdb.InitTest(t)
+
+	var live oapigen.Liveness
+	testdb.MustUnmarshal(t, testdb.CallJSON(t, "http://localhost:8080/v2/health/live"), &live)
+	require.True(t, live.Alive)
+}
+
+func TestHealthReady(t *testing.T) {
+	testdb.InitTest(t)
+	defer db.ResetGlobalVarsForTests()
+	health := config.Global.Health
+	t.Cleanup(func() { config.Global.Health = health })
+	config.Global.Health = config.Health{
+		ReadyMaxFetchLag:      10,
+		ReadyMaxCommitAge:     config.Duration(time.Minute),
+		ReadyMaxAggregatesLag: config.Duration(time.Minute),
+	}
+
+	setHeights(105, 100, time.Second)
+	var report oapigen.HealthReport
+	testdb.MustUnmarshal(t, testdb.CallJSON(t, "http://localhost:8080/v2/health/ready"), &report)
+	require.True(t, report.Ready)
+	require.Equal(t, 5, report.FetchLag)
+	require.True(t, report.Database.Reachable)
+	require.Len(t, report.Checks, 4)
+
+	setHeights(200, 100, time.Second)
+	testdb.CallFail(t, "http://localhost:8080/v2/health/ready", "fetching is 100 blocks behind")
+
+	setHeights(100, 100, time.Hour)
+	testdb.CallFail(t, "http://localhost:8080/v2/health/ready",
+		"last committed block is", "last aggregated block is")
+}
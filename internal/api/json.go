@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"net/url"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -18,6 +19,7 @@ import (
 	"gitlab.com/thorchain/midgard/internal/db"
 	"gitlab.com/thorchain/midgard/internal/graphql/model"
 	"gitlab.com/thorchain/midgard/internal/util"
+	"gitlab.com/thorchain/midgard/internal/util/jobs"
 	"gitlab.com/thorchain/midgard/internal/util/miderr"
 
 	"gitlab.com/thorchain/midgard/internal/timeseries"
@@ -51,9 +53,31 @@ func jsonHealth(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
 		LastFetched:    db.LastFetchedBlock.AsHeightTS(),
 		LastCommitted:  db.LastCommittedBlock.AsHeightTS(),
 		LastAggregated: db.LastAggregatedBlock.AsHeightTS(),
+		Jobs:           jobsHealth(),
 	})
 }
 
+func jobsHealth() []oapigen.JobHealth {
+	running := jobs.Running()
+	ret := make([]oapigen.JobHealth, len(running))
+	for i, job := range running {
+		ret[i] = oapigen.JobHealth{
+			Name:    job.Name,
+			State:   oapigen.JobHealthState(job.State),
+			Crashes: job.Crashes,
+		}
+		if job.RestartPolicy != "" {
+			policy := oapigen.JobHealthRestartPolicy(job.RestartPolicy)
+			ret[i].RestartPolicy = &policy
+		}
+		if job.LastError != "" {
+			lastError := job.LastError
+			ret[i].LastError = &lastError
+		}
+	}
+	return ret
+}
+
 func jsonEarningsHistory(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
 	f := func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
 		urlParams := r.URL.Query()
@@ -172,6 +196,87 @@ func jsonDepths(w http.ResponseWriter, r *http.Request, params httprouter.Params
 	GlobalApiCacheStore.Get(GlobalApiCacheStore.LongTermLifetime, f, w, r, params)
 }
 
+func jsonDepthChanges(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	f := func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+		pool := params[0].Value
+
+		if !timeseries.PoolExists(pool) {
+			miderr.BadRequestF("Unknown pool: %s", pool).ReportHTTP(w)
+			return
+		}
+
+		urlParams := r.URL.Query()
+		buckets, merr := db.BucketsFromQuery(r.Context(), &urlParams)
+		if merr != nil {
+			merr.ReportHTTP(w)
+			return
+		}
+
+		merr = util.CheckUrlEmpty(urlParams)
+		if merr != nil {
+			merr.ReportHTTP(w)
+			return
+		}
+
+		changes, err := stat.PoolDepthChangesHistory(r.Context(), buckets, pool)
+		if err != nil {
+			miderr.InternalErrE(err).ReportHTTP(w)
+			return
+		}
+		var result oapigen.DepthChangesHistoryResponse = toOapiDepthChangesResponse(changes)
+		respJSON(w, result)
+	}
+	GlobalApiCacheStore.Get(GlobalApiCacheStore.LongTermLifetime, f, w, r, params)
+}
+
+func toOapiDepthChangesItem(bucket stat.DepthChangeBucket) oapigen.DepthChangesHistoryItem {
+	changes := make([]oapigen.DepthChange, 0, len(bucket.Changes))
+	for _, c := range bucket.Changes {
+		changes = append(changes, oapigen.DepthChange{
+			Reason:  oapigen.DepthChangeReason(c.Reason),
+			AssetE8: util.IntStr(c.AssetE8),
+			RuneE8:  util.IntStr(c.RuneE8),
+			SynthE8: util.IntStr(c.SynthE8),
+		})
+	}
+	return oapigen.DepthChangesHistoryItem{
+		StartTime:  util.IntStr(bucket.Window.From.ToI()),
+		EndTime:    util.IntStr(bucket.Window.Until.ToI()),
+		AssetDelta: util.IntStr(bucket.AssetDelta),
+		RuneDelta:  util.IntStr(bucket.RuneDelta),
+		SynthDelta: util.IntStr(bucket.SynthDelta),
+		Changes:    changes,
+	}
+}
+
+func toOapiDepthChangesResponse(buckets []stat.DepthChangeBucket) (
+	result oapigen.DepthChangesHistoryResponse,
+) {
+	meta := stat.DepthChangeBucket{
+		Window:  db.Window{From: buckets[0].Window.From, Until: buckets[len(buckets)-1].Window.Until},
+		Changes: make([]stat.DepthChange, len(buckets[0].Changes)),
+	}
+	copy(meta.Changes, buckets[0].Changes)
+	for i := range meta.Changes {
+		meta.Changes[i] = stat.DepthChange{Reason: meta.Changes[i].Reason}
+	}
+
+	result.Intervals = make(oapigen.DepthChangesHistoryIntervals, 0, len(buckets))
+	for _, bucket := range buckets {
+		result.Intervals = append(result.Intervals, toOapiDepthChangesItem(bucket))
+		meta.AssetDelta += bucket.AssetDelta
+		meta.RuneDelta += bucket.RuneDelta
+		meta.SynthDelta += bucket.SynthDelta
+		for i, c := range bucket.Changes {
+			meta.Changes[i].AssetE8 += c.AssetE8
+			meta.Changes[i].RuneE8 += c.RuneE8
+			meta.Changes[i].SynthE8 += c.SynthE8
+		}
+	}
+	result.Meta = toOapiDepthChangesItem(meta)
+	return
+}
+
 func toOapiDepthResponse(
 	ctx context.Context,
 	beforeDepth timeseries.PoolDepths,
@@ -513,6 +618,321 @@ func toTVLHistoryResponse(depths []stat.TVLDepthBucket, bonds []stat.BondBucket)
 	return
 }
 
+func jsonRunePriceHistory(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	f := func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+		urlParams := r.URL.Query()
+
+		buckets, merr := db.BucketsFromQuery(r.Context(), &urlParams)
+		if merr != nil {
+			merr.ReportHTTP(w)
+			return
+		}
+		merr = util.CheckUrlEmpty(urlParams)
+		if merr != nil {
+			merr.ReportHTTP(w)
+			return
+		}
+
+		prices, err := stat.RunePriceHistory(r.Context(), buckets)
+		if err != nil {
+			miderr.InternalErrE(err).ReportHTTP(w)
+			return
+		}
+
+		var result oapigen.RunePriceHistoryResponse = toRunePriceHistoryResponse(prices)
+		respJSON(w, result)
+	}
+	GlobalApiCacheStore.Get(GlobalApiCacheStore.LongTermLifetime, f, w, r, params)
+}
+
+func toRunePriceHistoryResponse(prices []stat.RunePriceBucket) (
+	result oapigen.RunePriceHistoryResponse,
+) {
+	result.Intervals = make(oapigen.RunePriceHistoryIntervals, 0, len(prices))
+	for _, bucket := range prices {
+		result.Intervals = append(result.Intervals, oapigen.RunePriceHistoryItem{
+			StartTime:    util.IntStr(bucket.Window.From.ToI()),
+			EndTime:      util.IntStr(bucket.Window.Until.ToI()),
+			RunePriceUSD: floatStr(bucket.Price),
+			LowPriceUSD:  floatStr(bucket.Low),
+			HighPriceUSD: floatStr(bucket.High),
+			Method:       bucket.Method,
+			Pools:        bucket.Pools,
+		})
+	}
+	result.Meta = result.Intervals[len(prices)-1]
+	result.Meta.StartTime = result.Intervals[0].StartTime
+	return
+}
+
+func jsonAffiliateHistory(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	f := func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+		urlParams := r.URL.Query()
+
+		buckets, merr := db.BucketsFromQuery(r.Context(), &urlParams)
+		if merr != nil {
+			merr.ReportHTTP(w)
+			return
+		}
+
+		var affiliates []string
+		affiliate := util.ConsumeUrlParam(&urlParams, "affiliate")
+		if affiliate != "" {
+			names, err := timeseries.GetTHORNamesByAddress(r.Context(), &affiliate)
+			if err != nil {
+				miderr.InternalErrE(err).ReportHTTP(w)
+				return
+			}
+			affiliates = append([]string{affiliate}, names...)
+		}
+
+		merr = util.CheckUrlEmpty(urlParams)
+		if merr != nil {
+			merr.ReportHTTP(w)
+			return
+		}
+
+		history, err := stat.AffiliateHistory(r.Context(), buckets, affiliates)
+		if err != nil {
+			miderr.InternalErrE(err).ReportHTTP(w)
+			return
+		}
+
+		var result oapigen.AffiliateHistoryResponse = toAffiliateHistoryResponse(history)
+		respJSON(w, result)
+	}
+	GlobalApiCacheStore.Get(GlobalApiCacheStore.LongTermLifetime, f, w, r, params)
+}
+
+func toOapiAffiliates(stats []stat.AffiliateStats) oapigen.Affiliates {
+	ret := make(oapigen.Affiliates, 0, len(stats))
+	for _, s := range stats {
+		ret = append(ret, oapigen.AffiliateStats{
+			Affiliate: s.Affiliate,
+			SwapCount: util.IntStr(s.SwapCount),
+			Volume:    util.IntStr(s.VolumeE8),
+			VolumeUsd: util.IntStr(s.VolumeUsdE8),
+			Fees:      util.IntStr(s.FeesE8),
+		})
+	}
+	return ret
+}
+
+func toAffiliateHistoryResponse(history []stat.AffiliateBucket) (
+	result oapigen.AffiliateHistoryResponse,
+) {
+	var metaTotal stat.AffiliateStats
+	metaByAffiliate := map[string]*stat.AffiliateStats{}
+	result.Intervals = make(oapigen.AffiliateHistoryIntervals, 0, len(history))
+	for _, bucket := range history {
+		result.Intervals = append(result.Intervals, oapigen.AffiliateHistoryItem{
+			StartTime:  util.IntStr(bucket.Window.From.ToI()),
+			EndTime:    util.IntStr(bucket.Window.Until.ToI()),
+			SwapCount:  util.IntStr(bucket.Total.SwapCount),
+			Volume:     util.IntStr(bucket.Total.VolumeE8),
+			VolumeUsd:  util.IntStr(bucket.Total.VolumeUsdE8),
+			Fees:       util.IntStr(bucket.Total.FeesE8),
+			Affiliates: toOapiAffiliates(bucket.Affiliates),
+		})
+
+		metaTotal.SwapCount += bucket.Total.SwapCount
+		metaTotal.VolumeE8 += bucket.Total.VolumeE8
+		metaTotal.VolumeUsdE8 += bucket.Total.VolumeUsdE8
+		metaTotal.FeesE8 += bucket.Total.FeesE8
+		for _, a := range bucket.Affiliates {
+			m, ok := metaByAffiliate[a.Affiliate]
+			if !ok {
+				m = &stat.AffiliateStats{Affiliate: a.Affiliate}
+				metaByAffiliate[a.Affiliate] = m
+			}
+			m.SwapCount += a.SwapCount
+			m.VolumeE8 += a.VolumeE8
+			m.VolumeUsdE8 += a.VolumeUsdE8
+			m.FeesE8 += a.FeesE8
+		}
+	}
+
+	metaAffiliates := make([]stat.AffiliateStats, 0, len(metaByAffiliate))
+	for _, m := range metaByAffiliate {
+		metaAffiliates = append(metaAffiliates, *m)
+	}
+	sort.Slice(metaAffiliates, func(i, j int) bool {
+		if metaAffiliates[i].FeesE8 != metaAffiliates[j].FeesE8 {
+			return metaAffiliates[i].FeesE8 > metaAffiliates[j].FeesE8
+		}
+		return metaAffiliates[i].Affiliate < metaAffiliates[j].Affiliate
+	})
+
+	result.Meta = oapigen.AffiliateHistoryItem{
+		StartTime:  result.Intervals[0].StartTime,
+		EndTime:    result.Intervals[len(result.Intervals)-1].EndTime,
+		SwapCount:  util.IntStr(metaTotal.SwapCount),
+		Volume:     util.IntStr(metaTotal.VolumeE8),
+		VolumeUsd:  util.IntStr(metaTotal.VolumeUsdE8),
+		Fees:       util.IntStr(metaTotal.FeesE8),
+		Affiliates: toOapiAffiliates(metaAffiliates),
+	}
+	return
+}
+
+// Parses the `limit` param of the leaderboard endpoints, default is 50.
+func parseLimitParam(urlParams *url.Values) (int, miderr.Err) {
+	limitParam := util.ConsumeUrlParam(urlParams, "limit")
+	if limitParam == "" {
+		return 50, nil
+	}
+	limit, err := strconv.Atoi(limitParam)
+	if err != nil || limit < 1 || 400 < limit {
+		return 0, miderr.BadRequestF("invalid limit: %s, should be between [1..400]", limitParam)
+	}
+	return limit, nil
+}
+
+func jsonAffiliates(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	f := func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+		urlParams := r.URL.Query()
+
+		buckets, err := parsePeriodParam(&urlParams)
+		if err != nil {
+			miderr.BadRequest(err.Error()).ReportHTTP(w)
+			return
+		}
+
+		limit, merr := parseLimitParam(&urlParams)
+		if merr != nil {
+			merr.ReportHTTP(w)
+			return
+		}
+
+		merr = util.CheckUrlEmpty(urlParams)
+		if merr != nil {
+			merr.ReportHTTP(w)
+			return
+		}
+
+		affiliates, err := stat.AffiliateLeaderboard(r.Context(), buckets, limit)
+		if err != nil {
+			miderr.InternalErrE(err).ReportHTTP(w)
+			return
+		}
+		respJSON(w, oapigen.AffiliatesResponse(toOapiAffiliates(affiliates)))
+	}
+	GlobalApiCacheStore.Get(GlobalApiCacheStore.LongTermLifetime, f, w, r, params)
+}
+
+func jsonUsersHistory(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	f := func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+		urlParams := r.URL.Query()
+
+		buckets, merr := db.BucketsFromQuery(r.Context(), &urlParams)
+		if merr != nil {
+			merr.ReportHTTP(w)
+			return
+		}
+		merr = util.CheckUrlEmpty(urlParams)
+		if merr != nil {
+			merr.ReportHTTP(w)
+			return
+		}
+
+		history, err := stat.UsersHistory(r.Context(), buckets)
+		if err != nil {
+			miderr.InternalErrE(err).ReportHTTP(w)
+			return
+		}
+
+		// Active swappers can't be summed up, so the meta is queried separately.
+		meta := history
+		if !buckets.OneInterval() {
+			meta, err = stat.UsersHistory(r.Context(),
+				db.OneIntervalBuckets(buckets.Start(), buckets.End()))
+			if err != nil {
+				miderr.InternalErrE(err).ReportHTTP(w)
+				return
+			}
+		}
+
+		result := oapigen.UsersHistoryResponse{
+			Meta:      toOapiUsersHistoryItem(meta[0]),
+			Intervals: make(oapigen.UsersHistoryIntervals, 0, len(history)),
+		}
+		for _, bucket := range history {
+			result.Intervals = append(result.Intervals, toOapiUsersHistoryItem(bucket))
+		}
+		respJSON(w, result)
+	}
+	GlobalApiCacheStore.Get(GlobalApiCacheStore.LongTermLifetime, f, w, r, params)
+}
+
+func toOapiUsersHistoryItem(bucket stat.UsersBucket) oapigen.UsersHistoryItem {
+	swapSizes := make(oapigen.SwapSizeHistogram, 0, len(bucket.SwapSizes))
+	for _, bin := range bucket.SwapSizes {
+		var upperBound *string
+		if bin.Bin+1 < len(stat.SwapSizeBins) {
+			bound := util.IntStr(stat.SwapSizeBins[bin.Bin+1])
+			upperBound = &bound
+		}
+		swapSizes = append(swapSizes, oapigen.SwapSizeBin{
+			LowerBound: util.IntStr(stat.SwapSizeBins[bin.Bin]),
+			UpperBound: upperBound,
+			SwapCount:  util.IntStr(bin.SwapCount),
+			Volume:     util.IntStr(bin.VolumeE8),
+		})
+	}
+	return oapigen.UsersHistoryItem{
+		StartTime:         util.IntStr(bucket.Window.From.ToI()),
+		EndTime:           util.IntStr(bucket.Window.Until.ToI()),
+		ActiveSwappers:    util.IntStr(bucket.ActiveSwappers),
+		NewSwappers:       util.IntStr(bucket.NewSwappers),
+		ReturningSwappers: util.IntStr(bucket.ReturningSwappers()),
+		SwapCount:         util.IntStr(bucket.SwapCount),
+		Volume:            util.IntStr(bucket.VolumeE8),
+		SwapSizes:         swapSizes,
+	}
+}
+
+func jsonTraders(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	f := func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+		urlParams := r.URL.Query()
+
+		buckets, err := parsePeriodParam(&urlParams)
+		if err != nil {
+			miderr.BadRequest(err.Error()).ReportHTTP(w)
+			return
+		}
+
+		limit, merr := parseLimitParam(&urlParams)
+		if merr != nil {
+			merr.ReportHTTP(w)
+			return
+		}
+
+		merr = util.CheckUrlEmpty(urlParams)
+		if merr != nil {
+			merr.ReportHTTP(w)
+			return
+		}
+
+		traders, err := stat.TradersLeaderboard(r.Context(), buckets, limit)
+		if err != nil {
+			miderr.InternalErrE(err).ReportHTTP(w)
+			return
+		}
+
+		result := make(oapigen.TradersResponse, 0, len(traders))
+		for _, t := range traders {
+			result = append(result, oapigen.TraderStats{
+				Address:   t.Address,
+				SwapCount: util.IntStr(t.SwapCount),
+				Volume:    util.IntStr(t.VolumeE8),
+				VolumeUsd: util.IntStr(t.VolumeUsdE8),
+			})
+		}
+		respJSON(w, result)
+	}
+	GlobalApiCacheStore.Get(GlobalApiCacheStore.LongTermLifetime, f, w, r, params)
+}
+
 type Network struct {
 	ActiveBonds     []string `json:"activeBonds,string"`
 	ActiveNodeCount int      `json:"activeNodeCount,string"`
@@ -635,6 +1055,302 @@ func jsonNodes(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
 	respJSON(w, array)
 }
 
+func parseOffsetParam(urlParams *url.Values) (int, miderr.Err) {
+	offsetParam := util.ConsumeUrlParam(urlParams, "offset")
+	if offsetParam == "" {
+		return 0, nil
+	}
+	offset, err := strconv.Atoi(offsetParam)
+	if err != nil || offset < 0 {
+		return 0, miderr.BadRequestF("invalid offset: %s, should be a non-negative integer", offsetParam)
+	}
+	return offset, nil
+}
+
+func nonEmptyStrP(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func jsonNodeTimeline(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	f := func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+		address := params[0].Value
+		urlParams := r.URL.Query()
+
+		limit, merr := parseLimitParam(&urlParams)
+		if merr != nil {
+			merr.ReportHTTP(w)
+			return
+		}
+		offset, merr := parseOffsetParam(&urlParams)
+		if merr != nil {
+			merr.ReportHTTP(w)
+			return
+		}
+		merr = util.CheckUrlEmpty(urlParams)
+		if merr != nil {
+			merr.ReportHTTP(w)
+			return
+		}
+
+		events, err := stat.NodeTimeline(r.Context(), address, limit, offset)
+		if err != nil {
+			miderr.InternalErrE(err).ReportHTTP(w)
+			return
+		}
+		// The last status, version and ip address of the nodes are kept in lite mode, so the
+		// timeline of a node older than the retained window reaches before it.
+		if len(events) != 0 {
+			merr = db.CheckRetainedList(events[len(events)-1].Timestamp)
+			if merr != nil {
+				merr.ReportHTTP(w)
+				return
+			}
+		}
+
+		result := make(oapigen.NodeTimelineResponse, 0, len(events))
+		for _, e := range events {
+			event := oapigen.NodeEvent{
+				Height:        util.IntStr(e.Height),
+				Date:          util.IntStr(e.Timestamp.ToI()),
+				Type:          oapigen.NodeEventType(e.Type),
+				TxId:          nonEmptyStrP(e.TxID),
+				Reason:        nonEmptyStrP(e.Reason),
+				FormerStatus:  nonEmptyStrP(e.Former),
+				CurrentStatus: nonEmptyStrP(e.Current),
+				Version:       nonEmptyStrP(e.Version),
+				IpAddress:     nonEmptyStrP(e.IPAddress),
+			}
+			if e.Type != stat.NodeEventStatus && e.Type != stat.NodeEventVersion &&
+				e.Type != stat.NodeEventIPAddress {
+				amount := util.IntStr(e.Amount)
+				event.Amount = &amount
+			}
+			result = append(result, event)
+		}
+		respJSON(w, result)
+	}
+	GlobalApiCacheStore.Get(GlobalApiCacheStore.ShortTermLifetime, f, w, r, params)
+}
+
+func jsonChurns(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	f := func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+		urlParams := r.URL.Query()
+
+		limit, merr := parseLimitParam(&urlParams)
+		if merr != nil {
+			merr.ReportHTTP(w)
+			return
+		}
+		offset, merr := parseOffsetParam(&urlParams)
+		if merr != nil {
+			merr.ReportHTTP(w)
+			return
+		}
+		merr = util.CheckUrlEmpty(urlParams)
+		if merr != nil {
+			merr.ReportHTTP(w)
+			return
+		}
+
+		churns, err := stat.Churns(r.Context(), limit, offset)
+		if err != nil {
+			miderr.InternalErrE(err).ReportHTTP(w)
+			return
+		}
+		// A page which is not full reaches back to the first block.
+		oldest := db.FirstBlock.Get().Timestamp
+		if len(churns) == limit {
+			oldest = churns[len(churns)-1].Timestamp
+		}
+		merr = db.CheckRetainedList(oldest)
+		if merr != nil {
+			merr.ReportHTTP(w)
+			return
+		}
+
+		result := make(oapigen.ChurnsResponse, 0, len(churns))
+		for _, c := range churns {
+			item := oapigen.ChurnItem{
+				Height:        util.IntStr(c.Height),
+				Date:          util.IntStr(c.Timestamp.ToI()),
+				NodesJoined:   c.NodesJoined,
+				NodesLeft:     c.NodesLeft,
+				NewVaults:     c.NewVaults,
+				RetiredVaults: c.RetiredVaults,
+				BondBefore:    util.IntStr(c.BondBeforeE8),
+				BondAfter:     util.IntStr(c.BondAfterE8),
+			}
+			if c.MigrationEnd != 0 {
+				duration := util.IntStr((c.MigrationEnd - c.Timestamp).ToSecond().ToI())
+				item.Duration = &duration
+			}
+			result = append(result, item)
+		}
+		respJSON(w, result)
+	}
+	GlobalApiCacheStore.Get(GlobalApiCacheStore.ShortTermLifetime, f, w, r, params)
+}
+
+func toOapiMimir(values []timeseries.MimirValue) oapigen.Mimir {
+	ret := make(oapigen.Mimir, 0, len(values))
+	for _, v := range values {
+		ret = append(ret, oapigen.MimirItem{
+			Key:    v.Key,
+			Value:  v.Value,
+			Height: util.IntStr(v.Height),
+			Date:   util.IntStr(v.Timestamp.ToI()),
+		})
+	}
+	return ret
+}
+
+func jsonMimir(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	f := func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+		merr := util.CheckUrlEmpty(r.URL.Query())
+		if merr != nil {
+			merr.ReportHTTP(w)
+			return
+		}
+
+		values, err := timeseries.MimirValues(r.Context())
+		if err != nil {
+			miderr.InternalErrE(err).ReportHTTP(w)
+			return
+		}
+		result := oapigen.MimirResponse(toOapiMimir(values))
+		respJSON(w, result)
+	}
+	GlobalApiCacheStore.Get(GlobalApiCacheStore.ShortTermLifetime, f, w, r, params)
+}
+
+func jsonMimirHistory(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	f := func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+		key := params[0].Value
+
+		merr := util.CheckUrlEmpty(r.URL.Query())
+		if merr != nil {
+			merr.ReportHTTP(w)
+			return
+		}
+
+		values, err := timeseries.MimirHistory(r.Context(), key)
+		if err != nil {
+			miderr.InternalErrE(err).ReportHTTP(w)
+			return
+		}
+		if len(values) == 0 {
+			miderr.BadRequestF("Unknown mimir key: %s", key).ReportHTTP(w)
+			return
+		}
+		// The last value of the keys is kept in lite mode, so the history of a key set before
+		// the retained window reaches before it.
+		merr = db.CheckRetainedList(values[0].Timestamp)
+		if merr != nil {
+			merr.ReportHTTP(w)
+			return
+		}
+		result := oapigen.MimirHistoryResponse(toOapiMimir(values))
+		respJSON(w, result)
+	}
+	GlobalApiCacheStore.Get(GlobalApiCacheStore.ShortTermLifetime, f, w, r, params)
+}
+
+func jsonNodeMimir(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	f := func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+		urlParams := r.URL.Query()
+		key := util.ConsumeUrlParam(&urlParams, "key")
+
+		merr := util.CheckUrlEmpty(urlParams)
+		if merr != nil {
+			merr.ReportHTTP(w)
+			return
+		}
+
+		activeNodes, err := timeseries.ActiveNodeCount(r.Context(), db.NowNano())
+		if err != nil {
+			miderr.InternalErrE(err).ReportHTTP(w)
+			return
+		}
+		tallies, err := timeseries.NodeMimirTallies(r.Context(), key)
+		if err != nil {
+			miderr.InternalErrE(err).ReportHTTP(w)
+			return
+		}
+
+		result := oapigen.NodeMimirResponse{
+			ActiveNodeCount: util.IntStr(activeNodes),
+			Keys:            make([]oapigen.NodeMimirTally, 0, len(tallies)),
+		}
+		for _, tally := range tallies {
+			votes := make([]oapigen.NodeMimirVote, 0, len(tally.Votes))
+			for _, v := range tally.Votes {
+				votes = append(votes, oapigen.NodeMimirVote{
+					Value: v.Value,
+					Votes: util.IntStr(v.Votes),
+				})
+			}
+			result.Keys = append(result.Keys, oapigen.NodeMimirTally{Key: tally.Key, Votes: votes})
+		}
+		respJSON(w, result)
+	}
+	GlobalApiCacheStore.Get(GlobalApiCacheStore.ShortTermLifetime, f, w, r, params)
+}
+
+func toOapiNodeHistoryItem(bucket stat.NodeHistoryBucket) oapigen.NodeHistoryItem {
+	return oapigen.NodeHistoryItem{
+		StartTime:   util.IntStr(bucket.Window.From.ToI()),
+		EndTime:     util.IntStr(bucket.Window.Until.ToI()),
+		Bond:        util.IntStr(bucket.BondE8),
+		Rewards:     util.IntStr(bucket.RewardsE8),
+		Slashed:     util.IntStr(bucket.SlashedE8),
+		SlashPoints: util.IntStr(bucket.SlashPoints),
+	}
+}
+
+func jsonNodeHistory(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	f := func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+		address := params[0].Value
+		urlParams := r.URL.Query()
+
+		buckets, merr := db.BucketsFromQuery(r.Context(), &urlParams)
+		if merr != nil {
+			merr.ReportHTTP(w)
+			return
+		}
+		merr = util.CheckUrlEmpty(urlParams)
+		if merr != nil {
+			merr.ReportHTTP(w)
+			return
+		}
+
+		history, err := stat.NodeHistory(r.Context(), buckets, address)
+		if err != nil {
+			miderr.InternalErrE(err).ReportHTTP(w)
+			return
+		}
+
+		meta := stat.NodeHistoryBucket{
+			Window: db.Window{From: history[0].Window.From, Until: history[len(history)-1].Window.Until},
+			BondE8: history[len(history)-1].BondE8,
+		}
+		result := oapigen.NodeHistoryResponse{
+			Intervals: make(oapigen.NodeHistoryIntervals, 0, len(history)),
+		}
+		for _, bucket := range history {
+			result.Intervals = append(result.Intervals, toOapiNodeHistoryItem(bucket))
+			meta.RewardsE8 += bucket.RewardsE8
+			meta.SlashedE8 += bucket.SlashedE8
+			meta.SlashPoints += bucket.SlashPoints
+		}
+		result.Meta = toOapiNodeHistoryItem(meta)
+		respJSON(w, result)
+	}
+	GlobalApiCacheStore.Get(GlobalApiCacheStore.LongTermLifetime, f, w, r, params)
+}
+
 // Filters out Suspended pools.
 // If there is a status url parameter then returns pools with that status only.
 func poolsWithRequestedStatus(
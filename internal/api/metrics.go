@@ -0,0 +1,86 @@
+package api
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pascaldekloe/metrics"
+	"gitlab.com/thorchain/midgard/internal/db"
+	"gitlab.com/thorchain/midgard/internal/util/timer"
+)
+
+var (
+	requestDuration = metrics.Must2LabelHistogram(
+		"midgard_http_request_duration_seconds", "route", "code",
+		0.001, 0.003, 0.01, 0.03, 0.1, 0.3, 1, 3, 10, 30)
+	cacheRequests = metrics.Must2LabelCounter("midgard_cache_requests_total", "store", "result")
+
+	syncLagBlocks = metrics.MustInteger("midgard_sync_lag_blocks",
+		"Number of blocks the last committed block is behind the chain tip.")
+	syncLagSeconds = metrics.MustReal("midgard_sync_lag_seconds",
+		"Age of the last committed block.")
+	aggregatesLagSeconds = metrics.MustReal("midgard_aggregates_lag_seconds",
+		"Age of the last aggregated block.")
+)
+
+func init() {
+	metrics.MustHelp("midgard_http_request_duration_seconds", "Time to serve the requests by route and status code.")
+	metrics.MustHelp("midgard_cache_requests_total", "Requests served from the background (store=background) and the response (store=response) caches, result is hit, stale or miss.")
+}
+
+// Serves the metrics for Prometheus / OpenMetrics scrapers.
+func serveMetrics(w http.ResponseWriter, r *http.Request) {
+	updateLagMetrics(time.Now())
+	timer.ServeMetrics(w, r)
+}
+
+func updateLagMetrics(now time.Time) {
+	tip := db.LastThorNodeBlock.Get()
+	committed := db.LastCommittedBlock.Get()
+	aggregated := db.LastAggregatedBlock.Get()
+
+	var lag int64
+	if committed.Height < tip.Height {
+		lag = tip.Height - committed.Height
+	}
+	syncLagBlocks.Set(lag)
+	if committed.Height != 0 {
+		syncLagSeconds.Set(now.Sub(committed.Timestamp.ToTime()).Seconds())
+	}
+	if aggregated.Height != 0 {
+		aggregatesLagSeconds.Set(now.Sub(aggregated.Timestamp.ToTime()).Seconds())
+	}
+}
+
+// Records the status code for the request metrics. Websockets need Hijack, the proxy Flush.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (r *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("hijack not supported")
+	}
+	return hijacker.Hijack()
+}
+
+func observeRequest(route string, start time.Time, r *statusRecorder) {
+	requestDuration(route, strconv.Itoa(r.status)).AddSince(start)
+}
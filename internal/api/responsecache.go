@@ -97,11 +97,11 @@ func NewResponseCache(mainContext context.Context, config *config.Config) jobs.N
 		IgnoreCache:       Cachelifetime(-1),
 	}
 	ctx = mainContext
-	return jobs.Later("ResponseCacheDeleteExpiredJobs", func() {
+	return jobs.Supervised(ctx, "ResponseCacheDeleteExpiredJobs", jobs.RestartOnFailure, func() error {
 		for {
 			if ctx.Err() != nil {
 				CacheLogger.Info("Shutdown background response cache population")
-				return
+				return nil
 			}
 			GlobalApiCacheStore.DeleteExpired()
 			jobs.Sleep(ctx, time.Minute)
@@ -115,6 +115,30 @@ func (store *apiCacheStore) Flush() {
 	store.caches = make([]*apiCache, 0)
 }
 
+type ApiCacheStatus struct {
+	Name            string    `json:"name"`
+	LastUsed        time.Time `json:"lastUsed"`
+	LastRefreshed   time.Time `json:"lastRefreshed"`
+	RefreshInterval string    `json:"refreshInterval"`
+}
+
+func (store *apiCacheStore) Status() []ApiCacheStatus {
+	store.RLock()
+	defer store.RUnlock()
+	ret := make([]ApiCacheStatus, 0, len(store.caches))
+	for _, c := range store.caches {
+		c.responseMutex.RLock()
+		ret = append(ret, ApiCacheStatus{
+			Name:            c.name,
+			LastUsed:        c.lastUsed,
+			LastRefreshed:   c.lastRefreshed,
+			RefreshInterval: c.refreshInterval.String(),
+		})
+		c.responseMutex.RUnlock()
+	}
+	return ret
+}
+
 func (store *apiCacheStore) DeleteExpired() {
 	store.Lock()
 	defer store.Unlock()
@@ -134,12 +158,16 @@ func (store *apiCacheStore) Get(lifetime Cachelifetime, refreshFunc ApiCacheRefr
 	api := store.Add(r.URL.Path+"/"+r.URL.RawQuery, lifetime)
 	api.responseMutex.Lock()
 	defer api.responseMutex.Unlock()
-	if api.expired() {
+	if !api.expired() {
+		cacheRequests("response", "hit").Add(1)
+	} else {
 		if api.response.body == nil || len(api.response.body) == 0 || api.expiredTime() >= 2*api.refreshInterval.Seconds() {
+			cacheRequests("response", "miss").Add(1)
 			api.response.Flush()
 			refreshFunc(&api.response, r, params)
 			api.lastRefreshed = time.Now()
 		} else {
+			cacheRequests("response", "stale").Add(1)
 			go func(api *apiCache, refreshFunc ApiCacheRefreshFunc, r *http.Request, params httprouter.Params) {
 				api.runnerMutex.Lock()
 				defer api.runnerMutex.Unlock()
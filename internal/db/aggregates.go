@@ -11,6 +11,7 @@ import (
 
 	"gitlab.com/thorchain/midgard/internal/util/jobs"
 
+	"github.com/pascaldekloe/metrics"
 	"github.com/rs/zerolog/log"
 	"gitlab.com/thorchain/midgard/internal/util/timer"
 )
@@ -47,6 +48,24 @@ func WebsocketsPing() {
 	}
 }
 
+var WebhooksNotify *chan struct{}
+
+// Create webhooks channel, called if enabled by config.
+func CreateWebhooksChannel() {
+	webhooksChannel := make(chan struct{}, 2)
+	WebhooksNotify = &webhooksChannel
+}
+
+func WebhooksPing() {
+	// Notify webhooks whenever the aggregates caught up with the new blocks.
+	if WebhooksNotify != nil {
+		select {
+		case *WebhooksNotify <- struct{}{}:
+		default:
+		}
+	}
+}
+
 type aggregateColumnType int
 
 const (
@@ -572,6 +591,10 @@ func refreshAggregates(ctx context.Context, bulk bool, fullTimescaleRefreshForTe
 		}
 	}
 
+	// LastAggregatedBlock is not advanced if any of the steps fail, the blocks are aggregated
+	// again on the next refresh. Webhooks and retention rely on everything being aggregated
+	// up to it.
+	failed := false
 	for name := range aggregates {
 		for _, bucket := range intervals {
 			if !bucket.exact {
@@ -587,9 +610,10 @@ func refreshAggregates(ctx context.Context, bulk bool, fullTimescaleRefreshForTe
 					"CALL refresh_continuous_aggregate('midgard_agg.%s_%s', NULL, NULL)",
 					name, bucket.name)
 			}
-			_, err := TheDB.ExecContext(ctx, q)
+			err := execRefresh(ctx, name+"_"+bucket.name, q)
 			fmt.Println(q)
 			if err != nil {
+				failed = true
 				log.Error().Err(err).Msgf("Refreshing %s_%s", name, bucket.name)
 			}
 		}
@@ -601,9 +625,10 @@ func refreshAggregates(ctx context.Context, bulk bool, fullTimescaleRefreshForTe
 		}
 		q := fmt.Sprintf("CALL midgard_agg.refresh_watermarked_view('%s', '%d')",
 			name, refreshEnd)
-		_, err := TheDB.ExecContext(ctx, q)
+		err := execRefresh(ctx, name, q)
 		fmt.Println(q)
 		if err != nil {
+			failed = true
 			log.Error().Err(err).Msgf("Refreshing %s", name)
 		}
 	}
@@ -614,8 +639,9 @@ func refreshAggregates(ctx context.Context, bulk bool, fullTimescaleRefreshForTe
 			return
 		}
 		q := fmt.Sprintf("CALL midgard_agg.update_balances('%d')", refreshEnd)
-		_, err := TheDB.ExecContext(ctx, q)
+		err := execRefresh(ctx, "balances", q)
 		if err != nil {
+			failed = true
 			log.Error().Err(err).Msg("Refreshing balances")
 		}
 	}
@@ -626,30 +652,89 @@ func refreshAggregates(ctx context.Context, bulk bool, fullTimescaleRefreshForTe
 			return
 		}
 		q := fmt.Sprintf("CALL midgard_agg.update_members('%d')", refreshEnd)
-		_, err := TheDB.ExecContext(ctx, q)
+		err := execRefresh(ctx, "members", q)
 		if err != nil {
+			failed = true
 			log.Error().Err(err).Msg("Refreshing members")
 		}
 	}
 
+	{
+		// Refresh first swaps
+		if ctx.Err() != nil {
+			return
+		}
+		q := fmt.Sprintf("CALL midgard_agg.update_first_swaps('%d')", refreshEnd)
+		err := execRefresh(ctx, "first_swaps", q)
+		if err != nil {
+			failed = true
+			log.Error().Err(err).Msg("Refreshing first swaps")
+		}
+	}
+
+	{
+		// Refresh bond totals
+		if ctx.Err() != nil {
+			return
+		}
+		q := fmt.Sprintf("CALL midgard_agg.update_bond_totals('%d')", refreshEnd)
+		err := execRefresh(ctx, "bond_totals", q)
+		if err != nil {
+			failed = true
+			log.Error().Err(err).Msg("Refreshing bond totals")
+		}
+	}
+
+	{
+		// Refresh node bond totals
+		if ctx.Err() != nil {
+			return
+		}
+		q := fmt.Sprintf("CALL midgard_agg.update_node_bond_totals('%d')", refreshEnd)
+		err := execRefresh(ctx, "node_bond_totals", q)
+		if err != nil {
+			failed = true
+			log.Error().Err(err).Msg("Refreshing node bond totals")
+		}
+	}
+
 	{
 		// Refresh actions
 		if ctx.Err() != nil {
 			return
 		}
 		q := fmt.Sprintf("CALL midgard_agg.update_actions('%d')", refreshEnd)
-		_, err := TheDB.ExecContext(ctx, q)
+		err := execRefresh(ctx, "actions", q)
 		if err != nil {
+			failed = true
 			log.Error().Err(err).Msg("Refreshing actions")
 		}
 	}
+	if failed {
+		return
+	}
 	LastAggregatedBlock.Set(lastAggregated.Height, lastAggregated.Timestamp)
 
 	if !bulk && caughtUp {
 		WebsocketsPing()
+		WebhooksPing()
 	}
 }
 
+var aggregatesRefreshDuration = metrics.Must1LabelHistogram(
+	"midgard_aggregates_refresh_seconds", "step", 0.01, 0.1, 1, 10, 60, 300)
+
+func init() {
+	metrics.MustHelp("midgard_aggregates_refresh_seconds",
+		"Time of refreshing an aggregate, a materialized view or the balances, members and actions.")
+}
+
+func execRefresh(ctx context.Context, step string, q string) error {
+	defer aggregatesRefreshDuration(step).AddSince(time.Now())
+	_, err := TheDB.ExecContext(ctx, q)
+	return err
+}
+
 func RefreshAggregatesForTests() {
 	refreshAggregates(context.Background(), true, true)
 }
@@ -675,32 +760,42 @@ func RequestAggregatesRefresh() {
 	}
 }
 
+// Sets LastAggregatedBlock from the DB. Instances which don't refresh the aggregates themselves
+// call it periodically.
+func LoadAggregatesWatermark() error {
+	var lastAggregateBlockTimestamp Nano
+	err := TheDB.QueryRow(
+		"SELECT watermark FROM midgard_agg.watermarks WHERE materialized_table = 'actions'").
+		Scan(&lastAggregateBlockTimestamp)
+	if err != nil {
+		return err
+	}
+	LastAggregatedBlock.Set(0, lastAggregateBlockTimestamp)
+	return nil
+}
+
 func InitAggregatesRefresh(ctx context.Context) jobs.NamedFunction {
 	log.Info().Msg("Starting aggregates refresh job")
 	refreshRequests = make(chan struct{}, 1)
 
 	// Where did we stop last time
-	var lastAggregateBlockTimestamp Nano
-	err := TheDB.QueryRow(
-		"SELECT watermark FROM midgard_agg.watermarks WHERE materialized_table = 'actions'").
-		Scan(&lastAggregateBlockTimestamp)
+	err := LoadAggregatesWatermark()
 	if err != nil {
 		log.Fatal().Err(err).Msg("Failed to query last watermark")
 	}
-	LastAggregatedBlock.Set(0, lastAggregateBlockTimestamp)
-	log.Info().Str("watermark", lastAggregateBlockTimestamp.ToTime().Format("2006-01-02 15:04")).
+	log.Info().Str("watermark", LastAggregatedBlock.Get().Timestamp.ToTime().Format("2006-01-02 15:04")).
 		Msg("Resuming computing aggregates")
 
-	return jobs.Later("AggregatesRefresh", func() {
+	return jobs.Supervised(ctx, "AggregatesRefresh", jobs.RestartOnFailure, func() error {
 		for {
 			if ctx.Err() != nil {
 				log.Info().Msg("Shutdown aggregates refresh job")
-				return
+				return nil
 			}
 			select {
 			case <-ctx.Done():
 				log.Info().Msg("Shutdown aggregates refresh job")
-				return
+				return nil
 			case <-refreshRequests:
 				refreshAggregates(ctx, false, false)
 			case <-time.After(aggregatesRefreshInterval):
@@ -250,10 +250,25 @@ func restrictBuckets(firstBlock, lastBlock Second, buckets *Buckets) {
 	buckets.Timestamps = buckets.Timestamps[firstok : lastok+1]
 }
 
+// The first bucket may start before the retained window, but it has to overlap with it.
+func checkBucketsRetained(timestamps Seconds) miderr.Err {
+	if len(timestamps) < 2 || RetainedFrom() < timestamps[1] {
+		return nil
+	}
+	return CheckRetained(timestamps[0])
+}
+
 func generateBucketsWithInterval(ctx context.Context, from, to *Second, count *int64, interval Interval) (ret Buckets, merr miderr.Err) {
-	firstSecond := FirstBlock.Get().Timestamp.ToSecond()
+	firstSecond := firstAvailableSecond()
 	nowSecond := NowSecond()
 
+	if from != nil {
+		merr = CheckRetained(*from)
+		if merr != nil {
+			return
+		}
+	}
+
 	if count == nil {
 		if from == nil {
 			from = &firstSecond
@@ -294,6 +309,14 @@ func generateBucketsWithInterval(ctx context.Context, from, to *Second, count *i
 		if merr != nil {
 			return
 		}
+		requested := ret.Timestamps
+		if requestedCountInt < len(requested)-1 {
+			requested = requested[len(requested)-1-requestedCountInt:]
+		}
+		merr = checkBucketsRetained(requested)
+		if merr != nil {
+			return
+		}
 		restrictBuckets(firstSecond, nowSecond, &ret)
 		if requestedCountInt < ret.Count() {
 			// We might have more intervals then requested, trim the beginning.
@@ -327,9 +350,13 @@ func generateBucketsOnlyMeta(ctx context.Context, fromP, toP *Second, count *int
 		toP = &now
 	}
 	if fromP == nil {
-		fromV := FirstBlock.Get().Timestamp.ToSecond()
+		fromV := firstAvailableSecond()
 		fromP = &fromV
 	}
+	merr = CheckRetained(*fromP)
+	if merr != nil {
+		return
+	}
 	return OneIntervalBuckets(*fromP, *toP), nil
 }
 
@@ -68,6 +68,16 @@ func CombinedForkInfoMap() *map[string]config.ForkInfo {
 	return &m
 }
 
+// Returns the chain which continues chainId after its hard fork.
+func ChildChain(chainId string) (config.ForkInfo, bool) {
+	for _, fi := range *CombinedForkInfoMap() {
+		if fi.ParentChainId == chainId {
+			return fi, true
+		}
+	}
+	return config.ForkInfo{}, false
+}
+
 func mergeAdditionalInfo(chainId *FullyQualifiedChainId, info config.ForkInfo) {
 	if info.EarliestBlockHash != "" {
 		chainId.StartHash = info.EarliestBlockHash
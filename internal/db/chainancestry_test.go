@@ -0,0 +1,31 @@
+package db_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gitlab.com/thorchain/midgard/internal/db"
+)
+
+func TestSwitchToChildChain(t *testing.T) {
+	db.ResetGlobalVarsForTests()
+	defer db.ResetGlobalVarsForTests()
+
+	db.InitializeChainVars("thorchain", 1, "")
+	require.Equal(t, int64(4786559), db.CurrentChain.Get().HardForkHeight)
+	require.True(t, db.ContinuesAfterFork())
+
+	child, ok := db.ChildChain("thorchain")
+	require.True(t, ok)
+	require.Equal(t, "thorchain-mainnet-v1", child.ChainId)
+	require.Equal(t, int64(4786560), child.EarliestBlockHeight)
+
+	db.SwitchToChildChain(child)
+	require.Equal(t, "thorchain-mainnet-v1", db.CurrentChain.Get().Name)
+	require.Equal(t, int64(4786560), db.CurrentChain.Get().StartHeight)
+	require.Equal(t, "thorchain", db.RootChain.Get().Name)
+	require.False(t, db.ContinuesAfterFork())
+
+	_, ok = db.ChildChain("thorchain-mainnet-v1")
+	require.False(t, ok)
+}
@@ -68,7 +68,7 @@ func SetupWithoutUpdate() {
 
 	dbObj.SetMaxOpenConns(timeScale.MaxOpenConns)
 
-	Query = dbObj.QueryContext
+	Query = timedQuery(dbObj.QueryContext)
 
 	TheDB = dbObj
 }
@@ -96,44 +96,25 @@ func SetupDoNotCallDirectly() {
 	}
 }
 
+// Applies the pending migrations of the core and the aggregates schemas, resetting them if the
+// baseline DDL changed. See migrations.go.
 func UpdateDDLsIfNeeded(dbObj *sql.DB, cfg config.TimeScale) {
-	UpdateDDLIfNeeded(dbObj, "data", CoreDDL(), ddlHashKey,
-		cfg.NoAutoUpdateDDL || cfg.NoAutoUpdateAggregatesDDL)
+	withMigrationLock(dbObj, func() {
+		coreTrack.migrateUp(dbObj, cfg.NoAutoUpdateDDL || cfg.NoAutoUpdateAggregatesDDL)
 
-	// If 'data' DDL is updated the 'aggregates' DDL is automatically updated too, as
-	// the `constants` table is recreated with the 'data' DDL.
-	UpdateDDLIfNeeded(dbObj, "aggregates", AggregatesDDL(), aggregatesDdlHashKey,
-		cfg.NoAutoUpdateAggregatesDDL)
+		// If 'data' DDL is updated the 'aggregates' DDL is automatically updated too, as
+		// the `constants` table is recreated with the 'data' DDL.
+		aggregatesTrack.migrateUp(dbObj, cfg.NoAutoUpdateAggregatesDDL)
+	})
 }
 
-func UpdateDDLIfNeeded(dbObj *sql.DB, tag string, ddl []string, hashKey string, noauto bool) {
-	fileDdlHash := md5.Sum([]byte(strings.Join(ddl, "")))
-	currentDdlHash := liveDDLHash(dbObj, hashKey)
-
-	if fileDdlHash != currentDdlHash {
-		log.Info().Msgf(
-			"DDL hash mismatch for %s\n\tstored value in db is %x\n\thash of the code is %x",
-			tag, currentDdlHash, fileDdlHash)
-
-		if noauto && (currentDdlHash != md5Hash{}) {
-			log.Fatal().Msg("DDL update prohibited in config. You can manually force it with cmd/nukedb")
-		}
-		log.Info().Msgf("Applying new %s ddl...", tag)
-		for _, part := range ddl {
-			log.Info().Msgf("Applying %s", part)
-			_, err := dbObj.Exec(part)
-			if err != nil {
-				log.Fatal().Err(err).Msgf("Applying new %s ddl failed, exiting", tag)
-			}
+// Same as UpdateDDLsIfNeeded, but ignores the config and always allows resets.
+func MigrateUp(dbObj *sql.DB) {
+	withMigrationLock(dbObj, func() {
+		for _, track := range migrationTracks {
+			track.migrateUp(dbObj, false)
 		}
-		_, err := dbObj.Exec(`INSERT INTO constants (key, value) VALUES ($1, $2)
-							 ON CONFLICT (key) DO UPDATE SET value = $2`,
-			hashKey, fileDdlHash[:])
-		if err != nil {
-			log.Fatal().Err(err).Msg("Updating 'constants' table failed, exiting")
-		}
-		log.Info().Msgf("Successfully applied new %s schema", tag)
-	}
+	})
 }
 
 // Returns current file md5 hash stored in table or an empty hash if either constants table
@@ -1,4 +1,6 @@
--- version 26
+-- version 28
+-- Prefer adding a migration to migrations/core over editing this file, changing this file
+-- recreates the schema and forces a full resync. See migrations/README.md.
 
 CREATE EXTENSION IF NOT EXISTS timescaledb CASCADE;
 
@@ -108,6 +110,32 @@ CREATE TABLE block_pool_depths (
 CALL setup_hypertable('block_pool_depths');
 CREATE INDEX ON block_pool_depths (pool, block_timestamp DESC);
 
+-- Sparse table for the RUNE/USD price.
+-- A row is only inserted when the price, the aggregation method or the set of contributing
+-- USD pools changes. `pools` is a comma separated list of the contributing pools.
+CREATE TABLE rune_price_usd (
+                                price               DOUBLE PRECISION NOT NULL,
+                                method              TEXT NOT NULL,
+                                pools               TEXT NOT NULL,
+                                block_timestamp     BIGINT NOT NULL
+);
+
+CALL setup_hypertable('rune_price_usd');
+
+-- Depth changes of the pools summed up per block and reason (swap, add, withdraw, ...).
+-- The changes of a block add up to the difference of the depths in block_pool_depths.
+CREATE TABLE pool_depth_changes (
+                                pool                TEXT NOT NULL,
+                                reason              TEXT NOT NULL,
+                                asset_e8            BIGINT NOT NULL,
+                                rune_e8             BIGINT NOT NULL,
+                                synth_e8            BIGINT NOT NULL,
+                                block_timestamp     BIGINT NOT NULL
+);
+
+CALL setup_hypertable('pool_depth_changes');
+CREATE INDEX ON pool_depth_changes (pool, block_timestamp DESC);
+
 
 CREATE TABLE active_vault_events (
                                      add_asgard_addr     TEXT NOT NULL,
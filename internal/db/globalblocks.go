@@ -125,6 +125,24 @@ func InitializeChainVars(chainId string, startHeight int64, hash string) {
 	RootChain.set(root)
 }
 
+// Makes the child chain the current one, after the hard fork of the current chain was reached.
+func SwitchToChildChain(child config.ForkInfo) {
+	current := FullyQualifiedChainId{Name: child.ChainId}
+	mergeAdditionalInfo(&current, child)
+	log.Info().Msgf("Switched to chain %s from height %d", current.Name, current.StartHeight)
+	CurrentChain.set(current)
+}
+
+// Returns true if the current chain has a hard fork and Midgard switches to the child chain there.
+func ContinuesAfterFork() bool {
+	current := CurrentChain.Get()
+	if current.HardForkHeight == 0 {
+		return false
+	}
+	_, ok := ChildChain(current.Name)
+	return ok
+}
+
 // Takes the results from ThorNode `status` query and initializes both the
 // `CurrentChain` and `RootChain` global variables.
 // If the current chain is the root chain, initializes the `FirstBlock` variable too.
@@ -292,6 +310,23 @@ func SetFetchCaughtUp() {
 	atomic.StoreInt32(&fetchCaughtUp, 1)
 }
 
+// 0 == false ; 1 == true
+var ingestionPaused int32 = 0
+
+// SetIngestionPaused pauses or resumes the writing of new blocks.
+// Blocks already fetched stay in the queue until ingestion is resumed.
+func SetIngestionPaused(paused bool) {
+	var v int32
+	if paused {
+		v = 1
+	}
+	atomic.StoreInt32(&ingestionPaused, v)
+}
+
+func IngestionPaused() bool {
+	return atomic.LoadInt32(&ingestionPaused) == 1
+}
+
 // FullyCaughtUp returns true if the last stage of block processing (aggregation)
 // is less than the configured amount of time in the past.
 // At this point Midgard is fully functional and is ready to serve up-to-date data.
@@ -57,6 +57,17 @@ func (txi *ImmediateInserter) EndBlock() (err error) {
 	if txi.txn == nil {
 		log.Panic().Msg("No txn open")
 	}
+	err = checkWriterFence(func(query string, args ...interface{}) rowScanner {
+		return txi.txn.QueryRow(query, args...)
+	})
+	if err != nil {
+		err2 := txi.txn.Rollback()
+		if err2 != nil {
+			log.Error().Err(err2).Msg("ROLLBACK failed")
+		}
+		txi.txn = nil
+		return
+	}
 	err = txi.txn.Commit()
 	if err != nil {
 		log.Error().Err(err).Msg("COMMIT failed")
@@ -165,6 +176,17 @@ func (bi *BatchInserter) flushRaw(rawConn interface{}) (err error) {
 		}
 	}
 
+	err = checkWriterFence(func(query string, args ...interface{}) rowScanner {
+		return txn.QueryRow(context.Background(), query, args...)
+	})
+	if err != nil {
+		err2 := txn.Rollback(context.Background())
+		if err2 != nil {
+			log.Error().Err(err2).Msg("ROLLBACK failed")
+		}
+		return
+	}
+
 	err = txn.Commit(context.Background())
 
 	return
@@ -177,3 +199,18 @@ func (bi *BatchInserter) Flush() error {
 
 	return bi.db.Raw(bi.flushRaw)
 }
+
+// Drops the rows which were not committed yet. Used when an other instance takes over writing,
+// the next writes continue from the last block in block_log.
+func DiscardPendingInserts() {
+	if TheBatchInserter != nil {
+		TheBatchInserter.batches = nil
+	}
+	if TheImmediateInserter != nil && TheImmediateInserter.txn != nil {
+		err := TheImmediateInserter.txn.Rollback()
+		if err != nil {
+			log.Warn().Err(err).Msg("Rolling back pending block")
+		}
+		TheImmediateInserter.txn = nil
+	}
+}
@@ -0,0 +1,379 @@
+package db
+
+import (
+	"context"
+	"crypto/md5"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"gitlab.com/thorchain/midgard/config"
+)
+
+// Schema changes are done with migrations on top of the baseline DDL (ddl.sql for the core and
+// aggregates.sql, balances.sql, members.sql and the registered aggregates for the aggregates).
+//
+// Migrations are the files migrations/<track>/<version>_<name>.sql, they are applied in version
+// order and are recorded in the schema_migrations table of the track's schema. Every migration is
+// applied in its own transaction.
+//
+// The first line of the file can contain flags:
+//   -- midgard:needs-reset       the schema is recreated before applying the migration (e.g. when
+//                                the already stored data needs to change)
+//   -- midgard:no-transaction    the migration is not run in a transaction (e.g. for
+//                                `CREATE INDEX CONCURRENTLY`)
+//
+// If the baseline DDL changes the schema is recreated and all migrations are applied, as before
+// migrations existed.
+//
+// Instances sharing the DB (see writerlock.go) migrate one after the other, holding the
+// migrationLockKey advisory lock. A schema is not reset while an other instance holds the
+// writer lock, it would drop the tables from under the writer.
+
+//go:embed migrations
+var migrationFiles embed.FS
+
+const (
+	needsResetFlag    = "midgard:needs-reset"
+	noTransactionFlag = "midgard:no-transaction"
+)
+
+const migrationLockKey = 0x4d494752 // "MIGR"
+
+// Runs f holding the migration lock, waits while an other instance is migrating.
+func withMigrationLock(dbObj *sql.DB, f func()) {
+	ctx := context.Background()
+	conn, err := dbObj.Conn(ctx)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Opening a connection for the migration lock failed")
+	}
+	defer conn.Close()
+	_, err = conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", migrationLockKey)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Taking the migration lock failed")
+	}
+	defer func() {
+		_, err := conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", migrationLockKey)
+		if err != nil {
+			log.Warn().Err(err).Msg("Releasing the migration lock")
+		}
+	}()
+	f()
+}
+
+type Migration struct {
+	Version       int
+	Name          string
+	Up            string
+	NeedsReset    bool
+	NoTransaction bool
+}
+
+type AppliedMigration struct {
+	Version   int
+	Name      string
+	AppliedAt time.Time
+}
+
+type migrationTrack struct {
+	tag string
+	// Directory of the migration files under migrations/.
+	dir      string
+	schema   string
+	baseline func() []string
+	hashKey  string
+}
+
+var (
+	coreTrack       = migrationTrack{"data", "core", "midgard", CoreDDL, ddlHashKey}
+	aggregatesTrack = migrationTrack{
+		"aggregates", "aggregates", "midgard_agg", AggregatesDDL, aggregatesDdlHashKey}
+
+	// The core track comes first, a core reset also resets the aggregates.
+	migrationTracks = []migrationTrack{coreTrack, aggregatesTrack}
+)
+
+func (track migrationTrack) migrations() ([]Migration, error) {
+	dir := path.Join("migrations", track.dir)
+	entries, err := fs.ReadDir(migrationFiles, dir)
+	if err != nil {
+		// A missing directory would silently skip all the migrations of the track.
+		return nil, fmt.Errorf("reading %s migrations: %w", track.tag, err)
+	}
+
+	ret := []Migration{}
+	versions := map[int]string{}
+	for _, entry := range entries {
+		fileName := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(fileName, ".sql") {
+			continue
+		}
+		base := strings.TrimSuffix(fileName, ".sql")
+		versionStr, name, ok := strings.Cut(base, "_")
+		if !ok {
+			return nil, fmt.Errorf("migration file name should be <version>_<name>.sql: %s", fileName)
+		}
+		version, err := strconv.Atoi(versionStr)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("bad migration version in %s", fileName)
+		}
+		if other, ok := versions[version]; ok {
+			return nil, fmt.Errorf("duplicate migration version %d: %s, %s", version, other, fileName)
+		}
+		versions[version] = fileName
+
+		content, err := migrationFiles.ReadFile(path.Join(dir, fileName))
+		if err != nil {
+			return nil, err
+		}
+		firstLine, _, _ := strings.Cut(string(content), "\n")
+		ret = append(ret, Migration{
+			Version:       version,
+			Name:          name,
+			Up:            string(content),
+			NeedsReset:    strings.Contains(firstLine, needsResetFlag),
+			NoTransaction: strings.Contains(firstLine, noTransactionFlag),
+		})
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Version < ret[j].Version })
+	return ret, nil
+}
+
+func (track migrationTrack) baselineHash() md5Hash {
+	return md5.Sum([]byte(strings.Join(track.baseline(), "")))
+}
+
+func (track migrationTrack) createMigrationsTable(dbObj *sql.DB) error {
+	_, err := dbObj.Exec(`CREATE TABLE IF NOT EXISTS ` + track.schema + `.schema_migrations (
+		version     INT PRIMARY KEY,
+		name        TEXT NOT NULL,
+		applied_at  BIGINT NOT NULL
+	)`)
+	return err
+}
+
+// Returns the applied migrations, nil if the schema is not set up yet.
+func (track migrationTrack) applied(dbObj *sql.DB) ([]AppliedMigration, error) {
+	var exists bool
+	err := dbObj.QueryRow(`SELECT EXISTS (
+		SELECT * FROM pg_tables WHERE tablename = 'schema_migrations' AND schemaname = $1
+	)`, track.schema).Scan(&exists)
+	if err != nil || !exists {
+		return nil, err
+	}
+
+	rows, err := dbObj.Query(`SELECT version, name, applied_at FROM ` + track.schema +
+		`.schema_migrations ORDER BY version`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ret := []AppliedMigration{}
+	for rows.Next() {
+		var m AppliedMigration
+		var appliedAt Nano
+		err = rows.Scan(&m.Version, &m.Name, &appliedAt)
+		if err != nil {
+			return nil, err
+		}
+		m.AppliedAt = appliedAt.ToTime()
+		ret = append(ret, m)
+	}
+	return ret, rows.Err()
+}
+
+type MigrationStatus struct {
+	Tag string
+	// The baseline DDL differs from the one the schema was created with.
+	BaselineChanged bool
+	Applied         []AppliedMigration
+	Pending         []Migration
+}
+
+// Whether the schema has to be recreated to bring it up to date.
+func (s MigrationStatus) NeedsReset() bool {
+	if s.BaselineChanged {
+		return true
+	}
+	for _, m := range s.Pending {
+		if m.NeedsReset {
+			return true
+		}
+	}
+	return false
+}
+
+func (track migrationTrack) status(dbObj *sql.DB) (ret MigrationStatus, err error) {
+	ret.Tag = track.tag
+	ret.BaselineChanged = track.baselineHash() != liveDDLHash(dbObj, track.hashKey)
+
+	migrations, err := track.migrations()
+	if err != nil {
+		return ret, err
+	}
+	if ret.BaselineChanged {
+		ret.Pending = migrations
+		return ret, nil
+	}
+
+	ret.Applied, err = track.applied(dbObj)
+	if err != nil {
+		return ret, err
+	}
+	applied := map[int]bool{}
+	for _, m := range ret.Applied {
+		applied[m.Version] = true
+	}
+	for _, m := range migrations {
+		if !applied[m.Version] {
+			ret.Pending = append(ret.Pending, m)
+		}
+	}
+	return ret, nil
+}
+
+// Returns the migration status of the core and the aggregates schemas.
+// If the core schema is going to be reset the aggregates are reset too.
+func MigrationStatuses(dbObj *sql.DB) ([]MigrationStatus, error) {
+	ret := make([]MigrationStatus, 0, len(migrationTracks))
+	for _, track := range migrationTracks {
+		status, err := track.status(dbObj)
+		if err != nil {
+			return nil, fmt.Errorf("%s migration status: %w", track.tag, err)
+		}
+		if len(ret) != 0 && ret[0].NeedsReset() && !status.BaselineChanged {
+			status.BaselineChanged = true
+			status.Applied = nil
+			status.Pending, err = track.migrations()
+			if err != nil {
+				return nil, fmt.Errorf("%s migration status: %w", track.tag, err)
+			}
+		}
+		ret = append(ret, status)
+	}
+	return ret, nil
+}
+
+func (track migrationTrack) reset(dbObj *sql.DB) {
+	log.Info().Msgf("Applying new %s ddl...", track.tag)
+	for _, part := range track.baseline() {
+		log.Info().Msgf("Applying %s", part)
+		_, err := dbObj.Exec(part)
+		if err != nil {
+			log.Fatal().Err(err).Msgf("Applying new %s ddl failed, exiting", track.tag)
+		}
+	}
+	fileDdlHash := track.baselineHash()
+	_, err := dbObj.Exec(`INSERT INTO constants (key, value) VALUES ($1, $2)
+						 ON CONFLICT (key) DO UPDATE SET value = $2`,
+		track.hashKey, fileDdlHash[:])
+	if err != nil {
+		log.Fatal().Err(err).Msg("Updating 'constants' table failed, exiting")
+	}
+	log.Info().Msgf("Successfully applied new %s schema", track.tag)
+}
+
+func (track migrationTrack) apply(dbObj *sql.DB, m Migration) error {
+	record := `INSERT INTO ` + track.schema + `.schema_migrations (version, name, applied_at)
+		VALUES ($1, $2, $3)`
+	now := time.Now().UnixNano()
+
+	if m.NoTransaction {
+		_, err := dbObj.Exec(m.Up)
+		if err != nil {
+			return err
+		}
+		_, err = dbObj.Exec(record, m.Version, m.Name, now)
+		return err
+	}
+
+	tx, err := dbObj.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	_, err = tx.Exec(m.Up)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(record, m.Version, m.Name, now)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Brings the schema of the track up to date, resetting it if needed.
+// If `noReset` is set and the schema would need to be reset it exits with error instead. A schema
+// which doesn't exist yet is always created.
+func (track migrationTrack) migrateUp(dbObj *sql.DB, noReset bool) {
+	status, err := track.status(dbObj)
+	if err != nil {
+		log.Fatal().Err(err).Msgf("Reading %s migrations failed", track.tag)
+	}
+
+	if status.NeedsReset() {
+		if status.BaselineChanged {
+			log.Info().Msgf("DDL hash mismatch for %s", track.tag)
+		} else {
+			log.Info().Msgf("Pending %s migration needs a schema reset", track.tag)
+		}
+		if noReset && (liveDDLHash(dbObj, track.hashKey) != md5Hash{}) {
+			log.Fatal().Msg(
+				"DDL update prohibited in config. You can manually force it with cmd/nukedb")
+		}
+		// This instance takes the writer lock only after the migrations.
+		held, err := WriterLockHeld(dbObj, config.Global.WriterLock.Key)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Checking the writer lock failed")
+		}
+		if held {
+			log.Fatal().Msgf("Resetting the %s schema while an other instance holds the writer "+
+				"lock, stop the other instances first", track.tag)
+		}
+		track.reset(dbObj)
+		status, err = track.status(dbObj)
+		if err != nil {
+			log.Fatal().Err(err).Msgf("Reading %s migrations failed", track.tag)
+		}
+	}
+
+	err = track.createMigrationsTable(dbObj)
+	if err != nil {
+		log.Fatal().Err(err).Msgf("Creating %s migrations table failed", track.tag)
+	}
+	for _, m := range status.Pending {
+		log.Info().Msgf("Applying %s migration %d_%s", track.tag, m.Version, m.Name)
+		err = track.apply(dbObj, m)
+		if err != nil {
+			log.Fatal().Err(err).Msgf(
+				"Applying %s migration %d_%s failed, exiting", track.tag, m.Version, m.Name)
+		}
+	}
+}
+
+// Identifies the schema the code expects: the baseline hash and the last migration per track.
+// Used to check that a database snapshot can be loaded.
+func SchemaFingerprint() (map[string]string, error) {
+	ret := map[string]string{}
+	for _, track := range migrationTracks {
+		migrations, err := track.migrations()
+		if err != nil {
+			return nil, err
+		}
+		last := 0
+		if len(migrations) != 0 {
+			last = migrations[len(migrations)-1].Version
+		}
+		ret[track.tag] = fmt.Sprintf("%x:%d", track.baselineHash(), last)
+	}
+	return ret, nil
+}
@@ -0,0 +1,33 @@
+-- First swap of every address, updated incrementally by the aggregates refresh. The new swappers
+-- of /v2/history/users are looked up here instead of scanning all of swappers_5min.
+CREATE TABLE midgard_agg.first_swaps (
+    from_addr           TEXT NOT NULL PRIMARY KEY,
+    block_timestamp     BIGINT NOT NULL
+);
+
+INSERT INTO midgard_agg.watermarks (materialized_table, watermark)
+VALUES ('first_swaps', 0);
+
+-- Only the counted leg of the swaps, as countedSwapLeg in stat/swap.go.
+CREATE PROCEDURE midgard_agg.update_first_swaps(w_new bigint)
+    LANGUAGE plpgsql AS $BODY$
+DECLARE
+w_old bigint;
+BEGIN
+SELECT watermark FROM midgard_agg.watermarks WHERE materialized_table = 'first_swaps'
+    FOR UPDATE INTO w_old;
+IF w_new <= w_old THEN
+        RAISE WARNING 'Updating first swaps into past: % -> %', w_old, w_new;
+        RETURN;
+END IF;
+INSERT INTO midgard_agg.first_swaps (
+    SELECT from_addr, MIN(block_timestamp)
+    FROM swap_events
+    WHERE w_old <= block_timestamp AND block_timestamp < w_new
+        AND (from_asset <> 'THOR.RUNE' OR chain = 'THOR')
+    GROUP BY from_addr
+)
+ON CONFLICT (from_addr) DO NOTHING;
+UPDATE midgard_agg.watermarks SET watermark = w_new WHERE materialized_table = 'first_swaps';
+END
+$BODY$;
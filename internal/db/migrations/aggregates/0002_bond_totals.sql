@@ -0,0 +1,45 @@
+-- Total bond of all nodes after every block with bond events, updated incrementally by the
+-- aggregates refresh. The churns look up the bond at their heights here instead of summing all
+-- of bond_events.
+CREATE TABLE midgard_agg.bond_totals (
+    block_timestamp     BIGINT NOT NULL PRIMARY KEY,
+    change_e8           BIGINT NOT NULL,
+    total_e8            BIGINT NOT NULL
+);
+
+INSERT INTO midgard_agg.watermarks (materialized_table, watermark)
+VALUES ('bond_totals', 0);
+
+-- The signed bond change is signedBondExpression in stat/nodehistory.go.
+CREATE PROCEDURE midgard_agg.update_bond_totals(w_new bigint)
+    LANGUAGE plpgsql AS $BODY$
+DECLARE
+w_old bigint;
+total bigint;
+BEGIN
+SELECT watermark FROM midgard_agg.watermarks WHERE materialized_table = 'bond_totals'
+    FOR UPDATE INTO w_old;
+IF w_new <= w_old THEN
+        RAISE WARNING 'Updating bond totals into past: % -> %', w_old, w_new;
+        RETURN;
+END IF;
+SELECT COALESCE(
+    (SELECT total_e8 FROM midgard_agg.bond_totals ORDER BY block_timestamp DESC LIMIT 1), 0)
+    INTO total;
+INSERT INTO midgard_agg.bond_totals (
+    SELECT block_timestamp, change_e8,
+        total + SUM(change_e8) OVER (ORDER BY block_timestamp)
+    FROM (
+        SELECT block_timestamp, SUM(CASE
+                WHEN bond_type IN ('bond_paid', 'bond_reward') THEN e8
+                WHEN bond_type IN ('bond_returned', 'bond_cost') THEN -e8
+                ELSE 0
+                END)::BIGINT AS change_e8
+        FROM bond_events
+        WHERE w_old <= block_timestamp AND block_timestamp < w_new
+        GROUP BY block_timestamp
+    ) AS changes
+);
+UPDATE midgard_agg.watermarks SET watermark = w_new WHERE materialized_table = 'bond_totals';
+END
+$BODY$;
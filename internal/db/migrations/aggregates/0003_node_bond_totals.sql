@@ -0,0 +1,54 @@
+-- Bond of every node after every block in which it changed, updated incrementally by the
+-- aggregates refresh. The node history starts from here, bond_events before the retained window
+-- are deleted in lite mode.
+CREATE TABLE midgard_agg.node_bond_totals (
+    node_addr           TEXT NOT NULL,
+    block_timestamp     BIGINT NOT NULL,
+    bond_e8             BIGINT NOT NULL,
+    PRIMARY KEY (node_addr, block_timestamp)
+);
+
+INSERT INTO midgard_agg.watermarks (materialized_table, watermark)
+VALUES ('node_bond_totals', 0);
+
+-- The node and the signed bond change are bondNodeExpression and signedBondExpression in
+-- stat/nodehistory.go.
+CREATE PROCEDURE midgard_agg.update_node_bond_totals(w_new bigint)
+    LANGUAGE plpgsql AS $BODY$
+DECLARE
+w_old bigint;
+BEGIN
+SELECT watermark FROM midgard_agg.watermarks WHERE materialized_table = 'node_bond_totals'
+    FOR UPDATE INTO w_old;
+IF w_new <= w_old THEN
+        RAISE WARNING 'Updating node bond totals into past: % -> %', w_old, w_new;
+        RETURN;
+END IF;
+INSERT INTO midgard_agg.node_bond_totals (
+    SELECT c.node_addr, c.block_timestamp,
+        COALESCE((
+            SELECT p.bond_e8 FROM midgard_agg.node_bond_totals AS p
+            WHERE p.node_addr = c.node_addr
+            ORDER BY p.block_timestamp DESC LIMIT 1), 0)
+        + SUM(c.change_e8) OVER (PARTITION BY c.node_addr ORDER BY c.block_timestamp)
+    FROM (
+        SELECT
+            (CASE
+                WHEN memo ~* '^(BOND|UNBOND|LEAVE):' THEN SPLIT_PART(memo, ':', 2)
+                ELSE COALESCE(from_addr, to_addr)
+                END) AS node_addr,
+            block_timestamp,
+            SUM(CASE
+                WHEN bond_type IN ('bond_paid', 'bond_reward') THEN e8
+                WHEN bond_type IN ('bond_returned', 'bond_cost') THEN -e8
+                ELSE 0
+                END)::BIGINT AS change_e8
+        FROM bond_events
+        WHERE w_old <= block_timestamp AND block_timestamp < w_new
+        GROUP BY 1, 2
+    ) AS c
+    WHERE c.node_addr IS NOT NULL
+);
+UPDATE midgard_agg.watermarks SET watermark = w_new WHERE materialized_table = 'node_bond_totals';
+END
+$BODY$;
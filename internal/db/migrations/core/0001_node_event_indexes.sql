@@ -0,0 +1,7 @@
+-- Indexes for the node timeline (/v2/node/:addr/history).
+CREATE INDEX IF NOT EXISTS update_node_account_status_events_node_addr_idx
+    ON update_node_account_status_events (node_addr, block_timestamp DESC);
+CREATE INDEX IF NOT EXISTS set_version_events_node_addr_idx
+    ON set_version_events (node_addr, block_timestamp DESC);
+CREATE INDEX IF NOT EXISTS set_ip_address_events_node_addr_idx
+    ON set_ip_address_events (node_addr, block_timestamp DESC);
@@ -0,0 +1,68 @@
+-- Pool state written by the lp_consumer command from the Kafka topics. The Midgard writer doesn't
+-- use these tables, cmd/checks/lpconsumer compares them with block_pool_depths.
+
+-- Latest state of the pools. (height, event_offset) is the last event applied, replayed events
+-- are skipped with it.
+CREATE TABLE IF NOT EXISTS lp_pools (
+    pool                TEXT NOT NULL PRIMARY KEY,
+    asset_e8            BIGINT NOT NULL,
+    rune_e8             BIGINT NOT NULL,
+    synth_e8            BIGINT NOT NULL,
+    units               BIGINT NOT NULL,
+    add_count           BIGINT NOT NULL,
+    withdraw_count      BIGINT NOT NULL,
+    swap_count          BIGINT NOT NULL,
+    height              BIGINT NOT NULL,
+    event_offset        SMALLINT NOT NULL,
+    block_timestamp     BIGINT NOT NULL
+);
+
+-- Depths at the end of the blocks which changed the pool, like block_pool_depths.
+CREATE TABLE IF NOT EXISTS lp_pool_depths (
+    pool                TEXT NOT NULL,
+    asset_e8            BIGINT NOT NULL,
+    rune_e8             BIGINT NOT NULL,
+    synth_e8            BIGINT NOT NULL,
+    units               BIGINT NOT NULL,
+    height              BIGINT NOT NULL,
+    block_timestamp     BIGINT NOT NULL,
+    UNIQUE (pool, block_timestamp)
+);
+
+CALL setup_hypertable('lp_pool_depths');
+
+-- Liquidity provider positions. member_id is the RUNE address, or the asset address for
+-- asymmetric asset only providers.
+CREATE TABLE IF NOT EXISTS lp_members (
+    pool                    TEXT NOT NULL,
+    member_id               TEXT NOT NULL,
+    asset_addr              TEXT,
+    units                   BIGINT NOT NULL,
+    asset_added_e8          BIGINT NOT NULL,
+    rune_added_e8           BIGINT NOT NULL,
+    asset_withdrawn_e8      BIGINT NOT NULL,
+    rune_withdrawn_e8       BIGINT NOT NULL,
+    first_added_timestamp   BIGINT NOT NULL,
+    last_change_timestamp   BIGINT NOT NULL,
+    PRIMARY KEY (pool, member_id)
+);
+
+CREATE INDEX IF NOT EXISTS lp_members_asset_addr_idx ON lp_members (pool, asset_addr);
+
+-- Activity per pool in 5 minute buckets, bucket_start is in nanoseconds.
+CREATE TABLE IF NOT EXISTS lp_pool_stats (
+    pool                    TEXT NOT NULL,
+    bucket_start            BIGINT NOT NULL,
+    add_count               BIGINT NOT NULL DEFAULT 0,
+    add_asset_e8            BIGINT NOT NULL DEFAULT 0,
+    add_rune_e8             BIGINT NOT NULL DEFAULT 0,
+    add_asset_in_rune_e8    BIGINT NOT NULL DEFAULT 0,
+    withdraw_count          BIGINT NOT NULL DEFAULT 0,
+    withdraw_asset_e8       BIGINT NOT NULL DEFAULT 0,
+    withdraw_rune_e8        BIGINT NOT NULL DEFAULT 0,
+    swap_count              BIGINT NOT NULL DEFAULT 0,
+    swap_volume_rune_e8     BIGINT NOT NULL DEFAULT 0,
+    swap_fees_rune_e8       BIGINT NOT NULL DEFAULT 0,
+    rewards_e8              BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (pool, bucket_start)
+);
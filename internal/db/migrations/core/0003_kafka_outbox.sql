@@ -0,0 +1,9 @@
+-- Messages of the Kafka sink (config kafka.sink). They are inserted with the rows of their
+-- block, so only committed blocks are published, and deleted once published.
+CREATE TABLE IF NOT EXISTS kafka_outbox (
+    id                  BIGSERIAL PRIMARY KEY,
+    topic               TEXT NOT NULL,
+    msg_key             TEXT NOT NULL,
+    value               BYTEA NOT NULL,
+    height              BIGINT NOT NULL
+);
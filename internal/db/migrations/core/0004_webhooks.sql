@@ -0,0 +1,40 @@
+-- Webhooks registered with the admin API, the ones in the config (webhooks.hooks) are not
+-- stored.
+CREATE TABLE IF NOT EXISTS webhooks (
+    id                  TEXT NOT NULL PRIMARY KEY,
+    url                 TEXT NOT NULL,
+    secret              TEXT NOT NULL,
+    filter              JSONB NOT NULL,
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Timestamp of the last block whose actions and pool status changes were queued. One row.
+CREATE TABLE IF NOT EXISTS webhook_cursor (
+    id                  BOOLEAN NOT NULL PRIMARY KEY DEFAULT TRUE CHECK (id),
+    block_timestamp     BIGINT NOT NULL
+);
+
+-- Payloads waiting to be delivered, every webhook gets them in id order.
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id                  BIGSERIAL PRIMARY KEY,
+    webhook_id          TEXT NOT NULL,
+    height              BIGINT NOT NULL,
+    payload             BYTEA NOT NULL,
+    attempts            INT NOT NULL DEFAULT 0,
+    next_attempt        TIMESTAMPTZ NOT NULL DEFAULT now(),
+    last_error          TEXT
+);
+
+CREATE INDEX IF NOT EXISTS webhook_deliveries_webhook_idx ON webhook_deliveries (webhook_id, id);
+
+-- Payloads which failed max_attempts times.
+CREATE TABLE IF NOT EXISTS webhook_dead_letters (
+    id                  BIGINT NOT NULL PRIMARY KEY,
+    webhook_id          TEXT NOT NULL,
+    url                 TEXT NOT NULL,
+    height              BIGINT NOT NULL,
+    payload             BYTEA NOT NULL,
+    attempts            INT NOT NULL,
+    last_error          TEXT,
+    failed_at           TIMESTAMPTZ NOT NULL DEFAULT now()
+);
@@ -0,0 +1,14 @@
+-- Indexes for finding the last row per key when pruning old rows (retention.days), see
+-- lastRowKeys in retention.go. block_pool_depths and the node tables of 0001 have one already.
+CREATE INDEX IF NOT EXISTS pool_events_asset_idx
+    ON pool_events (asset, block_timestamp DESC);
+CREATE INDEX IF NOT EXISTS set_mimir_events_key_idx
+    ON set_mimir_events (key, block_timestamp DESC);
+CREATE INDEX IF NOT EXISTS set_node_mimir_address_key_idx
+    ON set_node_mimir (address, key, block_timestamp DESC);
+CREATE INDEX IF NOT EXISTS new_node_events_node_addr_idx
+    ON new_node_events (node_addr, block_timestamp DESC);
+CREATE INDEX IF NOT EXISTS set_node_keys_events_node_addr_idx
+    ON set_node_keys_events (node_addr, block_timestamp DESC);
+CREATE INDEX IF NOT EXISTS thorname_change_events_name_chain_idx
+    ON thorname_change_events (name, chain, block_timestamp DESC);
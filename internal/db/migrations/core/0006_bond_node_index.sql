@@ -0,0 +1,8 @@
+-- Index for looking up the bond events of a node (/v2/node/:addr/history and
+-- /v2/history/node/:addr). The expression has to match bondNodeExpression in
+-- internal/timeseries/stat/nodehistory.go exactly, otherwise the planner doesn't use it.
+CREATE INDEX IF NOT EXISTS bond_events_node_idx
+    ON bond_events ((CASE
+		WHEN memo ~* '^(BOND|UNBOND|LEAVE):' THEN SPLIT_PART(memo, ':', 2)
+		ELSE COALESCE(from_addr, to_addr)
+		END), block_timestamp DESC);
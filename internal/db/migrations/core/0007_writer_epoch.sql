@@ -0,0 +1,9 @@
+-- Term of the writer instance, incremented by every instance taking the writer lock. The block
+-- commits lock the row and check that the term is still theirs (see internal/db/writerlock.go).
+-- One row.
+CREATE TABLE IF NOT EXISTS writer_epoch (
+    id                  BOOLEAN NOT NULL PRIMARY KEY DEFAULT TRUE CHECK (id),
+    epoch               BIGINT NOT NULL
+);
+
+INSERT INTO writer_epoch (epoch) VALUES (0) ON CONFLICT DO NOTHING;
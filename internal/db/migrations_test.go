@@ -0,0 +1,38 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrationFiles(t *testing.T) {
+	for _, track := range migrationTracks {
+		migrations, err := track.migrations()
+		require.NoError(t, err, track.tag)
+		for i, m := range migrations {
+			require.NotEmpty(t, m.Name)
+			require.NotEmpty(t, m.Up)
+			if 0 < i {
+				require.Less(t, migrations[i-1].Version, m.Version)
+			}
+		}
+	}
+
+	core, err := coreTrack.migrations()
+	require.NoError(t, err)
+	require.NotEmpty(t, core)
+	require.Equal(t, 1, core[0].Version)
+	require.Equal(t, "node_event_indexes", core[0].Name)
+	require.False(t, core[0].NeedsReset)
+}
+
+func TestMigrationStatusNeedsReset(t *testing.T) {
+	status := MigrationStatus{Pending: []Migration{{Version: 1}}}
+	require.False(t, status.NeedsReset())
+
+	status.Pending = append(status.Pending, Migration{Version: 2, NeedsReset: true})
+	require.True(t, status.NeedsReset())
+
+	require.True(t, MigrationStatus{BaselineChanged: true}.NeedsReset())
+}
@@ -0,0 +1,51 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pascaldekloe/metrics"
+)
+
+var queryDuration = metrics.Must1LabelHistogram(
+	"midgard_db_query_duration_seconds", "query",
+	0.001, 0.003, 0.01, 0.03, 0.1, 0.3, 1, 3, 10, 30)
+
+func init() {
+	metrics.MustHelp("midgard_db_query_duration_seconds",
+		"Time until the first row of the queries by the function running them.")
+}
+
+// Names of the functions calling Query by program counter.
+var queryNames sync.Map
+
+// Returns the function calling Query, e.g. "timeseries.GetTHORName".
+func queryName() string {
+	pc, _, _, ok := runtime.Caller(2)
+	if !ok {
+		return "unknown"
+	}
+	if name, ok := queryNames.Load(pc); ok {
+		return name.(string)
+	}
+	name := "unknown"
+	if f := runtime.FuncForPC(pc); f != nil {
+		name = f.Name()
+		name = name[strings.LastIndex(name, "/")+1:]
+	}
+	queryNames.Store(pc, name)
+	return name
+}
+
+func timedQuery(
+	query func(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error),
+) func(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return func(ctx context.Context, q string, args ...interface{}) (*sql.Rows, error) {
+		defer queryDuration(queryName()).AddSince(time.Now())
+		return query(ctx, q, args...)
+	}
+}
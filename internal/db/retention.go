@@ -0,0 +1,338 @@
+package db
+
+// Lite mode: raw event rows older than `config.Global.Retention.Days` are deleted periodically.
+//
+// Kept regardless of their age:
+//   - the continuous aggregates, the tables they are computed from are trimmed with drop_chunks,
+//     which doesn't invalidate the aggregates (a DELETE would, and the next refresh would empty
+//     the old buckets),
+//   - midgard_agg: members, balances, actions and the other materialized tables,
+//   - block_log, the current state is restored from its last agg_state,
+//   - the last row of the tables which hold a state (see lastRowKeys) and pending liquidity
+//     which is still pending. For the hypertables with aggregates, which lose whole chunks, the
+//     last rows in the dropped range are copied forward to the first block after the cutoff.
+//
+// The API rejects ranges which start before the retained window, see CheckRetained and
+// CheckRetainedList. The bond of the nodes is kept in midgard_agg.node_bond_totals.
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"gitlab.com/thorchain/midgard/config"
+	"gitlab.com/thorchain/midgard/internal/util/jobs"
+	"gitlab.com/thorchain/midgard/internal/util/miderr"
+	"gitlab.com/thorchain/midgard/internal/util/timer"
+)
+
+var pruneTimer = timer.NewTimer("retention_prune")
+
+// The last row for every distinct value of these columns is kept.
+// Empty key list means the last row of the table is kept.
+var lastRowKeys = map[string][]string{
+	"block_pool_depths":                 {"pool"},
+	"rune_price_usd":                    {},
+	"pool_events":                       {"asset"},
+	"set_mimir_events":                  {"key"},
+	"set_node_mimir":                    {"address", "key"},
+	"new_node_events":                   {"node_addr"},
+	"update_node_account_status_events": {"node_addr"},
+	"set_version_events":                {"node_addr"},
+	"set_ip_address_events":             {"node_addr"},
+	"set_node_keys_events":              {"node_addr"},
+	"thorname_change_events":            {"name", "chain"},
+}
+
+// Additional conditions for rows to keep, `r` is the row being deleted.
+var keepConditions = map[string]string{
+	"pending_liquidity_events": `EXISTS (
+		SELECT 1 FROM midgard_agg.pending_adds AS p
+		WHERE p.pool = r.pool AND p.rune_addr = r.rune_addr
+			AND p.block_timestamp = r.block_timestamp)`,
+}
+
+func retentionEnabled() bool {
+	return 0 < config.Global.Retention.Days
+}
+
+func retentionPeriod() Second {
+	return Second(config.Global.Retention.Days) * 86400
+}
+
+// Returns the start of the window for which the raw events are kept, 0 if everything is kept.
+func RetainedFrom() Second {
+	if !retentionEnabled() {
+		return 0
+	}
+	return NowSecond() - retentionPeriod()
+}
+
+// Returns a BadRequest error if `from` is before the retained window.
+func CheckRetained(from Second) miderr.Err {
+	retainedFrom := RetainedFrom()
+	if from < retainedFrom {
+		return miderr.BadRequestF(
+			"Requested range starts at %d, before the retained window. "+
+				"This Midgard keeps only the last %d days of history, from %d",
+			from, config.Global.Retention.Days, retainedFrom)
+	}
+	return nil
+}
+
+// Returns a BadRequest error if a list without a time range (a page of a newest first list, a full
+// history) reaches back to `oldest`, before the retained window. Rows of it may be deleted.
+func CheckRetainedList(oldest Nano) miderr.Err {
+	retainedFrom := RetainedFrom()
+	if oldest.ToSecond() < retainedFrom {
+		return miderr.BadRequestF(
+			"Requested rows reach back to %d, before the retained window. "+
+				"This Midgard keeps only the last %d days of history, from %d",
+			oldest.ToSecond(), config.Global.Retention.Days, retainedFrom)
+	}
+	return nil
+}
+
+// Returns the default start of ranges: the first block, or the start of the retained window
+// if that's later.
+func firstAvailableSecond() Second {
+	first := FirstBlock.Get().Timestamp.ToSecond()
+	if retainedFrom := RetainedFrom(); first < retainedFrom {
+		return retainedFrom
+	}
+	return first
+}
+
+// Rows before the returned timestamp can be deleted.
+// The aggregates have to be computed for the deleted range.
+func pruneCutoff() Nano {
+	cutoff := LastCommittedBlock.Get().Timestamp - retentionPeriod().ToNano()
+	if lastAggregated := LastAggregatedBlock.Get().Timestamp; lastAggregated < cutoff {
+		cutoff = lastAggregated
+	}
+	return cutoff
+}
+
+type prunedTable struct {
+	name          string
+	hypertable    bool
+	hasAggregates bool
+}
+
+func prunedTables(ctx context.Context) ([]prunedTable, error) {
+	aggregated := map[string]bool{}
+	for _, agg := range aggregates {
+		aggregated[agg.table] = true
+	}
+
+	rows, err := Query(ctx, `
+		SELECT c.table_name, h.hypertable_name IS NOT NULL
+		FROM information_schema.columns AS c
+		JOIN information_schema.tables AS t
+			ON t.table_schema = c.table_schema AND t.table_name = c.table_name
+		LEFT JOIN timescaledb_information.hypertables AS h
+			ON h.hypertable_schema = c.table_schema AND h.hypertable_name = c.table_name
+		WHERE c.table_schema = 'midgard' AND c.column_name = 'block_timestamp'
+			AND t.table_type = 'BASE TABLE'
+		ORDER BY c.table_name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ret := []prunedTable{}
+	for rows.Next() {
+		var t prunedTable
+		err = rows.Scan(&t.name, &t.hypertable)
+		if err != nil {
+			return nil, err
+		}
+		t.hasAggregates = aggregated[t.name]
+		ret = append(ret, t)
+	}
+	return ret, rows.Err()
+}
+
+// Returns the condition which is true if `r` is the last row of its key, "" if the table doesn't
+// keep its last rows.
+func lastRowCondition(table string) string {
+	keys, ok := lastRowKeys[table]
+	if !ok {
+		return ""
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "NOT EXISTS (SELECT 1 FROM %s AS later WHERE ", table)
+	for _, key := range keys {
+		fmt.Fprintf(&b, "later.%s = r.%s AND ", key, key)
+	}
+	fmt.Fprint(&b, "r.block_timestamp < later.block_timestamp)")
+	return b.String()
+}
+
+// Returns the condition which is true for the rows which can't be deleted, "" if there is none.
+func keepCondition(table string) string {
+	conditions := []string{}
+	if condition := lastRowCondition(table); condition != "" {
+		conditions = append(conditions, condition)
+	}
+	if condition, ok := keepConditions[table]; ok {
+		conditions = append(conditions, condition)
+	}
+	return strings.Join(conditions, " OR ")
+}
+
+func tableColumns(ctx context.Context, table string) ([]string, error) {
+	rows, err := Query(ctx, `
+		SELECT column_name FROM information_schema.columns
+		WHERE table_schema = 'midgard' AND table_name = $1
+		ORDER BY ordinal_position`, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	ret := []string{}
+	for rows.Next() {
+		var column string
+		if err := rows.Scan(&column); err != nil {
+			return nil, err
+		}
+		ret = append(ret, column)
+	}
+	return ret, rows.Err()
+}
+
+// Copies the last rows before the cutoff to the first block after it, so they survive dropping
+// the chunks. A key which stopped changing (e.g. a suspended pool) doesn't hold back the pruning
+// this way. Returns false if there is no block after the cutoff yet.
+//
+// The copy invalidates the aggregate buckets it falls into, they are recomputed from the raw rows
+// at the next refresh. The cutoff has to be at a bucket start of the largest continuous aggregate,
+// so those buckets don't contain dropped rows.
+func copyLastRowsForward(ctx context.Context, table string, cutoff Nano) (bool, error) {
+	var to sql.NullInt64
+	err := TheDB.QueryRowContext(ctx,
+		"SELECT MIN(timestamp) FROM block_log WHERE $1 <= timestamp", cutoff).Scan(&to)
+	if err != nil || !to.Valid {
+		return false, err
+	}
+
+	columns, err := tableColumns(ctx, table)
+	if err != nil {
+		return false, err
+	}
+	values := make([]string, len(columns))
+	for i, column := range columns {
+		values[i] = "r." + column
+		if column == "block_timestamp" {
+			values[i] = "$2"
+		}
+	}
+	res, err := TheDB.ExecContext(ctx, fmt.Sprintf(
+		"INSERT INTO %s (%s) SELECT %s FROM %s AS r WHERE r.block_timestamp < $1 AND %s",
+		table, strings.Join(columns, ", "), strings.Join(values, ", "), table,
+		lastRowCondition(table)),
+		cutoff, to.Int64)
+	if err != nil {
+		return false, err
+	}
+	if n, err := res.RowsAffected(); err == nil && n != 0 {
+		log.Debug().Int64("rows", n).Msgf("Copied the last rows of %s forward", table)
+	}
+	return true, nil
+}
+
+// Returns the start of the bucket of the largest continuous aggregate containing t.
+func aggregateBucketStart(t Nano) Nano {
+	var largest Second
+	for _, interval := range intervals {
+		if interval.exact && largest < interval.minDuration {
+			largest = interval.minDuration
+		}
+	}
+	return t - t%largest.ToNano()
+}
+
+func pruneTable(ctx context.Context, t prunedTable, cutoff Nano) error {
+	keep := keepCondition(t.name)
+
+	if t.hypertable && t.hasAggregates {
+		if _, ok := lastRowKeys[t.name]; ok {
+			cutoff = aggregateBucketStart(cutoff)
+			copied, err := copyLastRowsForward(ctx, t.name, cutoff)
+			if err != nil || !copied {
+				return err
+			}
+		}
+		_, err := TheDB.ExecContext(ctx,
+			"SELECT drop_chunks($1, older_than => $2::BIGINT)", "midgard."+t.name, cutoff)
+		return err
+	}
+
+	if t.hypertable && keep == "" {
+		_, err := TheDB.ExecContext(ctx,
+			"SELECT drop_chunks($1, older_than => $2::BIGINT)", "midgard."+t.name, cutoff)
+		return err
+	}
+
+	q := "DELETE FROM " + t.name + " AS r WHERE r.block_timestamp < $1"
+	if keep != "" {
+		q += " AND NOT (" + keep + ")"
+	}
+	_, err := TheDB.ExecContext(ctx, q, cutoff)
+	return err
+}
+
+func pruneOldRows(ctx context.Context) {
+	defer pruneTimer.One()()
+
+	cutoff := pruneCutoff()
+	if cutoff <= FirstBlock.Get().Timestamp {
+		return
+	}
+	tables, err := prunedTables(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Listing tables to prune")
+		return
+	}
+	log.Info().Str("cutoff", cutoff.ToTime().Format("2006-01-02 15:04")).
+		Msg("Deleting old raw events")
+	for _, t := range tables {
+		if ctx.Err() != nil {
+			return
+		}
+		err = pruneTable(ctx, t, cutoff)
+		if err != nil {
+			log.Error().Err(err).Msgf("Pruning %s", t.name)
+		}
+	}
+}
+
+func PruneOldRowsForTests() {
+	pruneOldRows(context.Background())
+}
+
+func InitRetention(ctx context.Context) jobs.NamedFunction {
+	if !retentionEnabled() {
+		return jobs.EmptyJob()
+	}
+	log.Info().Int("days", config.Global.Retention.Days).Msg("Lite mode, old raw events are deleted")
+	interval := config.Global.Retention.PruneInterval.Value()
+	if interval <= 0 {
+		interval = time.Hour
+	}
+
+	return jobs.Supervised(ctx, "Retention", jobs.RestartOnFailure, func() error {
+		for {
+			select {
+			case <-ctx.Done():
+				log.Info().Msg("Shutdown retention job")
+				return nil
+			case <-time.After(interval):
+				pruneOldRows(ctx)
+			}
+		}
+	})
+}
@@ -0,0 +1,118 @@
+package db_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gitlab.com/thorchain/midgard/config"
+	"gitlab.com/thorchain/midgard/internal/db"
+	"gitlab.com/thorchain/midgard/internal/db/testdb"
+)
+
+func setRetentionDays(t *testing.T, days int) {
+	config.Global.Retention.Days = days
+	t.Cleanup(func() { config.Global.Retention.Days = 0 })
+}
+
+func TestRetainedWindow(t *testing.T) {
+	testdb.HideTestLogs(t)
+	setRetentionDays(t, 10)
+
+	db.FirstBlock.Set(1, testdb.StrToNano("2010-01-01 00:00:00"))
+	db.LastCommittedBlock.Set(100, testdb.StrToNano("2020-01-20 23:59:59"))
+
+	starts := bucketPass(t, fmt.Sprintf("interval=day&from=%d", db.StrToSec("2020-01-15 00:00:00")))
+	require.Equal(t, "2020-01-15 00:00:00", starts[0])
+
+	// Without from the retained window is returned.
+	starts = bucketPass(t, "interval=day")
+	require.Equal(t, "2020-01-11 00:00:00", starts[0])
+
+	bucketFail(t, fmt.Sprintf("interval=day&from=%d", db.StrToSec("2020-01-05 00:00:00")),
+		"retained window")
+	bucketFail(t, fmt.Sprintf("from=%d", db.StrToSec("2020-01-05 00:00:00")),
+		"retained window")
+	bucketFail(t, fmt.Sprintf("interval=day&count=20&to=%d", db.StrToSec("2020-01-20 00:00:00")),
+		"retained window")
+}
+
+func TestPruneOldRows(t *testing.T) {
+	testdb.InitTest(t)
+	setRetentionDays(t, 10)
+
+	db.FirstBlock.Set(1, testdb.StrToNano("2020-01-01 00:00:00"))
+	db.LastCommittedBlock.Set(100, testdb.StrToNano("2020-01-30 00:00:00"))
+	db.LastAggregatedBlock.Set(100, testdb.StrToNano("2020-01-30 00:00:00"))
+
+	testdb.InsertSetVersionEvent(t, "node1", "1.0.0", "2020-01-02 00:00:00")
+	testdb.InsertSetVersionEvent(t, "node1", "1.1.0", "2020-01-03 00:00:00")
+	testdb.InsertSetVersionEvent(t, "node2", "1.0.0", "2020-01-03 00:00:00")
+	testdb.InsertSetVersionEvent(t, "node2", "1.2.0", "2020-01-25 00:00:00")
+	testdb.InsertSlashPoints(t, "node1", 2, "old", "2020-01-02 00:00:00")
+	testdb.InsertSlashPoints(t, "node1", 3, "new", "2020-01-25 00:00:00")
+
+	db.PruneOldRowsForTests()
+
+	// The last version of node1 is kept, even though it's old.
+	rows, err := db.TheDB.Query("SELECT node_addr, version FROM set_version_events ORDER BY node_addr")
+	require.NoError(t, err)
+	defer rows.Close()
+	versions := []string{}
+	for rows.Next() {
+		var node, version string
+		require.NoError(t, rows.Scan(&node, &version))
+		versions = append(versions, node+":"+version)
+	}
+	require.Equal(t, []string{"node1:1.1.0", "node2:1.2.0"}, versions)
+
+	var slashReasons []string
+	slashRows, err := db.TheDB.Query("SELECT reason FROM slash_points")
+	require.NoError(t, err)
+	defer slashRows.Close()
+	for slashRows.Next() {
+		var reason string
+		require.NoError(t, slashRows.Scan(&reason))
+		slashReasons = append(slashReasons, reason)
+	}
+	require.Equal(t, []string{"new"}, slashReasons)
+}
+
+func TestPruneStalePool(t *testing.T) {
+	testdb.InitTest(t)
+	setRetentionDays(t, 10)
+
+	db.FirstBlock.Set(1, testdb.StrToNano("2020-01-01 00:00:00"))
+	db.LastCommittedBlock.Set(100, testdb.StrToNano("2020-01-30 00:00:00"))
+	db.LastAggregatedBlock.Set(100, testdb.StrToNano("2020-01-30 00:00:00"))
+	testdb.InsertBlockLog(t, 1, "2020-01-01 00:00:00")
+	testdb.InsertBlockLog(t, 50, "2020-01-20 00:00:05")
+	testdb.InsertBlockLog(t, 100, "2020-01-30 00:00:00")
+
+	// BTC.BTC didn't change since the 2nd, it doesn't hold back pruning ETH.ETH.
+	testdb.InsertBlockPoolDepth(t, "BTC.BTC", 10, 20, 1, "2020-01-01 00:00:00")
+	testdb.InsertBlockPoolDepth(t, "BTC.BTC", 11, 22, 1, "2020-01-02 00:00:00")
+	testdb.InsertBlockPoolDepth(t, "ETH.ETH", 1, 2, 1, "2020-01-02 00:00:00")
+	testdb.InsertBlockPoolDepth(t, "ETH.ETH", 3, 4, 1, "2020-01-25 00:00:00")
+
+	db.PruneOldRowsForTests()
+
+	rows, err := db.TheDB.Query(`
+		SELECT pool, asset_e8, block_timestamp FROM block_pool_depths ORDER BY pool, block_timestamp`)
+	require.NoError(t, err)
+	defer rows.Close()
+	depths := []string{}
+	for rows.Next() {
+		var pool string
+		var assetE8 int64
+		var timestamp db.Nano
+		require.NoError(t, rows.Scan(&pool, &assetE8, &timestamp))
+		depths = append(depths, fmt.Sprintf("%s:%d:%s",
+			pool, assetE8, timestamp.ToTime().UTC().Format("2006-01-02 15:04:05")))
+	}
+	// The last BTC.BTC row is copied to the first block after the cutoff (2020-01-20).
+	require.Equal(t, []string{
+		"BTC.BTC:11:2020-01-20 00:00:05",
+		"ETH.ETH:3:2020-01-25 00:00:00",
+	}, depths)
+}
@@ -105,6 +105,7 @@ type Swap struct {
 	TxID               string
 	PriceTarget        int64
 	Memo               string
+	Chain              string
 }
 
 func (x Swap) ToTendermint() abci.Event {
@@ -120,7 +121,7 @@ func (x Swap) ToTendermint() abci.Event {
 		"emit_asset":            x.EmitAsset,
 		"from":                  withDefaultStr(x.FromAddress, "addressfrom"),
 		"to":                    withDefaultStr(x.ToAddress, "addressto"),
-		"chain":                 "chain",
+		"chain":                 withDefaultStr(x.Chain, "chain"),
 		"id":                    withDefaultStr(x.TxID, "txid"),
 		"swap_target":           util.IntStr(x.PriceTarget),
 		"swap_slip":             util.IntStr(x.Slip),
@@ -47,7 +47,11 @@ func init() {
 		Sslmode:  "disable",
 	}
 
-	dbinit.Setup()
+	// The DB tests are skipped in short mode (see SetupTestDB), the tests which don't need the
+	// DB run without one.
+	if !shortMode() {
+		dbinit.Setup()
+	}
 
 	// TODO(huginn): create tests that test the two kind of inserters separately
 	if getEnvVariable("TEST_IMMEDIATE_INSERTER", "") == "1" {
@@ -55,6 +59,16 @@ func init() {
 	}
 }
 
+// testing.Short can't be called before the flags are parsed, init runs earlier.
+func shortMode() bool {
+	for _, arg := range os.Args[1:] {
+		if arg == "-test.short" || arg == "-test.short=true" {
+			return true
+		}
+	}
+	return false
+}
+
 func SetupTestDB(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping test in short mode")
@@ -64,6 +78,8 @@ func SetupTestDB(t *testing.T) {
 func DeleteTables(t *testing.T) {
 	MustExec(t, "DELETE FROM block_log")
 	MustExec(t, "DELETE FROM block_pool_depths")
+	MustExec(t, "DELETE FROM rune_price_usd")
+	MustExec(t, "DELETE FROM pool_depth_changes")
 	MustExec(t, "DELETE FROM stake_events")
 	MustExec(t, "DELETE FROM pending_liquidity_events")
 	MustExec(t, "DELETE FROM unstake_events")
@@ -74,8 +90,13 @@ func DeleteTables(t *testing.T) {
 	MustExec(t, "DELETE FROM bond_events")
 	MustExec(t, "DELETE FROM pool_events")
 	MustExec(t, "DELETE FROM update_node_account_status_events")
+	MustExec(t, "DELETE FROM slash_points")
+	MustExec(t, "DELETE FROM set_version_events")
+	MustExec(t, "DELETE FROM set_ip_address_events")
 	MustExec(t, "DELETE FROM active_vault_events")
+	MustExec(t, "DELETE FROM inactive_vault_events")
 	MustExec(t, "DELETE FROM set_mimir_events")
+	MustExec(t, "DELETE FROM set_node_mimir")
 	MustExec(t, "DELETE FROM thorname_change_events")
 	MustExec(t, "DELETE FROM outbound_events")
 	MustExec(t, "DELETE FROM fee_events")
@@ -96,6 +117,9 @@ func clearAggregates(t *testing.T) {
 	MustExec(t, "DELETE FROM midgard_agg.balances")
 	MustExec(t, "DELETE FROM midgard_agg.members_log")
 	MustExec(t, "DELETE FROM midgard_agg.members")
+	MustExec(t, "DELETE FROM midgard_agg.first_swaps")
+	MustExec(t, "DELETE FROM midgard_agg.bond_totals")
+	MustExec(t, "DELETE FROM midgard_agg.node_bond_totals")
 }
 
 func InitTest(t *testing.T) {
@@ -133,6 +157,7 @@ func InitTestBlocks(t *testing.T) *blockCreator {
 	record.ResetRecorderForTest()
 	timeseries.ResetLatestStateForTest()
 	timeseries.ResetDepthManagerForTest()
+	timeseries.ResetRunePriceRecorderForTest()
 	timeseries.UpdateUsdPools()
 	SetupTestDB(t)
 	DeleteTables(t)
@@ -429,6 +454,15 @@ func InsertBlockPoolDepth(t *testing.T, pool string, assetE8, runeE8, unit int64
 	MustExec(t, insertq, pool, assetE8, runeE8, 0, unit, 0, 0, timestamp)
 }
 
+func InsertRunePriceUSD(t *testing.T, price float64, pools string, blockTimestamp string) {
+	const insertq = `INSERT INTO rune_price_usd ` +
+		`(price, method, pools, block_timestamp) ` +
+		`VALUES ($1, $2, $3, $4)`
+
+	timestamp := nanoWithDefault(blockTimestamp)
+	MustExec(t, insertq, price, "deepest", pools, timestamp)
+}
+
 type FakeNodeStatus struct {
 	NodeAddr string
 	Former   string
@@ -444,6 +478,43 @@ func InsertUpdateNodeAccountStatusEvent(t *testing.T, fake FakeNodeStatus, block
 	MustExec(t, insertq, fake.NodeAddr, fake.Former, fake.Current, timestamp)
 }
 
+func InsertActiveVaultEvent(t *testing.T, vault, blockTimestamp string) {
+	const insertq = `INSERT INTO active_vault_events (add_asgard_addr, block_timestamp) VALUES ($1, $2)`
+	MustExec(t, insertq, vault, nanoWithDefault(blockTimestamp))
+}
+
+func InsertInactiveVaultEvent(t *testing.T, vault, blockTimestamp string) {
+	const insertq = `INSERT INTO inactive_vault_events (add_asgard_addr, block_timestamp) VALUES ($1, $2)`
+	MustExec(t, insertq, vault, nanoWithDefault(blockTimestamp))
+}
+
+func InsertSlashPoints(t *testing.T, nodeAddr string, points int64, reason, blockTimestamp string) {
+	const insertq = `INSERT INTO slash_points ` +
+		`(node_address, slash_points, reason, block_timestamp) ` +
+		`VALUES ($1, $2, $3, $4)`
+
+	timestamp := nanoWithDefault(blockTimestamp)
+	MustExec(t, insertq, nodeAddr, points, reason, timestamp)
+}
+
+func InsertSetVersionEvent(t *testing.T, nodeAddr, version, blockTimestamp string) {
+	const insertq = `INSERT INTO set_version_events ` +
+		`(node_addr, version, block_timestamp) ` +
+		`VALUES ($1, $2, $3)`
+
+	timestamp := nanoWithDefault(blockTimestamp)
+	MustExec(t, insertq, nodeAddr, version, timestamp)
+}
+
+func InsertSetIPAddressEvent(t *testing.T, nodeAddr, ipAddr, blockTimestamp string) {
+	const insertq = `INSERT INTO set_ip_address_events ` +
+		`(node_addr, ip_addr, block_timestamp) ` +
+		`VALUES ($1, $2, $3)`
+
+	timestamp := nanoWithDefault(blockTimestamp)
+	MustExec(t, insertq, nodeAddr, ipAddr, timestamp)
+}
+
 func getEnvVariable(key, def string) string {
 	value := os.Getenv(key)
 
@@ -466,3 +537,13 @@ func insertMimirEvent(t *testing.T, key string, value int64, blockTimestamp stri
 	timestamp := nanoWithDefault(blockTimestamp)
 	MustExec(t, insertq, key, strconv.FormatInt(value, 10), timestamp)
 }
+
+// Same as the recorder, the key and the value are stored in swapped columns.
+func InsertNodeMimirEvent(t *testing.T, address, key string, value int64, blockTimestamp string) {
+	const insertq = `INSERT INTO set_node_mimir ` +
+		`(address, key, value, block_timestamp) ` +
+		`VALUES ($1, $2, $3, $4)`
+
+	timestamp := nanoWithDefault(blockTimestamp)
+	MustExec(t, insertq, address, value, key, timestamp)
+}
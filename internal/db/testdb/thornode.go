@@ -12,7 +12,7 @@ const thorNodeUrl = "http://thornode.com"
 
 // Starts Thornode HTTP mock with some simiple / empty results.
 func StartMockThornode() (deactivateCallback func()) {
-	notinchain.BaseURL = thorNodeUrl
+	notinchain.SetURLs(thorNodeUrl, nil)
 	httpmock.Activate()
 
 	setInitialThornodeConstants()
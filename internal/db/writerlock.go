@@ -0,0 +1,147 @@
+package db
+
+// Only one Midgard instance writes to the DB, the one which holds a session level advisory lock.
+// The others serve the API from the same DB and take over when the writer goes away: the lock is
+// released by Postgres when the session of the writer ends.
+//
+// The block commits run on other connections, so the lock alone doesn't stop a writer whose lock
+// session died from committing blocks the new writer is writing too. Every writer increments
+// the epoch in the writer_epoch table when it takes the lock, and the commit transactions lock
+// the row and check that the epoch is still the one of their writer (see SetWriterFence). The
+// new writer's increment waits for a commit which locked the row first, and the commits after
+// it see the new epoch, so the check and the commit are atomic.
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+)
+
+var errWriterLockLost = errors.New("writer lock lost")
+
+// ErrWriterFenced is returned by the block commits after an other instance took the writer lock
+// over, the block is rolled back.
+var ErrWriterFenced = errors.New("writer lock lost, block not committed")
+
+const (
+	nextEpochQuery = "UPDATE writer_epoch SET epoch = epoch + 1 RETURNING epoch"
+	lockEpochQuery = "SELECT epoch FROM writer_epoch FOR UPDATE"
+)
+
+// True if the advisory lock $1 is held by the session with pid $2.
+const lockHeldQuery = `
+	SELECT EXISTS (
+		SELECT 1 FROM pg_locks
+		WHERE locktype = 'advisory' AND objsubid = 1 AND pid = $2 AND granted
+			AND ((classid::BIGINT << 32) | objid::BIGINT) = $1)`
+
+// True if the advisory lock $1 is held by any session.
+const lockTakenQuery = `
+	SELECT EXISTS (
+		SELECT 1 FROM pg_locks
+		WHERE locktype = 'advisory' AND objsubid = 1 AND granted
+			AND ((classid::BIGINT << 32) | objid::BIGINT) = $1)`
+
+// Returns true if an instance holds the writer lock key.
+func WriterLockHeld(dbObj *sql.DB, key int64) (held bool, err error) {
+	err = dbObj.QueryRow(lockTakenQuery, key).Scan(&held)
+	return held, err
+}
+
+type WriterLock struct {
+	key  int64
+	conn *sql.Conn
+	// Backend pid of the session holding the lock.
+	pid int
+	// Value of writer_epoch set when the lock was taken.
+	epoch int64
+}
+
+// Returns nil if the lock is held by an other instance.
+// The lock is held on a dedicated connection, which is kept until Release.
+func TryAcquireWriterLock(ctx context.Context, key int64) (*WriterLock, error) {
+	conn, err := TheDB.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var acquired bool
+	err = conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", key).Scan(&acquired)
+	if err != nil || !acquired {
+		conn.Close()
+		return nil, err
+	}
+	lock := &WriterLock{key: key, conn: conn}
+	err = conn.QueryRowContext(ctx, "SELECT pg_backend_pid()").Scan(&lock.pid)
+	if err != nil {
+		lock.Release()
+		return nil, err
+	}
+	err = conn.QueryRowContext(ctx, nextEpochQuery).Scan(&lock.epoch)
+	if err != nil {
+		lock.Release()
+		return nil, err
+	}
+	return lock, nil
+}
+
+// Returns an error if the lock was lost, e.g. the connection was dropped.
+func (l *WriterLock) Check(ctx context.Context) error {
+	var held bool
+	err := l.conn.QueryRowContext(ctx, lockHeldQuery, l.key, l.pid).Scan(&held)
+	if err != nil {
+		return err
+	}
+	if !held {
+		return errWriterLockLost
+	}
+	return nil
+}
+
+func (l *WriterLock) Release() {
+	_, err := l.conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", l.key)
+	if err != nil {
+		log.Warn().Err(err).Msg("Releasing writer lock")
+	}
+	l.conn.Close()
+}
+
+var writerFence struct {
+	sync.Mutex
+	lock *WriterLock
+}
+
+// SetWriterFence makes the block commits check that no other instance took the lock since l was
+// taken, nil turns it off.
+func SetWriterFence(l *WriterLock) {
+	writerFence.Lock()
+	defer writerFence.Unlock()
+	writerFence.lock = l
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// Called in the commit transaction of the blocks, right before the commit. Returns
+// ErrWriterFenced if an other instance took the writer lock since this one did. The epoch row
+// stays locked until the transaction ends.
+func checkWriterFence(queryRow func(query string, args ...interface{}) rowScanner) error {
+	writerFence.Lock()
+	l := writerFence.lock
+	writerFence.Unlock()
+	if l == nil {
+		return nil
+	}
+	var epoch int64
+	err := queryRow(lockEpochQuery).Scan(&epoch)
+	if err != nil {
+		return err
+	}
+	if epoch != l.epoch {
+		return ErrWriterFenced
+	}
+	return nil
+}
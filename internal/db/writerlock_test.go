@@ -0,0 +1,106 @@
+package db_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gitlab.com/thorchain/midgard/internal/db"
+	"gitlab.com/thorchain/midgard/internal/db/testdb"
+)
+
+func TestWriterLock(t *testing.T) {
+	testdb.InitTest(t)
+	ctx := context.Background()
+	const key = 12345
+
+	first, err := db.TryAcquireWriterLock(ctx, key)
+	require.NoError(t, err)
+	require.NotNil(t, first)
+	require.NoError(t, first.Check(ctx))
+
+	second, err := db.TryAcquireWriterLock(ctx, key)
+	require.NoError(t, err)
+	require.Nil(t, second)
+
+	first.Release()
+
+	second, err = db.TryAcquireWriterLock(ctx, key)
+	require.NoError(t, err)
+	require.NotNil(t, second)
+	require.NoError(t, second.Check(ctx))
+	second.Release()
+}
+
+func TestWriterLockHeld(t *testing.T) {
+	testdb.InitTest(t)
+	ctx := context.Background()
+	const key = 12347
+
+	held, err := db.WriterLockHeld(db.TheDB, key)
+	require.NoError(t, err)
+	require.False(t, held)
+
+	lock, err := db.TryAcquireWriterLock(ctx, key)
+	require.NoError(t, err)
+	require.NotNil(t, lock)
+	held, err = db.WriterLockHeld(db.TheDB, key)
+	require.NoError(t, err)
+	require.True(t, held)
+
+	lock.Release()
+	held, err = db.WriterLockHeld(db.TheDB, key)
+	require.NoError(t, err)
+	require.False(t, held)
+}
+
+func commitBlockLog(height int64) error {
+	err := db.Inserter.StartBlock()
+	if err != nil {
+		return err
+	}
+	err = db.Inserter.Insert("block_log", []string{"height", "timestamp", "hash"},
+		height, height*1e9, []byte{byte(height)})
+	if err != nil {
+		return err
+	}
+	err = db.Inserter.EndBlock()
+	if err != nil {
+		return err
+	}
+	return db.Inserter.Flush()
+}
+
+func TestWriterFence(t *testing.T) {
+	testdb.InitTest(t)
+	ctx := context.Background()
+	const key = 12346
+
+	lock, err := db.TryAcquireWriterLock(ctx, key)
+	require.NoError(t, err)
+	require.NotNil(t, lock)
+	db.SetWriterFence(lock)
+	defer db.SetWriterFence(nil)
+
+	require.NoError(t, commitBlockLog(1))
+
+	// The session of the lock ended, e.g. the connection was dropped, and an other instance
+	// took the lock over.
+	lock.Release()
+	next, err := db.TryAcquireWriterLock(ctx, key)
+	require.NoError(t, err)
+	require.NotNil(t, next)
+	defer next.Release()
+	require.ErrorIs(t, commitBlockLog(2), db.ErrWriterFenced)
+
+	var heights []int64
+	rows, err := db.TheDB.Query("SELECT height FROM block_log ORDER BY height")
+	require.NoError(t, err)
+	defer rows.Close()
+	for rows.Next() {
+		var height int64
+		require.NoError(t, rows.Scan(&height))
+		heights = append(heights, height)
+	}
+	require.Equal(t, []int64{1}, heights)
+}
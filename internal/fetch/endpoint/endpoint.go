@@ -0,0 +1,203 @@
+// Package endpoint keeps track of the health of the ThorNode and Tendermint endpoints, so
+// requests can fail over between them.
+//
+// Every endpoint has a circuit breaker: after MaxFailures consecutive failures it's skipped for
+// RetryAfter, then it's tried again. An endpoint caught returning different blocks than the
+// others is skipped for much longer.
+package endpoint
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pascaldekloe/metrics"
+	"gitlab.com/thorchain/midgard/internal/util/midlog"
+)
+
+var logger = midlog.LoggerForModule("endpoint")
+
+var (
+	upGauge        = metrics.Must1LabelInteger("midgard_endpoint_up", "url")
+	failureCount   = metrics.Must1LabelCounter("midgard_endpoint_failures_total", "url")
+	misbehaveCount = metrics.Must1LabelCounter("midgard_endpoint_misbehaving_total", "url")
+)
+
+func init() {
+	metrics.MustHelp("midgard_endpoint_up", "1 if the endpoint is used, 0 if it's skipped because of failures.")
+	metrics.MustHelp("midgard_endpoint_failures_total", "Number of failed requests to the endpoint.")
+	metrics.MustHelp("midgard_endpoint_misbehaving_total", "Number of times the endpoint returned a block hash which didn't match the other endpoints.")
+}
+
+// Misbehaving endpoints are skipped this many times longer than failing ones.
+const misbehavingPenalty = 10
+
+// Weight of the last measurement in the latency average.
+const latencyWeight = 0.2
+
+type Endpoint struct {
+	URL string
+	set *Set
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	latency   time.Duration
+	height    int64
+}
+
+// Returns false while the circuit breaker is open.
+func (e *Endpoint) Available(now time.Time) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return !now.Before(e.openUntil)
+}
+
+// Records the result of a request. Opens the circuit breaker after too many failures.
+func (e *Endpoint) Report(err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if err == nil {
+		if e.set.MaxFailures <= e.failures {
+			logger.InfoF("Endpoint %s recovered", e.URL)
+			upGauge(e.URL).Set(1)
+		}
+		e.failures = 0
+		return
+	}
+	failureCount(e.URL).Add(1)
+	e.failures++
+	if e.set.MaxFailures <= e.failures {
+		if e.failures == e.set.MaxFailures {
+			logger.WarnF("Endpoint %s failed %d times, skipping it for %v: %v",
+				e.URL, e.failures, e.set.RetryAfter, err)
+		}
+		e.openUntil = time.Now().Add(e.set.RetryAfter)
+		upGauge(e.URL).Set(0)
+	}
+}
+
+// Skips the endpoint for a long time, it returned data which contradicts the other endpoints.
+func (e *Endpoint) MarkMisbehaving(reason string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	retryAfter := misbehavingPenalty * e.set.RetryAfter
+	logger.ErrorF("Endpoint %s is misbehaving, skipping it for %v: %s", e.URL, retryAfter, reason)
+	misbehaveCount(e.URL).Add(1)
+	e.failures = e.set.MaxFailures
+	e.openUntil = time.Now().Add(retryAfter)
+	upGauge(e.URL).Set(0)
+}
+
+func (e *Endpoint) skippedUntil() time.Time {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.openUntil
+}
+
+// Records the duration of a request, endpoints with lower latency are preferred.
+func (e *Endpoint) ObserveLatency(d time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.latency == 0 {
+		e.latency = d
+		return
+	}
+	e.latency = time.Duration((1-latencyWeight)*float64(e.latency) + latencyWeight*float64(d))
+}
+
+func (e *Endpoint) Latency() time.Duration {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.latency
+}
+
+// Latest block height reported by the endpoint, 0 if unknown.
+func (e *Endpoint) Height() int64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.height
+}
+
+func (e *Endpoint) SetHeight(height int64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.height = height
+}
+
+// Set holds the state of the endpoints, created on their first use.
+type Set struct {
+	MaxFailures int
+	RetryAfter  time.Duration
+
+	mu    sync.Mutex
+	byURL map[string]*Endpoint
+}
+
+func NewSet(maxFailures int, retryAfter time.Duration) *Set {
+	if maxFailures <= 0 {
+		maxFailures = 1
+	}
+	return &Set{
+		MaxFailures: maxFailures,
+		RetryAfter:  retryAfter,
+		byURL:       map[string]*Endpoint{},
+	}
+}
+
+func (s *Set) Endpoint(url string) *Endpoint {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.byURL[url]
+	if !ok {
+		e = &Endpoint{URL: url, set: s}
+		s.byURL[url] = e
+		upGauge(url).Set(1)
+	}
+	return e
+}
+
+// Returns false if all the endpoints are skipped because of failures.
+// Without endpoints nothing is known to fail, the result is true.
+func (s *Set) AnyAvailable() bool {
+	s.mu.Lock()
+	endpoints := make([]*Endpoint, 0, len(s.byURL))
+	for _, e := range s.byURL {
+		endpoints = append(endpoints, e)
+	}
+	s.mu.Unlock()
+
+	now := time.Now()
+	for _, e := range endpoints {
+		if e.Available(now) {
+			return true
+		}
+	}
+	return len(endpoints) == 0
+}
+
+// Returns the endpoints in the order they should be tried: the available ones by latency, and
+// if none is available all of them, the one which is skipped for the shortest time first.
+// The order of equally fast endpoints is kept.
+func (s *Set) Ordered(urls []string) []*Endpoint {
+	now := time.Now()
+	available := []*Endpoint{}
+	all := make([]*Endpoint, 0, len(urls))
+	for _, url := range urls {
+		e := s.Endpoint(url)
+		all = append(all, e)
+		if e.Available(now) {
+			available = append(available, e)
+		}
+	}
+	if len(available) != 0 {
+		sort.SliceStable(available, func(i, j int) bool {
+			return available[i].Latency() < available[j].Latency()
+		})
+		return available
+	}
+	sort.SliceStable(all, func(i, j int) bool {
+		return all[i].skippedUntil().Before(all[j].skippedUntil())
+	})
+	return all
+}
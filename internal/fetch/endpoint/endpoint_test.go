@@ -0,0 +1,53 @@
+package endpoint_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gitlab.com/thorchain/midgard/internal/fetch/endpoint"
+)
+
+func urlsOf(endpoints []*endpoint.Endpoint) []string {
+	ret := []string{}
+	for _, e := range endpoints {
+		ret = append(ret, e.URL)
+	}
+	return ret
+}
+
+func TestOrderedByLatency(t *testing.T) {
+	s := endpoint.NewSet(3, time.Minute)
+	urls := []string{"http://a", "http://b", "http://c"}
+	require.Equal(t, urls, urlsOf(s.Ordered(urls)))
+
+	s.Endpoint("http://a").ObserveLatency(30 * time.Millisecond)
+	s.Endpoint("http://b").ObserveLatency(10 * time.Millisecond)
+	s.Endpoint("http://c").ObserveLatency(20 * time.Millisecond)
+	require.Equal(t, []string{"http://b", "http://c", "http://a"}, urlsOf(s.Ordered(urls)))
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	s := endpoint.NewSet(2, time.Minute)
+	urls := []string{"http://a", "http://b"}
+	a := s.Endpoint("http://a")
+	fail := errors.New("connection refused")
+
+	a.Report(fail)
+	require.Equal(t, urls, urlsOf(s.Ordered(urls)))
+
+	// A success resets the failure count.
+	a.Report(nil)
+	a.Report(fail)
+	require.Equal(t, urls, urlsOf(s.Ordered(urls)))
+
+	a.Report(fail)
+	require.False(t, a.Available(time.Now()))
+	require.True(t, a.Available(time.Now().Add(time.Minute)))
+	require.Equal(t, []string{"http://b"}, urlsOf(s.Ordered(urls)))
+
+	// When all are skipped all are returned, the one skipped for the shortest time first.
+	s.Endpoint("http://b").MarkMisbehaving("hash mismatch")
+	require.Equal(t, urls, urlsOf(s.Ordered(urls)))
+}
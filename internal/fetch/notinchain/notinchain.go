@@ -6,14 +6,58 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sync"
 	"time"
+
+	"gitlab.com/thorchain/midgard/internal/fetch/endpoint"
+)
+
+var (
+	// Guards the URLs, they are replaced at a hard fork while the API is querying.
+	urlsMutex sync.RWMutex
+	// The REST root.
+	baseURL string
+	// The REST roots of other nodes, used when baseURL fails.
+	extraURLs []string
 )
 
-// BaseURL defines the REST root.
-var BaseURL string
+// SetURLs sets the REST root and the ones of the other nodes to fail over to.
+func SetURLs(base string, extra []string) {
+	urlsMutex.Lock()
+	defer urlsMutex.Unlock()
+	baseURL = base
+	extraURLs = extra
+}
+
+// Endpoints tracks the health of the REST roots.
+var Endpoints = endpoint.NewSet(3, 30*time.Second)
 
 var Client http.Client
 
+// Gets the path from the fastest endpoint which is up, fails over to the others on errors.
+func get(path string) (*http.Response, error) {
+	urlsMutex.RLock()
+	urls := append([]string{baseURL}, extraURLs...)
+	urlsMutex.RUnlock()
+	var lastErr error
+	for _, e := range Endpoints.Ordered(urls) {
+		start := time.Now()
+		resp, err := Client.Get(e.URL + path)
+		if err == nil && resp.StatusCode/100 == 5 {
+			resp.Body.Close()
+			err = fmt.Errorf("HTTP status %q from %s", resp.Status, e.URL)
+		}
+		e.Report(err)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		e.ObserveLatency(time.Since(start))
+		return resp, nil
+	}
+	return nil, lastErr
+}
+
 // TODO(kashif) we can merge this in future into a better caching layer
 // not sure at this point if its necessary or not
 var (
@@ -92,7 +136,7 @@ type NodeAccount struct {
 
 // Get all nodes from the thorchain api
 func NodeAccountsLookup() ([]*NodeAccount, error) {
-	resp, err := Client.Get(BaseURL + "/nodes")
+	resp, err := get("/nodes")
 	if err != nil {
 		return nil, fmt.Errorf("node accounts unavailable from REST on %w", err)
 	}
@@ -108,7 +152,7 @@ func NodeAccountsLookup() ([]*NodeAccount, error) {
 
 // Get node details by address from the thorchain api
 func NodeAccountLookup(addr string) (*NodeAccount, error) {
-	resp, err := Client.Get(BaseURL + "/node/" + addr)
+	resp, err := get("/node/" + addr)
 	if err != nil {
 		return nil, fmt.Errorf("node account unavailable from REST on %w", err)
 	}
@@ -128,7 +172,7 @@ type Network struct {
 
 // Get vault data from the thorchain api
 func NetworkLookup() (*Network, error) {
-	resp, err := Client.Get(BaseURL + "/network")
+	resp, err := get("/network")
 	if err != nil {
 		return nil, fmt.Errorf("network data unavailable from REST on %w", err)
 	}
@@ -149,7 +193,7 @@ type Constants struct {
 var constants *Constants
 
 func LoadConstants() error {
-	resp, err := Client.Get(BaseURL + "/constants")
+	resp, err := get("/constants")
 	if err != nil {
 		return fmt.Errorf("constants unavailable from REST on %w", err)
 	}
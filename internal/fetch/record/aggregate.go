@@ -9,6 +9,9 @@ type runningTotals struct {
 	synthE8DepthPerPool map[string]*int64
 	unitsPerPool        map[string]*int64
 	poolPriceUSD        map[string]float64
+
+	// changes of the current block, see flushDepthChanges
+	depthChanges depthChanges
 }
 
 func newRunningTotals() *runningTotals {
@@ -17,6 +20,7 @@ func newRunningTotals() *runningTotals {
 		runeE8DepthPerPool:  make(map[string]*int64),
 		synthE8DepthPerPool: make(map[string]*int64),
 		unitsPerPool:        make(map[string]*int64),
+		depthChanges:        depthChanges{},
 	}
 }
 
@@ -34,7 +38,9 @@ func (t *runningTotals) CurrentDepths(pool []byte) (assetE8, runeE8, synthE8 int
 }
 
 // AddPoolAssetE8Depth adjusts the quantity. Use a negative value to deduct.
-func (t *runningTotals) AddPoolAssetE8Depth(pool []byte, assetE8 int64) {
+// The change is attributed to the given reason in pool_depth_changes.
+func (t *runningTotals) AddPoolAssetE8Depth(pool []byte, reason DepthChangeReason, assetE8 int64) {
+	t.depthChanges.get(pool, reason).assetE8 += assetE8
 	if p, ok := t.assetE8DepthPerPool[string(pool)]; ok {
 		*p += assetE8
 	} else {
@@ -43,7 +49,8 @@ func (t *runningTotals) AddPoolAssetE8Depth(pool []byte, assetE8 int64) {
 }
 
 // AddPoolRuneE8Depth adjusts the quantity. Use a negative value to deduct.
-func (t *runningTotals) AddPoolRuneE8Depth(pool []byte, runeE8 int64) {
+func (t *runningTotals) AddPoolRuneE8Depth(pool []byte, reason DepthChangeReason, runeE8 int64) {
+	t.depthChanges.get(pool, reason).runeE8 += runeE8
 	if p, ok := t.runeE8DepthPerPool[string(pool)]; ok {
 		*p += runeE8
 	} else {
@@ -52,7 +59,8 @@ func (t *runningTotals) AddPoolRuneE8Depth(pool []byte, runeE8 int64) {
 }
 
 // AddPoolSynthE8Depth adjusts the quantity. Use a negative value to deduct.
-func (t *runningTotals) AddPoolSynthE8Depth(pool []byte, synthE8 int64) {
+func (t *runningTotals) AddPoolSynthE8Depth(pool []byte, reason DepthChangeReason, synthE8 int64) {
+	t.depthChanges.get(pool, reason).synthE8 += synthE8
 	if p, ok := t.synthE8DepthPerPool[string(pool)]; ok {
 		*p += synthE8
 	} else {
@@ -129,7 +129,7 @@ func registerArtificialDeposits(unitChanges artificialUnitChanges) {
 					} else {
 						stake.AssetAddr = []byte(change.Addr)
 					}
-					Recorder.OnStake(&stake, meta)
+					recordEvent(&stake, meta)
 				} else {
 					unstake := Unstake{
 						Pool:       []byte(change.Pool),
@@ -141,7 +141,7 @@ func registerArtificialDeposits(unitChanges artificialUnitChanges) {
 						Chain:      []byte(strings.Split(change.Pool, ".")[0]),
 						Memo:       []byte("Midgard Fix"),
 					}
-					Recorder.OnUnstake(&unstake, meta)
+					recordEvent(&unstake, meta)
 				}
 			}
 		}
@@ -186,7 +186,7 @@ func registerArtificialPoolBallanceChanges(changes artificialPoolBallanceChanges
 			for _, change := range changesAtHeight {
 				poolBalanceChange := change.toEvent()
 				poolBalanceChange.Reason = reason
-				Recorder.OnPoolBalanceChange(&poolBalanceChange, meta)
+				recordEvent(&poolBalanceChange, meta)
 			}
 		}
 	}
@@ -45,7 +45,7 @@ func loadMainnetcorrectGenesisNode() {
 			Former:   []byte("Ready"),
 			Current:  []byte("Active"),
 		}
-		Recorder.OnUpdateNodeAccountStatus(&updateNodeAccountStatus, meta)
+		recordEvent(&updateNodeAccountStatus, meta)
 	})
 }
 
@@ -80,7 +80,7 @@ func (w *AdditionalWithdraw) Record(meta *Metadata) {
 		EmitAssetE8: w.AssetE8,
 		StakeUnits:  w.Units,
 	}
-	Recorder.OnUnstake(&unstake, meta)
+	recordEvent(&unstake, meta)
 }
 
 func addWithdraw(height int64, w AdditionalWithdraw) {
@@ -271,7 +271,7 @@ func loadMainnetWithdrawIncreasesUnits() {
 			},
 			StakeUnits: missingAdd.AdditionalUnits,
 		}
-		Recorder.OnStake(&stake, meta)
+		recordEvent(&stake, meta)
 	}
 	for k := range corrections {
 		AdditionalEvents.Add(k, correct)
@@ -432,7 +432,7 @@ func loadMainnetBalanceCorrections() {
 					Asset:    []byte(c.asset),
 					AmountE8: c.amountE8,
 				}
-				Recorder.OnTransfer(&transfer, meta)
+				recordEvent(&transfer, meta)
 			}
 		}
 		AdditionalEvents.Add(height, fn)
@@ -471,7 +471,7 @@ func loadMainnetPreregisterThornames() {
 				Chain:        []byte("THOR"),
 				ExpireHeight: 10787995,
 			}
-			Recorder.OnTHORNameChange(&thorNameChange, meta)
+			recordEvent(&thorNameChange, meta)
 		})
 	}
 }
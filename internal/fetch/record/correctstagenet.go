@@ -12,7 +12,7 @@ func loadStagenetCorrections(rootChainID string) {
 				Former:   empty,
 				Current:  []byte("Active"),
 			}
-			Recorder.OnUpdateNodeAccountStatus(&updateNodeAccountStatus, meta)
+			recordEvent(&updateNodeAccountStatus, meta)
 		})
 
 		// The TERRA.USD pool was renamed to TERRA.UST in a state migration. This creates
@@ -23,7 +23,7 @@ func loadStagenetCorrections(rootChainID string) {
 				Asset:  []byte("TERRA.UST"),
 				Status: []byte("Staged"),
 			}
-			Recorder.OnPool(&pool, meta)
+			recordEvent(&pool, meta)
 			stake := Stake{
 				AddBase: AddBase{
 					Pool:       []byte("TERRA.UST"),
@@ -38,19 +38,19 @@ func loadStagenetCorrections(rootChainID string) {
 				},
 				StakeUnits: 3135000000,
 			}
-			Recorder.OnStake(&stake, meta)
+			recordEvent(&stake, meta)
 		})
 		AdditionalEvents.Add(36720, func(meta *Metadata) {
 			pool := Pool{
 				Asset:  []byte("TERRA.USD"),
 				Status: []byte("Suspended"),
 			}
-			Recorder.OnPool(&pool, meta)
+			recordEvent(&pool, meta)
 			pool = Pool{
 				Asset:  []byte("TERRA.UST"),
 				Status: []byte("Available"),
 			}
-			Recorder.OnPool(&pool, meta)
+			recordEvent(&pool, meta)
 		})
 
 		AdditionalEvents.Add(627001, func(meta *Metadata) {
@@ -71,7 +71,7 @@ func loadStagenetCorrections(rootChainID string) {
 				EmitAssetE8: 492518419,
 				StakeUnits:  0,
 			}
-			Recorder.OnUnstake(&unstake, meta)
+			recordEvent(&unstake, meta)
 			stake := Stake{
 				AddBase: AddBase{
 					Pool:       []byte("TERRA.LUNA"),
@@ -86,7 +86,7 @@ func loadStagenetCorrections(rootChainID string) {
 				},
 				StakeUnits: 10423580154,
 			}
-			Recorder.OnStake(&stake, meta)
+			recordEvent(&stake, meta)
 
 			// Note that the liquidity providers for the UST pool are inconsistent with the
 			// pool units - this is known and will be rectified on a subsequent stagenet fork.
@@ -102,7 +102,7 @@ func loadStagenetCorrections(rootChainID string) {
 				EmitRuneE8:  722219743,
 				StakeUnits:  0,
 			}
-			Recorder.OnUnstake(&unstake, meta)
+			recordEvent(&unstake, meta)
 		})
 	}
 }
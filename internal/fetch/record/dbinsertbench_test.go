@@ -11,9 +11,12 @@ import (
 
 	"github.com/jackc/pgx/v4"
 	pgxstd "github.com/jackc/pgx/v4/stdlib"
+	"github.com/stretchr/testify/require"
 	"gitlab.com/thorchain/midgard/internal/db"
 	"gitlab.com/thorchain/midgard/internal/db/testdb"
 	"gitlab.com/thorchain/midgard/internal/fetch/record"
+	"gitlab.com/thorchain/midgard/internal/fetch/sync/chain"
+	"gitlab.com/thorchain/midgard/internal/timeseries"
 )
 
 func intToBytes(n int64) []byte {
@@ -347,3 +350,93 @@ func BenchmarkInsertBatchInserter(b *testing.B) {
 		batchInserterBatch(nil, int64(i), int64(to))
 	}
 }
+
+func resetForBlocks(t *testing.T) {
+	db.ResetGlobalVarsForTests()
+	record.ResetRecorderForTest()
+	timeseries.ResetLatestStateForTest()
+	timeseries.ResetDepthManagerForTest()
+	timeseries.ResetRunePriceRecorderForTest()
+	testdb.SetupTestDB(t)
+	testdb.DeleteTables(t)
+	db.InitializeChainVars("fakechain", 1, "hash1")
+}
+
+// Writes the blocks, parsing them in parallel if workers is not 0.
+func writeBlocks(t testing.TB, blocks []chain.Block, workers int) {
+	if workers == 0 {
+		for i := range blocks {
+			err := timeseries.ProcessBlock(&blocks[i], true)
+			require.NoError(t, err)
+		}
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	in := make(chan chain.Block)
+	go func() {
+		for _, block := range blocks {
+			in <- block
+		}
+		close(in)
+	}()
+	for parsed := range record.ParseBlocks(ctx, in, workers) {
+		err := timeseries.ProcessParsedBlock(parsed, true)
+		require.NoError(t, err)
+	}
+}
+
+// Returns the text representation of all the rows written by the blocks.
+func dumpWrittenTables(t *testing.T) string {
+	var b strings.Builder
+	for _, table := range []string{
+		"block_log", "block_pool_depths", "pool_depth_changes", "stake_events", "swap_events",
+		"switch_events", "transfer_events",
+	} {
+		rows, err := db.TheDB.Query("SELECT r::text FROM " + table + " AS r ORDER BY r::text")
+		require.NoError(t, err)
+		fmt.Fprintf(&b, "%s:\n", table)
+		for rows.Next() {
+			var row string
+			require.NoError(t, rows.Scan(&row))
+			fmt.Fprintln(&b, row)
+		}
+		require.NoError(t, rows.Err())
+		rows.Close()
+	}
+	return b.String()
+}
+
+func TestParallelParsingSameOutput(t *testing.T) {
+	testdb.HideTestLogs(t)
+
+	resetForBlocks(t)
+	writeBlocks(t, benchBlocks(50, 10), 0)
+	sequential := dumpWrittenTables(t)
+
+	resetForBlocks(t)
+	writeBlocks(t, benchBlocks(50, 10), 4)
+	parallel := dumpWrittenTables(t)
+
+	require.Contains(t, sequential, "swap_events:\n(")
+	require.Equal(t, sequential, parallel)
+}
+
+// Whole block writes, see the parse only benchmarks in muxbench_test.go:
+//
+// $ go test -run=NONE -bench WriteBlocks -p 1 ./internal/fetch/record/
+func benchmarkWriteBlocks(b *testing.B, workers int) {
+	resetForBlocks(nil)
+	blocks := benchBlocks(b.N, 100)
+	b.ResetTimer()
+	writeBlocks(b, blocks, workers)
+}
+
+func BenchmarkWriteBlocksSequential(b *testing.B) {
+	benchmarkWriteBlocks(b, 0)
+}
+
+func BenchmarkWriteBlocksParallel4(b *testing.B) {
+	benchmarkWriteBlocks(b, 4)
+}
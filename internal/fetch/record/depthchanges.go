@@ -0,0 +1,114 @@
+package record
+
+import (
+	"sort"
+
+	"gitlab.com/thorchain/midgard/internal/db"
+	"gitlab.com/thorchain/midgard/internal/util/miderr"
+)
+
+// DepthChangeReason tells which kind of event changed the depths of a pool.
+// The values are stored in the pool_depth_changes table.
+type DepthChangeReason string
+
+const (
+	DepthChangeSwap              DepthChangeReason = "swap"
+	DepthChangeAdd               DepthChangeReason = "add"
+	DepthChangeWithdraw          DepthChangeReason = "withdraw"
+	DepthChangeDonate            DepthChangeReason = "donate"
+	DepthChangeReward            DepthChangeReason = "reward"
+	DepthChangeGas               DepthChangeReason = "gas"
+	DepthChangeFee               DepthChangeReason = "fee"
+	DepthChangeSlash             DepthChangeReason = "slash"
+	DepthChangeErrata            DepthChangeReason = "errata"
+	DepthChangePoolBalanceChange DepthChangeReason = "pool_balance_change"
+	// Depths are set to 0 when the pool is suspended.
+	DepthChangeSuspension DepthChangeReason = "suspension"
+)
+
+// DepthChangeReasons lists all the reasons in the order they are reported.
+var DepthChangeReasons = []DepthChangeReason{
+	DepthChangeSwap,
+	DepthChangeAdd,
+	DepthChangeWithdraw,
+	DepthChangeDonate,
+	DepthChangeReward,
+	DepthChangeGas,
+	DepthChangeFee,
+	DepthChangeSlash,
+	DepthChangeErrata,
+	DepthChangePoolBalanceChange,
+	DepthChangeSuspension,
+}
+
+type depthChangeKey struct {
+	pool   string
+	reason DepthChangeReason
+}
+
+// DepthChange is the sum of the depth changes of a pool for one reason in a block, as written
+// into pool_depth_changes.
+type DepthChange struct {
+	Pool    string
+	Reason  DepthChangeReason
+	AssetE8 int64
+	RuneE8  int64
+	SynthE8 int64
+}
+
+type depthChange struct {
+	assetE8 int64
+	runeE8  int64
+	synthE8 int64
+}
+
+// Sums up the depth changes of the current block per pool and reason.
+type depthChanges map[depthChangeKey]*depthChange
+
+func (d depthChanges) get(pool []byte, reason DepthChangeReason) *depthChange {
+	key := depthChangeKey{pool: string(pool), reason: reason}
+	c, ok := d[key]
+	if !ok {
+		c = &depthChange{}
+		d[key] = c
+	}
+	return c
+}
+
+// Writes the depth changes of the block into pool_depth_changes and clears them. Returns the
+// changes written.
+func (t *runningTotals) flushDepthChanges(meta *Metadata) []DepthChange {
+	keys := make([]depthChangeKey, 0, len(t.depthChanges))
+	for key, c := range t.depthChanges {
+		if c.assetE8 != 0 || c.runeE8 != 0 || c.synthE8 != 0 {
+			keys = append(keys, key)
+		}
+	}
+	// Sort for deterministic insert order.
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].pool != keys[j].pool {
+			return keys[i].pool < keys[j].pool
+		}
+		return keys[i].reason < keys[j].reason
+	})
+
+	cols := []string{"pool", "reason", "asset_e8", "rune_e8", "synth_e8", "block_timestamp"}
+	var ret []DepthChange
+	for _, key := range keys {
+		c := t.depthChanges[key]
+		err := db.Inserter.Insert("pool_depth_changes", cols,
+			key.pool, string(key.reason), c.assetE8, c.runeE8, c.synthE8,
+			meta.BlockTimestamp.UnixNano())
+		if err != nil {
+			miderr.LogEventParseErrorF(
+				"pool depth change from height %d lost on %s", meta.BlockHeight, err)
+			continue
+		}
+		ret = append(ret, DepthChange{
+			Pool: key.pool, Reason: key.reason,
+			AssetE8: c.assetE8, RuneE8: c.runeE8, SynthE8: c.synthE8,
+		})
+	}
+	t.depthChanges = depthChanges{}
+	return ret
+}
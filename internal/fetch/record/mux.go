@@ -2,6 +2,7 @@ package record
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"time"
@@ -17,8 +18,10 @@ import (
 
 // Package Metrics
 var (
-	blockProcTimer = timer.NewTimer("block_write_process")
-	EventProcTime  = metrics.Must1LabelHistogram("midgard_chain_event_process_seconds", "type", 0.001, 0.01, 0.1)
+	blockProcTimer  = timer.NewTimer("block_write_process")
+	blockParseTimer = timer.NewTimer("block_write_parse")
+	EventProcTime   = metrics.Must1LabelHistogram("midgard_chain_event_process_seconds", "type",
+		1e-6, 1e-5, 1e-4, 0.001, 0.01, 0.1)
 
 	EventTotal            = metrics.Must1LabelCounter("midgard_chain_events_total", "group")
 	DeliverTxEventsTotal  = EventTotal("deliver_tx")
@@ -38,15 +41,44 @@ type Metadata struct {
 	BlockTimestamp time.Time // official acceptance moment
 }
 
-// Block invokes Listener for each transaction event in block.
-func ProcessBlock(block *chain.Block) {
-	defer blockProcTimer.One()()
+// A block with its events parsed and corrected, ready to be recorded.
+type ParsedBlock struct {
+	Block  *chain.Block
+	meta   Metadata
+	events []parsedEvent
+
+	// Set by RecordBlock: the events recorded in order, including the ones added by the
+	// corrections, as pointers to the record structs, and the pool depth changes.
+	Recorded     []interface{}
+	DepthChanges []DepthChange
+}
+
+type parsedEvent struct {
+	// Pointer to the parsed event, nil if the event is not recorded.
+	x   interface{}
+	err error
+	// Position of the event in the block, for the error log.
+	position  string
+	eventType string
+}
+
+// ParseBlock parses the events of the block and applies the corrections which don't depend on
+// the state. It doesn't modify the global state, so blocks can be parsed in parallel.
+func ParseBlock(block *chain.Block) *ParsedBlock {
+	defer blockParseTimer.One()()
 
 	applyBlockCorrections(block)
 
-	m := Metadata{
-		BlockHeight:    block.Height,
-		BlockTimestamp: block.Time,
+	ret := &ParsedBlock{
+		Block: block,
+		meta: Metadata{
+			BlockHeight:    block.Height,
+			BlockTimestamp: block.Time,
+		},
+	}
+	add := func(event abci.Event, position string) {
+		x, err := parseEvent(event, &ret.meta)
+		ret.events = append(ret.events, parsedEvent{x, err, position, event.Type})
 	}
 
 	// “The BeginBlock ABCI message is sent from the underlying Tendermint
@@ -55,21 +87,13 @@ func ProcessBlock(block *chain.Block) {
 	// It allows developers to have logic be executed at the beginning of
 	// each block.”
 	// — https://docs.cosmos.network/master/core/baseapp.html#beginblock
-	BeginBlockEventsTotal.Add(uint64(len(block.Results.BeginBlockEvents)))
 	for eventIndex, event := range block.Results.BeginBlockEvents {
-		if err := processEvent(event, &m); err != nil {
-			miderr.LogEventParseErrorF("block height %d begin event %d type %q skipped: %s",
-				block.Height, eventIndex, event.Type, err)
-		}
+		add(event, fmt.Sprintf("begin event %d", eventIndex))
 	}
 
 	for txIndex, tx := range block.Results.TxsResults {
-		DeliverTxEventsTotal.Add(uint64(len(tx.Events)))
 		for eventIndex, event := range tx.Events {
-			if err := processEvent(event, &m); err != nil {
-				miderr.LogEventParseErrorF("block height %d tx %d event %d type %q skipped: %s",
-					block.Height, txIndex, eventIndex, event.Type, err)
-			}
+			add(event, fmt.Sprintf("tx %d event %d", txIndex, eventIndex))
 		}
 	}
 
@@ -78,244 +102,210 @@ func ProcessBlock(block *chain.Block) {
 	// It allows developers to have logic be executed at the end of each
 	// block.”
 	// — https://docs.cosmos.network/master/core/baseapp.html#endblock
-	EndBlockEventsTotal.Add(uint64(len(block.Results.EndBlockEvents)))
 	for eventIndex, event := range block.Results.EndBlockEvents {
-		if err := processEvent(event, &m); err != nil {
-			miderr.LogEventParseErrorF("block height %d end event %d type %q skipped: %s",
-				block.Height, eventIndex, event.Type, err)
+		add(event, fmt.Sprintf("end event %d", eventIndex))
+	}
+	return ret
+}
+
+// RecordBlock invokes Recorder for each event of the block. Blocks have to be recorded in
+// order.
+func RecordBlock(b *ParsedBlock) {
+	defer blockProcTimer.One()()
+
+	BeginBlockEventsTotal.Add(uint64(len(b.Block.Results.BeginBlockEvents)))
+	for _, tx := range b.Block.Results.TxsResults {
+		DeliverTxEventsTotal.Add(uint64(len(tx.Events)))
+	}
+	EndBlockEventsTotal.Add(uint64(len(b.Block.Results.EndBlockEvents)))
+
+	for _, e := range b.events {
+		if e.err != nil {
+			miderr.LogEventParseErrorF("block height %d %s type %q skipped: %s",
+				b.Block.Height, e.position, e.eventType, e.err)
+			continue
+		}
+		if e.x != nil {
+			recordEvent(e.x, &b.meta)
 		}
 	}
 
-	AddMissingEvents(&m)
+	AddMissingEvents(&b.meta)
+
+	b.Recorded = Recorder.recorded
+	Recorder.recorded = nil
+	b.DepthChanges = Recorder.flushDepthChanges(&b.meta)
+}
+
+// ProcessBlock parses and records the events of the block.
+func ProcessBlock(block *chain.Block) {
+	RecordBlock(ParseBlock(block))
+}
+
+// ParseBlocks parses the blocks on `workers` goroutines, the parsed blocks are returned in the
+// same order as they arrived. The returned channel is closed when ctx is cancelled or blocks is
+// closed.
+func ParseBlocks(ctx context.Context, blocks <-chan chain.Block, workers int) <-chan *ParsedBlock {
+	if workers < 1 {
+		workers = 1
+	}
+	// The results in arrival order, the buffer limits the blocks being parsed at the same time.
+	pending := make(chan chan *ParsedBlock, workers)
+	ret := make(chan *ParsedBlock)
+
+	go func() {
+		defer close(pending)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case block, ok := <-blocks:
+				if !ok {
+					return
+				}
+				result := make(chan *ParsedBlock, 1)
+				select {
+				case pending <- result:
+				case <-ctx.Done():
+					return
+				}
+				go func() {
+					result <- ParseBlock(&block)
+				}()
+			}
+		}
+	}()
+
+	go func() {
+		defer close(ret)
+		for result := range pending {
+			parsed := <-result
+			select {
+			case ret <- parsed:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ret
 }
 
 var errEventType = errors.New("unknown event type")
 
-// Block notifies Listener for the transaction event.
+type tendermintEvent interface {
+	LoadTendermint(attrs []abci.EventAttribute) error
+}
+
+// Returns a pointer to the parsed event, or nil if the event is not recorded.
 // Errors do not include the event type in the message.
-func processEvent(event abci.Event, meta *Metadata) error {
+func parseEvent(event abci.Event, meta *Metadata) (interface{}, error) {
 	defer EventProcTime(event.Type).AddSince(time.Now())
 
 	attrs := event.Attributes
 	AttrPerEvent.Add(float64(len(attrs)))
 
-	switch event.Type {
-	case "ActiveVault":
-		var x ActiveVault
-		if err := x.LoadTendermint(attrs); err != nil {
-			return err
-		}
-		Recorder.OnActiveVault(&x, meta)
-	case "donate":
-		// TODO(acsaba): rename add to donate
-		var x Add
-		if err := x.LoadTendermint(attrs); err != nil {
-			return err
-		}
-		Recorder.OnAdd(&x, meta)
-	case "asgard_fund_yggdrasil":
-		var x AsgardFundYggdrasil
-		if err := x.LoadTendermint(attrs); err != nil {
-			return err
-		}
-		Recorder.OnAsgardFundYggdrasil(&x, meta)
-	case "bond":
-		var x Bond
-		if err := x.LoadTendermint(attrs); err != nil {
-			return err
-		}
-		Recorder.OnBond(&x, meta)
-	case "errata":
-		var x Errata
-		if err := x.LoadTendermint(attrs); err != nil {
-			return err
+	newEvent, ok := eventTypes[event.Type]
+	if !ok {
+		if ignoredEventTypes[event.Type] {
+			return nil, nil
 		}
-		Recorder.OnErrata(&x, meta)
-	case "fee":
-		var x Fee
-		if err := x.LoadTendermint(attrs); err != nil {
-			return err
-		}
-		if CorrectionsFeeEventIsOK(&x, meta) {
-			Recorder.OnFee(&x, meta)
-		}
-	case "InactiveVault":
-		var x InactiveVault
-		if err := x.LoadTendermint(attrs); err != nil {
-			return err
-		}
-		Recorder.OnInactiveVault(&x, meta)
-	case "gas":
-		var x Gas
-		if err := x.LoadTendermint(attrs); err != nil {
-			return err
-		}
-		Recorder.OnGas(&x, meta)
-	case "message":
-		var x Message
-		if err := x.LoadTendermint(attrs); err != nil {
-			return err
-		}
-		Recorder.OnMessage(&x, meta)
-	case "new_node":
-		var x NewNode
-		if err := x.LoadTendermint(attrs); err != nil {
-			return err
-		}
-		Recorder.OnNewNode(&x, meta)
-	case "outbound":
-		var x Outbound
-		if err := x.LoadTendermint(attrs); err != nil {
-			return err
-		}
-		Recorder.OnOutbound(&x, meta)
-	case "pool":
-		var x Pool
-		if err := x.LoadTendermint(attrs); err != nil {
-			return err
-		}
-		Recorder.OnPool(&x, meta)
-	case "refund":
-		var x Refund
-		if err := x.LoadTendermint(attrs); err != nil {
-			return err
-		}
-		Recorder.OnRefund(&x, meta)
-	case "reserve":
-		var x Reserve
-		if err := x.LoadTendermint(attrs); err != nil {
-			return err
-		}
-		Recorder.OnReserve(&x, meta)
-	case "rewards":
-		var x Rewards
-		if err := x.LoadTendermint(attrs); err != nil {
-			return err
-		}
-		PoolRewardsTotal.Add(uint64(len(x.PerPool)))
-		Recorder.OnRewards(&x, meta)
-	case "set_ip_address":
-		var x SetIPAddress
-		if err := x.LoadTendermint(attrs); err != nil {
-			return err
-		}
-		Recorder.OnSetIPAddress(&x, meta)
-	case "set_mimir":
-		var x SetMimir
-		if err := x.LoadTendermint(attrs); err != nil {
-			return err
-		}
-		Recorder.OnSetMimir(&x, meta)
-	case "set_node_keys":
-		var x SetNodeKeys
-		if err := x.LoadTendermint(attrs); err != nil {
-			return err
-		}
-		Recorder.OnSetNodeKeys(&x, meta)
-	case "set_version":
-		var x SetVersion
-		if err := x.LoadTendermint(attrs); err != nil {
-			return err
-		}
-		Recorder.OnSetVersion(&x, meta)
-	case "slash":
-		var x Slash
-		if err := x.LoadTendermint(attrs); err != nil {
-			return err
-		}
-		Recorder.OnSlash(&x, meta)
-	case "pending_liquidity":
-		var x PendingLiquidity
-		if err := x.LoadTendermint(attrs); err != nil {
-			return err
-		}
-		Recorder.OnPendingLiquidity(&x, meta)
-	case "add_liquidity":
-		var x Stake
-		if err := x.LoadTendermint(attrs); err != nil {
-			return err
-		}
-		Recorder.OnStake(&x, meta)
-	case "swap":
-		var x Swap
-		if err := x.LoadTendermint(attrs); err != nil {
-			return err
-		}
-		Recorder.OnSwap(&x, meta)
-	case "transfer":
-		var x Transfer
-		if err := x.LoadTendermint(attrs); err != nil {
-			return err
-		}
-		Recorder.OnTransfer(&x, meta)
-	case "withdraw":
-		// TODO(acsaba): rename unstake->withdraw.
-		var x Unstake
-		if err := x.LoadTendermint(attrs); err != nil {
-			return err
-		}
-		if CorrectWithdraw(&x, meta) == Discard {
-			break
-		}
-		Recorder.OnUnstake(&x, meta)
-	case "UpdateNodeAccountStatus":
-		var x UpdateNodeAccountStatus
-		if err := x.LoadTendermint(attrs); err != nil {
-			return err
-		}
-		Recorder.OnUpdateNodeAccountStatus(&x, meta)
-	case "validator_request_leave":
-		var x ValidatorRequestLeave
-		if err := x.LoadTendermint(attrs); err != nil {
-			return err
-		}
-		Recorder.OnValidatorRequestLeave(&x, meta)
-	case "pool_balance_change":
-		var x PoolBalanceChange
-		if err := x.LoadTendermint(attrs); err != nil {
-			return err
-		}
-		Recorder.OnPoolBalanceChange(&x, meta)
-	case "thorname":
-		var x THORNameChange
-		if err := x.LoadTendermint(attrs); err != nil {
-			return err
-		}
-		Recorder.OnTHORNameChange(&x, meta)
-	case "switch":
-		var x Switch
-		if err := x.LoadTendermint(attrs); err != nil {
-			return err
-		}
-		Recorder.OnSwitch(&x, meta)
-	case "slash_points":
-		var x SlashPoints
-		if err := x.LoadTendermint(attrs); err != nil {
-			return err
-		}
-		Recorder.OnSlashPoints(&x, meta)
-	case "set_node_mimir":
-		var x SetNodeMimir
-		if err := x.LoadTendermint(attrs); err != nil {
-			return err
-		}
-		Recorder.OnSetNodeMimir(&x, meta)
-	case "tx":
-	case "coin_spent", "coin_received":
-	case "coinbase":
-	case "burn":
-	case "tss_keygen", "tss_keysign":
-	case "create_client", "update_client":
-	case "connection_open_init":
-	case "security":
-	case "scheduled_outbound":
-	default:
 		miderr.LogEventParseErrorF("Unknown event type: %s, attributes: %s",
 			event.Type, FormatAttributes(attrs))
 		UnknownsTotal.Add(1)
-		return errEventType
+		return nil, errEventType
+	}
+	x := newEvent()
+	if err := x.LoadTendermint(attrs); err != nil {
+		return nil, err
+	}
+
+	switch e := x.(type) {
+	case *Fee:
+		if !CorrectionsFeeEventIsOK(e, meta) {
+			return nil, nil
+		}
+	case *Unstake:
+		if CorrectWithdraw(e, meta) == Discard {
+			return nil, nil
+		}
+	}
+	return x, nil
+}
+
+// Notifies the Recorder of a parsed event. The corrections add events with it too.
+func recordEvent(x interface{}, meta *Metadata) {
+	switch e := x.(type) {
+	case *ActiveVault:
+		Recorder.OnActiveVault(e, meta)
+	case *Add:
+		Recorder.OnAdd(e, meta)
+	case *AsgardFundYggdrasil:
+		Recorder.OnAsgardFundYggdrasil(e, meta)
+	case *Bond:
+		Recorder.OnBond(e, meta)
+	case *Errata:
+		Recorder.OnErrata(e, meta)
+	case *Fee:
+		Recorder.OnFee(e, meta)
+	case *InactiveVault:
+		Recorder.OnInactiveVault(e, meta)
+	case *Gas:
+		Recorder.OnGas(e, meta)
+	case *Message:
+		Recorder.OnMessage(e, meta)
+	case *NewNode:
+		Recorder.OnNewNode(e, meta)
+	case *Outbound:
+		Recorder.OnOutbound(e, meta)
+	case *Pool:
+		Recorder.OnPool(e, meta)
+	case *Refund:
+		Recorder.OnRefund(e, meta)
+	case *Reserve:
+		Recorder.OnReserve(e, meta)
+	case *Rewards:
+		PoolRewardsTotal.Add(uint64(len(e.PerPool)))
+		Recorder.OnRewards(e, meta)
+	case *SetIPAddress:
+		Recorder.OnSetIPAddress(e, meta)
+	case *SetMimir:
+		Recorder.OnSetMimir(e, meta)
+	case *SetNodeKeys:
+		Recorder.OnSetNodeKeys(e, meta)
+	case *SetVersion:
+		Recorder.OnSetVersion(e, meta)
+	case *Slash:
+		Recorder.OnSlash(e, meta)
+	case *PendingLiquidity:
+		Recorder.OnPendingLiquidity(e, meta)
+	case *Stake:
+		Recorder.OnStake(e, meta)
+	case *Swap:
+		Recorder.OnSwap(e, meta)
+	case *Transfer:
+		Recorder.OnTransfer(e, meta)
+	case *Unstake:
+		Recorder.OnUnstake(e, meta)
+	case *UpdateNodeAccountStatus:
+		Recorder.OnUpdateNodeAccountStatus(e, meta)
+	case *ValidatorRequestLeave:
+		Recorder.OnValidatorRequestLeave(e, meta)
+	case *PoolBalanceChange:
+		Recorder.OnPoolBalanceChange(e, meta)
+	case *THORNameChange:
+		Recorder.OnTHORNameChange(e, meta)
+	case *Switch:
+		Recorder.OnSwitch(e, meta)
+	case *SlashPoints:
+		Recorder.OnSlashPoints(e, meta)
+	case *SetNodeMimir:
+		Recorder.OnSetNodeMimir(e, meta)
+	default:
+		miderr.LogEventParseErrorF("Parsed event of unexpected type %T", x)
+		return
 	}
-	return nil
+	Recorder.recorded = append(Recorder.recorded, x)
 }
 
 func FormatAttributes(attrs []abci.EventAttribute) string {
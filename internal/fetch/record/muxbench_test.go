@@ -1,11 +1,16 @@
 package record_test
 
 import (
+	"context"
+	"fmt"
 	"testing"
+	"time"
 
 	abci "github.com/tendermint/tendermint/abci/types"
+	coretypes "github.com/tendermint/tendermint/rpc/core/types"
 	"gitlab.com/thorchain/midgard/internal/db/testdb"
 	"gitlab.com/thorchain/midgard/internal/fetch/record"
+	"gitlab.com/thorchain/midgard/internal/fetch/sync/chain"
 )
 
 type FakeDemux struct {
@@ -141,3 +146,70 @@ func BenchmarkLoadDirect(b *testing.B) {
 		}
 	}
 }
+
+// Blocks with the same events as `events` repeated, the first block adds liquidity to the pool.
+func benchBlocks(count, txsPerBlock int) []chain.Block {
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	ret := make([]chain.Block, count)
+	for i := range ret {
+		height := int64(i + 1)
+		results := &coretypes.ResultBlockResults{Height: height}
+		if i == 0 {
+			results.EndBlockEvents = []abci.Event{testdb.AddLiquidity{
+				Pool:        "BTC.BTC",
+				AssetAmount: 1000,
+				RuneAmount:  2000,
+			}.ToTendermint()}
+		}
+		for j := 0; j < txsPerBlock; j++ {
+			results.TxsResults = append(results.TxsResults, &abci.ResponseDeliverTx{Events: events})
+		}
+		ret[i] = chain.Block{
+			Height:  height,
+			Time:    start.Add(time.Duration(i) * 5 * time.Second),
+			Hash:    []byte(fmt.Sprintf("hash%d", height)),
+			Results: results,
+		}
+	}
+	return ret
+}
+
+func BenchmarkParseBlockSequential(b *testing.B) {
+	blocks := benchBlocks(b.N, 100)
+	b.ResetTimer()
+	for i := range blocks {
+		parsedBlocks += len(record.ParseBlock(&blocks[i]).Block.Hash)
+	}
+}
+
+// Compared to BenchmarkParseBlockSequential the throughput should scale with the workers until
+// the cores are saturated:
+//
+// $ go test -run=NONE -bench ParseBlock -cpu 1,4,8 ./internal/fetch/record/
+func benchmarkParseBlockParallel(b *testing.B, workers int) {
+	blocks := benchBlocks(b.N, 100)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	b.ResetTimer()
+
+	in := make(chan chain.Block)
+	go func() {
+		for _, block := range blocks {
+			in <- block
+		}
+		close(in)
+	}()
+	for parsed := range record.ParseBlocks(ctx, in, workers) {
+		parsedBlocks += len(parsed.Block.Hash)
+	}
+}
+
+var parsedBlocks int
+
+func BenchmarkParseBlockParallel4(b *testing.B) {
+	benchmarkParseBlockParallel(b, 4)
+}
+
+func BenchmarkParseBlockParallel8(b *testing.B) {
+	benchmarkParseBlockParallel(b, 8)
+}
@@ -23,8 +23,17 @@ var Recorder = &eventRecorder{
 	runningTotals: *newRunningTotals(),
 }
 
+// Drops the in-memory state, it's restored from the DB with timeseries.Setup.
+func ResetRecorder() {
+	Recorder.runningTotals = *newRunningTotals()
+	Recorder.recorded = nil
+}
+
 type eventRecorder struct {
 	runningTotals
+
+	// Events recorded in the current block, see ParsedBlock.Recorded.
+	recorded []interface{}
 }
 
 func (*eventRecorder) OnActiveVault(e *ActiveVault, meta *Metadata) {
@@ -49,8 +58,8 @@ func (r *eventRecorder) OnAdd(e *Add, meta *Metadata) {
 		return
 	}
 
-	r.AddPoolAssetE8Depth(e.Pool, e.AssetE8)
-	r.AddPoolRuneE8Depth(e.Pool, e.RuneE8)
+	r.AddPoolAssetE8Depth(e.Pool, DepthChangeDonate, e.AssetE8)
+	r.AddPoolRuneE8Depth(e.Pool, DepthChangeDonate, e.RuneE8)
 }
 
 func (r *eventRecorder) OnAsgardFundYggdrasil(e *AsgardFundYggdrasil, meta *Metadata) {
@@ -86,8 +95,8 @@ func (r *eventRecorder) OnErrata(e *Errata, meta *Metadata) {
 		return
 	}
 
-	r.AddPoolAssetE8Depth(e.Asset, e.AssetE8)
-	r.AddPoolRuneE8Depth(e.Asset, e.RuneE8)
+	r.AddPoolAssetE8Depth(e.Asset, DepthChangeErrata, e.AssetE8)
+	r.AddPoolRuneE8Depth(e.Asset, DepthChangeErrata, e.RuneE8)
 }
 
 func (r *eventRecorder) OnFee(e *Fee, meta *Metadata) {
@@ -107,12 +116,12 @@ func (r *eventRecorder) OnFee(e *Fee, meta *Metadata) {
 	pool := GetNativeAsset(e.Asset)
 	if !IsRune(e.Asset) {
 		if coinType == AssetNative {
-			r.AddPoolAssetE8Depth(pool, e.AssetE8)
+			r.AddPoolAssetE8Depth(pool, DepthChangeFee, e.AssetE8)
 		}
 		if coinType == AssetSynth {
-			r.AddPoolSynthE8Depth(pool, -e.AssetE8)
+			r.AddPoolSynthE8Depth(pool, DepthChangeFee, -e.AssetE8)
 		}
-		r.AddPoolRuneE8Depth(pool, -e.PoolDeduct)
+		r.AddPoolRuneE8Depth(pool, DepthChangeFee, -e.PoolDeduct)
 	}
 }
 
@@ -125,8 +134,8 @@ func (r *eventRecorder) OnGas(e *Gas, meta *Metadata) {
 		return
 	}
 
-	r.AddPoolAssetE8Depth(e.Asset, -e.AssetE8)
-	r.AddPoolRuneE8Depth(e.Asset, e.RuneE8)
+	r.AddPoolAssetE8Depth(e.Asset, DepthChangeGas, -e.AssetE8)
+	r.AddPoolRuneE8Depth(e.Asset, DepthChangeGas, e.RuneE8)
 }
 
 func (*eventRecorder) OnInactiveVault(e *InactiveVault, meta *Metadata) {
@@ -186,6 +195,10 @@ func (r *eventRecorder) OnPool(e *Pool, meta *Metadata) {
 	}
 	if strings.ToLower(string(e.Status)) == "suspended" {
 		pool := string(e.Asset)
+		assetE8, runeE8, _ := r.CurrentDepths(e.Asset)
+		suspension := r.depthChanges.get(e.Asset, DepthChangeSuspension)
+		suspension.assetE8 -= assetE8
+		suspension.runeE8 -= runeE8
 		r.SetAssetDepth(pool, 0)
 		r.SetRuneDepth(pool, 0)
 	}
@@ -242,7 +255,7 @@ func (r *eventRecorder) OnRewards(e *Rewards, meta *Metadata) {
 	}
 
 	for _, a := range e.PerPool {
-		r.AddPoolRuneE8Depth(a.Asset, a.E8)
+		r.AddPoolRuneE8Depth(a.Asset, DepthChangeReward, a.E8)
 	}
 }
 
@@ -301,9 +314,9 @@ func (r *eventRecorder) OnSlash(e *Slash, meta *Metadata) {
 		coinType := GetCoinType(a.Asset)
 		switch coinType {
 		case Rune:
-			r.AddPoolRuneE8Depth(e.Pool, a.E8)
+			r.AddPoolRuneE8Depth(e.Pool, DepthChangeSlash, a.E8)
 		case AssetNative:
-			r.AddPoolAssetE8Depth(e.Pool, a.E8)
+			r.AddPoolAssetE8Depth(e.Pool, DepthChangeSlash, a.E8)
 		default:
 			miderr.LogEventParseErrorF("Unhandeled slash coin type: %s", a.Asset)
 		}
@@ -354,8 +367,8 @@ func (r *eventRecorder) OnStake(e *Stake, meta *Metadata) {
 		return
 	}
 
-	r.AddPoolAssetE8Depth(e.Pool, e.AssetE8)
-	r.AddPoolRuneE8Depth(e.Pool, e.RuneE8)
+	r.AddPoolAssetE8Depth(e.Pool, DepthChangeAdd, e.AssetE8)
+	r.AddPoolRuneE8Depth(e.Pool, DepthChangeAdd, e.RuneE8)
 }
 
 type PoolDepths struct {
@@ -435,22 +448,22 @@ func (r *eventRecorder) OnSwap(e *Swap, meta *Metadata) {
 	if toCoin == Rune {
 		// Swap adds pool asset in exchange of RUNE.
 		if fromCoin == AssetNative {
-			r.AddPoolAssetE8Depth(e.Pool, e.FromE8)
+			r.AddPoolAssetE8Depth(e.Pool, DepthChangeSwap, e.FromE8)
 		}
 		// Swap burns synths in exchange of RUNE.
 		if fromCoin == AssetSynth {
-			r.AddPoolSynthE8Depth(e.Pool, -e.FromE8)
+			r.AddPoolSynthE8Depth(e.Pool, DepthChangeSwap, -e.FromE8)
 		}
-		r.AddPoolRuneE8Depth(e.Pool, -e.ToE8)
+		r.AddPoolRuneE8Depth(e.Pool, DepthChangeSwap, -e.ToE8)
 	} else {
 		// Swap adds RUNE to pool in exchange of asset.
-		r.AddPoolRuneE8Depth(e.Pool, e.FromE8)
+		r.AddPoolRuneE8Depth(e.Pool, DepthChangeSwap, e.FromE8)
 		if toCoin == AssetNative {
-			r.AddPoolAssetE8Depth(e.Pool, -e.ToE8)
+			r.AddPoolAssetE8Depth(e.Pool, DepthChangeSwap, -e.ToE8)
 		}
 		// Swap mints synths in exchange of RUNE.
 		if toCoin == AssetSynth {
-			r.AddPoolSynthE8Depth(e.Pool, e.ToE8)
+			r.AddPoolSynthE8Depth(e.Pool, DepthChangeSwap, e.ToE8)
 		}
 	}
 }
@@ -497,11 +510,11 @@ func (r *eventRecorder) OnUnstake(e *Unstake, meta *Metadata) {
 		miderr.LogEventParseErrorF("unstake event from height %d lost on %s", meta.BlockHeight, err)
 	}
 	// Rune/Asset withdrawn from pool
-	r.AddPoolAssetE8Depth(e.Pool, -e.EmitAssetE8)
-	r.AddPoolRuneE8Depth(e.Pool, -e.EmitRuneE8)
+	r.AddPoolAssetE8Depth(e.Pool, DepthChangeWithdraw, -e.EmitAssetE8)
+	r.AddPoolRuneE8Depth(e.Pool, DepthChangeWithdraw, -e.EmitRuneE8)
 
 	// Rune added to pool from reserve as impermanent loss protection
-	r.AddPoolRuneE8Depth(e.Pool, e.ImpLossProtectionE8)
+	r.AddPoolRuneE8Depth(e.Pool, DepthChangeWithdraw, e.ImpLossProtectionE8)
 
 	// Logic for withdraw changed since start of chaosnet 2021-04.
 	//
@@ -520,7 +533,7 @@ func (r *eventRecorder) OnUnstake(e *Unstake, meta *Metadata) {
 	//     the coin sent in.
 	if meta.BlockHeight < withdrawCoinKeptHeight {
 		if e.AssetE8 != 0 && string(e.Pool) == string(e.Asset) {
-			r.AddPoolAssetE8Depth(e.Pool, e.AssetE8)
+			r.AddPoolAssetE8Depth(e.Pool, DepthChangeWithdraw, e.AssetE8)
 		}
 	}
 }
@@ -567,14 +580,14 @@ func (r *eventRecorder) OnPoolBalanceChange(e *PoolBalanceChange, meta *Metadata
 		if !e.AssetAdd {
 			assetAmount *= -1
 		}
-		r.AddPoolAssetE8Depth(e.Asset, assetAmount)
+		r.AddPoolAssetE8Depth(e.Asset, DepthChangePoolBalanceChange, assetAmount)
 	}
 	runeAmount := e.RuneAmt
 	if runeAmount != 0 {
 		if !e.RuneAdd {
 			runeAmount *= -1
 		}
-		r.AddPoolRuneE8Depth(e.Asset, runeAmount)
+		r.AddPoolRuneE8Depth(e.Asset, DepthChangePoolBalanceChange, runeAmount)
 	}
 }
 
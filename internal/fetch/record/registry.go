@@ -0,0 +1,77 @@
+package record
+
+import "sort"
+
+// Constructors of the recorded events by their Tendermint event type.
+var eventTypes = map[string]func() tendermintEvent{
+	"ActiveVault":           func() tendermintEvent { return &ActiveVault{} },
+	"asgard_fund_yggdrasil": func() tendermintEvent { return &AsgardFundYggdrasil{} },
+	"bond":                  func() tendermintEvent { return &Bond{} },
+	// TODO(acsaba): rename add to donate
+	"donate":            func() tendermintEvent { return &Add{} },
+	"errata":            func() tendermintEvent { return &Errata{} },
+	"fee":               func() tendermintEvent { return &Fee{} },
+	"InactiveVault":     func() tendermintEvent { return &InactiveVault{} },
+	"gas":               func() tendermintEvent { return &Gas{} },
+	"message":           func() tendermintEvent { return &Message{} },
+	"new_node":          func() tendermintEvent { return &NewNode{} },
+	"outbound":          func() tendermintEvent { return &Outbound{} },
+	"pool":              func() tendermintEvent { return &Pool{} },
+	"refund":            func() tendermintEvent { return &Refund{} },
+	"reserve":           func() tendermintEvent { return &Reserve{} },
+	"rewards":           func() tendermintEvent { return &Rewards{} },
+	"set_ip_address":    func() tendermintEvent { return &SetIPAddress{} },
+	"set_mimir":         func() tendermintEvent { return &SetMimir{} },
+	"set_node_keys":     func() tendermintEvent { return &SetNodeKeys{} },
+	"set_version":       func() tendermintEvent { return &SetVersion{} },
+	"slash":             func() tendermintEvent { return &Slash{} },
+	"pending_liquidity": func() tendermintEvent { return &PendingLiquidity{} },
+	"add_liquidity":     func() tendermintEvent { return &Stake{} },
+	"swap":              func() tendermintEvent { return &Swap{} },
+	"transfer":          func() tendermintEvent { return &Transfer{} },
+	// TODO(acsaba): rename unstake->withdraw.
+	"withdraw":                func() tendermintEvent { return &Unstake{} },
+	"UpdateNodeAccountStatus": func() tendermintEvent { return &UpdateNodeAccountStatus{} },
+	"validator_request_leave": func() tendermintEvent { return &ValidatorRequestLeave{} },
+	"pool_balance_change":     func() tendermintEvent { return &PoolBalanceChange{} },
+	"thorname":                func() tendermintEvent { return &THORNameChange{} },
+	"switch":                  func() tendermintEvent { return &Switch{} },
+	"slash_points":            func() tendermintEvent { return &SlashPoints{} },
+	"set_node_mimir":          func() tendermintEvent { return &SetNodeMimir{} },
+}
+
+// Known Tendermint event types which are not recorded.
+var ignoredEventTypes = map[string]bool{
+	"tx":                   true,
+	"coin_spent":           true,
+	"coin_received":        true,
+	"coinbase":             true,
+	"burn":                 true,
+	"tss_keygen":           true,
+	"tss_keysign":          true,
+	"create_client":        true,
+	"update_client":        true,
+	"connection_open_init": true,
+	"security":             true,
+	"scheduled_outbound":   true,
+}
+
+// EventTypes returns the Tendermint types of all the recorded events in alphabetic order.
+func EventTypes() []string {
+	ret := make([]string, 0, len(eventTypes))
+	for t := range eventTypes {
+		ret = append(ret, t)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// NewEvent returns a pointer to an empty event struct for the Tendermint type (e.g. *Stake for
+// "add_liquidity"), or nil if events of the type are not recorded.
+func NewEvent(eventType string) interface{} {
+	newEvent, ok := eventTypes[eventType]
+	if !ok {
+		return nil
+	}
+	return newEvent()
+}
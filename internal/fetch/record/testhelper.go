@@ -1,5 +1,5 @@
 package record
 
 func ResetRecorderForTest() {
-	Recorder.runningTotals = *newRunningTotals()
+	ResetRecorder()
 }
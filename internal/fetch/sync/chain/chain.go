@@ -2,17 +2,23 @@
 package chain
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
+	"sync"
 	"time"
 
 	"github.com/pascaldekloe/metrics"
 	rpchttp "github.com/tendermint/tendermint/rpc/client/http"
 	coretypes "github.com/tendermint/tendermint/rpc/core/types"
+	jsonrpctypes "github.com/tendermint/tendermint/rpc/jsonrpc/types"
+	tmtypes "github.com/tendermint/tendermint/types"
 	"gitlab.com/thorchain/midgard/config"
 	"gitlab.com/thorchain/midgard/internal/db"
+	"gitlab.com/thorchain/midgard/internal/fetch/endpoint"
 	"gitlab.com/thorchain/midgard/internal/util/miderr"
 	"gitlab.com/thorchain/midgard/internal/util/midlog"
 	"gitlab.com/thorchain/midgard/internal/util/timer"
@@ -33,22 +39,45 @@ type Block struct {
 	Results *coretypes.ResultBlockResults `json:"results"`
 }
 
-// Client provides Tendermint access.
-type Client struct {
-	ctx context.Context
+// node is a Tendermint RPC endpoint.
+type node struct {
+	*endpoint.Endpoint
 
 	// Single RPC access
 	client *rpchttp.HTTP
 
-	// Parallel / batched access
+	// Parallel / batched access, one client for every parallel fetch
 	batchClients []*rpchttp.BatchHTTP
+}
+
+// Client provides Tendermint access.
+// With multiple endpoints the requests fail over between the nodes, parallel fetches are spread
+// over them and the block hashes are cross-checked.
+type Client struct {
+	ctx context.Context
+
+	urls      []string
+	nodes     map[string]*node
+	endpoints *endpoint.Set
 
 	batchSize   int // divisible by parallelism
 	parallelism int
 }
 
-func (c *Client) FetchSingle(height int64) (*coretypes.ResultBlockResults, error) {
-	return c.client.BlockResults(c.ctx, &height)
+func (c *Client) FetchSingle(height int64) (ret *coretypes.ResultBlockResults, err error) {
+	for _, n := range c.nodesAt(height) {
+		ret, err = n.client.BlockResults(c.ctx, &height)
+		c.report(n, err)
+		if err == nil {
+			return ret, nil
+		}
+	}
+	return nil, err
+}
+
+// Returns false if all the Tendermint endpoints are skipped because of failures.
+func (c *Client) Reachable() bool {
+	return c.endpoints.AnyAvailable()
 }
 
 func (c *Client) BatchSize() int {
@@ -57,48 +86,100 @@ func (c *Client) BatchSize() int {
 
 // NewClient configures a new instance. Timeout applies to all requests on endpoint.
 func NewClient(ctx context.Context) (*Client, error) {
-	cfg := &config.Global
-	var timeout time.Duration = cfg.ThorChain.ReadTimeout.Value()
+	return NewClientFor(ctx, config.Global.ThorChain)
+}
 
-	endpoint, err := url.Parse(cfg.ThorChain.TendermintURL)
-	if err != nil {
-		logger.FatalE(err, "Exit on malformed Tendermint RPC URL")
-	}
+// Like NewClient, but with the endpoints of cfg.
+func NewClientFor(ctx context.Context, cfg config.ThorChain) (*Client, error) {
+	var timeout time.Duration = cfg.ReadTimeout.Value()
 
-	batchSize := cfg.ThorChain.FetchBatchSize
-	parallelism := cfg.ThorChain.Parallelism
+	batchSize := cfg.FetchBatchSize
+	parallelism := cfg.Parallelism
 	if batchSize%parallelism != 0 {
 		logger.FatalF("BatchSize=%d must be divisible by Parallelism=%d", batchSize, parallelism)
 	}
 
+	c := &Client{
+		ctx:   ctx,
+		urls:  append([]string{cfg.TendermintURL}, cfg.ExtraTendermintURLs...),
+		nodes: map[string]*node{},
+		endpoints: endpoint.NewSet(
+			cfg.EndpointMaxFailures, cfg.EndpointRetryAfter.Value()),
+		batchSize:   batchSize,
+		parallelism: parallelism,
+	}
+	for _, u := range c.urls {
+		n, err := newNode(c.endpoints.Endpoint(u), timeout, parallelism)
+		if err != nil {
+			return nil, err
+		}
+		c.nodes[u] = n
+	}
+	return c, nil
+}
+
+func newNode(e *endpoint.Endpoint, timeout time.Duration, parallelism int) (*node, error) {
+	u, err := url.Parse(e.URL)
+	if err != nil {
+		logger.FatalE(err, "Exit on malformed Tendermint RPC URL")
+	}
+
 	// need the path separate from the URL for some reason
-	path := endpoint.Path
-	endpoint.Path = ""
-	remote := endpoint.String()
+	path := u.Path
+	u.Path = ""
+	remote := u.String()
 
-	var client *rpchttp.HTTP
-	var batchClients []*rpchttp.BatchHTTP
+	n := &node{Endpoint: e}
 	for i := 0; i < parallelism; i++ {
 		// rpchttp.NewWithTimeout rounds to seconds for some reason
-		client, err = rpchttp.NewWithClient(remote, path, &http.Client{Timeout: timeout})
+		n.client, err = rpchttp.NewWithClient(remote, path, &http.Client{Timeout: timeout})
 		if err != nil {
 			return nil, fmt.Errorf("tendermint RPC client instantiation: %w", err)
 		}
-		batchClients = append(batchClients, client.NewBatch())
+		n.batchClients = append(n.batchClients, n.client.NewBatch())
+	}
+	return n, nil
+}
+
+// Returns the nodes in the order they should be tried. The ones known to be behind `height`
+// are left out, unless all of them are.
+func (c *Client) nodesAt(height int64) []*node {
+	ret := []*node{}
+	behind := []*node{}
+	for _, e := range c.endpoints.Ordered(c.urls) {
+		n := c.nodes[e.URL]
+		if h := n.Height(); h != 0 && h < height {
+			behind = append(behind, n)
+		} else {
+			ret = append(ret, n)
+		}
+	}
+	if len(ret) == 0 {
+		return behind
 	}
+	return ret
+}
 
-	return &Client{
-		ctx:          ctx,
-		client:       client,
-		batchClients: batchClients,
-		batchSize:    batchSize,
-		parallelism:  parallelism,
-	}, nil
+// Errors returned by the node itself (e.g. the block is not available yet) and cancellation
+// don't count as failures of the node.
+func (c *Client) report(n *node, err error) {
+	var rpcerror *jsonrpctypes.RPCError
+	if errors.As(err, &rpcerror) {
+		err = nil
+	}
+	if err != nil && c.ctx.Err() != nil {
+		return
+	}
+	n.Report(err)
 }
 
 func (c *Client) FirstBlockHash() (hash string, err error) {
+	return c.BlockHash(1)
+}
+
+func (c *Client) BlockHash(height int64) (hash string, err error) {
 	block := Block{}
-	err = c.fetchBlock(&block, 1)
+	err = c.fetchBlock(&block, height)
 	if err != nil {
 		return "", err
 	}
@@ -106,14 +187,50 @@ func (c *Client) FirstBlockHash() (hash string, err error) {
 }
 
 // Fetch the summary of the chain: latest height, node address, ...
+// All the nodes are queried, this also serves as their health check. The status of the node with
+// the latest block is returned.
 func (c *Client) RefreshStatus() (*coretypes.ResultStatus, error) {
-	return c.client.Status(c.ctx)
+	nodes := c.nodesAt(0)
+	statuses := make([]*coretypes.ResultStatus, len(nodes))
+	errs := make([]error, len(nodes))
+	var wg sync.WaitGroup
+	for i, n := range nodes {
+		wg.Add(1)
+		go func(i int, n *node) {
+			defer wg.Done()
+			start := time.Now()
+			statuses[i], errs[i] = n.client.Status(c.ctx)
+			c.report(n, errs[i])
+			if errs[i] == nil {
+				n.ObserveLatency(time.Since(start))
+				n.SetHeight(statuses[i].SyncInfo.LatestBlockHeight)
+			}
+		}(i, n)
+	}
+	wg.Wait()
+
+	var ret *coretypes.ResultStatus
+	var err error
+	for i := range nodes {
+		if errs[i] != nil {
+			err = errs[i]
+			continue
+		}
+		if ret == nil || ret.SyncInfo.LatestBlockHeight < statuses[i].SyncInfo.LatestBlockHeight {
+			ret = statuses[i]
+		}
+	}
+	if ret == nil {
+		return nil, err
+	}
+	return ret, nil
 }
 
 var (
 	fetchTimerBatch    = timer.NewTimer("block_fetch_batch")
 	fetchTimerParallel = timer.NewTimer("block_fetch_parallel")
 	fetchTimerSingle   = timer.NewTimer("block_fetch_single")
+	crossCheckTimer    = timer.NewTimer("block_fetch_cross_check")
 )
 
 type Iterator struct {
@@ -165,10 +282,24 @@ func (i *Iterator) nextBatch() (hasMore bool, err error) {
 	return true, err
 }
 
-func (c *Client) fetchBlock(block *Block, height int64) error {
+func (c *Client) fetchBlock(block *Block, height int64) (err error) {
+	for _, n := range c.nodesAt(height) {
+		err = c.fetchBlockFrom(n, block, height)
+		c.report(n, err)
+		if err == nil {
+			err = c.crossCheck(n, block)
+		}
+		if err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+func (c *Client) fetchBlockFrom(n *node, block *Block, height int64) error {
 	defer fetchTimerSingle.One()()
 
-	info, err := c.client.BlockchainInfo(c.ctx, height, height)
+	info, err := n.client.BlockchainInfo(c.ctx, height, height)
 	if err != nil {
 		return fmt.Errorf("BlockchainInfo for %d, failed: %w", height, err)
 	}
@@ -187,7 +318,7 @@ func (c *Client) fetchBlock(block *Block, height int64) error {
 	block.Time = header.Time
 	block.Hash = []byte(info.BlockMetas[0].BlockID.Hash)
 
-	block.Results, err = c.client.BlockResults(c.ctx, &block.Height)
+	block.Results, err = n.client.BlockResults(c.ctx, &block.Height)
 	if err != nil {
 		return fmt.Errorf("BlockResults for %d, failed: %w", height, err)
 	}
@@ -198,18 +329,88 @@ func (c *Client) fetchBlock(block *Block, height int64) error {
 			height, block.Height, block.Results.Height)
 	}
 
+	next, err := c.nextHeader(n, height)
+	if err != nil {
+		return err
+	}
+	return verifyLinks([]Block{*block}, []*tmtypes.Header{header}, next)
+}
+
+// Returns the header of the block after height, nil if the node doesn't have it yet.
+func (c *Client) nextHeader(n *node, height int64) (*tmtypes.Header, error) {
+	next := height + 1
+	if n.Height() < next {
+		return nil, nil
+	}
+	info, err := n.client.BlockchainInfo(c.ctx, next, next)
+	if err != nil {
+		return nil, fmt.Errorf("BlockchainInfo for %d, failed: %w", next, err)
+	}
+	if len(info.BlockMetas) != 1 || info.BlockMetas[0].Header.Height != next {
+		return nil, fmt.Errorf("BlockchainInfo for %d, wrong results", next)
+	}
+	return &info.BlockMetas[0].Header, nil
+}
+
+// Verifies that the fetched blocks form a chain, so cross-checking the hash of the last block
+// covers all of them: every hash is the hash of its header, and every header contains the hash
+// of the previous block and of the results of the previous block. The results of the last
+// block are verified against next, if it's available.
+// Note: the results hash covers the code, data and gas of the transactions, not the events.
+func verifyLinks(batch []Block, headers []*tmtypes.Header, next *tmtypes.Header) error {
+	for i := range batch {
+		block := &batch[i]
+		if !bytes.Equal(headers[i].Hash(), block.Hash) {
+			return fmt.Errorf("block hash of %d doesn't match its header", block.Height)
+		}
+		if 0 < i && !bytes.Equal(headers[i].LastBlockID.Hash, batch[i-1].Hash) {
+			return fmt.Errorf("header of %d doesn't link to the hash of %d",
+				block.Height, batch[i-1].Height)
+		}
+		resultsHeader := next
+		if i+1 < len(headers) {
+			resultsHeader = headers[i+1]
+		}
+		if resultsHeader == nil {
+			continue
+		}
+		resultsHash := tmtypes.NewResults(block.Results.TxsResults).Hash()
+		if !bytes.Equal(resultsHeader.LastResultsHash, resultsHash) {
+			return fmt.Errorf("BlockResults for %d don't match the results hash of %d",
+				block.Height, resultsHeader.Height)
+		}
+	}
 	return nil
 }
 
-func (c *Client) fetchBlocks(clientIdx int, batch []Block, height int64) error {
+// Fetches a part of a batch from nodes[first], fails over to the next nodes on errors.
+func (c *Client) fetchPart(nodes []*node, first, clientIdx int, batch []Block, height int64) (
+	err error,
+) {
+	for i := range nodes {
+		node := nodes[(first+i)%len(nodes)]
+		err = c.fetchBlocks(node, clientIdx, batch, height)
+		c.report(node, err)
+		if err == nil {
+			err = c.crossCheck(node, &batch[len(batch)-1])
+		}
+		if err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+func (c *Client) fetchBlocks(node *node, clientIdx int, batch []Block, height int64) error {
 	// Note: n > 1 is required
 	n := len(batch)
 	var err error
-	client := c.batchClients[clientIdx]
+	client := node.batchClients[clientIdx]
 	defer fetchTimerBatch.Batch(n)()
 
 	last := height + int64(n) - 1
 	infos := make([]*coretypes.ResultBlockchainInfo, n)
+	headers := make([]*tmtypes.Header, n)
 	for i := 0; i < n; i++ {
 		h := height + int64(i)
 		// Note(huginn): we could do "batched batched" request: asking for 20 BlockchainInfos
@@ -242,6 +443,7 @@ func (c *Client) fetchBlocks(clientIdx int, batch []Block, height int64) error {
 		block.Height = header.Height
 		block.Time = header.Time
 		block.Hash = []byte(info.BlockMetas[0].BlockID.Hash)
+		headers[i] = header
 	}
 
 	for i := range batch {
@@ -267,17 +469,23 @@ func (c *Client) fetchBlocks(clientIdx int, batch []Block, height int64) error {
 		}
 	}
 
-	return nil
+	next, err := c.nextHeader(node, last)
+	if err != nil {
+		return err
+	}
+	return verifyLinks(batch, headers, next)
 }
 
+// The parts of the batch are spread over the nodes which have the blocks.
 func (c *Client) fetchBlocksParallel(batch []Block, height int64, parallelism int) error {
 	n := len(batch)
 	if n == 1 {
 		return c.fetchBlock(&batch[0], height)
 	}
 
+	nodes := c.nodesAt(height + int64(n) - 1)
 	if parallelism == 1 {
-		return c.fetchBlocks(0, batch, height)
+		return c.fetchPart(nodes, 0, 0, batch, height)
 	}
 
 	k := n / parallelism
@@ -287,12 +495,14 @@ func (c *Client) fetchBlocksParallel(batch []Block, height int64, parallelism in
 
 	defer fetchTimerParallel.Batch(n)()
 
+	// Every part uses its own batch client index, so the parts never share a batch client,
+	// not even after failing over to an other node.
 	done := make(chan error, parallelism)
 	for i := 0; i < parallelism; i++ {
 		clientIdx := i
 		start := i * k
 		go func() {
-			err := c.fetchBlocks(clientIdx, batch[start:start+k], height+int64(start))
+			err := c.fetchPart(nodes, clientIdx, clientIdx, batch[start:start+k], height+int64(start))
 			done <- err
 		}()
 	}
@@ -306,3 +516,56 @@ func (c *Client) fetchBlocksParallel(batch []Block, height int64, parallelism in
 	}
 	return err
 }
+
+// Number of other nodes the fetched blocks are compared with.
+const crossCheckNodes = 2
+
+// Compares the hash of the block with other nodes. The batches are verified to be linked by the
+// hashes in the headers (see verifyLinks), so checking the last block covers the whole batch.
+// A node disagreeing with the majority is marked as misbehaving. An error is returned if the
+// block can't be trusted.
+func (c *Client) crossCheck(from *node, block *Block) error {
+	if len(c.urls) == 1 {
+		return nil
+	}
+	defer crossCheckTimer.One()()
+
+	agree := []*node{from}
+	disagree := []*node{}
+	for _, n := range c.nodesAt(block.Height) {
+		if n == from {
+			continue
+		}
+		if crossCheckNodes <= len(agree)+len(disagree)-1 {
+			break
+		}
+		info, err := n.client.BlockchainInfo(c.ctx, block.Height, block.Height)
+		c.report(n, err)
+		if err != nil || len(info.BlockMetas) != 1 {
+			continue
+		}
+		if bytes.Equal(info.BlockMetas[0].BlockID.Hash, block.Hash) {
+			agree = append(agree, n)
+		} else {
+			disagree = append(disagree, n)
+		}
+	}
+	if len(disagree) == 0 {
+		return nil
+	}
+
+	reason := fmt.Sprintf("hash mismatch at height %d", block.Height)
+	switch {
+	case len(disagree) < len(agree):
+		for _, n := range disagree {
+			n.MarkMisbehaving(reason)
+		}
+		return nil
+	case len(agree) < len(disagree):
+		from.MarkMisbehaving(reason)
+		return fmt.Errorf("%s, %s disagrees with the other nodes", reason, from.URL)
+	default:
+		return fmt.Errorf("%s between %s and %s, can't tell which one is right",
+			reason, from.URL, disagree[0].URL)
+	}
+}
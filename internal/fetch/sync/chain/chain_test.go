@@ -0,0 +1,65 @@
+package chain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	coretypes "github.com/tendermint/tendermint/rpc/core/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+)
+
+// Builds n linked blocks starting at height 1, and the header of the block after them.
+func linkedBlocks(n int) ([]Block, []*tmtypes.Header, *tmtypes.Header) {
+	blocks := make([]Block, n)
+	headers := make([]*tmtypes.Header, n+1)
+	for i := 0; i <= n; i++ {
+		header := &tmtypes.Header{
+			ChainID:        "thorchain",
+			Height:         int64(i + 1),
+			ValidatorsHash: []byte("validators"),
+		}
+		if 0 < i {
+			header.LastBlockID.Hash = blocks[i-1].Hash
+			header.LastResultsHash = tmtypes.NewResults(blocks[i-1].Results.TxsResults).Hash()
+		}
+		headers[i] = header
+		if i == n {
+			break
+		}
+		blocks[i] = Block{
+			Height: header.Height,
+			Hash:   header.Hash(),
+			Results: &coretypes.ResultBlockResults{
+				Height:     header.Height,
+				TxsResults: []*abci.ResponseDeliverTx{{Code: 0, Data: []byte{byte(i)}}},
+			},
+		}
+	}
+	return blocks, headers[:n], headers[n]
+}
+
+func TestVerifyLinks(t *testing.T) {
+	blocks, headers, next := linkedBlocks(3)
+	require.NoError(t, verifyLinks(blocks, headers, next))
+	require.NoError(t, verifyLinks(blocks, headers, nil))
+
+	blocks, headers, next = linkedBlocks(3)
+	blocks[1].Hash = []byte("forged")
+	require.Error(t, verifyLinks(blocks, headers, next))
+
+	// A consistent block which is not the one the next header points to.
+	blocks, headers, next = linkedBlocks(3)
+	headers[1].AppHash = []byte("other")
+	blocks[1].Hash = headers[1].Hash()
+	require.Error(t, verifyLinks(blocks, headers, next))
+
+	blocks, headers, next = linkedBlocks(3)
+	blocks[0].Results.TxsResults[0].Code = 1
+	require.Error(t, verifyLinks(blocks, headers, next))
+
+	blocks, headers, next = linkedBlocks(3)
+	blocks[2].Results.TxsResults[0].Code = 1
+	require.Error(t, verifyLinks(blocks, headers, next))
+	require.NoError(t, verifyLinks(blocks, headers, nil))
+}
@@ -0,0 +1,226 @@
+package chain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"sync/atomic"
+	"time"
+
+	"github.com/gobwas/ws"
+	"github.com/gobwas/ws/wsutil"
+	"gitlab.com/thorchain/midgard/internal/db"
+)
+
+// Tendermint pings the subscribers every ~30 seconds, no message for longer means the
+// connection is dead.
+const newBlockReadTimeout = 60 * time.Second
+
+var (
+	newBlockReconnectMin = time.Second
+	newBlockReconnectMax = time.Minute
+)
+
+const subscribeNewBlock = `{"jsonrpc":"2.0","id":1,"method":"subscribe",` +
+	`"params":{"query":"tm.event='NewBlock'"}}`
+
+// NewBlocks receives the NewBlock events from the websocket of a Tendermint node.
+// It reconnects on errors, meanwhile the caller is expected to poll.
+type NewBlocks struct {
+	urls      []string
+	heights   chan int64
+	connected int32
+}
+
+// Subscribes to the NewBlock events until ctx is cancelled. The first of tendermintURLs is used
+// while it works, when it fails the next ones are tried in turn.
+func SubscribeNewBlocks(ctx context.Context, tendermintURLs []string) (*NewBlocks, error) {
+	if len(tendermintURLs) == 0 {
+		return nil, fmt.Errorf("no Tendermint URL")
+	}
+	s := &NewBlocks{heights: make(chan int64, 1)}
+	for _, tendermintURL := range tendermintURLs {
+		wsURL, err := websocketURL(tendermintURL)
+		if err != nil {
+			return nil, err
+		}
+		s.urls = append(s.urls, wsURL)
+	}
+	go s.run(ctx)
+	return s, nil
+}
+
+// The RPC endpoint is configured as http(s)://host:port/websocket.
+func websocketURL(tendermintURL string) (string, error) {
+	u, err := url.Parse(tendermintURL)
+	if err != nil {
+		return "", err
+	}
+	switch u.Scheme {
+	case "http", "ws":
+		u.Scheme = "ws"
+	case "https", "wss":
+		u.Scheme = "wss"
+	default:
+		return "", fmt.Errorf("unsupported scheme of Tendermint URL %q", tendermintURL)
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = "/websocket"
+	}
+	return u.String(), nil
+}
+
+// Height of the last committed block. Only the latest height is kept, if it isn't consumed in
+// time the earlier ones are dropped.
+func (s *NewBlocks) Heights() <-chan int64 {
+	return s.heights
+}
+
+// Returns true while the subscription is active.
+func (s *NewBlocks) Connected() bool {
+	return atomic.LoadInt32(&s.connected) == 1
+}
+
+func (s *NewBlocks) run(ctx context.Context) {
+	backoff := newBlockReconnectMin
+	current := 0
+	for {
+		wsURL := s.urls[current]
+		subscribed, err := s.listen(ctx, wsURL)
+		atomic.StoreInt32(&s.connected, 0)
+		if ctx.Err() != nil {
+			return
+		}
+		if subscribed {
+			backoff = newBlockReconnectMin
+		}
+		if current == 0 && subscribed {
+			logger.WarnF("NewBlock subscription on %s failed, polling until reconnected in %v: %v",
+				wsURL, backoff, err)
+		} else {
+			// The other endpoints are tried right away, the backoff is after a whole round.
+			current = (current + 1) % len(s.urls)
+			if current != 0 {
+				logger.WarnF("NewBlock subscription on %s failed, trying %s: %v",
+					wsURL, s.urls[current], err)
+				continue
+			}
+			logger.WarnF("NewBlock subscription failed on all endpoints, "+
+				"polling until reconnected in %v: %v", backoff, err)
+		}
+		db.SleepWithContext(ctx, backoff)
+		if ctx.Err() != nil {
+			return
+		}
+		backoff *= 2
+		if newBlockReconnectMax < backoff {
+			backoff = newBlockReconnectMax
+		}
+	}
+}
+
+type newBlockMessage struct {
+	Result struct {
+		Data struct {
+			Value struct {
+				Block struct {
+					Header struct {
+						Height int64 `json:"height,string"`
+					} `json:"header"`
+				} `json:"block"`
+			} `json:"value"`
+		} `json:"data"`
+	} `json:"result"`
+	Error *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Data    string `json:"data"`
+	} `json:"error"`
+}
+
+// Returns when the connection fails. Subscribed is true if the subscription was accepted.
+func (s *NewBlocks) listen(ctx context.Context, wsURL string) (subscribed bool, err error) {
+	conn, br, _, err := ws.Dial(ctx, wsURL)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	// Unblocks the reads on cancellation.
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	// The handshake may have read the first frames already.
+	rw := io.ReadWriter(conn)
+	if br != nil {
+		rw = struct {
+			io.Reader
+			io.Writer
+		}{io.MultiReader(br, conn), conn}
+	}
+
+	err = wsutil.WriteClientText(conn, []byte(subscribeNewBlock))
+	if err != nil {
+		return false, err
+	}
+
+	for {
+		err = conn.SetReadDeadline(time.Now().Add(newBlockReadTimeout))
+		if err != nil {
+			return subscribed, err
+		}
+		// Pings are answered while reading.
+		data, err := wsutil.ReadServerText(rw)
+		if err != nil {
+			return subscribed, err
+		}
+
+		var msg newBlockMessage
+		err = json.Unmarshal(data, &msg)
+		if err != nil {
+			return subscribed, fmt.Errorf("malformed message: %w", err)
+		}
+		if msg.Error != nil {
+			return subscribed, fmt.Errorf("subscribe error %d: %s %s",
+				msg.Error.Code, msg.Error.Message, msg.Error.Data)
+		}
+
+		height := msg.Result.Data.Value.Block.Header.Height
+		if height == 0 {
+			// Reply to the subscribe request.
+			if !subscribed {
+				subscribed = true
+				atomic.StoreInt32(&s.connected, 1)
+				logger.InfoF("Subscribed to new blocks on %s", wsURL)
+			}
+			continue
+		}
+		s.notify(height)
+	}
+}
+
+func (s *NewBlocks) notify(height int64) {
+	select {
+	case s.heights <- height:
+		return
+	default:
+	}
+	// Replace the height which wasn't consumed yet.
+	select {
+	case <-s.heights:
+	default:
+	}
+	select {
+	case s.heights <- height:
+	default:
+	}
+}
@@ -0,0 +1,142 @@
+package chain
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gobwas/ws"
+	"github.com/gobwas/ws/wsutil"
+	"github.com/stretchr/testify/require"
+)
+
+// Stand-in for the Tendermint websocket. Every connection gets the subscribe reply and the
+// heights of the next batch of events, then the connection is closed.
+type fakeTendermint struct {
+	t           *testing.T
+	connections chan []int64
+	subscribes  chan string
+}
+
+func (f *fakeTendermint) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	conn, _, _, err := ws.UpgradeHTTP(r, w)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	request, err := wsutil.ReadClientText(conn)
+	if err != nil {
+		return
+	}
+	select {
+	case f.subscribes <- string(request):
+	default:
+	}
+
+	heights, ok := <-f.connections
+	if !ok {
+		return
+	}
+	if heights == nil {
+		reply := `{"jsonrpc":"2.0","id":1,"error":{"code":-32603,"message":"Internal error","data":"subscriptions disabled"}}`
+		_ = wsutil.WriteServerText(conn, []byte(reply))
+		return
+	}
+	_ = wsutil.WriteServerText(conn, []byte(`{"jsonrpc":"2.0","id":1,"result":{}}`))
+	for _, height := range heights {
+		event := fmt.Sprintf(`{"jsonrpc":"2.0","id":"1#event","result":{`+
+			`"query":"tm.event='NewBlock'","data":{"type":"tendermint/event/NewBlock",`+
+			`"value":{"block":{"header":{"chain_id":"thorchain","height":"%d"}}}}}}`, height)
+		_ = wsutil.WriteServerText(conn, []byte(event))
+		time.Sleep(10 * time.Millisecond)
+	}
+	// Keep the connection open until the next batch is requested.
+	<-f.connections
+}
+
+func nextHeight(t *testing.T, s *NewBlocks) int64 {
+	select {
+	case height := <-s.Heights():
+		return height
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "no new block notification")
+		return 0
+	}
+}
+
+func TestNewBlocksSubscription(t *testing.T) {
+	newBlockReconnectMin = 10 * time.Millisecond
+	defer func() { newBlockReconnectMin = time.Second }()
+
+	fake := &fakeTendermint{
+		t: t, connections: make(chan []int64), subscribes: make(chan string, 10)}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s, err := SubscribeNewBlocks(ctx, []string{server.URL + "/websocket"})
+	require.NoError(t, err)
+
+	// Subscription is rejected first, it's retried.
+	fake.connections <- nil
+	require.False(t, s.Connected())
+
+	fake.connections <- []int64{10}
+	require.Equal(t, int64(10), nextHeight(t, s))
+	require.True(t, s.Connected())
+	require.True(t, strings.Contains(<-fake.subscribes, "tm.event='NewBlock'"))
+
+	// The connection is closed, the subscription reconnects.
+	fake.connections <- nil
+	fake.connections <- []int64{11, 12}
+	require.Equal(t, int64(11), nextHeight(t, s))
+	require.Equal(t, int64(12), nextHeight(t, s))
+
+	cancel()
+	close(fake.connections)
+}
+
+func TestNewBlocksFallback(t *testing.T) {
+	newBlockReconnectMin = 10 * time.Millisecond
+	defer func() { newBlockReconnectMin = time.Second }()
+
+	down := httptest.NewServer(http.NotFoundHandler())
+	downURL := down.URL + "/websocket"
+	down.Close()
+
+	fake := &fakeTendermint{
+		t: t, connections: make(chan []int64), subscribes: make(chan string, 10)}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s, err := SubscribeNewBlocks(ctx, []string{downURL, server.URL + "/websocket"})
+	require.NoError(t, err)
+
+	fake.connections <- []int64{10}
+	require.Equal(t, int64(10), nextHeight(t, s))
+	require.True(t, s.Connected())
+
+	cancel()
+	close(fake.connections)
+}
+
+func TestWebsocketURL(t *testing.T) {
+	u, err := websocketURL("http://localhost:26657/websocket")
+	require.NoError(t, err)
+	require.Equal(t, "ws://localhost:26657/websocket", u)
+
+	u, err = websocketURL("https://rpc.thorchain.info")
+	require.NoError(t, err)
+	require.Equal(t, "wss://rpc.thorchain.info/websocket", u)
+
+	_, err = websocketURL("ftp://localhost")
+	require.Error(t, err)
+}
@@ -0,0 +1,108 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gitlab.com/thorchain/midgard/config"
+	"gitlab.com/thorchain/midgard/internal/db"
+	"gitlab.com/thorchain/midgard/internal/fetch/notinchain"
+	"gitlab.com/thorchain/midgard/internal/fetch/sync/blockstore"
+	"gitlab.com/thorchain/midgard/internal/fetch/sync/chain"
+)
+
+// How often the waiting for the child chain is logged.
+const forkWaitLogInterval = 5 * time.Minute
+
+// Returns true if all the blocks of the current chain were fetched and there is a child chain
+// to switch to.
+func (s *Sync) atHardFork(nextHeight int64) bool {
+	hardFork := db.CurrentChain.Get().HardForkHeight
+	return hardFork != 0 && hardFork < nextHeight && db.ContinuesAfterFork()
+}
+
+// Endpoints of the child chain, the ones not set in its ForkInfo are taken from the config.
+func childChainConfig(child config.ForkInfo) (config.ThorChain, config.BlockStore) {
+	thorChain := config.Global.ThorChain
+	if child.TendermintURL != "" {
+		thorChain.TendermintURL = child.TendermintURL
+		thorChain.ExtraTendermintURLs = nil
+	}
+	if child.ThorNodeURL != "" {
+		thorChain.ThorNodeURL = child.ThorNodeURL
+		thorChain.ExtraThorNodeURLs = nil
+	}
+	blockStore := config.Global.BlockStore
+	if child.BlockStoreLocal != "" {
+		blockStore.Local = child.BlockStoreLocal
+	}
+	if child.BlockStoreRemote != "" {
+		blockStore.Remote = child.BlockStoreRemote
+	}
+	return thorChain, blockStore
+}
+
+// Returns the hash of the first block of the child chain, an error if the node doesn't serve it yet.
+func childChainFirstHash(client *chain.Client, child config.ForkInfo) (string, error) {
+	status, err := client.RefreshStatus()
+	if err != nil {
+		return "", err
+	}
+	if status.NodeInfo.Network != child.ChainId {
+		return "", fmt.Errorf("node is on chain %s", status.NodeInfo.Network)
+	}
+	return client.BlockHash(child.EarliestBlockHeight)
+}
+
+// Waits until the node serves the child chain, checks its first block and switches over.
+// Returns an error if the child chain doesn't match its definition or on cancellation.
+func (s *Sync) switchToChildChain(ctx context.Context) error {
+	current := db.CurrentChain.Get()
+	child, _ := db.ChildChain(current.Name)
+	logger.InfoF("Reached hard fork of %s at height %d, switching to %s",
+		current.Name, current.HardForkHeight, child.ChainId)
+
+	thorChain, blockStoreConfig := childChainConfig(child)
+	client, err := chain.NewClientFor(ctx, thorChain)
+	if err != nil {
+		return err
+	}
+
+	var hash string
+	var lastLog time.Time
+	for {
+		hash, err = childChainFirstHash(client, child)
+		if err == nil {
+			break
+		}
+		if forkWaitLogInterval <= time.Since(lastLog) {
+			lastLog = time.Now()
+			logger.InfoF("Waiting for chain %s on %s: %v", child.ChainId, thorChain.TendermintURL, err)
+		}
+		db.SleepWithContext(ctx, thorChain.LastChainBackoff.Value())
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+	if child.EarliestBlockHash != "" && hash != child.EarliestBlockHash {
+		return fmt.Errorf("first block of %s at height %d mismatch, node: %s, config: %s",
+			child.ChainId, child.EarliestBlockHeight, hash, child.EarliestBlockHash)
+	}
+
+	s.clientMutex.Lock()
+	s.chainClient = client
+	notinchain.SetURLs(thorChain.ThorNodeURL, thorChain.ExtraThorNodeURLs)
+	// The blockstore of the parent chain can't be used, it may contain blocks of the old chain
+	// after the fork.
+	s.blockStore = nil
+	if child.BlockStoreLocal != "" {
+		s.blockStore = blockstore.NewBlockStore(ctx, blockStoreConfig, child.ChainId)
+	}
+	s.clientMutex.Unlock()
+	s.subscribeNewBlocks(thorChain)
+
+	child.EarliestBlockHash = hash
+	db.SwitchToChildChain(child)
+	return nil
+}
@@ -6,11 +6,13 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	gosync "sync"
 	"time"
 
 	"github.com/pascaldekloe/metrics"
 	"gitlab.com/thorchain/midgard/config"
 	"gitlab.com/thorchain/midgard/internal/db"
+	"gitlab.com/thorchain/midgard/internal/fetch/endpoint"
 	"gitlab.com/thorchain/midgard/internal/fetch/notinchain"
 	"gitlab.com/thorchain/midgard/internal/fetch/sync/blockstore"
 	"gitlab.com/thorchain/midgard/internal/fetch/sync/chain"
@@ -33,8 +35,13 @@ var CursorHeight = metrics.Must1LabelInteger("midgard_chain_cursor_height", "nod
 var NodeHeight = metrics.Must1LabelRealSample("midgard_chain_height", "node")
 
 type Sync struct {
-	chainClient *chain.Client
-	blockStore  *blockstore.BlockStore
+	// Guards replacing chainClient and blockStore at the hard fork, needed only for reading
+	// them outside of the fetch job.
+	clientMutex   gosync.RWMutex
+	chainClient   *chain.Client
+	blockStore    *blockstore.BlockStore
+	newBlocks     *chain.NewBlocks
+	stopNewBlocks context.CancelFunc
 
 	ctx          context.Context
 	status       *coretypes.ResultStatus
@@ -43,7 +50,10 @@ type Sync struct {
 
 var CheckBlockStoreBlocks = false
 
+// Used by the API and tools, so it locks clientMutex.
 func (s *Sync) FetchSingle(height int64) (*coretypes.ResultBlockResults, error) {
+	s.clientMutex.RLock()
+	defer s.clientMutex.RUnlock()
 	if s.blockStore != nil && s.blockStore.HasHeight(height) {
 		block, err := s.blockStore.SingleBlock(height)
 		if err != nil {
@@ -129,6 +139,19 @@ var loopTimer = timer.NewTimer("sync_next")
 // that height.
 // The error return is never nil. See ErrQuit and ErrNoData for normal exit.
 func (s *Sync) CatchUp(out chan<- chain.Block, startHeight int64) (
+	height int64, inSync bool, err error) {
+	return s.catchUp(s.ctx, out, startHeight)
+}
+
+// CatchUpContext is CatchUp, but stops when ctx is cancelled too, e.g. when the consumer of the
+// blocks failed.
+func (s *Sync) CatchUpContext(ctx context.Context, out chan<- chain.Block, startHeight int64) (
+	height int64, inSync bool, err error) {
+	return s.catchUp(ctx, out, startHeight)
+}
+
+// Like CatchUp, but stops when ctx is cancelled, which may be shorter lived than the Sync.
+func (s *Sync) catchUp(ctx context.Context, out chan<- chain.Block, startHeight int64) (
 	height int64, inSync bool, err error) {
 	originalStartHeight := startHeight
 
@@ -145,6 +168,11 @@ func (s *Sync) CatchUp(out chan<- chain.Block, startHeight int64) (
 		finalBlockHeight -= 1
 	}
 
+	// Blocks of the old chain after its fork are not fetched.
+	if hardFork := db.CurrentChain.Get().HardForkHeight; hardFork != 0 && hardFork < finalBlockHeight {
+		finalBlockHeight = hardFork
+	}
+
 	i := NewIterator(s, startHeight, finalBlockHeight)
 
 	s.reportDetailed(startHeight, false, i.FetchingFrom())
@@ -154,7 +182,7 @@ func (s *Sync) CatchUp(out chan<- chain.Block, startHeight int64) (
 	inSync = finalBlockHeight < originalStartHeight+heightEpsilon
 
 	for {
-		if s.ctx.Err() != nil {
+		if ctx.Err() != nil {
 			// Job was cancelled.
 			return startHeight, false, nil
 		}
@@ -176,7 +204,7 @@ func (s *Sync) CatchUp(out chan<- chain.Block, startHeight int64) (
 		}
 
 		select {
-		case <-s.ctx.Done():
+		case <-ctx.Done():
 			return startHeight, false, nil
 		case out <- *block:
 			startHeight = block.Height + 1
@@ -195,7 +223,12 @@ func (s *Sync) CatchUp(out chan<- chain.Block, startHeight int64) (
 	}
 }
 
-func (s *Sync) KeepInSync(ctx context.Context, out chan chain.Block) {
+// First wait before fetching the latest block again when its results are not available yet. It
+// doubles with every retry until it reaches ThorChain.LastChainBackoff.
+const minResultsRetry = 100 * time.Millisecond
+
+// Returns on shutdown, or with an error if fetching can't continue.
+func (s *Sync) KeepInSync(ctx context.Context, out chan chain.Block) error {
 	heightOnStart := db.LastCommittedBlock.Get().Height
 	midlog.InfoF("Starting chain read from previous height in DB %d", heightOnStart)
 
@@ -203,24 +236,37 @@ func (s *Sync) KeepInSync(ctx context.Context, out chan chain.Block) {
 
 	var previousHeight int64 = 0
 	errorCountAtCurrentHeight := 0
+	resultsRetry := minResultsRetry
 
 	for {
 		if ctx.Err() != nil {
 			// Requested to stop
-			return
+			return nil
 		}
 		var err error
 		var inSync bool
 
-		nextHeightToFetch, inSync, err = s.CatchUp(out, nextHeightToFetch)
+		if s.atHardFork(nextHeightToFetch) {
+			err = s.switchToChildChain(ctx)
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				return fmt.Errorf("switching to the chain after the hard fork failed: %w", err)
+			}
+		}
+
+		nextHeightToFetch, inSync, err = s.catchUp(ctx, out, nextHeightToFetch)
 		if err != nil {
 			var rpcerror *jsonrpctypes.RPCError
-			// Don't log this particular error, as we expect to get it quite often.
 			// One can only get this error when fetching results for a single (the latest)
-			// block.
+			// block. After a NewBlock notification it's expected, the node indexes the results
+			// shortly after announcing the block.
 			// For details, see: https://discord.com/channels/838986635756044328/973251236025466961
-			if !(errors.As(err, &rpcerror) &&
-				strings.HasPrefix(rpcerror.Data, "could not find results for height")) {
+			resultsMissing := errors.As(err, &rpcerror) &&
+				strings.HasPrefix(rpcerror.Data, "could not find results for height")
+			// Don't log this particular error, as we expect to get it quite often.
+			if !resultsMissing {
 				midlog.DebugF("Block fetch error at height %d, retrying: %v",
 					nextHeightToFetch, err)
 			}
@@ -228,37 +274,100 @@ func (s *Sync) KeepInSync(ctx context.Context, out chan chain.Block) {
 				errorCountAtCurrentHeight++
 				const maxErrorCount = 20
 				if maxErrorCount < errorCountAtCurrentHeight {
-					midlog.ErrorF(
-						"Already failed %d times fetching height %d, quitting",
-						maxErrorCount, nextHeightToFetch)
-					jobs.InitiateShutdown()
-					return
+					return fmt.Errorf("already failed %d times fetching height %d: %w",
+						maxErrorCount, nextHeightToFetch, err)
 				}
 			}
-			db.SleepWithContext(ctx, config.Global.ThorChain.LastChainBackoff.Value())
+			wait := config.Global.ThorChain.LastChainBackoff.Value()
+			if resultsMissing && resultsRetry < wait {
+				wait = resultsRetry
+				resultsRetry *= 2
+			}
+			db.SleepWithContext(ctx, wait)
 		}
 
 		if inSync {
 			db.SetFetchCaughtUp()
-			db.SleepWithContext(ctx, 2*time.Second)
+			s.waitForNewBlock(ctx, nextHeightToFetch)
 		}
 
 		if previousHeight != nextHeightToFetch {
 			previousHeight = nextHeightToFetch
 			errorCountAtCurrentHeight = 0
+			resultsRetry = minResultsRetry
+		}
+	}
+}
+
+// Replaces the NewBlock subscription with one on the Tendermint endpoints of thorChain. The extra
+// endpoints are used when the main one is down.
+func (s *Sync) subscribeNewBlocks(thorChain config.ThorChain) {
+	if s.stopNewBlocks != nil {
+		s.stopNewBlocks()
+		s.stopNewBlocks = nil
+	}
+	s.newBlocks = nil
+	if config.Global.ThorChain.NoNewBlockSubscription {
+		return
+	}
+	ctx, cancel := context.WithCancel(s.ctx)
+	urls := append([]string{thorChain.TendermintURL}, thorChain.ExtraTendermintURLs...)
+	newBlocks, err := chain.SubscribeNewBlocks(ctx, urls)
+	if err != nil {
+		cancel()
+		logger.WarnF("Not subscribing to new blocks, polling ThorNode: %v", err)
+		return
+	}
+	s.newBlocks = newBlocks
+	s.stopNewBlocks = cancel
+}
+
+// Interval of polling ThorNode for new blocks when in sync.
+const pollInterval = 2 * time.Second
+
+// Returns when the block at height was committed on the node, or after pollInterval.
+// Polling continues even with the subscription, in case it misses a block.
+func (s *Sync) waitForNewBlock(ctx context.Context, height int64) {
+	if s.newBlocks == nil {
+		db.SleepWithContext(ctx, pollInterval)
+		return
+	}
+	timeout := time.After(pollInterval)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timeout:
+			return
+		case newHeight := <-s.newBlocks.Heights():
+			if height <= newHeight {
+				return
+			}
 		}
 	}
 }
 
 func (s *Sync) BlockStoreHeight() int64 {
+	s.clientMutex.RLock()
+	defer s.clientMutex.RUnlock()
 	return s.blockStore.LastFetchedHeight()
 }
 
+// Returns false if all the Tendermint or all the ThorNode endpoints are failing.
+func (s *Sync) ThorNodeReachable() bool {
+	s.clientMutex.RLock()
+	defer s.clientMutex.RUnlock()
+	return s.chainClient.Reachable() && notinchain.Endpoints.AnyAvailable()
+}
+
 var GlobalSync *Sync
 
 func InitGlobalSync(ctx context.Context) {
 	var err error
-	notinchain.BaseURL = config.Global.ThorChain.ThorNodeURL
+	thorChain := config.Global.ThorChain
+	notinchain.SetURLs(thorChain.ThorNodeURL, thorChain.ExtraThorNodeURLs)
+	notinchain.Endpoints = endpoint.NewSet(
+		thorChain.EndpointMaxFailures, thorChain.EndpointRetryAfter.Value())
 	GlobalSync = &Sync{ctx: ctx}
 	GlobalSync.chainClient, err = chain.NewClient(ctx)
 	if err != nil {
@@ -272,15 +381,17 @@ func InitGlobalSync(ctx context.Context) {
 	}
 	db.InitializeChainVarsFromThorNodeStatus(GlobalSync.status)
 
+	GlobalSync.subscribeNewBlocks(thorChain)
+
 	GlobalSync.blockStore = blockstore.NewBlockStore(
 		ctx, config.Global.BlockStore, db.RootChain.Get().Name)
 }
 
-func InitBlockFetch(ctx context.Context) (<-chan chain.Block, jobs.NamedFunction) {
-	InitGlobalSync(ctx)
-
+// Creates the fetch job, InitGlobalSync has to be called before.
+// Fetching starts from the last block written to the DB.
+func StartBlockFetch(ctx context.Context) (<-chan chain.Block, jobs.NamedFunction) {
 	ch := make(chan chain.Block, GlobalSync.chainClient.BatchSize())
-	return ch, jobs.Later("BlockFetch", func() {
-		GlobalSync.KeepInSync(ctx, ch)
+	return ch, jobs.Supervised(ctx, "BlockFetch", jobs.RestartNever, func() error {
+		return GlobalSync.KeepInSync(ctx, ch)
 	})
 }
@@ -0,0 +1,229 @@
+package kafkasink
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Shopify/sarama"
+	"github.com/pascaldekloe/metrics"
+	"gitlab.com/thorchain/midgard/config"
+	"gitlab.com/thorchain/midgard/internal/db"
+	"gitlab.com/thorchain/midgard/internal/util/jobs"
+	"gitlab.com/thorchain/midgard/internal/util/kafka"
+	"gitlab.com/thorchain/midgard/internal/util/midlog"
+)
+
+const checkpointKey = "sink"
+
+var (
+	sinkMessages = metrics.MustCounter("midgard_kafka_sink_messages_total",
+		"Number of messages published by the Kafka sink.")
+	sinkAborts = metrics.MustCounter("midgard_kafka_sink_aborted_transactions_total",
+		"Number of Kafka sink transactions aborted.")
+	sinkHeight = metrics.MustInteger("midgard_kafka_sink_height",
+		"Height of the last block published by the Kafka sink.")
+	sinkPending = metrics.MustInteger("midgard_kafka_sink_outbox_rows",
+		"Messages in the outbox which are not published yet.")
+)
+
+// The last outbox row published, written in every transaction.
+type checkpoint struct {
+	OutboxID int64 `json:"outboxId"`
+	Height   int64 `json:"height"`
+}
+
+type outboxRow struct {
+	id     int64
+	height int64
+	message
+}
+
+func checkpointTopic() string {
+	if t := config.Global.Kafka.Sink.CheckpointTopic; t != "" {
+		return t
+	}
+	return config.Global.Kafka.Sink.EventTopic + "-checkpoint"
+}
+
+// Init returns the job publishing the outbox. It runs with the block writer.
+func Init(ctx context.Context) jobs.NamedFunction {
+	if !Enabled() {
+		return jobs.EmptyJob()
+	}
+	midlog.InfoF("Kafka sink publishes to %s and %s",
+		config.Global.Kafka.Sink.EventTopic, config.Global.Kafka.Sink.DepthTopic)
+	// On failures the producer is recreated, it continues after the last checkpoint.
+	return jobs.Supervised(ctx, "KafkaSink", jobs.RestartOnFailure, func() error {
+		return run(ctx)
+	})
+}
+
+func run(ctx context.Context) error {
+	c := config.Global.Kafka.Sink
+	brokers := config.Global.Kafka.Brokers
+
+	last, err := readCheckpoint(brokers)
+	if err != nil {
+		return fmt.Errorf("reading the kafka sink checkpoint: %w", err)
+	}
+	producer, err := sarama.NewSyncProducer(brokers, kafka.NewTransactionalConfig(c.TransactionalID))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := producer.Close(); err != nil {
+			midlog.WarnF("Error trying to close the kafka sink producer: %v", err)
+		}
+	}()
+
+	var lastID int64
+	err = db.TheDB.QueryRowContext(ctx, "SELECT last_value FROM kafka_outbox_id_seq").Scan(&lastID)
+	if err != nil {
+		return err
+	}
+	if lastID < last.OutboxID {
+		return fmt.Errorf(
+			"checkpoint at outbox row %d but the outbox ends at %d, the database was reset. "+
+				"Delete the topic %s to publish the blocks from the beginning",
+			last.OutboxID, lastID, checkpointTopic())
+	}
+
+	for {
+		if ctx.Err() != nil {
+			return nil
+		}
+		// Rows up to the checkpoint are published, also when the delete after the last
+		// transaction didn't happen.
+		if err := deletePublished(ctx, last.OutboxID); err != nil {
+			return err
+		}
+
+		rows, err := readOutbox(ctx, last.OutboxID, c.BatchSize)
+		if err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			jobs.Sleep(ctx, c.PollInterval.Value())
+			continue
+		}
+
+		last, err = publishRows(producer, rows, checkpointTopic())
+		if err != nil {
+			return err
+		}
+		sinkHeight.Set(last.Height)
+	}
+}
+
+// Publishes the rows and the checkpoint after them in one transaction.
+func publishRows(producer sarama.SyncProducer, rows []outboxRow, checkpointTopic string) (
+	checkpoint, error) {
+	msgs := make([]*sarama.ProducerMessage, 0, len(rows)+1)
+	for _, row := range rows {
+		msgs = append(msgs, &sarama.ProducerMessage{
+			Topic: row.topic,
+			Key:   sarama.StringEncoder(row.key),
+			Value: sarama.ByteEncoder(row.value),
+		})
+	}
+	lastRow := rows[len(rows)-1]
+	c := checkpoint{OutboxID: lastRow.id, Height: lastRow.height}
+	value, err := json.Marshal(c)
+	if err != nil {
+		return checkpoint{}, err
+	}
+	msgs = append(msgs, &sarama.ProducerMessage{
+		Topic: checkpointTopic,
+		Key:   sarama.StringEncoder(checkpointKey),
+		Value: sarama.ByteEncoder(value),
+	})
+
+	if err := producer.BeginTxn(); err != nil {
+		return checkpoint{}, err
+	}
+	if err := producer.SendMessages(msgs); err != nil {
+		abort(producer)
+		return checkpoint{}, fmt.Errorf("sending outbox rows up to %d: %w", c.OutboxID, err)
+	}
+	if err := producer.CommitTxn(); err != nil {
+		abort(producer)
+		return checkpoint{}, fmt.Errorf("commit of outbox rows up to %d: %w", c.OutboxID, err)
+	}
+	sinkMessages.Add(uint64(len(rows)))
+	return c, nil
+}
+
+func abort(producer sarama.SyncProducer) {
+	sinkAborts.Add(1)
+	if err := producer.AbortTxn(); err != nil {
+		midlog.WarnF("Aborting the kafka sink transaction failed: %v", err)
+	}
+}
+
+func readCheckpoint(brokers []string) (last checkpoint, err error) {
+	client, err := sarama.NewClient(brokers, kafka.NewConfig())
+	if err != nil {
+		return checkpoint{}, err
+	}
+	defer client.Close()
+	consumer, err := sarama.NewConsumerFromClient(client)
+	if err != nil {
+		return checkpoint{}, err
+	}
+	defer consumer.Close()
+
+	err = kafka.ReadTails(client, consumer, checkpointTopic(), func(msg *sarama.ConsumerMessage) error {
+		var c checkpoint
+		if err := json.Unmarshal(msg.Value, &c); err != nil {
+			return fmt.Errorf("malformed checkpoint at offset %d: %w", msg.Offset, err)
+		}
+		if last.OutboxID < c.OutboxID {
+			last = c
+		}
+		return nil
+	})
+	return last, err
+}
+
+func readOutbox(ctx context.Context, afterID int64, limit int) ([]outboxRow, error) {
+	rows, err := db.Query(ctx, `
+		SELECT id, height, topic, msg_key, value FROM kafka_outbox
+		WHERE $1 < id
+		ORDER BY id
+		LIMIT $2`, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ret []outboxRow
+	for rows.Next() {
+		var r outboxRow
+		if err := rows.Scan(&r.id, &r.height, &r.topic, &r.key, &r.value); err != nil {
+			return nil, err
+		}
+		ret = append(ret, r)
+	}
+	return ret, rows.Err()
+}
+
+func deletePublished(ctx context.Context, upToID int64) error {
+	_, err := db.TheDB.ExecContext(ctx, "DELETE FROM kafka_outbox WHERE id <= $1", upToID)
+	if err != nil {
+		return fmt.Errorf("deleting published outbox rows: %w", err)
+	}
+	// Approximate, rolled back blocks leave gaps in the ids. Counting the rows would be slow
+	// with a long backlog.
+	var lastID int64
+	err = db.TheDB.QueryRowContext(ctx, "SELECT COALESCE(MAX(id), 0) FROM kafka_outbox").Scan(&lastID)
+	if err != nil {
+		return err
+	}
+	if upToID < lastID {
+		sinkPending.Set(lastID - upToID)
+	} else {
+		sinkPending.Set(0)
+	}
+	return nil
+}
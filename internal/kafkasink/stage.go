@@ -0,0 +1,129 @@
+// Package kafkasink publishes what the block writer recorded to Kafka: the events after the
+// corrections and the pool depths of the committed blocks.
+//
+// The messages are inserted into the kafka_outbox table with the rows of their block, so they
+// are committed or rolled back together with the block. The KafkaSink job publishes the outbox
+// in Kafka transactions, each with a checkpoint of the last outbox row published, and deletes
+// the published rows. After a restart it continues after the checkpoint, so every committed
+// block is published exactly once, even when Kafka was unavailable for a while.
+package kafkasink
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"gitlab.com/thorchain/midgard/config"
+	"gitlab.com/thorchain/midgard/internal/db"
+	"gitlab.com/thorchain/midgard/internal/fetch/record"
+	"gitlab.com/thorchain/midgard/internal/util/kafka"
+)
+
+var outboxCols = []string{"topic", "msg_key", "value", "height"}
+
+func Enabled() bool {
+	return config.Global.Kafka.Sink.Enabled
+}
+
+// Depths of a pool at the end of a block.
+type Depths struct {
+	AssetE8 int64
+	RuneE8  int64
+	SynthE8 int64
+	Units   int64
+}
+
+type message struct {
+	topic string
+	key   string
+	value []byte
+}
+
+// StageBlock inserts the messages of a recorded block into the outbox. depths has the pools
+// which changed in the block. Call it only when the sink is enabled.
+func StageBlock(parsed *record.ParsedBlock, depths map[string]Depths) error {
+	c := config.Global.Kafka.Sink
+	msgs, err := blockMessages(parsed, depths, c.EventTopic, c.DepthTopic)
+	if err != nil {
+		return err
+	}
+	for _, msg := range msgs {
+		err := db.Inserter.Insert("kafka_outbox", outboxCols,
+			msg.topic, msg.key, msg.value, parsed.Block.Height)
+		if err != nil {
+			return fmt.Errorf("kafka outbox of height %d: %w", parsed.Block.Height, err)
+		}
+	}
+	return nil
+}
+
+// Returns the recorded events in order, then the pool depths sorted by pool.
+func blockMessages(parsed *record.ParsedBlock, depths map[string]Depths,
+	eventTopic, depthTopic string) ([]message, error) {
+	block := parsed.Block
+	// The offset of the event index is an int16, also in the keys of the consumers.
+	if math.MaxInt16 < len(parsed.Recorded)-1 {
+		return nil, fmt.Errorf("height %d has %d events, more than the event index can address",
+			block.Height, len(parsed.Recorded))
+	}
+	msgs := make([]message, 0, len(parsed.Recorded)+len(depths))
+
+	var eventCodec kafka.ParsedEventCodec
+	for i, x := range parsed.Recorded {
+		eventType, err := kafka.EventType(x)
+		if err != nil {
+			return nil, err
+		}
+		ev := kafka.ParsedEvent{
+			EventIndex:     kafka.EventIdx{Height: block.Height, Offset: int16(i)},
+			BlockTimestamp: block.Time,
+			Type:           eventType,
+			Event:          x,
+		}
+		value, err := eventCodec.Encode(ev)
+		if err != nil {
+			return nil, fmt.Errorf("height %d event %d: %w", block.Height, i, err)
+		}
+		msgs = append(msgs, message{
+			topic: eventTopic,
+			key:   fmt.Sprintf("%d.%06d", block.Height, i),
+			value: value,
+		})
+	}
+
+	changes := map[string][]kafka.PoolDepthDelta{}
+	for _, c := range parsed.DepthChanges {
+		changes[c.Pool] = append(changes[c.Pool], kafka.PoolDepthDelta{
+			Reason:  string(c.Reason),
+			AssetE8: c.AssetE8,
+			RuneE8:  c.RuneE8,
+			SynthE8: c.SynthE8,
+		})
+	}
+	pools := make([]string, 0, len(depths))
+	for pool := range depths {
+		pools = append(pools, pool)
+	}
+	sort.Strings(pools)
+
+	var depthCodec kafka.PoolDepthCodec
+	for _, pool := range pools {
+		d := depths[pool]
+		pd := kafka.PoolDepth{
+			Height:         block.Height,
+			BlockTimestamp: block.Time,
+			Pool:           pool,
+			AssetE8:        d.AssetE8,
+			RuneE8:         d.RuneE8,
+			SynthE8:        d.SynthE8,
+			Units:          d.Units,
+			Changes:        changes[pool],
+		}
+		value, err := depthCodec.Encode(pd)
+		if err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, message{topic: depthTopic, key: pd.Key(), value: value})
+	}
+	return msgs, nil
+}
@@ -0,0 +1,102 @@
+package kafkasink
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/Shopify/sarama/mocks"
+	"github.com/stretchr/testify/require"
+	"gitlab.com/thorchain/midgard/internal/fetch/record"
+	"gitlab.com/thorchain/midgard/internal/fetch/sync/chain"
+	"gitlab.com/thorchain/midgard/internal/util/kafka"
+)
+
+func TestBlockMessages(t *testing.T) {
+	blockTime := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	parsed := &record.ParsedBlock{
+		Block: &chain.Block{Height: 42, Time: blockTime},
+		Recorded: []interface{}{
+			&record.Swap{Pool: []byte("BTC.BTC"), FromAsset: []byte("THOR.RUNE"), FromE8: 100},
+			&record.Fee{Tx: []byte("tx"), Asset: []byte("BTC.BTC"), AssetE8: 2},
+		},
+		DepthChanges: []record.DepthChange{
+			{Pool: "BTC.BTC", Reason: record.DepthChangeSwap, RuneE8: 100},
+		},
+	}
+	depths := map[string]Depths{
+		"ETH.ETH": {AssetE8: 5, RuneE8: 6, Units: 7},
+		"BTC.BTC": {AssetE8: 1, RuneE8: 2, SynthE8: 3, Units: 4},
+	}
+
+	msgs, err := blockMessages(parsed, depths, "events", "depths")
+	require.NoError(t, err)
+	require.Len(t, msgs, 4)
+
+	require.Equal(t, "events", msgs[0].topic)
+	require.Equal(t, "42.000000", msgs[0].key)
+	v, err := kafka.ParsedEventCodec{}.Decode(msgs[0].value)
+	require.NoError(t, err)
+	ev := v.(kafka.ParsedEvent)
+	require.Equal(t, kafka.EventIdx{Height: 42, Offset: 0}, ev.EventIndex)
+	require.Equal(t, "swap", ev.Type)
+	require.Equal(t, int64(100), ev.Event.(record.Swap).FromE8)
+
+	require.Equal(t, "42.000001", msgs[1].key)
+	v, err = kafka.ParsedEventCodec{}.Decode(msgs[1].value)
+	require.NoError(t, err)
+	require.Equal(t, "fee", v.(kafka.ParsedEvent).Type)
+
+	// Pools in order, with their changes.
+	require.Equal(t, "depths", msgs[2].topic)
+	require.Equal(t, "BTC.BTC", msgs[2].key)
+	v, err = kafka.PoolDepthCodec{}.Decode(msgs[2].value)
+	require.NoError(t, err)
+	require.Equal(t, kafka.PoolDepth{
+		Height: 42, BlockTimestamp: blockTime, Pool: "BTC.BTC",
+		AssetE8: 1, RuneE8: 2, SynthE8: 3, Units: 4,
+		Changes: []kafka.PoolDepthDelta{{Reason: "swap", RuneE8: 100}},
+	}, v)
+	require.Equal(t, "ETH.ETH", msgs[3].key)
+}
+
+func TestBlockMessagesTooManyEvents(t *testing.T) {
+	parsed := &record.ParsedBlock{
+		Block:    &chain.Block{Height: 42},
+		Recorded: make([]interface{}, math.MaxInt16+2),
+	}
+	_, err := blockMessages(parsed, nil, "events", "depths")
+	require.ErrorContains(t, err, "height 42")
+}
+
+func TestPublishRows(t *testing.T) {
+	producer := mocks.NewSyncProducer(t, kafka.NewTransactionalConfig("test"))
+	var sent []*sarama.ProducerMessage
+	for i := 0; i < 3; i++ {
+		producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(
+			func(msg *sarama.ProducerMessage) error {
+				sent = append(sent, msg)
+				return nil
+			})
+	}
+
+	rows := []outboxRow{
+		{id: 7, height: 41, message: message{topic: "events", key: "41.000000", value: []byte("a")}},
+		{id: 8, height: 42, message: message{topic: "depths", key: "BTC.BTC", value: []byte("b")}},
+	}
+	c, err := publishRows(producer, rows, "checkpoints")
+	require.NoError(t, err)
+	require.Equal(t, checkpoint{OutboxID: 8, Height: 42}, c)
+	require.Equal(t, sarama.ProducerTxnFlagReady, producer.TxnStatus())
+	require.NoError(t, producer.Close())
+
+	require.Len(t, sent, 3)
+	require.Equal(t, "checkpoints", sent[2].Topic)
+	value, err := sent[2].Value.Encode()
+	require.NoError(t, err)
+	var written checkpoint
+	require.NoError(t, json.Unmarshal(value, &written))
+	require.Equal(t, c, written)
+}
@@ -334,6 +334,55 @@ func (a *action) completeFromDBRead(meta *actionMeta, fees coinList) {
 	}
 }
 
+// ActionWithAddresses is an action with all the addresses involved, which includes addresses
+// which are not in the ins and outs (e.g. the affiliate).
+type ActionWithAddresses struct {
+	Action    oapigen.Action
+	Addresses []string
+}
+
+// ActionsBetween returns the actions of the blocks in (from, to], oldest first.
+func ActionsBetween(ctx context.Context, from, to db.Nano) ([]ActionWithAddresses, error) {
+	rows, err := db.Query(ctx, `
+		SELECT height, block_timestamp, type, pools, ins, outs, fees, meta, addresses
+		FROM midgard_agg.actions
+		WHERE $1 < block_timestamp AND block_timestamp <= $2
+		ORDER BY block_timestamp`, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("actions query: %w", err)
+	}
+	defer rows.Close()
+
+	ret := []ActionWithAddresses{}
+	for rows.Next() {
+		var result action
+		var ins, outs transactionList
+		var fees coinList
+		var meta actionMeta
+		var addresses []string
+		err := rows.Scan(
+			&result.height,
+			&result.date,
+			&result.actionType,
+			pq.Array(&result.pools),
+			&ins,
+			&outs,
+			&fees,
+			&meta,
+			pq.Array(&addresses),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("actions read: %w", err)
+		}
+
+		result.in = ins
+		result.out = outs
+		result.completeFromDBRead(&meta, fees)
+		ret = append(ret, ActionWithAddresses{Action: result.toOapigen(), Addresses: addresses})
+	}
+	return ret, rows.Err()
+}
+
 // Gets a list of actions generated by external transactions and return its associated data
 func GetActions(ctx context.Context, moment time.Time, params ActionsParams) (
 	oapigen.ActionsResponse, error) {
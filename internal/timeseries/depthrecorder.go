@@ -45,9 +45,9 @@ var depthRecorder depthManager
 // Insert rows in the block_pool_depths for every changed value in the depth maps.
 // If there is no change it doesn't write out anything.
 // All values will be writen out together (assetDepth, runeDepth, synthDepth), even if only one of the values
-// changed in the pool.
+// changed in the pool. Returns the pools which changed.
 func (sm *depthManager) update(
-	timestamp time.Time, assetE8DepthPerPool, runeE8DepthPerPool, synthE8DepthPerPool, unitPerPool map[string]int64, pricePerPool, priceUSDPerPool map[string]float64) error {
+	timestamp time.Time, assetE8DepthPerPool, runeE8DepthPerPool, synthE8DepthPerPool, unitPerPool map[string]int64, pricePerPool, priceUSDPerPool map[string]float64) (changed []string, err error) {
 	blockTimestamp := timestamp.UnixNano()
 	// We need to iterate over all 2*n maps: {old,new}{Asset,Rune,Synth}.
 	// First put all pool names into a set.
@@ -68,7 +68,6 @@ func (sm *depthManager) update(
 
 	cols := []string{"pool", "asset_e8", "rune_e8", "synth_e8", "price", "priceusd", "units", "block_timestamp"}
 
-	var err error
 	for pool := range poolNames {
 		assetDiff, assetValue := sm.assetE8DepthSnapshot.diffAtKey(pool, assetE8DepthPerPool)
 		runeDiff, runeValue := sm.runeE8DepthSnapshot.diffAtKey(pool, runeE8DepthPerPool)
@@ -81,6 +80,7 @@ func (sm *depthManager) update(
 			if err != nil {
 				break
 			}
+			changed = append(changed, pool)
 		}
 	}
 	sm.assetE8DepthSnapshot.save(assetE8DepthPerPool)
@@ -89,10 +89,10 @@ func (sm *depthManager) update(
 	sm.unitSnapshot.save(unitPerPool)
 
 	if err != nil {
-		return fmt.Errorf("error saving depths (timestamp: %d): %w", blockTimestamp, err)
+		return nil, fmt.Errorf("error saving depths (timestamp: %d): %w", blockTimestamp, err)
 	}
 
-	return nil
+	return changed, nil
 }
 
 func ResetDepthManagerForTest() {
@@ -122,6 +122,40 @@ func PoolStatus(ctx context.Context, pool string) (string, error) {
 	return strings.ToLower(status), rows.Err()
 }
 
+type PoolStatusChange struct {
+	Pool      string
+	Status    string
+	Height    int64
+	Timestamp db.Nano
+}
+
+// PoolStatusChangesBetween returns the pool status changes of the blocks in (from, to],
+// oldest first. status is lowercase.
+func PoolStatusChangesBetween(ctx context.Context, from, to db.Nano) ([]PoolStatusChange, error) {
+	const q = `
+	SELECT p.asset, p.status, b.height, p.block_timestamp
+	FROM pool_events p JOIN block_log b ON b.timestamp = p.block_timestamp
+	WHERE $1 < p.block_timestamp AND p.block_timestamp <= $2
+	ORDER BY p.block_timestamp`
+
+	rows, err := db.Query(ctx, q, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ret []PoolStatusChange
+	for rows.Next() {
+		var c PoolStatusChange
+		if err := rows.Scan(&c.Pool, &c.Status, &c.Height, &c.Timestamp); err != nil {
+			return nil, err
+		}
+		c.Status = strings.ToLower(c.Status)
+		ret = append(ret, c)
+	}
+	return ret, rows.Err()
+}
+
 var RewardEntriesAggregate = db.RegisterAggregate(
 	db.NewAggregate("rewards_event_entries", "rewards_event_entries").
 		AddGroupColumn("pool").
@@ -7,8 +7,6 @@ import (
 	"fmt"
 	"testing"
 
-	"gitlab.com/thorchain/midgard/internal/db"
-
 	"github.com/99designs/gqlgen/client"
 	"github.com/99designs/gqlgen/graphql/handler"
 	"github.com/stretchr/testify/require"
@@ -0,0 +1,124 @@
+package timeseries
+
+import (
+	"context"
+
+	"gitlab.com/thorchain/midgard/internal/db"
+)
+
+type MimirValue struct {
+	Key       string
+	Value     string
+	Height    int64
+	Timestamp db.Nano
+}
+
+// Keys are case insensitive in ThorNode, they are reported upper case.
+const mimirValuesQuery = `
+	SELECT
+		UPPER(key) AS ukey,
+		value,
+		COALESCE(height, 0),
+		block_timestamp
+	FROM set_mimir_events
+	LEFT JOIN block_log ON timestamp = block_timestamp
+`
+
+func queryMimirValues(ctx context.Context, q string, args ...interface{}) (
+	ret []MimirValue, err error,
+) {
+	rows, err := db.Query(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ret = []MimirValue{}
+	for rows.Next() {
+		var v MimirValue
+		err = rows.Scan(&v.Key, &v.Value, &v.Height, &v.Timestamp)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, v)
+	}
+	return ret, rows.Err()
+}
+
+// Returns the last value set for every mimir key, sorted by key.
+func MimirValues(ctx context.Context) ([]MimirValue, error) {
+	q := `
+		SELECT DISTINCT ON (ukey) * FROM (` + mimirValuesQuery + `) AS v
+		ORDER BY ukey, block_timestamp DESC
+	`
+	return queryMimirValues(ctx, q)
+}
+
+// Returns all the values set for the mimir key, oldest first.
+func MimirHistory(ctx context.Context, key string) ([]MimirValue, error) {
+	q := mimirValuesQuery + `
+		WHERE UPPER(key) = UPPER($1)
+		ORDER BY block_timestamp ASC
+	`
+	return queryMimirValues(ctx, q, key)
+}
+
+type NodeMimirVote struct {
+	Value string
+	Votes int64
+}
+
+type NodeMimirTally struct {
+	Key   string
+	Votes []NodeMimirVote
+}
+
+// Returns the tally of the last votes of the currently active nodes per mimir key.
+// If key is empty all keys are returned.
+//
+// Note: the key and the value columns of set_node_mimir are swapped, the key name is stored in
+// the value column and the voted value in the key column.
+func NodeMimirTallies(ctx context.Context, key string) (ret []NodeMimirTally, err error) {
+	q := `
+		WITH active_nodes AS (
+			SELECT node_addr
+			FROM update_node_account_status_events
+			GROUP BY node_addr
+			HAVING LAST(current, block_timestamp) = 'Active'
+		), last_votes AS (
+			SELECT DISTINCT ON (address, UPPER(value))
+				address,
+				UPPER(value) AS vote_key,
+				key::TEXT AS vote_value
+			FROM set_node_mimir
+			WHERE $1 = '' OR UPPER(value) = UPPER($1)
+			ORDER BY address, UPPER(value), block_timestamp DESC
+		)
+		SELECT vote_key, vote_value, COUNT(*) AS votes
+		FROM last_votes
+		JOIN active_nodes ON node_addr = address
+		GROUP BY vote_key, vote_value
+		ORDER BY vote_key, votes DESC, vote_value
+	`
+	rows, err := db.Query(ctx, q, key)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ret = []NodeMimirTally{}
+	for rows.Next() {
+		var voteKey string
+		var vote NodeMimirVote
+		err = rows.Scan(&voteKey, &vote.Value, &vote.Votes)
+		if err != nil {
+			return nil, err
+		}
+		if len(ret) == 0 || ret[len(ret)-1].Key != voteKey {
+			ret = append(ret, NodeMimirTally{Key: voteKey})
+		}
+		last := &ret[len(ret)-1]
+		last.Votes = append(last.Votes, vote)
+	}
+	return ret, rows.Err()
+}
@@ -0,0 +1,87 @@
+package timeseries_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gitlab.com/thorchain/midgard/config"
+	"gitlab.com/thorchain/midgard/internal/db/testdb"
+	"gitlab.com/thorchain/midgard/openapi/generated/oapigen"
+)
+
+func TestMimirE2E(t *testing.T) {
+	testdb.InitTest(t)
+	testdb.InsertBlockLog(t, 1, "2020-09-01 00:00:00")
+	testdb.InsertBlockLog(t, 2, "2020-09-02 00:00:00")
+
+	testdb.SetThornodeConstant(t, "HaltTrading", 1, "2020-09-01 00:00:00")
+	testdb.SetThornodeConstant(t, "ChurnInterval", 43200, "2020-09-01 00:00:00")
+	testdb.SetThornodeConstant(t, "HALTTRADING", 0, "2020-09-02 00:00:00")
+
+	body := testdb.CallJSON(t, "http://localhost:8080/v2/mimir")
+	var mimir oapigen.MimirResponse
+	testdb.MustUnmarshal(t, body, &mimir)
+	require.Equal(t, oapigen.MimirResponse{
+		{Key: "CHURNINTERVAL", Value: "43200", Height: "1",
+			Date: "1598918400000000000"},
+		{Key: "HALTTRADING", Value: "0", Height: "2",
+			Date: "1599004800000000000"},
+	}, mimir)
+
+	body = testdb.CallJSON(t, "http://localhost:8080/v2/mimir/haltTrading/history")
+	var history oapigen.MimirHistoryResponse
+	testdb.MustUnmarshal(t, body, &history)
+	require.Equal(t, 2, len(history))
+	require.Equal(t, "1", history[0].Value)
+	require.Equal(t, "0", history[1].Value)
+
+	testdb.JSONFailGeneral(t, "http://localhost:8080/v2/mimir/NOSUCHKEY/history")
+
+	// In lite mode the values before the retained window may be deleted.
+	config.Global.Retention.Days = 10
+	t.Cleanup(func() { config.Global.Retention.Days = 0 })
+	testdb.CallFail(t, "http://localhost:8080/v2/mimir/HALTTRADING/history", "retained window")
+}
+
+func TestNodeMimirE2E(t *testing.T) {
+	testdb.InitTest(t)
+
+	for _, node := range []string{"node1", "node2", "node3"} {
+		testdb.InsertUpdateNodeAccountStatusEvent(t,
+			testdb.FakeNodeStatus{NodeAddr: node, Former: "Standby", Current: "Active"},
+			"2020-09-01 00:00:00")
+	}
+	testdb.InsertUpdateNodeAccountStatusEvent(t,
+		testdb.FakeNodeStatus{NodeAddr: "node4", Former: "Active", Current: "Standby"},
+		"2020-09-01 00:00:00")
+
+	testdb.InsertNodeMimirEvent(t, "node1", "HALTTRADING", 1, "2020-09-02 00:00:00")
+	testdb.InsertNodeMimirEvent(t, "node2", "HALTTRADING", 1, "2020-09-02 00:00:00")
+	testdb.InsertNodeMimirEvent(t, "node3", "HALTTRADING", 1, "2020-09-02 00:00:00")
+	// Changed vote, only the last one counts.
+	testdb.InsertNodeMimirEvent(t, "node3", "HALTTRADING", 0, "2020-09-03 00:00:00")
+	// Not active
+	testdb.InsertNodeMimirEvent(t, "node4", "HALTTRADING", 0, "2020-09-03 00:00:00")
+	testdb.InsertNodeMimirEvent(t, "node1", "MAXSYNTHPERASSETDEPTH", 3000, "2020-09-03 00:00:00")
+
+	body := testdb.CallJSON(t, "http://localhost:8080/v2/node_mimir")
+	var result oapigen.NodeMimirResponse
+	testdb.MustUnmarshal(t, body, &result)
+	require.Equal(t, oapigen.NodeMimirResponse{
+		ActiveNodeCount: "3",
+		Keys: []oapigen.NodeMimirTally{
+			{Key: "HALTTRADING", Votes: []oapigen.NodeMimirVote{
+				{Value: "1", Votes: "2"},
+				{Value: "0", Votes: "1"},
+			}},
+			{Key: "MAXSYNTHPERASSETDEPTH", Votes: []oapigen.NodeMimirVote{
+				{Value: "3000", Votes: "1"},
+			}},
+		},
+	}, result)
+
+	body = testdb.CallJSON(t, "http://localhost:8080/v2/node_mimir?key=haltTrading")
+	testdb.MustUnmarshal(t, body, &result)
+	require.Equal(t, 1, len(result.Keys))
+	require.Equal(t, "HALTTRADING", result.Keys[0].Key)
+}
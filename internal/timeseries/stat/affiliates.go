@@ -0,0 +1,148 @@
+package stat
+
+import (
+	"context"
+	"strconv"
+
+	"gitlab.com/thorchain/midgard/internal/db"
+)
+
+// The affiliate address (or THORName) and fee basis points are parsed from the swap memo, the same
+// way as for the swap actions (see aggregates.sql).
+const (
+	affiliateExpression = `CASE
+		WHEN SUBSTRING(memo FROM ':.*:.*:.*:(.*):.*') = to_addr THEN NULL
+		ELSE SUBSTRING(memo FROM ':.*:.*:.*:(.+):.*')
+		END`
+	affiliateBPExpression = `COALESCE(
+		SUBSTRING(memo FROM ':.*:.*:.*:.*:(\d{1,5})(:|$)')::BIGINT, 0)`
+)
+
+var AffiliatesAggregate = db.RegisterAggregate(db.NewAggregate("affiliates", "swap_events").
+	AddGroupExpression("affiliate", affiliateExpression).
+	AddSumlikeExpression("swap_count",
+		"SUM(CASE WHEN "+countedSwapLeg+" THEN 1 ELSE 0 END)::BIGINT").
+	AddSumlikeExpression("volume_e8",
+		"SUM("+swapVolumeInRune+")::BIGINT").
+	AddSumlikeExpression("volume_e8_usd",
+		"SUM(("+swapVolumeInRune+") * priceusd)::BIGINT").
+	AddSumlikeExpression("fees_e8",
+		"SUM(("+swapVolumeInRune+") * "+affiliateBPExpression+" / 10000)::BIGINT"))
+
+type AffiliateStats struct {
+	Affiliate   string
+	SwapCount   int64
+	VolumeE8    int64
+	VolumeUsdE8 int64
+	FeesE8      int64
+}
+
+func (s *AffiliateStats) add(o AffiliateStats) {
+	s.SwapCount += o.SwapCount
+	s.VolumeE8 += o.VolumeE8
+	s.VolumeUsdE8 += o.VolumeUsdE8
+	s.FeesE8 += o.FeesE8
+}
+
+type AffiliateBucket struct {
+	Window db.Window
+	// Sum of all the affiliates in the bucket, Affiliate is left empty.
+	Total      AffiliateStats
+	Affiliates []AffiliateStats
+}
+
+// Returns dense buckets with the per affiliate stats sorted by fees.
+// If `affiliates` is empty all the affiliates are returned.
+func AffiliateHistory(ctx context.Context, buckets db.Buckets, affiliates []string) (
+	ret []AffiliateBucket, err error,
+) {
+	filter := "affiliate IS NOT NULL"
+	params := []interface{}{}
+	if len(affiliates) != 0 {
+		filter = "affiliate = ANY($1)"
+		params = append(params, affiliates)
+	}
+	q, params := AffiliatesAggregate.BucketedQuery(`
+		SELECT
+			aggregate_timestamp/1000000000 AS time,
+			affiliate,
+			SUM(swap_count),
+			SUM(volume_e8),
+			SUM(volume_e8_usd),
+			SUM(fees_e8) AS fees
+		FROM %s
+		WHERE `+filter+`
+		GROUP BY time, affiliate
+		ORDER BY time ASC, fees DESC, affiliate ASC
+	`, buckets, nil, params)
+
+	rows, err := db.Query(ctx, q, params...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ret = make([]AffiliateBucket, buckets.Count())
+	for i := range ret {
+		ret[i].Window = buckets.BucketWindow(i)
+		ret[i].Affiliates = []AffiliateStats{}
+	}
+
+	idx := 0
+	for rows.Next() {
+		var timestamp db.Second
+		var stats AffiliateStats
+		err = rows.Scan(&timestamp, &stats.Affiliate,
+			&stats.SwapCount, &stats.VolumeE8, &stats.VolumeUsdE8, &stats.FeesE8)
+		if err != nil {
+			return nil, err
+		}
+		for idx < len(ret) && ret[idx].Window.From < timestamp {
+			idx++
+		}
+		if idx == len(ret) {
+			break
+		}
+		ret[idx].Total.add(stats)
+		ret[idx].Affiliates = append(ret[idx].Affiliates, stats)
+	}
+	return ret, rows.Err()
+}
+
+// Returns the affiliates with the highest fees earned in the given period.
+func AffiliateLeaderboard(ctx context.Context, buckets db.Buckets, limit int) (
+	ret []AffiliateStats, err error,
+) {
+	q, params := AffiliatesAggregate.BucketedQuery(`
+		SELECT
+			affiliate,
+			SUM(swap_count),
+			SUM(volume_e8),
+			SUM(volume_e8_usd),
+			SUM(fees_e8) AS fees
+		FROM %s
+		WHERE affiliate IS NOT NULL
+		GROUP BY affiliate
+		ORDER BY fees DESC, affiliate ASC
+	`, buckets, nil, nil)
+	params = append(params, limit)
+	q += " LIMIT $" + strconv.Itoa(len(params))
+
+	rows, err := db.Query(ctx, q, params...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ret = []AffiliateStats{}
+	for rows.Next() {
+		var stats AffiliateStats
+		err = rows.Scan(&stats.Affiliate,
+			&stats.SwapCount, &stats.VolumeE8, &stats.VolumeUsdE8, &stats.FeesE8)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, stats)
+	}
+	return ret, rows.Err()
+}
@@ -0,0 +1,131 @@
+package stat_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gitlab.com/thorchain/midgard/internal/db"
+	"gitlab.com/thorchain/midgard/internal/db/testdb"
+	"gitlab.com/thorchain/midgard/openapi/generated/oapigen"
+)
+
+func affiliateTestBlocks(t *testing.T) {
+	blocks := testdb.InitTestBlocks(t)
+
+	blocks.NewBlock(t, "2020-09-01 00:00:00",
+		testdb.AddLiquidity{
+			Pool: "BNB.BNB", AssetAmount: 1000000, RuneAmount: 10000000, LiquidityProviderUnits: 1,
+		},
+		testdb.AddLiquidity{
+			Pool: "BTC.BTC", AssetAmount: 1000000, RuneAmount: 10000000, LiquidityProviderUnits: 1,
+		},
+		testdb.PoolActivate{Pool: "BNB.BNB"},
+		testdb.PoolActivate{Pool: "BTC.BTC"},
+	)
+
+	blocks.NewBlock(t, "2020-09-01 00:10:00",
+		testdb.Swap{
+			TxID:               "tx1",
+			Coin:               "100000 BNB.BNB",
+			EmitAsset:          "100 THOR.RUNE",
+			Pool:               "BNB.BNB",
+			LiquidityFeeInRune: 10000,
+			ToAddress:          "thoraddr",
+			Memo:               "=:THOR.RUNE:thoraddr:0:aff1:100",
+		},
+		// No affiliate
+		testdb.Swap{
+			TxID:      "tx2",
+			Coin:      "2000 THOR.RUNE",
+			EmitAsset: "10 BNB.BNB",
+			Pool:      "BNB.BNB",
+			Chain:     "THOR",
+			ToAddress: "bnbaddr",
+			Memo:      "=:BNB.BNB:bnbaddr",
+		},
+	)
+
+	// Double swap, counted only once
+	blocks.NewBlock(t, "2020-09-02 00:10:00",
+		testdb.Swap{
+			TxID:      "tx3",
+			Coin:      "1000 BTC.BTC",
+			EmitAsset: "50000 THOR.RUNE",
+			Pool:      "BTC.BTC",
+			Chain:     "BTC",
+			ToAddress: "bnbaddr",
+			Memo:      "=:BNB.BNB:bnbaddr:0:aff2:50",
+		},
+		testdb.Swap{
+			TxID:      "tx3",
+			Coin:      "50000 THOR.RUNE",
+			EmitAsset: "400 BNB.BNB",
+			Pool:      "BNB.BNB",
+			Chain:     "BTC",
+			ToAddress: "bnbaddr",
+			Memo:      "=:BNB.BNB:bnbaddr:0:aff2:50",
+		},
+	)
+
+	db.RefreshAggregatesForTests()
+}
+
+func TestAffiliateHistoryE2E(t *testing.T) {
+	affiliateTestBlocks(t)
+
+	from := db.StrToSec("2020-09-01 00:00:00")
+	to := db.StrToSec("2020-09-03 00:00:00")
+
+	body := testdb.CallJSON(t, fmt.Sprintf(
+		"http://localhost:8080/v2/history/affiliates?interval=day&from=%d&to=%d", from, to))
+
+	var result oapigen.AffiliateHistoryResponse
+	testdb.MustUnmarshal(t, body, &result)
+
+	require.Equal(t, 2, len(result.Intervals))
+	require.Equal(t, "1", result.Intervals[0].SwapCount)
+	require.Equal(t, "10100", result.Intervals[0].Volume)
+	require.Equal(t, "101", result.Intervals[0].Fees)
+	require.Equal(t, 1, len(result.Intervals[0].Affiliates))
+	require.Equal(t, "aff1", result.Intervals[0].Affiliates[0].Affiliate)
+
+	require.Equal(t, "1", result.Intervals[1].SwapCount)
+	require.Equal(t, "50000", result.Intervals[1].Volume)
+	require.Equal(t, "250", result.Intervals[1].Fees)
+
+	require.Equal(t, "2", result.Meta.SwapCount)
+	require.Equal(t, "60100", result.Meta.Volume)
+	require.Equal(t, "351", result.Meta.Fees)
+	require.Equal(t, 2, len(result.Meta.Affiliates))
+	require.Equal(t, "aff2", result.Meta.Affiliates[0].Affiliate)
+	require.Equal(t, "aff1", result.Meta.Affiliates[1].Affiliate)
+
+	body = testdb.CallJSON(t, fmt.Sprintf(
+		"http://localhost:8080/v2/history/affiliates?affiliate=aff1&from=%d&to=%d", from, to))
+	testdb.MustUnmarshal(t, body, &result)
+
+	require.Equal(t, 1, len(result.Intervals))
+	require.Equal(t, "1", result.Meta.SwapCount)
+	require.Equal(t, "101", result.Meta.Fees)
+}
+
+func TestAffiliatesLeaderboardE2E(t *testing.T) {
+	affiliateTestBlocks(t)
+
+	body := testdb.CallJSON(t, "http://localhost:8080/v2/affiliates?period=all")
+
+	var result oapigen.AffiliatesResponse
+	testdb.MustUnmarshal(t, body, &result)
+	require.Equal(t, oapigen.AffiliatesResponse{
+		{Affiliate: "aff2", SwapCount: "1", Volume: "50000", VolumeUsd: "0", Fees: "250"},
+		{Affiliate: "aff1", SwapCount: "1", Volume: "10100", VolumeUsd: "0", Fees: "101"},
+	}, result)
+
+	body = testdb.CallJSON(t, "http://localhost:8080/v2/affiliates?period=all&limit=1")
+	testdb.MustUnmarshal(t, body, &result)
+	require.Equal(t, 1, len(result))
+	require.Equal(t, "aff2", result[0].Affiliate)
+
+	testdb.JSONFailGeneral(t, "http://localhost:8080/v2/affiliates?limit=0")
+}
@@ -0,0 +1,206 @@
+package stat
+
+import (
+	"context"
+	"sort"
+
+	"github.com/lib/pq"
+	"gitlab.com/thorchain/midgard/internal/db"
+)
+
+// Churn is a block where new vaults were activated (same as timeseries.LastChurnHeight).
+type Churn struct {
+	Height    int64
+	Timestamp db.Nano
+	// Nodes which became Active or stopped being Active in the churn block.
+	NodesJoined []string
+	NodesLeft   []string
+	// Vaults activated in the churn block.
+	NewVaults []string
+	// Vaults retired after the churn, until the next churn.
+	RetiredVaults []string
+	// Total bond of all nodes before and after the churn block.
+	BondBeforeE8 int64
+	BondAfterE8  int64
+	// Time of the last retired vault, the migration is finished by then. 0 if no vault was
+	// retired yet.
+	MigrationEnd db.Nano
+}
+
+// Returns the churns, newest first.
+func Churns(ctx context.Context, limit, offset int) (ret []Churn, err error) {
+	q := `
+		WITH churns AS (
+			SELECT
+				block_timestamp,
+				ARRAY_AGG(add_asgard_addr ORDER BY add_asgard_addr) AS vaults
+			FROM active_vault_events
+			GROUP BY block_timestamp
+		)
+		SELECT
+			COALESCE(height, 0),
+			block_timestamp,
+			vaults
+		FROM churns
+		LEFT JOIN block_log ON timestamp = block_timestamp
+		ORDER BY block_timestamp DESC
+		LIMIT $1 OFFSET $2
+	`
+	rows, err := db.Query(ctx, q, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ret = []Churn{}
+	for rows.Next() {
+		var c Churn
+		err = rows.Scan(&c.Height, &c.Timestamp, pq.Array(&c.NewVaults))
+		if err != nil {
+			return nil, err
+		}
+		c.NodesJoined = []string{}
+		c.NodesLeft = []string{}
+		c.RetiredVaults = []string{}
+		ret = append(ret, c)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(ret) == 0 {
+		return ret, nil
+	}
+
+	err = addChurnNodes(ctx, ret)
+	if err != nil {
+		return nil, err
+	}
+	err = addRetiredVaults(ctx, ret)
+	if err != nil {
+		return nil, err
+	}
+	err = addChurnBonds(ctx, ret)
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func churnIndexes(churns []Churn) (timestamps []int64, idx map[db.Nano]int) {
+	timestamps = make([]int64, len(churns))
+	idx = make(map[db.Nano]int, len(churns))
+	for i, c := range churns {
+		timestamps[i] = c.Timestamp.ToI()
+		idx[c.Timestamp] = i
+	}
+	return
+}
+
+func addChurnNodes(ctx context.Context, churns []Churn) error {
+	timestamps, idx := churnIndexes(churns)
+	q := `
+		SELECT block_timestamp, node_addr, former, current
+		FROM update_node_account_status_events
+		WHERE block_timestamp = ANY($1)
+		ORDER BY node_addr
+	`
+	rows, err := db.Query(ctx, q, pq.Array(timestamps))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var timestamp db.Nano
+		var node, former, current string
+		err = rows.Scan(&timestamp, &node, &former, &current)
+		if err != nil {
+			return err
+		}
+		c := &churns[idx[timestamp]]
+		if current == "Active" && former != "Active" {
+			c.NodesJoined = append(c.NodesJoined, node)
+		} else if former == "Active" && current != "Active" {
+			c.NodesLeft = append(c.NodesLeft, node)
+		}
+	}
+	return rows.Err()
+}
+
+// The old vaults are retired when the funds are migrated to the new ones, this happens in later
+// blocks. Inactive vaults are assigned to the last churn before them.
+func addRetiredVaults(ctx context.Context, churns []Churn) error {
+	// churns are ordered newest first.
+	q := `
+		SELECT block_timestamp, add_asgard_addr
+		FROM inactive_vault_events
+		WHERE $1 <= block_timestamp
+			AND block_timestamp < COALESCE(
+				(SELECT MIN(block_timestamp) FROM active_vault_events WHERE $2 < block_timestamp),
+				9223372036854775807)
+		ORDER BY block_timestamp, add_asgard_addr
+	`
+	oldest := churns[len(churns)-1].Timestamp
+	newest := churns[0].Timestamp
+	rows, err := db.Query(ctx, q, oldest, newest)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var timestamp db.Nano
+		var vault string
+		err = rows.Scan(&timestamp, &vault)
+		if err != nil {
+			return err
+		}
+		// Index of the newest churn not after the timestamp.
+		i := sort.Search(len(churns), func(i int) bool {
+			return churns[i].Timestamp <= timestamp
+		})
+		if i == len(churns) {
+			continue
+		}
+		churns[i].RetiredVaults = append(churns[i].RetiredVaults, vault)
+		churns[i].MigrationEnd = timestamp
+	}
+	return rows.Err()
+}
+
+// The bond totals are materialized by the aggregates refresh, churns after the last refresh get
+// the total at the refresh.
+func addChurnBonds(ctx context.Context, churns []Churn) error {
+	timestamps, idx := churnIndexes(churns)
+	q := `
+		SELECT
+			c.block_timestamp,
+			COALESCE((
+				SELECT total_e8 FROM midgard_agg.bond_totals
+				WHERE block_timestamp < c.block_timestamp
+				ORDER BY block_timestamp DESC LIMIT 1), 0),
+			COALESCE((
+				SELECT total_e8 FROM midgard_agg.bond_totals
+				WHERE block_timestamp <= c.block_timestamp
+				ORDER BY block_timestamp DESC LIMIT 1), 0)
+		FROM UNNEST($1::BIGINT[]) AS c(block_timestamp)
+	`
+	rows, err := db.Query(ctx, q, pq.Array(timestamps))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var timestamp db.Nano
+		var before, after int64
+		err = rows.Scan(&timestamp, &before, &after)
+		if err != nil {
+			return err
+		}
+		c := &churns[idx[timestamp]]
+		c.BondBeforeE8 = before
+		c.BondAfterE8 = after
+	}
+	return rows.Err()
+}
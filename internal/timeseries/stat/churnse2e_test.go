@@ -0,0 +1,89 @@
+package stat_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gitlab.com/thorchain/midgard/config"
+	"gitlab.com/thorchain/midgard/internal/db"
+	"gitlab.com/thorchain/midgard/internal/db/testdb"
+	"gitlab.com/thorchain/midgard/internal/util"
+	"gitlab.com/thorchain/midgard/openapi/generated/oapigen"
+)
+
+func TestChurnsE2E(t *testing.T) {
+	testdb.InitTest(t)
+
+	testdb.InsertBlockLog(t, 10, "2020-01-01 00:00:00")
+	testdb.InsertBlockLog(t, 20, "2020-01-02 00:00:00")
+	testdb.InsertBlockLog(t, 30, "2020-01-03 00:00:00")
+
+	testdb.InsertBondEvent(t, testdb.FakeBond{
+		BondType: "bond_paid", E8: 1000, BlockTimestamp: "2019-12-31 00:00:00",
+	})
+
+	// First churn
+	testdb.InsertActiveVaultEvent(t, "vault1", "2020-01-01 00:00:00")
+	testdb.InsertUpdateNodeAccountStatusEvent(t, testdb.FakeNodeStatus{
+		NodeAddr: "node1", Former: "Standby", Current: "Active",
+	}, "2020-01-01 00:00:00")
+
+	// Second churn
+	testdb.InsertActiveVaultEvent(t, "vault3", "2020-01-02 00:00:00")
+	testdb.InsertActiveVaultEvent(t, "vault2", "2020-01-02 00:00:00")
+	testdb.InsertUpdateNodeAccountStatusEvent(t, testdb.FakeNodeStatus{
+		NodeAddr: "node2", Former: "Standby", Current: "Active",
+	}, "2020-01-02 00:00:00")
+	testdb.InsertUpdateNodeAccountStatusEvent(t, testdb.FakeNodeStatus{
+		NodeAddr: "node1", Former: "Active", Current: "Standby",
+	}, "2020-01-02 00:00:00")
+	testdb.InsertBondEvent(t, testdb.FakeBond{
+		BondType: "bond_reward", E8: 50, BlockTimestamp: "2020-01-02 00:00:00",
+	})
+	testdb.InsertInactiveVaultEvent(t, "vault1", "2020-01-02 01:00:00")
+
+	// The bond totals are read from the aggregates.
+	db.LastCommittedBlock.Set(30, testdb.StrToNano("2020-01-03 00:00:00"))
+	db.RefreshAggregatesForTests()
+
+	body := testdb.CallJSON(t, "http://localhost:8080/v2/churns")
+
+	var result oapigen.ChurnsResponse
+	testdb.MustUnmarshal(t, body, &result)
+
+	require.Equal(t, 2, len(result))
+
+	duration := "3600"
+	require.Equal(t, oapigen.ChurnItem{
+		Height:        "20",
+		Date:          util.IntStr(testdb.StrToNano("2020-01-02 00:00:00").ToI()),
+		NodesJoined:   []string{"node2"},
+		NodesLeft:     []string{"node1"},
+		NewVaults:     []string{"vault2", "vault3"},
+		RetiredVaults: []string{"vault1"},
+		BondBefore:    "1000",
+		BondAfter:     "1050",
+		Duration:      &duration,
+	}, result[0])
+
+	require.Equal(t, oapigen.ChurnItem{
+		Height:        "10",
+		Date:          util.IntStr(testdb.StrToNano("2020-01-01 00:00:00").ToI()),
+		NodesJoined:   []string{"node1"},
+		NodesLeft:     []string{},
+		NewVaults:     []string{"vault1"},
+		RetiredVaults: []string{},
+		BondBefore:    "1000",
+		BondAfter:     "1000",
+	}, result[1])
+
+	body = testdb.CallJSON(t, "http://localhost:8080/v2/churns?limit=1&offset=1")
+	testdb.MustUnmarshal(t, body, &result)
+	require.Equal(t, 1, len(result))
+	require.Equal(t, "10", result[0].Height)
+
+	// In lite mode the churns before the retained window may be deleted.
+	config.Global.Retention.Days = 10
+	t.Cleanup(func() { config.Global.Retention.Days = 0 })
+	testdb.CallFail(t, "http://localhost:8080/v2/churns?limit=5", "retained window")
+}
@@ -0,0 +1,105 @@
+package stat
+
+import (
+	"context"
+
+	"gitlab.com/thorchain/midgard/internal/db"
+	"gitlab.com/thorchain/midgard/internal/fetch/record"
+)
+
+var depthChangesAggregate = db.RegisterAggregate(
+	db.NewAggregate("pool_depth_changes", "pool_depth_changes").
+		AddGroupColumn("pool").
+		AddGroupColumn("reason").
+		AddBigintSumColumn("asset_e8").
+		AddBigintSumColumn("rune_e8").
+		AddBigintSumColumn("synth_e8"))
+
+type DepthChange struct {
+	Reason  record.DepthChangeReason
+	AssetE8 int64
+	RuneE8  int64
+	SynthE8 int64
+}
+
+type DepthChangeBucket struct {
+	Window db.Window
+	// Difference of the depths at the end and at the start of the bucket.
+	AssetDelta int64
+	RuneDelta  int64
+	SynthDelta int64
+	// One entry for every reason in record.DepthChangeReasons, these add up to the deltas.
+	Changes []DepthChange
+}
+
+// Returns dense buckets with the depth changes of the pool broken down by reason.
+func PoolDepthChangesHistory(ctx context.Context, buckets db.Buckets, pool string) (
+	ret []DepthChangeBucket, err error,
+) {
+	beforeDepth, depths, err := PoolDepthHistory(ctx, buckets, pool)
+	if err != nil {
+		return nil, err
+	}
+
+	reasonIdx := make(map[record.DepthChangeReason]int, len(record.DepthChangeReasons))
+	for i, reason := range record.DepthChangeReasons {
+		reasonIdx[reason] = i
+	}
+
+	ret = make([]DepthChangeBucket, buckets.Count())
+	prev := beforeDepth
+	for i := range ret {
+		current := depths[i].Depths
+		ret[i] = DepthChangeBucket{
+			Window:     buckets.BucketWindow(i),
+			AssetDelta: current.AssetDepth - prev.AssetDepth,
+			RuneDelta:  current.RuneDepth - prev.RuneDepth,
+			SynthDelta: current.SynthDepth - prev.SynthDepth,
+			Changes:    make([]DepthChange, len(record.DepthChangeReasons)),
+		}
+		for j, reason := range record.DepthChangeReasons {
+			ret[i].Changes[j].Reason = reason
+		}
+		prev = current
+	}
+
+	q, params := depthChangesAggregate.BucketedQuery(`
+		SELECT
+			aggregate_timestamp/1000000000 AS time,
+			reason,
+			SUM(asset_e8),
+			SUM(rune_e8),
+			SUM(synth_e8)
+		FROM %s
+		GROUP BY time, reason
+		ORDER BY time ASC
+	`, buckets, []string{"pool = $1"}, []interface{}{pool})
+
+	rows, err := db.Query(ctx, q, params...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	idx := 0
+	for rows.Next() {
+		var timestamp db.Second
+		var change DepthChange
+		err = rows.Scan(&timestamp, &change.Reason, &change.AssetE8, &change.RuneE8, &change.SynthE8)
+		if err != nil {
+			return nil, err
+		}
+		for idx < len(ret) && ret[idx].Window.From < timestamp {
+			idx++
+		}
+		if idx == len(ret) {
+			break
+		}
+		j, ok := reasonIdx[change.Reason]
+		if !ok {
+			continue
+		}
+		ret[idx].Changes[j] = change
+	}
+	return ret, rows.Err()
+}
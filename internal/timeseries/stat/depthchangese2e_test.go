@@ -0,0 +1,100 @@
+package stat_test
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gitlab.com/thorchain/midgard/internal/db"
+	"gitlab.com/thorchain/midgard/internal/db/testdb"
+	"gitlab.com/thorchain/midgard/openapi/generated/oapigen"
+)
+
+func changeOf(t *testing.T, item oapigen.DepthChangesHistoryItem, reason string) oapigen.DepthChange {
+	for _, c := range item.Changes {
+		if string(c.Reason) == reason {
+			return c
+		}
+	}
+	t.Fatalf("reason %s missing", reason)
+	return oapigen.DepthChange{}
+}
+
+func requireChangesAddUp(t *testing.T, item oapigen.DepthChangesHistoryItem) {
+	var asset, rune int64
+	for _, c := range item.Changes {
+		a, err := strconv.ParseInt(c.AssetE8, 10, 64)
+		require.Nil(t, err)
+		r, err := strconv.ParseInt(c.RuneE8, 10, 64)
+		require.Nil(t, err)
+		asset += a
+		rune += r
+	}
+	require.Equal(t, item.AssetDelta, strconv.FormatInt(asset, 10))
+	require.Equal(t, item.RuneDelta, strconv.FormatInt(rune, 10))
+}
+
+func TestDepthChangesHistoryE2E(t *testing.T) {
+	blocks := testdb.InitTestBlocks(t)
+
+	blocks.NewBlock(t, "2020-09-01 00:10:00",
+		testdb.AddLiquidity{
+			Pool: "BNB.BNB", AssetAmount: 1000, RuneAmount: 2000, LiquidityProviderUnits: 1,
+		},
+		testdb.PoolActivate{Pool: "BNB.BNB"},
+	)
+
+	blocks.NewBlock(t, "2020-09-02 00:10:00",
+		testdb.Swap{
+			Coin:      "100 BNB.BNB",
+			EmitAsset: "150 THOR.RUNE",
+			Pool:      "BNB.BNB",
+		},
+		testdb.Withdraw{
+			Pool:                   "BNB.BNB",
+			Coin:                   "1 THOR.RUNE",
+			EmitAsset:              10,
+			EmitRune:               20,
+			LiquidityProviderUnits: 1,
+		},
+	)
+
+	db.RefreshAggregatesForTests()
+
+	from := db.StrToSec("2020-09-01 00:00:00")
+	to := db.StrToSec("2020-09-03 00:00:00")
+	body := testdb.CallJSON(t, fmt.Sprintf(
+		"http://localhost:8080/v2/history/depth_changes/BNB.BNB?interval=day&from=%d&to=%d",
+		from, to))
+
+	var result oapigen.DepthChangesHistoryResponse
+	testdb.MustUnmarshal(t, body, &result)
+
+	require.Equal(t, 2, len(result.Intervals))
+
+	day1 := result.Intervals[0]
+	require.Equal(t, "1000", day1.AssetDelta)
+	require.Equal(t, "2000", day1.RuneDelta)
+	require.Equal(t, "1000", changeOf(t, day1, "add").AssetE8)
+	require.Equal(t, "0", changeOf(t, day1, "swap").AssetE8)
+	requireChangesAddUp(t, day1)
+
+	day2 := result.Intervals[1]
+	require.Equal(t, "90", day2.AssetDelta)
+	require.Equal(t, "-170", day2.RuneDelta)
+	require.Equal(t, "100", changeOf(t, day2, "swap").AssetE8)
+	require.Equal(t, "-150", changeOf(t, day2, "swap").RuneE8)
+	require.Equal(t, "-10", changeOf(t, day2, "withdraw").AssetE8)
+	require.Equal(t, "-20", changeOf(t, day2, "withdraw").RuneE8)
+	requireChangesAddUp(t, day2)
+
+	require.Equal(t, "1090", result.Meta.AssetDelta)
+	require.Equal(t, "1830", result.Meta.RuneDelta)
+	requireChangesAddUp(t, result.Meta)
+}
+
+func TestDepthChangesUnknownPool(t *testing.T) {
+	testdb.InitTest(t)
+	testdb.JSONFailGeneral(t, "http://localhost:8080/v2/history/depth_changes/BNB.BNB")
+}
@@ -0,0 +1,253 @@
+package stat
+
+import (
+	"context"
+
+	"gitlab.com/thorchain/midgard/internal/db"
+)
+
+// The node of a bond event is given in the memo for bonds, unbonds and leaves (e.g.
+// `BOND:<node>:<provider>`), for the other bond types it's the address of the event
+// (same as in the bond state check).
+//
+// Direct lookups by node use the bond_events_node_idx expression index (core migration 0006),
+// the expression there has to be kept identical to this one. update_node_bond_totals (aggregates
+// migration 0003) has a copy of it too.
+const bondNodeExpression = `CASE
+		WHEN memo ~* '^(BOND|UNBOND|LEAVE):' THEN SPLIT_PART(memo, ':', 2)
+		ELSE COALESCE(from_addr, to_addr)
+		END`
+
+// The bond change of a bond event. midgard_agg.update_bond_totals and update_node_bond_totals
+// (aggregates migrations 0002 and 0003) have a copy of it.
+const signedBondExpression = `CASE
+		WHEN bond_type IN ('bond_paid', 'bond_reward') THEN e8
+		WHEN bond_type IN ('bond_returned', 'bond_cost') THEN -e8
+		ELSE 0
+		END`
+
+var nodeBondsAggregate = db.RegisterAggregate(db.NewAggregate("node_bonds", "bond_events").
+	AddGroupExpression("node_addr", bondNodeExpression).
+	AddSumlikeExpression("bond_change_e8", "SUM("+signedBondExpression+")::BIGINT").
+	AddSumlikeExpression("rewards_e8",
+		"SUM(CASE WHEN bond_type = 'bond_reward' THEN e8 ELSE 0 END)::BIGINT").
+	AddSumlikeExpression("slashed_e8",
+		"SUM(CASE WHEN bond_type = 'bond_cost' THEN e8 ELSE 0 END)::BIGINT"))
+
+var nodeSlashPointsAggregate = db.RegisterAggregate(
+	db.NewAggregate("node_slash_points", "slash_points").
+		AddGroupColumn("node_address").
+		AddBigintSumColumn("slash_points"))
+
+// Types of the node timeline entries, the bond types are used as is.
+const (
+	NodeEventSlashPoints = "slash_points"
+	NodeEventStatus      = "status"
+	NodeEventVersion     = "version"
+	NodeEventIPAddress   = "ip_address"
+)
+
+// NodeEvent is an entry of the node timeline. Only the fields relevant for the Type are filled.
+type NodeEvent struct {
+	Height    int64
+	Timestamp db.Nano
+	Type      string
+	// Bond amount for bond events, number of points for slash points.
+	Amount    int64
+	TxID      string
+	Reason    string
+	Former    string
+	Current   string
+	Version   string
+	IPAddress string
+}
+
+// Returns the events of the node, newest first.
+//
+// Note: slash_amounts are not included, those are recorded per pool without a node address.
+// Slashes of the bond show up as bond_cost events.
+func NodeTimeline(ctx context.Context, nodeAddr string, limit, offset int) (
+	ret []NodeEvent, err error,
+) {
+	q := `
+		WITH node_events AS (
+			SELECT block_timestamp, bond_type AS type, e8 AS amount, tx,
+				'' AS reason, '' AS former, '' AS current, '' AS version, '' AS ip_addr
+			FROM bond_events
+			WHERE (` + bondNodeExpression + `) = $1
+			UNION ALL
+			SELECT block_timestamp, 'slash_points', slash_points, '',
+				reason, '', '', '', ''
+			FROM slash_points
+			WHERE node_address = $1
+			UNION ALL
+			SELECT block_timestamp, 'status', 0, '',
+				'', former, current, '', ''
+			FROM update_node_account_status_events
+			WHERE node_addr = $1
+			UNION ALL
+			SELECT block_timestamp, 'version', 0, '',
+				'', '', '', version, ''
+			FROM set_version_events
+			WHERE node_addr = $1
+			UNION ALL
+			SELECT block_timestamp, 'ip_address', 0, '',
+				'', '', '', '', ip_addr
+			FROM set_ip_address_events
+			WHERE node_addr = $1
+		)
+		SELECT
+			COALESCE(height, 0),
+			node_events.*
+		FROM node_events
+		LEFT JOIN block_log ON timestamp = block_timestamp
+		ORDER BY block_timestamp DESC, type ASC
+		LIMIT $2 OFFSET $3
+	`
+	rows, err := db.Query(ctx, q, nodeAddr, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ret = []NodeEvent{}
+	for rows.Next() {
+		var e NodeEvent
+		err = rows.Scan(&e.Height, &e.Timestamp, &e.Type, &e.Amount, &e.TxID,
+			&e.Reason, &e.Former, &e.Current, &e.Version, &e.IPAddress)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, e)
+	}
+	return ret, rows.Err()
+}
+
+type NodeHistoryBucket struct {
+	Window db.Window
+	// Bond of the node at the end of the bucket.
+	BondE8      int64
+	RewardsE8   int64
+	SlashedE8   int64
+	SlashPoints int64
+}
+
+// Returns dense buckets with the bond, rewards and slashes of the node.
+func NodeHistory(ctx context.Context, buckets db.Buckets, nodeAddr string) (
+	ret []NodeHistoryBucket, err error,
+) {
+	ret = make([]NodeHistoryBucket, buckets.Count())
+	for i := range ret {
+		ret[i].Window = buckets.BucketWindow(i)
+	}
+
+	// The bond before the buckets is the materialized total (the old bond_events may be deleted in
+	// lite mode) plus the changes after the last aggregates refresh.
+	var bond int64
+	beforeQ := `
+		WITH w AS (
+			SELECT watermark FROM midgard_agg.watermarks
+			WHERE materialized_table = 'node_bond_totals'
+		)
+		SELECT
+			COALESCE((
+				SELECT bond_e8 FROM midgard_agg.node_bond_totals
+				WHERE node_addr = $1 AND block_timestamp < LEAST($2, (SELECT watermark FROM w))
+				ORDER BY block_timestamp DESC LIMIT 1), 0)
+			+ COALESCE((
+				SELECT SUM(` + signedBondExpression + `)::BIGINT
+				FROM bond_events
+				WHERE (` + bondNodeExpression + `) = $1
+					AND (SELECT watermark FROM w) <= block_timestamp AND block_timestamp < $2), 0)
+		`
+	beforeRows, err := db.Query(ctx, beforeQ, nodeAddr, buckets.Start().ToNano())
+	if err != nil {
+		return nil, err
+	}
+	defer beforeRows.Close()
+	if beforeRows.Next() {
+		err = beforeRows.Scan(&bond)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	q, params := nodeBondsAggregate.BucketedQuery(`
+		SELECT
+			aggregate_timestamp/1000000000 AS time,
+			SUM(bond_change_e8),
+			SUM(rewards_e8),
+			SUM(slashed_e8)
+		FROM %s
+		WHERE node_addr = $1
+		GROUP BY time
+		ORDER BY time ASC
+	`, buckets, nil, []interface{}{nodeAddr})
+
+	rows, err := db.Query(ctx, q, params...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	changes := make([]int64, len(ret))
+	idx := 0
+	for rows.Next() {
+		var timestamp db.Second
+		var change, rewards, slashed int64
+		err = rows.Scan(&timestamp, &change, &rewards, &slashed)
+		if err != nil {
+			return nil, err
+		}
+		for idx < len(ret) && ret[idx].Window.From < timestamp {
+			idx++
+		}
+		if idx == len(ret) {
+			break
+		}
+		changes[idx] = change
+		ret[idx].RewardsE8 = rewards
+		ret[idx].SlashedE8 = slashed
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	for i := range ret {
+		bond += changes[i]
+		ret[i].BondE8 = bond
+	}
+
+	q, params = nodeSlashPointsAggregate.BucketedQuery(`
+		SELECT
+			aggregate_timestamp/1000000000 AS time,
+			SUM(slash_points)
+		FROM %s
+		WHERE node_address = $1
+		GROUP BY time
+		ORDER BY time ASC
+	`, buckets, nil, []interface{}{nodeAddr})
+
+	slashRows, err := db.Query(ctx, q, params...)
+	if err != nil {
+		return nil, err
+	}
+	defer slashRows.Close()
+
+	idx = 0
+	for slashRows.Next() {
+		var timestamp db.Second
+		var points int64
+		err = slashRows.Scan(&timestamp, &points)
+		if err != nil {
+			return nil, err
+		}
+		for idx < len(ret) && ret[idx].Window.From < timestamp {
+			idx++
+		}
+		if idx == len(ret) {
+			break
+		}
+		ret[idx].SlashPoints = points
+	}
+	return ret, slashRows.Err()
+}
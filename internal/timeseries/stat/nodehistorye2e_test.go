@@ -0,0 +1,142 @@
+package stat_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gitlab.com/thorchain/midgard/config"
+	"gitlab.com/thorchain/midgard/internal/db"
+	"gitlab.com/thorchain/midgard/internal/db/testdb"
+	"gitlab.com/thorchain/midgard/openapi/generated/oapigen"
+)
+
+func nodeTestEvents(t *testing.T) {
+	testdb.InitTest(t)
+
+	testdb.InsertBlockLog(t, 1, "2020-01-01 12:00:00")
+	testdb.InsertBlockLog(t, 2, "2020-01-02 12:00:00")
+	testdb.InsertBlockLog(t, 3, "2020-01-03 12:00:00")
+
+	testdb.InsertBondEvent(t, testdb.FakeBond{
+		Tx:             "TX1",
+		FromAddr:       "provider",
+		Memo:           "BOND:thornode1",
+		BondType:       "bond_paid",
+		E8:             1000,
+		BlockTimestamp: "2020-01-01 12:00:00",
+	})
+	// Bond of another node.
+	testdb.InsertBondEvent(t, testdb.FakeBond{
+		FromAddr:       "provider",
+		Memo:           "BOND:thornode2",
+		BondType:       "bond_paid",
+		E8:             5000,
+		BlockTimestamp: "2020-01-01 12:00:00",
+	})
+	testdb.InsertUpdateNodeAccountStatusEvent(t, testdb.FakeNodeStatus{
+		NodeAddr: "thornode1", Former: "Standby", Current: "Active",
+	}, "2020-01-02 12:00:00")
+	testdb.InsertSetVersionEvent(t, "thornode1", "1.90.0", "2020-01-02 12:00:00")
+	testdb.InsertSetIPAddressEvent(t, "thornode1", "1.2.3.4", "2020-01-02 12:00:00")
+	testdb.InsertBondEvent(t, testdb.FakeBond{
+		FromAddr:       "thornode1",
+		BondType:       "bond_reward",
+		E8:             30,
+		BlockTimestamp: "2020-01-03 12:00:00",
+	})
+	testdb.InsertBondEvent(t, testdb.FakeBond{
+		FromAddr:       "thornode1",
+		BondType:       "bond_cost",
+		E8:             10,
+		BlockTimestamp: "2020-01-03 12:00:00",
+	})
+	testdb.InsertSlashPoints(t, "thornode1", 2, "not_signing", "2020-01-03 12:00:00")
+
+	db.RefreshAggregatesForTests()
+}
+
+func TestNodeTimelineE2E(t *testing.T) {
+	nodeTestEvents(t)
+
+	body := testdb.CallJSON(t, "http://localhost:8080/v2/node/thornode1/history")
+
+	var result oapigen.NodeTimelineResponse
+	testdb.MustUnmarshal(t, body, &result)
+
+	require.Equal(t, 7, len(result))
+	require.Equal(t, oapigen.NodeEventTypeBondCost, result[0].Type)
+	require.Equal(t, "3", result[0].Height)
+	require.Equal(t, "10", *result[0].Amount)
+	require.Equal(t, oapigen.NodeEventTypeSlashPoints, result[2].Type)
+	require.Equal(t, "not_signing", *result[2].Reason)
+
+	require.Equal(t, oapigen.NodeEventTypeStatus, result[4].Type)
+	require.Equal(t, "Active", *result[4].CurrentStatus)
+	require.Nil(t, result[4].Amount)
+
+	last := result[6]
+	require.Equal(t, oapigen.NodeEventTypeBondPaid, last.Type)
+	require.Equal(t, "1", last.Height)
+	require.Equal(t, "1000", *last.Amount)
+	require.Equal(t, "TX1", *last.TxId)
+
+	body = testdb.CallJSON(t, "http://localhost:8080/v2/node/thornode1/history?limit=2&offset=5")
+	testdb.MustUnmarshal(t, body, &result)
+	require.Equal(t, 2, len(result))
+	require.Equal(t, oapigen.NodeEventTypeVersion, result[0].Type)
+	require.Equal(t, "1.90.0", *result[0].Version)
+
+	testdb.JSONFailGeneral(t, "http://localhost:8080/v2/node/thornode1/history?limit=0")
+}
+
+func TestNodeHistoryE2E(t *testing.T) {
+	nodeTestEvents(t)
+
+	from := db.StrToSec("2020-01-02 00:00:00")
+	to := db.StrToSec("2020-01-04 00:00:00")
+	body := testdb.CallJSON(t, fmt.Sprintf(
+		"http://localhost:8080/v2/history/node/thornode1?interval=day&from=%d&to=%d", from, to))
+
+	var result oapigen.NodeHistoryResponse
+	testdb.MustUnmarshal(t, body, &result)
+
+	require.Equal(t, 2, len(result.Intervals))
+	require.Equal(t, oapigen.NodeHistoryItem{
+		StartTime:   epochStr("2020-01-02 00:00:00"),
+		EndTime:     epochStr("2020-01-03 00:00:00"),
+		Bond:        "1000",
+		Rewards:     "0",
+		Slashed:     "0",
+		SlashPoints: "0",
+	}, result.Intervals[0])
+	require.Equal(t, oapigen.NodeHistoryItem{
+		StartTime:   epochStr("2020-01-03 00:00:00"),
+		EndTime:     epochStr("2020-01-04 00:00:00"),
+		Bond:        "1020",
+		Rewards:     "30",
+		Slashed:     "10",
+		SlashPoints: "2",
+	}, result.Intervals[1])
+
+	require.Equal(t, "1020", result.Meta.Bond)
+	require.Equal(t, "2", result.Meta.SlashPoints)
+
+	// The bond before the buckets is kept when the old bond events are deleted in lite mode.
+	testdb.MustExec(t, "DELETE FROM bond_events WHERE block_timestamp < $1",
+		testdb.StrToNano("2020-01-02 00:00:00"))
+	body = testdb.CallJSON(t, fmt.Sprintf(
+		"http://localhost:8080/v2/history/node/thornode1?interval=day&count=2&to=%d", to))
+	testdb.MustUnmarshal(t, body, &result)
+	require.Equal(t, "1000", result.Intervals[0].Bond)
+	require.Equal(t, "1020", result.Intervals[1].Bond)
+}
+
+func TestNodeTimelineRetention(t *testing.T) {
+	nodeTestEvents(t)
+	config.Global.Retention.Days = 10
+	t.Cleanup(func() { config.Global.Retention.Days = 0 })
+
+	testdb.CallFail(t, "http://localhost:8080/v2/node/thornode1/history?limit=3",
+		"retained window")
+}
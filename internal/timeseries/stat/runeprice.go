@@ -0,0 +1,133 @@
+package stat
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"strings"
+
+	"gitlab.com/thorchain/midgard/internal/db"
+)
+
+type RunePriceBucket struct {
+	Window db.Window
+	// Last price in the bucket, NaN if there was no price yet.
+	Price float64
+	Low   float64
+	High  float64
+	// Aggregation method and contributing pools of the last price.
+	Method string
+	Pools  []string
+}
+
+var runePriceAggregate = db.RegisterAggregate(db.NewAggregate("rune_price_usd", "rune_price_usd").
+	AddLastColumn("price").
+	AddLastColumn("method").
+	AddLastColumn("pools").
+	AddMinColumn("price").
+	AddMaxColumn("price"))
+
+type runePriceRow struct {
+	price  float64
+	method string
+	pools  []string
+}
+
+func splitPools(pools string) []string {
+	if pools == "" {
+		return []string{}
+	}
+	return strings.Split(pools, ",")
+}
+
+func runePriceBefore(ctx context.Context, timestamp db.Nano) (ret runePriceRow, err error) {
+	ret.price = math.NaN()
+	ret.pools = []string{}
+	q := `
+		SELECT price, method, pools
+		FROM rune_price_usd
+		WHERE block_timestamp < $1
+		ORDER BY block_timestamp DESC
+		LIMIT 1
+	`
+	rows, err := db.Query(ctx, q, timestamp)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		var pools string
+		err = rows.Scan(&ret.price, &ret.method, &pools)
+		ret.pools = splitPools(pools)
+	}
+	return
+}
+
+// Returns the recorded RUNE/USD price for every bucket, carrying the last price over buckets in
+// which it didn't change. Low and High include the price the bucket started with.
+func RunePriceHistory(ctx context.Context, buckets db.Buckets) (ret []RunePriceBucket, err error) {
+	current, err := runePriceBefore(ctx, buckets.Start().ToNano())
+	if err != nil {
+		return nil, err
+	}
+
+	q, qargs := runePriceAggregate.BucketedQuery(`
+		SELECT
+			price,
+			method,
+			pools,
+			min_price,
+			max_price,
+			aggregate_timestamp / 1000000000 AS truncated
+		FROM %s
+		WHERE price IS NOT NULL
+		ORDER BY aggregate_timestamp ASC
+	`, buckets, nil, nil)
+
+	ret = make([]RunePriceBucket, buckets.Count())
+
+	var next, inBucket struct {
+		row       runePriceRow
+		low, high float64
+	}
+	applied := false
+	readNext := func(rows *sql.Rows) (nextTimestamp db.Second, err error) {
+		var pools string
+		err = rows.Scan(
+			&next.row.price, &next.row.method, &pools, &next.low, &next.high, &nextTimestamp)
+		if err != nil {
+			return 0, err
+		}
+		next.row.pools = splitPools(pools)
+		return
+	}
+	applyNext := func() {
+		inBucket = next
+		applied = true
+	}
+	saveBucket := func(idx int, bucketWindow db.Window) {
+		low, high := current.price, current.price
+		if applied {
+			if math.IsNaN(low) || inBucket.low < low {
+				low = inBucket.low
+			}
+			if math.IsNaN(high) || high < inBucket.high {
+				high = inBucket.high
+			}
+			current = inBucket.row
+			applied = false
+		}
+		ret[idx] = RunePriceBucket{
+			Window: bucketWindow,
+			Price:  current.price,
+			Low:    low,
+			High:   high,
+			Method: current.method,
+			Pools:  current.pools,
+		}
+	}
+
+	err = queryBucketedGeneral(ctx, buckets, readNext, applyNext, saveBucket, q, qargs...)
+	return ret, err
+}
@@ -119,6 +119,18 @@ type swapFeesTotal struct {
 	AssetAmount int64
 }
 
+const (
+	// A double swap is recorded as two swap events with the same memo. To count them only once
+	// the second (RUNE -> asset) leg is skipped, which is recognised by having RUNE inbound
+	// from a chain other than THORChain.
+	countedSwapLeg = `(from_asset <> 'THOR.RUNE' OR chain = 'THOR')`
+
+	// Value of the swap in RUNE, measured on the RUNE side of the counted leg.
+	swapVolumeInRune = `CASE WHEN ` + countedSwapLeg + ` THEN
+		CASE WHEN _direction%2 = 0 THEN from_e8 ELSE to_e8 + liq_fee_in_rune_e8 END
+		ELSE 0 END`
+)
+
 var SwapsAggregate = db.RegisterAggregate(db.NewAggregate("swaps", "swap_events").
 	AddGroupColumn("pool").
 	AddGroupColumn("_direction").
@@ -0,0 +1,199 @@
+package stat
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gitlab.com/thorchain/midgard/internal/db"
+)
+
+var SwappersAggregate = db.RegisterAggregate(db.NewAggregate("swappers", "swap_events").
+	AddGroupColumn("from_addr").
+	AddSumlikeExpression("swap_count",
+		"SUM(CASE WHEN "+countedSwapLeg+" THEN 1 ELSE 0 END)::BIGINT").
+	AddSumlikeExpression("volume_e8",
+		"SUM("+swapVolumeInRune+")::BIGINT").
+	AddSumlikeExpression("volume_e8_usd",
+		"SUM(("+swapVolumeInRune+") * priceusd)::BIGINT"))
+
+// Lower bounds (in RUNE e8) of the swap size histogram bins. The first bin starts at 0, the last
+// one is unbounded.
+var SwapSizeBins = []int64{0, 10e8, 100e8, 1000e8, 10000e8, 100000e8}
+
+func swapSizeBinExpression() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "CASE WHEN NOT %s THEN -1", countedSwapLeg)
+	for i := len(SwapSizeBins) - 1; 0 < i; i-- {
+		fmt.Fprintf(&b, "\n\t\tWHEN %d <= (%s) THEN %d", SwapSizeBins[i], swapVolumeInRune, i)
+	}
+	fmt.Fprint(&b, "\n\t\tELSE 0 END")
+	return b.String()
+}
+
+var SwapSizesAggregate = db.RegisterAggregate(db.NewAggregate("swap_sizes", "swap_events").
+	AddGroupExpression("size_bin", swapSizeBinExpression()).
+	AddSumlikeExpression("swap_count", "COUNT(1)").
+	AddSumlikeExpression("volume_e8", "SUM("+swapVolumeInRune+")::BIGINT"))
+
+type SwapSizeBin struct {
+	// Index into SwapSizeBins
+	Bin       int
+	SwapCount int64
+	VolumeE8  int64
+}
+
+type UsersBucket struct {
+	Window db.Window
+	// Addresses which swapped in the bucket.
+	ActiveSwappers int64
+	// Addresses which swapped the first time in the bucket.
+	NewSwappers int64
+	SwapCount   int64
+	VolumeE8    int64
+	// One entry for every bin of SwapSizeBins.
+	SwapSizes []SwapSizeBin
+}
+
+func (b UsersBucket) ReturningSwappers() int64 {
+	return b.ActiveSwappers - b.NewSwappers
+}
+
+// Returns dense buckets with the number of active and new swappers and the swap size histogram.
+func UsersHistory(ctx context.Context, buckets db.Buckets) (ret []UsersBucket, err error) {
+	ret = make([]UsersBucket, buckets.Count())
+	for i := range ret {
+		ret[i].Window = buckets.BucketWindow(i)
+		ret[i].SwapSizes = make([]SwapSizeBin, len(SwapSizeBins))
+		for bin := range ret[i].SwapSizes {
+			ret[i].SwapSizes[bin].Bin = bin
+		}
+	}
+
+	bucketIdx := func(timestamp db.Second) int {
+		for i := range ret {
+			if ret[i].Window.From == timestamp {
+				return i
+			}
+		}
+		return -1
+	}
+
+	// An address is new in the bucket if its first swap is in the bucket. The first swaps are
+	// kept up to date by the aggregates refresh, only the active addresses are looked up.
+	q, params := SwappersAggregate.BucketedQuery(`
+		SELECT
+			aggregate_timestamp/1000000000 AS time,
+			COUNT(*),
+			COUNT(*) FILTER (WHERE aggregate_timestamp <= first_swaps.block_timestamp),
+			SUM(swap_count),
+			SUM(volume_e8)
+		FROM %s
+		JOIN midgard_agg.first_swaps USING (from_addr)
+		WHERE 0 < swap_count
+		GROUP BY time
+		ORDER BY time ASC
+	`, buckets, nil, nil)
+
+	rows, err := db.Query(ctx, q, params...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var timestamp db.Second
+		var bucket UsersBucket
+		err = rows.Scan(&timestamp, &bucket.ActiveSwappers, &bucket.NewSwappers,
+			&bucket.SwapCount, &bucket.VolumeE8)
+		if err != nil {
+			return nil, err
+		}
+		idx := bucketIdx(timestamp)
+		if idx < 0 {
+			continue
+		}
+		ret[idx].ActiveSwappers = bucket.ActiveSwappers
+		ret[idx].NewSwappers = bucket.NewSwappers
+		ret[idx].SwapCount = bucket.SwapCount
+		ret[idx].VolumeE8 = bucket.VolumeE8
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	q, params = SwapSizesAggregate.BucketedQuery(`
+		SELECT
+			aggregate_timestamp/1000000000 AS time,
+			size_bin,
+			SUM(swap_count),
+			SUM(volume_e8)
+		FROM %s
+		WHERE 0 <= size_bin
+		GROUP BY time, size_bin
+		ORDER BY time ASC, size_bin ASC
+	`, buckets, nil, nil)
+
+	sizeRows, err := db.Query(ctx, q, params...)
+	if err != nil {
+		return nil, err
+	}
+	defer sizeRows.Close()
+	for sizeRows.Next() {
+		var timestamp db.Second
+		var bin SwapSizeBin
+		err = sizeRows.Scan(&timestamp, &bin.Bin, &bin.SwapCount, &bin.VolumeE8)
+		if err != nil {
+			return nil, err
+		}
+		idx := bucketIdx(timestamp)
+		if idx < 0 || bin.Bin < 0 || len(SwapSizeBins) <= bin.Bin {
+			continue
+		}
+		ret[idx].SwapSizes[bin.Bin] = bin
+	}
+	return ret, sizeRows.Err()
+}
+
+type TraderStats struct {
+	Address     string
+	SwapCount   int64
+	VolumeE8    int64
+	VolumeUsdE8 int64
+}
+
+// Returns the addresses with the highest swap volume in the given period.
+func TradersLeaderboard(ctx context.Context, buckets db.Buckets, limit int) (
+	ret []TraderStats, err error,
+) {
+	q, params := SwappersAggregate.BucketedQuery(`
+		SELECT
+			from_addr,
+			SUM(swap_count),
+			SUM(volume_e8) AS volume,
+			SUM(volume_e8_usd)
+		FROM %s
+		GROUP BY from_addr
+		HAVING 0 < SUM(swap_count)
+		ORDER BY volume DESC, from_addr ASC
+	`, buckets, nil, nil)
+	params = append(params, limit)
+	q += " LIMIT $" + strconv.Itoa(len(params))
+
+	rows, err := db.Query(ctx, q, params...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ret = []TraderStats{}
+	for rows.Next() {
+		var stats TraderStats
+		err = rows.Scan(&stats.Address, &stats.SwapCount, &stats.VolumeE8, &stats.VolumeUsdE8)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, stats)
+	}
+	return ret, rows.Err()
+}
@@ -0,0 +1,117 @@
+package stat_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gitlab.com/thorchain/midgard/internal/db"
+	"gitlab.com/thorchain/midgard/internal/db/testdb"
+	"gitlab.com/thorchain/midgard/openapi/generated/oapigen"
+)
+
+func traderTestBlocks(t *testing.T) {
+	blocks := testdb.InitTestBlocks(t)
+
+	blocks.NewBlock(t, "2020-09-01 00:00:00",
+		testdb.AddLiquidity{
+			Pool: "BNB.BNB", AssetAmount: 1000000, RuneAmount: 10000000, LiquidityProviderUnits: 1,
+		},
+		testdb.PoolActivate{Pool: "BNB.BNB"},
+	)
+
+	blocks.NewBlock(t, "2020-09-01 00:10:00",
+		testdb.Swap{
+			Coin:               "100000 BNB.BNB",
+			EmitAsset:          "100 THOR.RUNE",
+			Pool:               "BNB.BNB",
+			LiquidityFeeInRune: 10000,
+			FromAddress:        "addrA",
+		},
+		testdb.Swap{
+			Coin:        "2000000000 THOR.RUNE",
+			EmitAsset:   "10 BNB.BNB",
+			Pool:        "BNB.BNB",
+			Chain:       "THOR",
+			FromAddress: "addrB",
+		},
+	)
+
+	blocks.NewBlock(t, "2020-09-02 00:10:00",
+		testdb.Swap{
+			Coin:        "100000 BNB.BNB",
+			EmitAsset:   "900 THOR.RUNE",
+			Pool:        "BNB.BNB",
+			FromAddress: "addrA",
+		},
+		testdb.Swap{
+			Coin:        "5000000000 THOR.RUNE",
+			EmitAsset:   "10 BNB.BNB",
+			Pool:        "BNB.BNB",
+			Chain:       "THOR",
+			FromAddress: "addrC",
+		},
+	)
+
+	db.RefreshAggregatesForTests()
+}
+
+func TestUsersHistoryE2E(t *testing.T) {
+	traderTestBlocks(t)
+
+	from := db.StrToSec("2020-09-01 00:00:00")
+	to := db.StrToSec("2020-09-03 00:00:00")
+
+	body := testdb.CallJSON(t, fmt.Sprintf(
+		"http://localhost:8080/v2/history/users?interval=day&from=%d&to=%d", from, to))
+
+	var result oapigen.UsersHistoryResponse
+	testdb.MustUnmarshal(t, body, &result)
+
+	require.Equal(t, 2, len(result.Intervals))
+
+	day1 := result.Intervals[0]
+	require.Equal(t, "2", day1.ActiveSwappers)
+	require.Equal(t, "2", day1.NewSwappers)
+	require.Equal(t, "0", day1.ReturningSwappers)
+	require.Equal(t, "2", day1.SwapCount)
+	require.Equal(t, "2000010100", day1.Volume)
+
+	day2 := result.Intervals[1]
+	require.Equal(t, "2", day2.ActiveSwappers)
+	require.Equal(t, "1", day2.NewSwappers)
+	require.Equal(t, "1", day2.ReturningSwappers)
+	require.Equal(t, "5000000900", day2.Volume)
+
+	require.Equal(t, "0", day2.SwapSizes[0].LowerBound)
+	require.Equal(t, "1000000000", *day2.SwapSizes[0].UpperBound)
+	require.Equal(t, "1", day2.SwapSizes[0].SwapCount)
+	require.Equal(t, "900", day2.SwapSizes[0].Volume)
+	require.Equal(t, "1", day2.SwapSizes[1].SwapCount)
+	require.Equal(t, "5000000000", day2.SwapSizes[1].Volume)
+	require.Equal(t, "0", day2.SwapSizes[2].SwapCount)
+	require.Nil(t, day2.SwapSizes[len(day2.SwapSizes)-1].UpperBound)
+
+	require.Equal(t, "3", result.Meta.ActiveSwappers)
+	require.Equal(t, "3", result.Meta.NewSwappers)
+	require.Equal(t, "4", result.Meta.SwapCount)
+	require.Equal(t, "2", result.Meta.SwapSizes[0].SwapCount)
+}
+
+func TestTradersLeaderboardE2E(t *testing.T) {
+	traderTestBlocks(t)
+
+	body := testdb.CallJSON(t, "http://localhost:8080/v2/traders?period=all")
+
+	var result oapigen.TradersResponse
+	testdb.MustUnmarshal(t, body, &result)
+	require.Equal(t, oapigen.TradersResponse{
+		{Address: "addrC", SwapCount: "1", Volume: "5000000000", VolumeUsd: "0"},
+		{Address: "addrB", SwapCount: "1", Volume: "2000000000", VolumeUsd: "0"},
+		{Address: "addrA", SwapCount: "2", Volume: "11000", VolumeUsd: "0"},
+	}, result)
+
+	body = testdb.CallJSON(t, "http://localhost:8080/v2/traders?period=all&limit=2")
+	testdb.MustUnmarshal(t, body, &result)
+	require.Equal(t, 2, len(result))
+}
@@ -2,33 +2,18 @@ package stat
 
 import (
 	"fmt"
-	"math"
 	"net/http"
+	"strings"
 
 	"gitlab.com/thorchain/midgard/config"
 	"gitlab.com/thorchain/midgard/internal/timeseries"
 )
 
 func runePriceUSDForDepths(depths timeseries.DepthMap) float64 {
-	ret := math.NaN()
-	var maxdepth int64 = -1
-
-	for _, pool := range config.Global.UsdPools {
-		poolInfo, ok := depths[pool]
-		if ok && maxdepth < poolInfo.RuneDepth {
-			maxdepth = poolInfo.RuneDepth
-
-			if poolInfo.AssetPrice() != 0 {
-				ret = 1 / poolInfo.AssetPrice()
-			} else {
-				ret = 1
-			}
-		}
-	}
-	return ret
+	return timeseries.ComputeRunePriceUSD(depths, config.Global.UsdPools, config.Global.UsdPrice).Price
 }
 
-// Returns the 1/price from the depest whitelisted pool.
+// Returns the 1/price aggregated from the whitelisted pools, see config.UsdPrice.
 func RunePriceUSD() float64 {
 	return runePriceUSDForDepths(timeseries.Latest.GetState().Pools)
 }
@@ -46,5 +31,9 @@ func ServeUSDDebug(resp http.ResponseWriter, req *http.Request) {
 		}
 	}
 
-	fmt.Fprintf(resp, "\n\nrunePriceUSD: %v", RunePriceUSD())
+	price := timeseries.ComputeRunePriceUSD(
+		state.Pools, config.Global.UsdPools, config.Global.UsdPrice)
+	fmt.Fprintf(resp, "\n\nrunePriceUSD: %v", price.Price)
+	fmt.Fprintf(resp, "\nmethod: %s", price.Method)
+	fmt.Fprintf(resp, "\ncontributing pools: %s", strings.Join(price.Pools, ", "))
 }
@@ -1,12 +1,12 @@
 package stat_test
 
 import (
+	"fmt"
 	"testing"
 
-	"gitlab.com/thorchain/midgard/config"
-
 	"github.com/stretchr/testify/require"
 	"gitlab.com/thorchain/midgard/config"
+	"gitlab.com/thorchain/midgard/internal/db"
 	"gitlab.com/thorchain/midgard/internal/db/testdb"
 	"gitlab.com/thorchain/midgard/internal/timeseries"
 	"gitlab.com/thorchain/midgard/openapi/generated/oapigen"
@@ -74,3 +74,45 @@ func TestPrices(t *testing.T) {
 		require.Equal(t, "2", result.AssetPrice)
 	}
 }
+
+func TestRunePriceHistoryE2E(t *testing.T) {
+	testdb.InitTest(t)
+
+	testdb.InsertRunePriceUSD(t, 2, "USDA", "2020-01-05 12:00:00")
+	testdb.InsertRunePriceUSD(t, 3, "USDA", "2020-01-10 12:00:00")
+	testdb.InsertRunePriceUSD(t, 1.5, "USDB", "2020-01-10 14:00:00")
+	testdb.InsertRunePriceUSD(t, 4, "USDB", "2020-01-12 08:00:00")
+
+	db.RefreshAggregatesForTests()
+
+	from := db.StrToSec("2020-01-09 00:00:00")
+	to := db.StrToSec("2020-01-13 00:00:00")
+
+	body := testdb.CallJSON(t, fmt.Sprintf(
+		"http://localhost:8080/v2/history/rune_price?interval=day&from=%d&to=%d", from, to))
+
+	var result oapigen.RunePriceHistoryResponse
+	testdb.MustUnmarshal(t, body, &result)
+
+	require.Equal(t, 4, len(result.Intervals))
+	require.Equal(t, epochStr("2020-01-09 00:00:00"), result.Meta.StartTime)
+	require.Equal(t, epochStr("2020-01-13 00:00:00"), result.Meta.EndTime)
+	require.Equal(t, "4", result.Meta.RunePriceUSD)
+
+	// from the initial value
+	require.Equal(t, "2", result.Intervals[0].RunePriceUSD)
+	require.Equal(t, []string{"USDA"}, result.Intervals[0].Pools)
+
+	require.Equal(t, "1.5", result.Intervals[1].RunePriceUSD)
+	require.Equal(t, "1.5", result.Intervals[1].LowPriceUSD)
+	require.Equal(t, "3", result.Intervals[1].HighPriceUSD)
+	require.Equal(t, []string{"USDB"}, result.Intervals[1].Pools)
+
+	// gapfill
+	require.Equal(t, "1.5", result.Intervals[2].RunePriceUSD)
+	require.Equal(t, "1.5", result.Intervals[2].HighPriceUSD)
+
+	require.Equal(t, "4", result.Intervals[3].RunePriceUSD)
+	require.Equal(t, "1.5", result.Intervals[3].LowPriceUSD)
+	require.Equal(t, "deepest", result.Intervals[3].Method)
+}
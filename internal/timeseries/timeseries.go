@@ -6,15 +6,16 @@ import (
 	"encoding/gob"
 	"errors"
 	"fmt"
-	"math"
 	"sync/atomic"
 	"time"
 
 	"gitlab.com/thorchain/midgard/internal/fetch/sync/chain"
 
 	"github.com/rs/zerolog/log"
+	"gitlab.com/thorchain/midgard/config"
 	"gitlab.com/thorchain/midgard/internal/db"
 	"gitlab.com/thorchain/midgard/internal/fetch/record"
+	"gitlab.com/thorchain/midgard/internal/kafkasink"
 	"gitlab.com/thorchain/midgard/internal/util/timer"
 )
 
@@ -92,6 +93,30 @@ func Setup(whitelist []string) error {
 	return rows.Err()
 }
 
+// Drops the in-memory write state and restores it from the DB.
+// Used when a standby instance takes over writing.
+func Reload(whitelist []string) error {
+	record.ResetRecorder()
+	depthRecorder = depthManager{}
+	runePriceRecorder = runePriceManager{}
+	return Setup(whitelist)
+}
+
+// Reloads the state if an other instance committed blocks since the last load. Used by the
+// standby instances to follow the writer.
+func ReloadIfChanged(whitelist []string) error {
+	var height int64
+	err := QueryOneValue(&height, context.Background(),
+		"SELECT COALESCE(MAX(height), 0) FROM block_log")
+	if err != nil {
+		return fmt.Errorf("last block lookup: %w", err)
+	}
+	if height == db.LastCommittedBlock.Get().Height {
+		return nil
+	}
+	return Reload(whitelist)
+}
+
 // QueryOneValue is a helper to make store single value queries
 // result into dest
 func QueryOneValue(dest interface{}, ctx context.Context, query string, args ...interface{}) error {
@@ -115,21 +140,22 @@ func QueryOneValue(dest interface{}, ctx context.Context, query string, args ...
 	return nil
 }
 
+func runePriceUSDForDepths(depths DepthMap) RunePriceUSD {
+	return ComputeRunePriceUSD(depths, usdPoolWhitelist, config.Global.UsdPrice)
+}
+
 func RunePriceUSDForDepths(depths DepthMap) float64 {
-	ret := math.NaN()
-	var maxdepth int64 = -1
-
-	for _, pool := range usdPoolWhitelist {
-		poolInfo, ok := depths[pool]
-		if ok && maxdepth < poolInfo.RuneDepth {
-			maxdepth = poolInfo.RuneDepth
-			ret = 1 / poolInfo.AssetPrice()
-		}
-	}
-	return ret
+	return runePriceUSDForDepths(depths).Price
 }
 
 func ProcessBlock(block *chain.Block, commit bool) (err error) {
+	return ProcessParsedBlock(record.ParseBlock(block), commit)
+}
+
+// Records a block parsed with record.ParseBlock or record.ParseBlocks.
+// Blocks have to be processed in order.
+func ProcessParsedBlock(parsed *record.ParsedBlock, commit bool) (err error) {
+	block := parsed.Block
 	err = db.Inserter.StartBlock()
 	if err != nil {
 		return
@@ -138,7 +164,7 @@ func ProcessBlock(block *chain.Block, commit bool) (err error) {
 	poolPrice := make(map[string]float64)
 	poolPriceUSD := make(map[string]float64)
 	// Record all the events
-	record.ProcessBlock(block)
+	record.RecordBlock(parsed)
 
 	depths := make(map[string]PoolDepths)
 	for pool := range record.Recorder.AssetE8DepthPerPool() {
@@ -148,11 +174,12 @@ func ProcessBlock(block *chain.Block, commit bool) (err error) {
 			PoolUnit:   record.Recorder.UnitsPerPool()[pool],
 		}
 	}
+	runePriceUSD := runePriceUSDForDepths(depths)
 	for pool := range record.Recorder.AssetE8DepthPerPool() {
 		if _, ok := record.Recorder.AssetE8DepthPerPool()[pool]; ok {
 			if _, ok := record.Recorder.RuneE8DepthPerPool()[pool]; ok {
 				poolPrice[pool] = AssetPrice(record.Recorder.AssetE8DepthPerPool()[pool], record.Recorder.RuneE8DepthPerPool()[pool])
-				poolPriceUSD[pool] = RunePriceUSDForDepths(depths) * poolPrice[pool]
+				poolPriceUSD[pool] = runePriceUSD.Price * poolPrice[pool]
 			}
 		}
 	}
@@ -194,7 +221,7 @@ func ProcessBlock(block *chain.Block, commit bool) (err error) {
 		return fmt.Errorf("persist block height %d: %w", block.Height, err)
 	}
 
-	err = depthRecorder.update(block.Time,
+	changedPools, err := depthRecorder.update(block.Time,
 		track.aggTrack.AssetE8DepthPerPool,
 		track.aggTrack.RuneE8DepthPerPool,
 		track.aggTrack.SynthE8DepthPerPool,
@@ -203,6 +230,18 @@ func ProcessBlock(block *chain.Block, commit bool) (err error) {
 		return
 	}
 
+	if kafkasink.Enabled() {
+		err = kafkasink.StageBlock(parsed, sinkDepths(parsed, changedPools, &track.aggTrack))
+		if err != nil {
+			return
+		}
+	}
+
+	err = runePriceRecorder.update(block.Time, runePriceUSD)
+	if err != nil {
+		return
+	}
+
 	err = db.Inserter.EndBlock()
 	if err != nil {
 		return
@@ -212,6 +251,9 @@ func ProcessBlock(block *chain.Block, commit bool) (err error) {
 		defer blockFlushTimer.One()()
 
 		err = db.Inserter.Flush()
+		if errors.Is(err, db.ErrWriterFenced) {
+			return err
+		}
 		if err != nil {
 			db.MarkBatchInserterFail()
 			log.Fatal().Err(err).Msg("Inserter.Flush() failed. Marking BatchInserter as failed and exiting to switch to TxInserter.")
@@ -231,6 +273,27 @@ func ProcessBlock(block *chain.Block, commit bool) (err error) {
 	return nil
 }
 
+// Depths of the pools which changed in the block, including the ones with depth changes which
+// cancel out.
+func sinkDepths(parsed *record.ParsedBlock, changedPools []string, agg *aggTrack) map[string]kafkasink.Depths {
+	ret := map[string]kafkasink.Depths{}
+	add := func(pool string) {
+		ret[pool] = kafkasink.Depths{
+			AssetE8: agg.AssetE8DepthPerPool[pool],
+			RuneE8:  agg.RuneE8DepthPerPool[pool],
+			SynthE8: agg.SynthE8DepthPerPool[pool],
+			Units:   agg.UnitsPerPool[pool],
+		}
+	}
+	for _, pool := range changedPools {
+		add(pool)
+	}
+	for _, c := range parsed.DepthChanges {
+		add(c.Pool)
+	}
+	return ret
+}
+
 func setLastBlock(track *blockTrack) {
 	lastBlockTrack.Store(track)
 	db.LastCommittedBlock.Set(track.Height, db.TimeToNano(track.Timestamp))
@@ -0,0 +1,182 @@
+// RUNE/USD price oracle.
+// The price is aggregated from the configured USD pools and every change is recorded in the
+// rune_price_usd table together with the pools that contributed to it.
+package timeseries
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"gitlab.com/thorchain/midgard/config"
+	"gitlab.com/thorchain/midgard/internal/db"
+)
+
+// RunePriceUSD is the result of the price aggregation.
+type RunePriceUSD struct {
+	// NaN if none of the USD pools exist.
+	Price float64
+	// Method which was used for the aggregation.
+	Method string
+	// USD pools which contributed to the price, in the order of the config.
+	Pools []string
+}
+
+type usdPoolPrice struct {
+	pool string
+	// USD per RUNE
+	price float64
+	// rune depth in e8
+	weight float64
+}
+
+func usdPriceMethod(cfg config.UsdPrice) string {
+	if cfg.Method == "" {
+		return "deepest"
+	}
+	return cfg.Method
+}
+
+// ComputeRunePriceUSD aggregates the RUNE/USD price from the given USD pools.
+//
+// Pools which don't exist, are empty or have less rune depth than `MinRuneDepth` are ignored.
+// If `MaxDeviation` is set, pools whose price is further from the rune depth weighted median
+// than the allowed ratio are rejected before the configured method is applied.
+// If none of the pools qualify the price of the deepest existing pool is used, 1 if it's empty,
+// as before the aggregation was configurable (method "fallback").
+func ComputeRunePriceUSD(depths DepthMap, usdPools []string, cfg config.UsdPrice) RunePriceUSD {
+	ret := RunePriceUSD{Price: math.NaN(), Method: usdPriceMethod(cfg)}
+
+	candidates := make([]usdPoolPrice, 0, len(usdPools))
+	for _, pool := range usdPools {
+		poolInfo, ok := depths[pool]
+		if !ok || poolInfo.AssetDepth <= 0 || poolInfo.RuneDepth <= 0 {
+			continue
+		}
+		if poolInfo.RuneDepth < cfg.MinRuneDepth {
+			continue
+		}
+		candidates = append(candidates, usdPoolPrice{
+			pool:   pool,
+			price:  1 / poolInfo.AssetPrice(),
+			weight: float64(poolInfo.RuneDepth),
+		})
+	}
+	if len(candidates) == 0 {
+		return fallbackRunePriceUSD(depths, usdPools)
+	}
+
+	if 0 < cfg.MaxDeviation {
+		median := weightedMedian(candidates)
+		kept := candidates[:0]
+		for _, c := range candidates {
+			if math.Abs(c.price-median) <= cfg.MaxDeviation*median {
+				kept = append(kept, c)
+			}
+		}
+		candidates = kept
+	}
+
+	switch ret.Method {
+	case "median":
+		ret.Price = weightedMedian(candidates)
+		ret.Pools = poolNames(candidates)
+	case "mean":
+		var sum, weights float64
+		for _, c := range candidates {
+			sum += c.price * c.weight
+			weights += c.weight
+		}
+		ret.Price = sum / weights
+		ret.Pools = poolNames(candidates)
+	default:
+		deepest := candidates[0]
+		for _, c := range candidates[1:] {
+			if deepest.weight < c.weight {
+				deepest = c
+			}
+		}
+		ret.Price = deepest.price
+		ret.Pools = []string{deepest.pool}
+	}
+	return ret
+}
+
+func fallbackRunePriceUSD(depths DepthMap, usdPools []string) RunePriceUSD {
+	ret := RunePriceUSD{Price: math.NaN(), Method: "fallback"}
+	var maxDepth int64 = -1
+	for _, pool := range usdPools {
+		poolInfo, ok := depths[pool]
+		if !ok || poolInfo.RuneDepth <= maxDepth {
+			continue
+		}
+		maxDepth = poolInfo.RuneDepth
+		ret.Price = 1
+		if poolInfo.AssetPrice() != 0 {
+			ret.Price = 1 / poolInfo.AssetPrice()
+		}
+		ret.Pools = []string{pool}
+	}
+	return ret
+}
+
+// Returns the lower weighted median of the prices. Doesn't modify the input.
+func weightedMedian(prices []usdPoolPrice) float64 {
+	sorted := make([]usdPoolPrice, len(prices))
+	copy(sorted, prices)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].price < sorted[j].price })
+
+	var total float64
+	for _, p := range sorted {
+		total += p.weight
+	}
+	var cumulative float64
+	for _, p := range sorted {
+		cumulative += p.weight
+		if total <= 2*cumulative {
+			return p.price
+		}
+	}
+	return sorted[len(sorted)-1].price
+}
+
+func poolNames(prices []usdPoolPrice) []string {
+	ret := make([]string, len(prices))
+	for i, p := range prices {
+		ret[i] = p.pool
+	}
+	return ret
+}
+
+// Keeps track of the last recorded RUNE/USD price and inserts a row into rune_price_usd
+// whenever it changes.
+type runePriceManager struct {
+	last *RunePriceUSD
+}
+
+var runePriceRecorder runePriceManager
+
+func (rm *runePriceManager) update(timestamp time.Time, price RunePriceUSD) error {
+	if math.IsNaN(price.Price) {
+		return nil
+	}
+	if rm.last != nil && rm.last.Price == price.Price && rm.last.Method == price.Method &&
+		strings.Join(rm.last.Pools, ",") == strings.Join(price.Pools, ",") {
+		return nil
+	}
+
+	cols := []string{"price", "method", "pools", "block_timestamp"}
+	err := db.Inserter.Insert("rune_price_usd", cols,
+		price.Price, price.Method, strings.Join(price.Pools, ","), timestamp.UnixNano())
+	if err != nil {
+		return fmt.Errorf("error saving rune price (timestamp: %d): %w", timestamp.UnixNano(), err)
+	}
+	rm.last = &price
+	return nil
+}
+
+func ResetRunePriceRecorderForTest() {
+	runePriceRecorder = runePriceManager{}
+}
@@ -0,0 +1,72 @@
+package timeseries
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gitlab.com/thorchain/midgard/config"
+)
+
+var testUsdDepths = DepthMap{
+	"USDA": {AssetDepth: 300, RuneDepth: 100},
+	"USDB": {AssetDepth: 5000, RuneDepth: 1000},
+	"USDC": {AssetDepth: 4000, RuneDepth: 1000},
+	"USDX": {AssetDepth: 100000, RuneDepth: 1000},
+	"USDZ": {AssetDepth: 0, RuneDepth: 0},
+}
+
+func TestRunePriceDeepest(t *testing.T) {
+	price := ComputeRunePriceUSD(testUsdDepths, []string{"USDA", "USDB", "USDC"}, config.UsdPrice{})
+	require.Equal(t, 5.0, price.Price)
+	require.Equal(t, "deepest", price.Method)
+	require.Equal(t, []string{"USDB"}, price.Pools)
+}
+
+func TestRunePriceMedian(t *testing.T) {
+	price := ComputeRunePriceUSD(testUsdDepths, []string{"USDA", "USDB", "USDC", "USDZ"},
+		config.UsdPrice{Method: "median"})
+	require.Equal(t, 4.0, price.Price)
+	require.Equal(t, []string{"USDA", "USDB", "USDC"}, price.Pools)
+}
+
+func TestRunePriceMean(t *testing.T) {
+	price := ComputeRunePriceUSD(testUsdDepths, []string{"USDA", "USDB", "USDC"},
+		config.UsdPrice{Method: "mean"})
+	require.InDelta(t, 9300.0/2100, price.Price, 1e-9)
+}
+
+func TestRunePriceOutlier(t *testing.T) {
+	price := ComputeRunePriceUSD(testUsdDepths, []string{"USDB", "USDC", "USDX"},
+		config.UsdPrice{Method: "mean", MaxDeviation: 0.5})
+	require.Equal(t, 4.5, price.Price)
+	require.Equal(t, []string{"USDB", "USDC"}, price.Pools)
+}
+
+func TestRunePriceMinDepth(t *testing.T) {
+	price := ComputeRunePriceUSD(testUsdDepths, []string{"USDA", "USDB", "USDC"},
+		config.UsdPrice{Method: "median", MinRuneDepth: 500})
+	require.Equal(t, 4.0, price.Price)
+	require.Equal(t, []string{"USDB", "USDC"}, price.Pools)
+
+	price = ComputeRunePriceUSD(testUsdDepths, []string{"USDA"},
+		config.UsdPrice{MinRuneDepth: 500})
+	require.Equal(t, 3.0, price.Price)
+	require.Equal(t, "fallback", price.Method)
+	require.Equal(t, []string{"USDA"}, price.Pools)
+}
+
+func TestRunePriceFallback(t *testing.T) {
+	price := ComputeRunePriceUSD(testUsdDepths, []string{"USDZ"}, config.UsdPrice{})
+	require.Equal(t, 1.0, price.Price)
+	require.Equal(t, "fallback", price.Method)
+	require.Equal(t, []string{"USDZ"}, price.Pools)
+
+	price = ComputeRunePriceUSD(testUsdDepths, []string{"USDA", "USDZ"},
+		config.UsdPrice{MinRuneDepth: 500})
+	require.Equal(t, 3.0, price.Price)
+
+	price = ComputeRunePriceUSD(testUsdDepths, []string{"MISSING"}, config.UsdPrice{})
+	require.True(t, math.IsNaN(price.Price))
+	require.Empty(t, price.Pools)
+}
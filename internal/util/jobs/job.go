@@ -3,6 +3,8 @@ package jobs
 import (
 	"context"
 	"fmt"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/rs/zerolog/log"
@@ -10,7 +12,8 @@ import (
 
 type NamedFunction struct {
 	name string
-	job  func()
+	// Gets the id of the job in the Running list.
+	job func(id int)
 }
 
 type RunningJob struct {
@@ -26,18 +29,84 @@ func Later(name string, job func()) NamedFunction {
 	if name == "" {
 		log.Fatal().Msg("Missing job name")
 	}
-	return NamedFunction{name, job}
+	return NamedFunction{name, func(int) { job() }}
+}
+
+type JobStatus struct {
+	Name    string    `json:"name"`
+	Started time.Time `json:"started"`
+	// Empty for jobs without a supervisor.
+	RestartPolicy string `json:"restartPolicy,omitempty"`
+	State         string `json:"state"`
+	Crashes       int    `json:"crashes"`
+	LastError     string `json:"lastError,omitempty"`
+}
+
+const (
+	StateRunning    = "running"
+	StateRestarting = "restarting"
+)
+
+var (
+	runningMutex sync.Mutex
+	runningJobs  = map[int]*JobStatus{}
+	lastJobId    int
+)
+
+// Registers a job in the list returned by Running, returns its id.
+func register(name string) int {
+	runningMutex.Lock()
+	defer runningMutex.Unlock()
+	lastJobId++
+	runningJobs[lastJobId] = &JobStatus{Name: name, Started: time.Now(), State: StateRunning}
+	return lastJobId
+}
+
+func unregister(id int) {
+	runningMutex.Lock()
+	defer runningMutex.Unlock()
+	delete(runningJobs, id)
+}
+
+func updateStatus(id int, update func(status *JobStatus)) {
+	runningMutex.Lock()
+	defer runningMutex.Unlock()
+	if status, ok := runningJobs[id]; ok {
+		update(status)
+	}
 }
 
 func Start(name string, job func()) RunningJob {
+	return start(name, func(int) { job() })
+}
+
+func start(name string, job func(id int)) RunningJob {
 	ret := RunningJob{quitFinished: make(chan struct{}), name: name}
+	id := register(name)
 	go func() {
-		job()
+		job(id)
+		unregister(id)
 		ret.quitFinished <- struct{}{}
 	}()
 	return ret
 }
 
+// Returns the jobs which are running, in the order they were started.
+func Running() []JobStatus {
+	runningMutex.Lock()
+	defer runningMutex.Unlock()
+	ids := make([]int, 0, len(runningJobs))
+	for id := range runningJobs {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	ret := make([]JobStatus, 0, len(ids))
+	for _, id := range ids {
+		ret = append(ret, *runningJobs[id])
+	}
+	return ret
+}
+
 func (nf NamedFunction) Start() *RunningJob {
 	if nf.job == nil {
 		return nil
@@ -45,7 +114,7 @@ func (nf NamedFunction) Start() *RunningJob {
 	if nf.name == "" {
 		log.Fatal().Msg("Job without name")
 	}
-	job := Start(nf.name, nf.job)
+	job := start(nf.name, nf.job)
 	return &job
 }
 
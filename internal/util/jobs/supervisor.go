@@ -0,0 +1,125 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"github.com/pascaldekloe/metrics"
+	"gitlab.com/thorchain/midgard/internal/util/midlog"
+)
+
+// RestartPolicy decides what the supervisor does when a job stops before its context is
+// cancelled. A job fails when it returns an error or panics.
+type RestartPolicy int
+
+const (
+	// Failure is fatal, Midgard shuts down.
+	RestartNever RestartPolicy = iota
+	// Restarted with exponential backoff after failures, returning nil finishes the job.
+	RestartOnFailure
+	// Restarted with exponential backoff whenever it stops.
+	RestartAlways
+)
+
+func (p RestartPolicy) String() string {
+	switch p {
+	case RestartNever:
+		return "never"
+	case RestartOnFailure:
+		return "on-failure"
+	case RestartAlways:
+		return "always"
+	}
+	return fmt.Sprintf("RestartPolicy(%d)", int(p))
+}
+
+// Restart backoff doubles from MinRestartBackoff up to MaxRestartBackoff. It's reset if the job
+// ran for at least StableRunTime.
+var (
+	MinRestartBackoff = time.Second
+	MaxRestartBackoff = 5 * time.Minute
+	StableRunTime     = 10 * time.Minute
+)
+
+var supervisorLogger = midlog.LoggerForModule("jobs")
+
+var (
+	crashCount   = metrics.Must1LabelCounter("midgard_job_crashes_total", "job")
+	restartCount = metrics.Must1LabelCounter("midgard_job_restarts_total", "job")
+)
+
+func init() {
+	metrics.MustHelp("midgard_job_crashes_total", "Number of times the job returned an error or panicked.")
+	metrics.MustHelp("midgard_job_restarts_total", "Number of times the job was restarted by the supervisor.")
+}
+
+// Supervised creates a job which is restarted according to policy until ctx is cancelled.
+func Supervised(ctx context.Context, name string, policy RestartPolicy, job func() error) NamedFunction {
+	if name == "" {
+		supervisorLogger.Fatal("Missing job name")
+	}
+	return NamedFunction{name, func(id int) {
+		supervise(ctx, id, name, policy, job)
+	}}
+}
+
+func supervise(ctx context.Context, id int, name string, policy RestartPolicy, job func() error) {
+	updateStatus(id, func(status *JobStatus) {
+		status.RestartPolicy = policy.String()
+	})
+	backoff := MinRestartBackoff
+	for {
+		started := time.Now()
+		err := Recovered(job)
+		if ctx.Err() != nil {
+			return
+		}
+		if StableRunTime <= time.Since(started) {
+			backoff = MinRestartBackoff
+		}
+
+		if err == nil {
+			if policy != RestartAlways {
+				return
+			}
+			supervisorLogger.WarnF("Job %s stopped, restarting in %v", name, backoff)
+		} else {
+			crashCount(name).Add(1)
+			updateStatus(id, func(status *JobStatus) {
+				status.Crashes++
+				status.LastError = err.Error()
+			})
+			if policy == RestartNever {
+				supervisorLogger.ErrorEF(err, "Job %s failed, shutting down", name)
+				InitiateShutdown()
+				return
+			}
+			supervisorLogger.ErrorEF(err, "Job %s failed, restarting in %v", name, backoff)
+		}
+
+		updateStatus(id, func(status *JobStatus) { status.State = StateRestarting })
+		Sleep(ctx, backoff)
+		if ctx.Err() != nil {
+			return
+		}
+		backoff *= 2
+		if MaxRestartBackoff < backoff {
+			backoff = MaxRestartBackoff
+		}
+		restartCount(name).Add(1)
+		updateStatus(id, func(status *JobStatus) { status.State = StateRunning })
+	}
+}
+
+// Recovered runs the job and turns its panics into errors. Goroutines started by a supervised
+// job can use it to report their failure to the job instead of crashing Midgard.
+func Recovered(job func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v\n%s", r, debug.Stack())
+		}
+	}()
+	return job()
+}
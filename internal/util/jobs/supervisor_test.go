@@ -0,0 +1,93 @@
+package jobs_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gitlab.com/thorchain/midgard/internal/util/jobs"
+)
+
+func fastRestarts(t *testing.T) {
+	minBackoff, maxBackoff := jobs.MinRestartBackoff, jobs.MaxRestartBackoff
+	jobs.MinRestartBackoff = time.Millisecond
+	jobs.MaxRestartBackoff = 4 * time.Millisecond
+	t.Cleanup(func() {
+		jobs.MinRestartBackoff, jobs.MaxRestartBackoff = minBackoff, maxBackoff
+	})
+}
+
+func jobStatus(t *testing.T, name string) jobs.JobStatus {
+	for _, status := range jobs.Running() {
+		if status.Name == name {
+			return status
+		}
+	}
+	t.Fatalf("Job %s is not running", name)
+	return jobs.JobStatus{}
+}
+
+func TestSupervisorRestartsOnFailure(t *testing.T) {
+	fastRestarts(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	runs := 0
+	blocked := make(chan struct{})
+	job := jobs.Supervised(ctx, "TestOnFailure", jobs.RestartOnFailure, func() error {
+		runs++
+		switch runs {
+		case 1:
+			return errors.New("first failure")
+		case 2:
+			panic("second failure")
+		}
+		close(blocked)
+		<-ctx.Done()
+		return nil
+	}).Start()
+
+	<-blocked
+	status := jobStatus(t, "TestOnFailure")
+	require.Equal(t, "on-failure", status.RestartPolicy)
+	require.Equal(t, jobs.StateRunning, status.State)
+	require.Equal(t, 2, status.Crashes)
+	require.Contains(t, status.LastError, "second failure")
+
+	cancel()
+	job.MustWait()
+	require.Equal(t, 3, runs)
+}
+
+func TestSupervisorOnFailureFinishes(t *testing.T) {
+	fastRestarts(t)
+	runs := 0
+	jobs.Supervised(context.Background(), "TestFinish", jobs.RestartOnFailure, func() error {
+		runs++
+		return nil
+	}).Start().MustWait()
+	require.Equal(t, 1, runs)
+}
+
+func TestSupervisorRestartsAlways(t *testing.T) {
+	fastRestarts(t)
+	runs := 0
+	ctx, cancel := context.WithCancel(context.Background())
+	jobs.Supervised(ctx, "TestAlways", jobs.RestartAlways, func() error {
+		runs++
+		if runs == 3 {
+			cancel()
+		}
+		return nil
+	}).Start().MustWait()
+	require.Equal(t, 3, runs)
+}
+
+func TestRecovered(t *testing.T) {
+	require.NoError(t, jobs.Recovered(func() error { return nil }))
+	err := jobs.Recovered(func() error { panic("read failure") })
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "read failure")
+}
@@ -0,0 +1,187 @@
+package kafka_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/gob"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/abci/types"
+
+	"gitlab.com/thorchain/midgard/internal/fetch/record"
+	"gitlab.com/thorchain/midgard/internal/util/kafka"
+)
+
+// First event of block 305, written by the producer before V1.
+const legacyIndexedEventB64 = "AEn/gQMBAQxJbmRleGVkRXZlbnQB/4IAAQMBCkV2ZW50SW5kZXgB/4QAAQ5CbG9ja1RpbWVzdGFtcAH/hgABBUV2ZW50Af+IAAAALP+DAwEBCEV2ZW50SWR4Af+EAAECAQZIZWlnaHQBBAABBk9mZnNldAEEAAAAEP+FBQEBBFRpbWUB/4YAAAAs/4cDAQEFRXZlbnQB/4gAAQIBBFR5cGUBDAABCkF0dHJpYnV0ZXMB/4wAAAAl/4sCAQEWW110eXBlcy5FdmVudEF0dHJpYnV0ZQH/jAAB/4oAADj/iQMBAQ5FdmVudEF0dHJpYnV0ZQH/igABAwEDS2V5AQoAAQVWYWx1ZQEKAAEFSW5kZXgBAgAAAEP/ggEB/gJiAAEPAQAAAA7YA5jZNk/Htf//AQEHbWVzc2FnZQEBAQZhY3Rpb24BD3NldF9uZXR3b3JrX2ZlZQEBAAAA"
+
+var blockTime = time.Date(2021, 4, 10, 13, 2, 17, 911198133, time.UTC)
+
+func TestIndexedEventRoundTrip(t *testing.T) {
+	var codec kafka.IndexedEventCodec
+	event := kafka.IndexedEvent{
+		EventIndex:     kafka.EventIdx{Height: 305, Offset: 7},
+		Height:         305,
+		Offset:         7,
+		BlockTimestamp: blockTime,
+		Event: &types.Event{Type: "message", Attributes: []types.EventAttribute{
+			{Key: []byte("action"), Value: []byte("set_network_fee"), Index: true},
+		}},
+	}
+
+	data, err := codec.Encode(event)
+	require.NoError(t, err)
+	require.Equal(t, kafka.V1, data[0])
+	require.JSONEq(t, `{
+		"height": 305,
+		"offset": 7,
+		"blockTimestamp": "2021-04-10T13:02:17.911198133Z",
+		"event": {"type": "message", "attributes": [
+			{"key": "action", "value": "set_network_fee", "index": true}]}
+	}`, string(data[1:]))
+
+	decoded, err := codec.Decode(data)
+	require.NoError(t, err)
+	require.Equal(t, event, decoded)
+}
+
+func TestIndexedEventLegacyGob(t *testing.T) {
+	data, err := base64.StdEncoding.DecodeString(legacyIndexedEventB64)
+	require.NoError(t, err)
+	require.Equal(t, kafka.V0, data[0])
+
+	var codec kafka.IndexedEventCodec
+	decoded, err := codec.Decode(data)
+	require.NoError(t, err)
+
+	event := decoded.(kafka.IndexedEvent)
+	require.Equal(t, kafka.EventIdx{Height: 305, Offset: 0}, event.EventIndex)
+	require.True(t, blockTime.Equal(event.BlockTimestamp))
+	require.Equal(t, "message", event.Event.Type)
+	require.Equal(t, "set_network_fee", string(event.Event.Attributes[0].Value))
+}
+
+func TestIndexedEventUnknownVersion(t *testing.T) {
+	var codec kafka.IndexedEventCodec
+	_, err := codec.Decode([]byte{9, '{', '}'})
+	require.Error(t, err)
+}
+
+func TestParsedEventRoundTrip(t *testing.T) {
+	var codec kafka.ParsedEventCodec
+	event := kafka.ParsedEvent{
+		EventIndex:        kafka.EventIdx{Height: 1000, Offset: 3},
+		BlockTimestamp:    blockTime,
+		OriginalPartition: 2,
+		Type:              "stake",
+		Event: record.Stake{
+			AddBase: record.AddBase{
+				Pool:      []byte("BTC.BTC"),
+				AssetTx:   []byte("A1B2"),
+				AssetE8:   100,
+				RuneAddr:  []byte("thor1xyz"),
+				RuneE8:    9007199254740993,
+				RuneChain: []byte("THOR"),
+			},
+			StakeUnits: 42,
+		},
+	}
+
+	data, err := codec.Encode(event)
+	require.NoError(t, err)
+	require.Equal(t, kafka.V1, data[0])
+
+	var msg struct {
+		Type      string                 `json:"type"`
+		EventType string                 `json:"eventType"`
+		Event     map[string]interface{} `json:"event"`
+	}
+	require.NoError(t, json.Unmarshal(data[1:], &msg))
+	require.Equal(t, "stake", msg.Type)
+	require.Equal(t, "add_liquidity", msg.EventType)
+	require.Equal(t, "BTC.BTC", msg.Event["pool"])
+	require.Equal(t, "9007199254740993", msg.Event["runeE8"])
+	require.Equal(t, "42", msg.Event["stakeUnits"])
+	require.Nil(t, msg.Event["assetAddr"])
+
+	decoded, err := codec.Decode(data)
+	require.NoError(t, err)
+	require.Equal(t, event, decoded)
+}
+
+func TestParsedEventAllTypes(t *testing.T) {
+	var codec kafka.ParsedEventCodec
+	for _, eventType := range record.EventTypes() {
+		payload := record.NewEvent(eventType)
+		require.NotNil(t, payload, eventType)
+
+		data, err := codec.Encode(kafka.ParsedEvent{Type: eventType, Event: payload})
+		require.NoError(t, err, eventType)
+
+		decoded, err := codec.Decode(data)
+		require.NoError(t, err, eventType)
+		// Payloads decode as values, like the gob ones did.
+		require.Equal(t, payload, ptrTo(decoded.(kafka.ParsedEvent).Event), eventType)
+	}
+}
+
+func TestParsedEventRewards(t *testing.T) {
+	var codec kafka.ParsedEventCodec
+	event := kafka.ParsedEvent{
+		BlockTimestamp: blockTime,
+		Type:           "rewards",
+		Event: record.Rewards{
+			BondE8:  5,
+			PerPool: []record.Amount{{Asset: []byte("BNB.BNB"), E8: -7}},
+		},
+	}
+
+	data, err := codec.Encode(event)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"perPool":[{"asset":"BNB.BNB","e8":"-7"}]`)
+
+	decoded, err := codec.Decode(data)
+	require.NoError(t, err)
+	require.Equal(t, event, decoded)
+}
+
+func TestParsedEventNumbersAccepted(t *testing.T) {
+	var codec kafka.ParsedEventCodec
+	decoded, err := codec.Decode(append([]byte{kafka.V1},
+		`{"type":"swap","eventType":"swap","event":{"fromE8":12,"priceusd":1.5,"unknown":true}}`...))
+	require.NoError(t, err)
+	require.Equal(t, record.Swap{FromE8: 12, Priceusd: 1.5}, decoded.(kafka.ParsedEvent).Event)
+}
+
+func TestParsedEventUnregistered(t *testing.T) {
+	var codec kafka.ParsedEventCodec
+	_, err := codec.Encode(kafka.ParsedEvent{Type: "foo", Event: struct{}{}})
+	require.Error(t, err)
+}
+
+func TestParsedEventLegacyGob(t *testing.T) {
+	event := kafka.ParsedEvent{
+		EventIndex:     kafka.EventIdx{Height: 1000, Offset: 3},
+		BlockTimestamp: blockTime,
+		Type:           "swap",
+		Event:          record.Swap{Pool: []byte("BTC.BTC"), FromE8: 12, Priceusd: 1.5},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, gob.NewEncoder(&buf).Encode(event))
+
+	var codec kafka.ParsedEventCodec
+	decoded, err := codec.Decode(buf.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, event, decoded)
+}
+
+// Returns a pointer to a copy of the value, as record.NewEvent returns pointers.
+func ptrTo(v interface{}) interface{} {
+	p := reflect.New(reflect.TypeOf(v))
+	p.Elem().Set(reflect.ValueOf(v))
+	return p.Interface()
+}
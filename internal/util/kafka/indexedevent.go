@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/binary"
 	"encoding/gob"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/tendermint/tendermint/abci/types"
@@ -29,10 +30,6 @@ type EventIdx struct {
 	Offset int16
 }
 
-const (
-	V0 byte = 0
-)
-
 func (ie IndexedEvent) KeyAsString() (string, error) {
 	return fmt.Sprintf("%v.%06d", ie.Height, ie.Offset), nil
 }
@@ -53,6 +50,33 @@ func (ie IndexedEvent) KeyAsBinary() ([10]byte, error) {
 	return val, nil
 }
 
+// JSON form of the indexed events, schema version V1.
+type indexedEventV1 struct {
+	Height         int64     `json:"height"`
+	Offset         int16     `json:"offset"`
+	BlockTimestamp time.Time `json:"blockTimestamp"`
+	Event          *eventV1  `json:"event"`
+}
+
+type eventV1 struct {
+	Type       string             `json:"type"`
+	Attributes []eventAttributeV1 `json:"attributes"`
+}
+
+type eventAttributeV1 struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+	Index bool   `json:"index,omitempty"`
+}
+
+// Returns the EventIndex, falling back to the Height and Offset fields when it's not set.
+func (ie IndexedEvent) index() EventIdx {
+	if ie.EventIndex == (EventIdx{}) {
+		return EventIdx{Height: ie.Height, Offset: ie.Offset}
+	}
+	return ie.EventIndex
+}
+
 func (i *IndexedEventCodec) Encode(value interface{}) ([]byte, error) {
 	if _, isEvent := value.(IndexedEvent); !isEvent {
 		return nil, fmt.Errorf("codec requires value types.IndexedEvent, got %T", value)
@@ -60,15 +84,36 @@ func (i *IndexedEventCodec) Encode(value interface{}) ([]byte, error) {
 
 	iEvent := value.(IndexedEvent)
 
+	idx := iEvent.index()
+	msg := indexedEventV1{
+		Height:         idx.Height,
+		Offset:         idx.Offset,
+		BlockTimestamp: iEvent.BlockTimestamp,
+	}
+	if iEvent.Event != nil {
+		msg.Event = &eventV1{
+			Type:       iEvent.Event.Type,
+			Attributes: make([]eventAttributeV1, len(iEvent.Event.Attributes)),
+		}
+		for i, attr := range iEvent.Event.Attributes {
+			msg.Event.Attributes[i] = eventAttributeV1{
+				Key:   string(attr.Key),
+				Value: string(attr.Value),
+				Index: attr.Index,
+			}
+		}
+	}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+
 	// 1048576 is the tendermint default max_txs_bytes, plus some space for the iEvent fields
-	cbuf := NewCappedBuffer(make([]byte, 0, 1024), 1048576+256)
+	cbuf := NewCappedBuffer(make([]byte, 0, 1+len(data)), 1048576+256)
 
 	// Write Version
-	version := []byte{V0}
-	cbuf.Write(version)
-
-	enc := gob.NewEncoder(cbuf)
-	if err := enc.Encode(iEvent); err != nil {
+	cbuf.Write([]byte{V1})
+	if _, err := cbuf.Write(data); err != nil {
 		return nil, err
 	}
 
@@ -76,18 +121,54 @@ func (i *IndexedEventCodec) Encode(value interface{}) ([]byte, error) {
 }
 
 func (e *IndexedEventCodec) Decode(data []byte) (interface{}, error) {
-	version := data[0]
-	if version != V0 {
-		return nil, errors.New("unknown version while decoding message")
+	if len(data) == 0 {
+		return nil, errors.New("empty message")
 	}
 
-	buf := bytes.NewReader(data[1:])
+	switch data[0] {
+	case V0:
+		return decodeIndexedEventV0(data[1:])
+	case V1:
+		return decodeIndexedEventV1(data[1:])
+	default:
+		return nil, fmt.Errorf("unknown version %d while decoding message", data[0])
+	}
+}
+
+func decodeIndexedEventV0(data []byte) (IndexedEvent, error) {
+	buf := bytes.NewReader(data)
 	decode := gob.NewDecoder(buf)
 
 	iEvent := IndexedEvent{}
 
 	if err := decode.Decode(&iEvent); err != nil {
-		return nil, err
+		return IndexedEvent{}, err
+	}
+
+	return iEvent, nil
+}
+
+func decodeIndexedEventV1(data []byte) (IndexedEvent, error) {
+	var msg indexedEventV1
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return IndexedEvent{}, err
+	}
+
+	iEvent := IndexedEvent{
+		EventIndex:     EventIdx{Height: msg.Height, Offset: msg.Offset},
+		Height:         msg.Height,
+		Offset:         msg.Offset,
+		BlockTimestamp: msg.BlockTimestamp,
+	}
+	if msg.Event != nil {
+		iEvent.Event = &types.Event{Type: msg.Event.Type}
+		for _, attr := range msg.Event.Attributes {
+			iEvent.Event.Attributes = append(iEvent.Event.Attributes, types.EventAttribute{
+				Key:   []byte(attr.Key),
+				Value: []byte(attr.Value),
+				Index: attr.Index,
+			})
+		}
 	}
 
 	return iEvent, nil
@@ -3,7 +3,8 @@ package kafka
 import (
 	"bytes"
 	"encoding/gob"
-	"gitlab.com/thorchain/midgard/internal/fetch/record"
+	"encoding/json"
+	"fmt"
 	"time"
 )
 
@@ -21,48 +22,93 @@ type ParsedEvent struct {
 
 type ParsedEventCodec struct{}
 
-func init() {
-	gob.Register(record.Add{})
-	gob.Register(record.Errata{})
-	gob.Register(record.Fee{})
-	gob.Register(record.Gas{})
-	gob.Register(record.PoolBalanceChange{})
-	gob.Register(record.Rewards{})
-	gob.Register(record.Slash{})
-	gob.Register(record.Stake{})
-	gob.Register(record.Swap{})
-	gob.Register(record.Unstake{})
+// JSON form of the parsed events, schema version V1.
+type parsedEventV1 struct {
+	Height            int64     `json:"height"`
+	Offset            int16     `json:"offset"`
+	BlockTimestamp    time.Time `json:"blockTimestamp"`
+	OriginalPartition int32     `json:"originalPartition"`
+	Type              string    `json:"type"`
+
+	// Tendermint type of the payload, Type may be something else (e.g. "stake").
+	EventType string          `json:"eventType,omitempty"`
+	Event     json.RawMessage `json:"event"`
 }
 
 func (pc ParsedEventCodec) Encode(value interface{}) (b []byte, e error) {
-	//return json.Marshal(value)
-
-	var encodedEvent bytes.Buffer
-	enc := gob.NewEncoder(&encodedEvent)
+	pEvent, isEvent := value.(ParsedEvent)
+	if !isEvent {
+		return nil, fmt.Errorf("codec requires value kafka.ParsedEvent, got %T", value)
+	}
 
-	// Encode the value.
-	err := enc.Encode(value)
+	msg := parsedEventV1{
+		Height:            pEvent.EventIndex.Height,
+		Offset:            pEvent.EventIndex.Offset,
+		BlockTimestamp:    pEvent.BlockTimestamp,
+		OriginalPartition: pEvent.OriginalPartition,
+		Type:              pEvent.Type,
+		Event:             json.RawMessage("null"),
+	}
+	if pEvent.Event != nil {
+		var err error
+		msg.EventType, err = payloadType(pEvent.Event)
+		if err != nil {
+			return nil, err
+		}
+		msg.Event, err = marshalPayload(pEvent.Event)
+		if err != nil {
+			return nil, err
+		}
+	}
+	data, err := json.Marshal(msg)
 	if err != nil {
 		return nil, err
 	}
 
-	return encodedEvent.Bytes(), nil
+	return append([]byte{V1}, data...), nil
 }
 
 func (pc ParsedEventCodec) Decode(data []byte) (b interface{}, e error) {
-	var (
-		c   ParsedEvent
-		err error
-	)
-	//
-	//err = json.Unmarshal(data, &c)
-	//return c, err
+	// Messages written before V1 are gob without a version byte. A gob stream starts with the
+	// length of the ParsedEvent type definition, which is never 1, so V1 followed by '{' is
+	// unambiguous.
+	if len(data) > 1 && data[0] == V1 && data[1] == '{' {
+		return decodeParsedEventV1(data[1:])
+	}
+	return decodeParsedEventGob(data)
+}
+
+func decodeParsedEventV1(data []byte) (ParsedEvent, error) {
+	var msg parsedEventV1
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return ParsedEvent{}, err
+	}
+
+	pEvent := ParsedEvent{
+		EventIndex:        EventIdx{Height: msg.Height, Offset: msg.Offset},
+		BlockTimestamp:    msg.BlockTimestamp,
+		OriginalPartition: msg.OriginalPartition,
+		Type:              msg.Type,
+	}
+	if msg.EventType != "" {
+		var err error
+		pEvent.Event, err = unmarshalPayload(msg.EventType, msg.Event)
+		if err != nil {
+			return ParsedEvent{}, err
+		}
+	}
+
+	return pEvent, nil
+}
+
+func decodeParsedEventGob(data []byte) (ParsedEvent, error) {
+	var c ParsedEvent
 
 	reader := bytes.NewReader(data)
 	dec := gob.NewDecoder(reader)
 
-	if err = dec.Decode(&c); err != nil {
-		return nil, err
+	if err := dec.Decode(&c); err != nil {
+		return ParsedEvent{}, err
 	}
 
 	return c, nil
@@ -70,8 +116,7 @@ func (pc ParsedEventCodec) Decode(data []byte) (b interface{}, e error) {
 
 func NewParsedEventFromIndexedEvent(event IndexedEvent) ParsedEvent {
 	p := ParsedEvent{}
-	p.EventIndex.Height = event.Height
-	p.EventIndex.Offset = event.Offset
+	p.EventIndex = event.index()
 	p.Type = event.Event.Type
 	p.Event = event.Event
 	p.BlockTimestamp = event.BlockTimestamp
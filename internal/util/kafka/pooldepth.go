@@ -0,0 +1,59 @@
+package kafka
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// PoolDepth is the state of a pool at the end of a block which changed it, with the depth
+// changes of the block by reason. Written by the Midgard Kafka sink, it matches the
+// block_pool_depths and pool_depth_changes rows.
+type PoolDepth struct {
+	Height         int64            `json:"height"`
+	BlockTimestamp time.Time        `json:"blockTimestamp"`
+	Pool           string           `json:"pool"`
+	AssetE8        int64            `json:"assetE8,string"`
+	RuneE8         int64            `json:"runeE8,string"`
+	SynthE8        int64            `json:"synthE8,string"`
+	Units          int64            `json:"units,string"`
+	Changes        []PoolDepthDelta `json:"changes"`
+}
+
+// PoolDepthDelta is the sum of the depth changes of a pool for one reason (e.g. "swap").
+type PoolDepthDelta struct {
+	Reason  string `json:"reason"`
+	AssetE8 int64  `json:"assetE8,string"`
+	RuneE8  int64  `json:"runeE8,string"`
+	SynthE8 int64  `json:"synthE8,string"`
+}
+
+// Key of the message, the pool, so the changes of a pool stay in order.
+func (d PoolDepth) Key() string {
+	return d.Pool
+}
+
+type PoolDepthCodec struct{}
+
+func (PoolDepthCodec) Encode(value interface{}) ([]byte, error) {
+	d, ok := value.(PoolDepth)
+	if !ok {
+		return nil, fmt.Errorf("codec requires value kafka.PoolDepth, got %T", value)
+	}
+	data, err := json.Marshal(d)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{V1}, data...), nil
+}
+
+func (PoolDepthCodec) Decode(data []byte) (interface{}, error) {
+	if len(data) == 0 || data[0] != V1 {
+		return nil, fmt.Errorf("unsupported pool depth schema version")
+	}
+	var d PoolDepth
+	if err := json.Unmarshal(data[1:], &d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
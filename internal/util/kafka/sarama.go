@@ -0,0 +1,27 @@
+package kafka
+
+import "github.com/Shopify/sarama"
+
+// Transactions need Kafka 0.11, the admin APIs used by the consumers need 2.1.
+var Version = sarama.V2_1_0_0
+
+// NewConfig returns a sarama config which reads committed messages only, so the messages of
+// aborted transactions are skipped.
+func NewConfig() *sarama.Config {
+	cfg := sarama.NewConfig()
+	cfg.Version = Version
+	cfg.Consumer.IsolationLevel = sarama.ReadCommitted
+	return cfg
+}
+
+// NewTransactionalConfig returns the config of an idempotent producer with transactions.
+// Only one producer may run with a transactional id, a new one fences off the old one.
+func NewTransactionalConfig(transactionalID string) *sarama.Config {
+	cfg := NewConfig()
+	cfg.Producer.Idempotent = true
+	cfg.Producer.RequiredAcks = sarama.WaitForAll
+	cfg.Producer.Return.Successes = true
+	cfg.Producer.Transaction.ID = transactionalID
+	cfg.Net.MaxOpenRequests = 1
+	return cfg
+}
@@ -0,0 +1,208 @@
+package kafka
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"gitlab.com/thorchain/midgard/internal/fetch/record"
+)
+
+// Messages start with a schema version byte.
+//
+// V0 is the Go gob encoding. It was only used for the indexed events, the parsed events were
+// written as gob without a version byte.
+//
+// V1 is a JSON document, see docs/kafka-schema.md. Byte slices are strings and the 64 bit
+// integers of the event payloads are decimal strings, so they don't lose precision in
+// languages with float64 numbers only.
+const (
+	V0 byte = 0
+	V1 byte = 1
+)
+
+// CurrentVersion is the schema version the codecs encode with.
+const CurrentVersion = V1
+
+// Tendermint event type by the Go type of the parsed event payload.
+var payloadTypes = map[reflect.Type]string{}
+
+func init() {
+	for _, eventType := range record.EventTypes() {
+		t := reflect.TypeOf(record.NewEvent(eventType)).Elem()
+		payloadTypes[t] = eventType
+		// Needed to decode the gob messages, the payloads are sent as values.
+		gob.Register(reflect.Zero(t).Interface())
+	}
+}
+
+// Returns the Tendermint event type of a parsed event payload, which is a record struct or a
+// pointer to one.
+func payloadType(event interface{}) (string, error) {
+	t := reflect.TypeOf(event)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	eventType, ok := payloadTypes[t]
+	if !ok {
+		return "", fmt.Errorf("event payload of unregistered type %T", event)
+	}
+	return eventType, nil
+}
+
+// EventType returns the Tendermint event type of a record struct or a pointer to one.
+func EventType(event interface{}) (string, error) {
+	return payloadType(event)
+}
+
+// Converts a record struct into a JSON object. Field names are in lowerCamelCase, embedded
+// structs are flattened.
+func marshalPayload(event interface{}) (json.RawMessage, error) {
+	v := reflect.Indirect(reflect.ValueOf(event))
+	return json.Marshal(jsonValue(v))
+}
+
+// Inverse of marshalPayload, returns the record struct for the Tendermint event type as a value.
+func unmarshalPayload(eventType string, data json.RawMessage) (interface{}, error) {
+	event := record.NewEvent(eventType)
+	if event == nil {
+		return nil, fmt.Errorf("unknown event type %q", eventType)
+	}
+	v := reflect.ValueOf(event).Elem()
+	if err := setJSONValue(v, data); err != nil {
+		return nil, fmt.Errorf("event type %q: %w", eventType, err)
+	}
+	return v.Interface(), nil
+}
+
+func jsonValue(v reflect.Value) interface{} {
+	switch v.Kind() {
+	case reflect.Struct:
+		obj := make(map[string]interface{})
+		addJSONFields(obj, v)
+		return obj
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return string(v.Bytes())
+		}
+		list := make([]interface{}, v.Len())
+		for i := range list {
+			list[i] = jsonValue(v.Index(i))
+		}
+		return list
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	default:
+		return v.Interface()
+	}
+}
+
+func addJSONFields(obj map[string]interface{}, v reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		switch {
+		case f.Anonymous && f.Type.Kind() == reflect.Struct:
+			addJSONFields(obj, v.Field(i))
+		case f.IsExported():
+			obj[fieldName(f.Name)] = jsonValue(v.Field(i))
+		}
+	}
+}
+
+func setJSONValue(v reflect.Value, data json.RawMessage) error {
+	if bytes.Equal(data, []byte("null")) {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		var obj map[string]json.RawMessage
+		if err := json.Unmarshal(data, &obj); err != nil {
+			return err
+		}
+		return setJSONFields(v, obj)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			var s string
+			if err := json.Unmarshal(data, &s); err != nil {
+				return err
+			}
+			v.SetBytes([]byte(s))
+			return nil
+		}
+		var list []json.RawMessage
+		if err := json.Unmarshal(data, &list); err != nil {
+			return err
+		}
+		slice := reflect.MakeSlice(v.Type(), len(list), len(list))
+		for i, item := range list {
+			if err := setJSONValue(slice.Index(i), item); err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		// Numbers are accepted too, not only the strings we write.
+		n, err := strconv.ParseInt(strings.Trim(string(data), `"`), 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+		return nil
+	default:
+		return json.Unmarshal(data, v.Addr().Interface())
+	}
+}
+
+// Unknown fields are ignored, so that fields can be added without breaking consumers.
+func setJSONFields(v reflect.Value, obj map[string]json.RawMessage) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		switch {
+		case f.Anonymous && f.Type.Kind() == reflect.Struct:
+			if err := setJSONFields(v.Field(i), obj); err != nil {
+				return err
+			}
+		case f.IsExported():
+			name := fieldName(f.Name)
+			data, ok := obj[name]
+			if !ok {
+				continue
+			}
+			if err := setJSONValue(v.Field(i), data); err != nil {
+				return fmt.Errorf("field %q: %w", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Converts a Go field name to lowerCamelCase, leading acronyms included: AssetE8 -> assetE8,
+// IPAddr -> ipAddr, THORName -> thorName.
+func fieldName(goName string) string {
+	runes := []rune(goName)
+	upper := 0
+	for upper < len(runes) && unicode.IsUpper(runes[upper]) {
+		upper++
+	}
+	if upper > 1 && upper < len(runes) && unicode.IsLower(runes[upper]) {
+		// The last capital starts the next word.
+		upper--
+	}
+	for i := 0; i < upper; i++ {
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	return string(runes)
+}
@@ -0,0 +1,92 @@
+package kafka
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Shopify/sarama"
+)
+
+// The last messages of a topic are read to resume after the last committed transaction.
+
+const (
+	// Messages read back from the end of every partition. Every transaction writes a commit
+	// marker after its messages, aborted transactions write more.
+	TailLookback = 16
+
+	// Wait for more messages before taking the last one read. The offsets at the end of a
+	// partition may be transaction markers or aborted messages, which are not delivered.
+	TailIdleTimeout = 2 * time.Second
+)
+
+// OffsetGetter is the offset lookup of sarama.Client, replaceable in tests.
+type OffsetGetter interface {
+	GetOffset(topic string, partitionID int32, time int64) (int64, error)
+}
+
+// ReadTails calls read with the last messages of every partition of the topic. The consumer has
+// to read committed messages only.
+func ReadTails(offsets OffsetGetter, consumer sarama.Consumer, topic string,
+	read func(*sarama.ConsumerMessage) error) error {
+	partitions, err := consumer.Partitions(topic)
+	if err != nil {
+		return err
+	}
+
+	for _, partition := range partitions {
+		newest, err := offsets.GetOffset(topic, partition, sarama.OffsetNewest)
+		if err != nil {
+			return err
+		}
+		oldest, err := offsets.GetOffset(topic, partition, sarama.OffsetOldest)
+		if err != nil {
+			return err
+		}
+		if newest <= oldest {
+			continue
+		}
+		start := newest - TailLookback
+		if start < oldest {
+			start = oldest
+		}
+
+		n, err := readPartition(consumer, topic, partition, start, newest, read)
+		if err != nil {
+			return err
+		}
+		// The messages read are transaction markers or aborted. If the whole partition was
+		// read it has no committed messages yet, otherwise they are further back.
+		if n == 0 && start != oldest {
+			return fmt.Errorf("no committed message in the last %d offsets of %s/%d",
+				TailLookback, topic, partition)
+		}
+	}
+	return nil
+}
+
+// Reads the partition from start up to newest, returns the number of messages read.
+func readPartition(consumer sarama.Consumer, topic string, partition int32, start, newest int64,
+	read func(*sarama.ConsumerMessage) error) (n int, err error) {
+	pc, err := consumer.ConsumePartition(topic, partition, start)
+	if err != nil {
+		return 0, err
+	}
+	defer pc.Close()
+
+	for {
+		select {
+		case msg := <-pc.Messages():
+			if err := read(msg); err != nil {
+				return n, err
+			}
+			n++
+			if newest <= msg.Offset+1 {
+				return n, nil
+			}
+		case err := <-pc.Errors():
+			return n, err
+		case <-time.After(TailIdleTimeout):
+			return n, nil
+		}
+	}
+}
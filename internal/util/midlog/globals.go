@@ -12,6 +12,9 @@ var GlobalLogger Logger
 var exitFunction func()
 var subloggers = map[string]*Logger{}
 
+// The loggers are derived from it, each with its own levelHook.
+var baseLogger zerolog.Logger
+
 func init() {
 	SetGlobalOutput(os.Stdout, false)
 }
@@ -23,31 +26,34 @@ func SetExitFunctionForTest(f func()) {
 }
 
 func SetFromConfig(config LogConfig) {
-	zerolog.SetGlobalLevel(zerolog.Level(config.Level))
+	SetLevel(zerolog.Level(config.Level))
 }
 
 // Not thread safe, call it durring global initialization or test initialization
 func SetGlobalOutput(w io.Writer, noColor bool) {
-	log.Logger = log.Output(
+	baseLogger = zerolog.New(
 		zerolog.ConsoleWriter{
 			Out:        w,
 			TimeFormat: "2006-01-02 15:04:05",
 			PartsOrder: []string{"level", "time", "caller", "message"},
 			NoColor:    noColor,
 		},
-	)
+	).With().Timestamp().Logger()
+	log.Logger = baseLogger.Hook(levelHook{})
 	GlobalLogger.zlog = log.Logger
 	refreshSubloggers()
 }
 
 func LoggerForModule(module string) *Logger {
 	l := newSublogger(module)
+	levelsMutex.Lock()
 	subloggers[module] = &l
+	levelsMutex.Unlock()
 	return &l
 }
 
 func newSublogger(module string) Logger {
-	return Logger{GlobalLogger.zlog.With().Str("module", module).Logger()}
+	return Logger{baseLogger.With().Str("module", module).Logger().Hook(levelHook{module})}
 }
 
 func refreshSubloggers() {
@@ -0,0 +1,95 @@
+package midlog
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+// Levels can be set per module at runtime. The zerolog global level is kept at the lowest of
+// them, levelHook drops the events below the level of their module.
+var (
+	levelsMutex  sync.RWMutex
+	defaultLevel = zerolog.DebugLevel
+	moduleLevels = map[string]zerolog.Level{}
+)
+
+type levelHook struct {
+	module string
+}
+
+func (h levelHook) Run(e *zerolog.Event, level zerolog.Level, msg string) {
+	if level < moduleLevel(h.module) {
+		e.Discard()
+	}
+}
+
+func moduleLevel(module string) zerolog.Level {
+	levelsMutex.RLock()
+	defer levelsMutex.RUnlock()
+	if level, ok := moduleLevels[module]; ok {
+		return level
+	}
+	return defaultLevel
+}
+
+// Call with levelsMutex locked.
+func applyLevels() {
+	lowest := defaultLevel
+	for _, level := range moduleLevels {
+		if level < lowest {
+			lowest = level
+		}
+	}
+	zerolog.SetGlobalLevel(lowest)
+}
+
+// Sets the level of the modules which have no level of their own.
+func SetLevel(level zerolog.Level) {
+	levelsMutex.Lock()
+	defer levelsMutex.Unlock()
+	defaultLevel = level
+	applyLevels()
+}
+
+func SetModuleLevel(module string, level zerolog.Level) {
+	levelsMutex.Lock()
+	defer levelsMutex.Unlock()
+	moduleLevels[module] = level
+	applyLevels()
+}
+
+// The module falls back to the default level.
+func ResetModuleLevel(module string) {
+	levelsMutex.Lock()
+	defer levelsMutex.Unlock()
+	delete(moduleLevels, module)
+	applyLevels()
+}
+
+// Returns the default level and the level of every module.
+func Levels() (zerolog.Level, map[string]zerolog.Level) {
+	levelsMutex.RLock()
+	defer levelsMutex.RUnlock()
+	ret := map[string]zerolog.Level{}
+	for module := range subloggers {
+		ret[module] = defaultLevel
+	}
+	for module, level := range moduleLevels {
+		ret[module] = level
+	}
+	return defaultLevel, ret
+}
+
+// Returns the modules which have a logger.
+func Modules() []string {
+	levelsMutex.RLock()
+	defer levelsMutex.RUnlock()
+	ret := make([]string, 0, len(subloggers))
+	for module := range subloggers {
+		ret = append(ret, module)
+	}
+	sort.Strings(ret)
+	return ret
+}
@@ -12,10 +12,6 @@ func LogCommandLine() {
 	fmt.Printf("Command: %s\n", strings.Join(os.Args, " "))
 }
 
-func SetLevel(level zerolog.Level) {
-	zerolog.SetGlobalLevel(level)
-}
-
 ///////////////////// Global utility functions
 
 func DebugT(t Tag, msg string) {
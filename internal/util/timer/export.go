@@ -0,0 +1,149 @@
+package timer
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/pascaldekloe/metrics"
+)
+
+// The /debug/metrics page of pascaldekloe/metrics names the histogram buckets without the _bucket
+// suffix and stamps every sample, which standard scrapers don't understand. The exporter below
+// rewrites it into the Prometheus text format or into OpenMetrics, keeping the metric names.
+
+const (
+	prometheusContentType  = "text/plain; version=0.0.4; charset=utf-8"
+	openMetricsContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+)
+
+// ServeMetrics serves all the metrics in OpenMetrics format if the scraper accepts it, in the
+// Prometheus text format otherwise.
+func ServeMetrics(w http.ResponseWriter, r *http.Request) {
+	openMetrics := strings.Contains(r.Header.Get("Accept"), "application/openmetrics-text")
+	if openMetrics {
+		w.Header().Set("Content-Type", openMetricsContentType)
+	} else {
+		w.Header().Set("Content-Type", prometheusContentType)
+	}
+
+	var buf bytes.Buffer
+	metrics.WriteText(&buf)
+	err := ExportText(w, &buf, openMetrics)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// ExportText converts the output of metrics.WriteText.
+//
+// For OpenMetrics the counter families are named without the _total suffix and the counter
+// samples always have it.
+func ExportText(w io.Writer, r io.Reader, openMetrics bool) error {
+	out := bufio.NewWriter(w)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var family, metricType string
+	// The _count of a histogram is written after its buckets.
+	var histogramCount string
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "" || (strings.HasPrefix(line, "# ") &&
+			!strings.HasPrefix(line, "# TYPE ") && !strings.HasPrefix(line, "# HELP ")):
+			continue
+
+		case strings.HasPrefix(line, "# TYPE "):
+			fields := strings.Fields(line)
+			if len(fields) != 4 {
+				return fmt.Errorf("malformed type line: %q", line)
+			}
+			family, metricType = fields[2], fields[3]
+			fmt.Fprintf(out, "# TYPE %s %s\n", familyName(family, metricType, openMetrics), metricType)
+
+		case strings.HasPrefix(line, "# HELP "):
+			help := strings.TrimPrefix(line, "# HELP "+family+" ")
+			if openMetrics {
+				help = strings.ReplaceAll(help, `"`, `\"`)
+			}
+			fmt.Fprintf(out, "# HELP %s %s\n", familyName(family, metricType, openMetrics), help)
+
+		default:
+			name, labels, value, err := parseSample(line)
+			if err != nil {
+				return err
+			}
+			switch metricType {
+			case "histogram":
+				switch name {
+				case family:
+					name += "_bucket"
+				case family + "_count":
+					histogramCount = name + labels + " " + value + "\n"
+					continue
+				case family + "_sum":
+					out.WriteString(histogramCount)
+					histogramCount = ""
+				}
+			case "counter":
+				if openMetrics && !strings.HasSuffix(name, "_total") {
+					name += "_total"
+				}
+			}
+			fmt.Fprintf(out, "%s%s %s\n", name, labels, value)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	if openMetrics {
+		out.WriteString("# EOF\n")
+	}
+	return out.Flush()
+}
+
+func familyName(name, metricType string, openMetrics bool) string {
+	if openMetrics && metricType == "counter" {
+		return strings.TrimSuffix(name, "_total")
+	}
+	return name
+}
+
+// Splits `name{labels} value [timestamp]`, the timestamp is dropped.
+func parseSample(line string) (name, labels, value string, err error) {
+	rest := line
+	end := strings.IndexAny(rest, "{ ")
+	if end <= 0 {
+		return "", "", "", fmt.Errorf("malformed sample: %q", line)
+	}
+	name, rest = rest[:end], rest[end:]
+
+	if rest[0] == '{' {
+		quoted := false
+		end = -1
+		for i := 1; i < len(rest) && end < 0; i++ {
+			switch {
+			case quoted && rest[i] == '\\':
+				i++
+			case rest[i] == '"':
+				quoted = !quoted
+			case !quoted && rest[i] == '}':
+				end = i
+			}
+		}
+		if end < 0 {
+			return "", "", "", fmt.Errorf("malformed labels: %q", line)
+		}
+		labels, rest = rest[:end+1], rest[end+1:]
+	}
+
+	fields := strings.Fields(rest)
+	if len(fields) == 0 {
+		return "", "", "", fmt.Errorf("sample without value: %q", line)
+	}
+	return name, labels, fields[0], nil
+}
@@ -0,0 +1,71 @@
+package timer_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gitlab.com/thorchain/midgard/internal/util/timer"
+)
+
+const sampleText = `# Prometheus Samples
+
+# TYPE requests_total counter
+# HELP requests_total Number of "requests".
+requests_total{route="/v2/pool/:pool"} 3 1792344055917
+
+# TYPE failures counter
+failures 1 1792344055917
+
+# TYPE latency_seconds histogram
+latency_seconds_count{code="200",route="/v2/x {y}"} 1 1792344055917
+latency_seconds{le="0.1",code="200",route="/v2/x {y}"} 0 1792344055917
+latency_seconds{le="+Inf",code="200",route="/v2/x {y}"} 1 1792344055917
+latency_seconds_sum{code="200",route="/v2/x {y}"} 0.5 1792344055917
+
+# TYPE height gauge
+# HELP height Last block.
+height 4 1792344055917
+`
+
+func export(t *testing.T, openMetrics bool) string {
+	var buf bytes.Buffer
+	require.NoError(t, timer.ExportText(&buf, strings.NewReader(sampleText), openMetrics))
+	return buf.String()
+}
+
+func TestExportPrometheus(t *testing.T) {
+	require.Equal(t, `# TYPE requests_total counter
+# HELP requests_total Number of "requests".
+requests_total{route="/v2/pool/:pool"} 3
+# TYPE failures counter
+failures 1
+# TYPE latency_seconds histogram
+latency_seconds_bucket{le="0.1",code="200",route="/v2/x {y}"} 0
+latency_seconds_bucket{le="+Inf",code="200",route="/v2/x {y}"} 1
+latency_seconds_count{code="200",route="/v2/x {y}"} 1
+latency_seconds_sum{code="200",route="/v2/x {y}"} 0.5
+# TYPE height gauge
+# HELP height Last block.
+height 4
+`, export(t, false))
+}
+
+func TestExportOpenMetrics(t *testing.T) {
+	require.Equal(t, `# TYPE requests counter
+# HELP requests Number of \"requests\".
+requests_total{route="/v2/pool/:pool"} 3
+# TYPE failures counter
+failures_total 1
+# TYPE latency_seconds histogram
+latency_seconds_bucket{le="0.1",code="200",route="/v2/x {y}"} 0
+latency_seconds_bucket{le="+Inf",code="200",route="/v2/x {y}"} 1
+latency_seconds_count{code="200",route="/v2/x {y}"} 1
+latency_seconds_sum{code="200",route="/v2/x {y}"} 0.5
+# TYPE height gauge
+# HELP height Last block.
+height 4
+# EOF
+`, export(t, true))
+}
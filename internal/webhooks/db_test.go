@@ -0,0 +1,104 @@
+package webhooks_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gitlab.com/thorchain/midgard/config"
+	"gitlab.com/thorchain/midgard/internal/db/testdb"
+	"gitlab.com/thorchain/midgard/internal/webhooks"
+)
+
+// The webhook tables are created by the core migrations.
+func clearWebhookTables(t *testing.T) {
+	testdb.MustExec(t, "DELETE FROM webhooks")
+	testdb.MustExec(t, "DELETE FROM webhook_cursor")
+	testdb.MustExec(t, "DELETE FROM webhook_deliveries")
+	testdb.MustExec(t, "DELETE FROM webhook_dead_letters")
+}
+
+func TestRegisterListRemove(t *testing.T) {
+	testdb.InitTest(t)
+	clearWebhookTables(t)
+	ctx := context.Background()
+
+	hook := config.Webhook{
+		ID:     "swaps",
+		URL:    "https://example.com/hook",
+		Secret: "secret",
+		Filter: config.WebhookFilter{
+			Events: []string{webhooks.EventAction}, ActionTypes: []string{"swap"}},
+	}
+	require.Nil(t, webhooks.Register(ctx, hook))
+
+	hooks, err := webhooks.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, hooks, 1)
+	require.Equal(t, "swaps", hooks[0].ID)
+	require.Equal(t, "admin", hooks[0].Source)
+	require.Equal(t, []string{"swap"}, hooks[0].Filter.ActionTypes)
+
+	removed, merr := webhooks.Remove(ctx, "swaps")
+	require.Nil(t, merr)
+	require.True(t, removed)
+	removed, merr = webhooks.Remove(ctx, "swaps")
+	require.Nil(t, merr)
+	require.False(t, removed)
+}
+
+func TestDeliverAndDeadLetters(t *testing.T) {
+	testdb.InitTest(t)
+	clearWebhookTables(t)
+	ctx := context.Background()
+
+	var fail int32
+	var received int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&fail) != 0 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		atomic.AddInt64(&received, 1)
+	}))
+	defer server.Close()
+
+	defer func(c config.Webhooks) { config.Global.Webhooks = c }(config.Global.Webhooks)
+	config.Global.Webhooks.MaxAttempts = 1
+
+	hook := config.Webhook{ID: "all", URL: server.URL}
+	require.Nil(t, webhooks.Register(ctx, hook))
+	require.NoError(t, webhooks.QueueForTest(ctx, 1,
+		&webhooks.Payload{WebhookID: "all", Height: "1"}, 1))
+	require.NoError(t, webhooks.QueueForTest(ctx, 2,
+		&webhooks.Payload{WebhookID: "all", Height: "2"}, 2))
+	cursor, err := webhooks.CursorForTest(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), int64(cursor))
+
+	// The head fails, the next one is not sent before it.
+	atomic.StoreInt32(&fail, 1)
+	require.NoError(t, webhooks.DeliverForTest(ctx, server.Client()))
+	letters, err := webhooks.DeadLetters(ctx, "all", 10)
+	require.NoError(t, err)
+	require.Len(t, letters, 1)
+	require.Equal(t, int64(1), letters[0].Height)
+	require.Equal(t, int64(0), atomic.LoadInt64(&received))
+
+	atomic.StoreInt32(&fail, 0)
+	require.NoError(t, webhooks.DeliverForTest(ctx, server.Client()))
+	require.Equal(t, int64(1), atomic.LoadInt64(&received))
+
+	n, err := webhooks.RetryDeadLetters(ctx, "all")
+	require.NoError(t, err)
+	require.Equal(t, int64(1), n)
+	require.NoError(t, webhooks.DeliverForTest(ctx, server.Client()))
+	require.Equal(t, int64(2), atomic.LoadInt64(&received))
+
+	pending, err := webhooks.PendingForTest(ctx)
+	require.NoError(t, err)
+	require.Empty(t, pending)
+}
@@ -0,0 +1,384 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pascaldekloe/metrics"
+	"gitlab.com/thorchain/midgard/config"
+	"gitlab.com/thorchain/midgard/internal/db"
+)
+
+// How often the due retries are checked when there are no new blocks.
+const deliveryPollInterval = time.Second
+
+var (
+	queuedPayloads = metrics.MustCounter("midgard_webhook_queued_payloads_total",
+		"Number of webhook payloads queued for delivery.")
+	pendingPayloads = metrics.MustInteger("midgard_webhook_pending_payloads",
+		"Webhook payloads which are not delivered yet.")
+	deliveries       = metrics.Must1LabelCounter("midgard_webhook_deliveries_total", "webhook")
+	deliveryFailures = metrics.Must1LabelCounter("midgard_webhook_delivery_failures_total", "webhook")
+	deadLetters      = metrics.Must1LabelCounter("midgard_webhook_dead_letters_total", "webhook")
+	deliveryDuration = metrics.Must1LabelHistogram(
+		"midgard_webhook_delivery_seconds", "webhook", 0.01, 0.1, 1, 10)
+)
+
+func init() {
+	metrics.MustHelp("midgard_webhook_deliveries_total", "Number of payloads delivered to the webhook.")
+	metrics.MustHelp("midgard_webhook_delivery_failures_total",
+		"Number of failed delivery attempts to the webhook.")
+	metrics.MustHelp("midgard_webhook_dead_letters_total",
+		"Number of payloads moved to the dead letters after max_attempts failures.")
+	metrics.MustHelp("midgard_webhook_delivery_seconds", "Time of the delivery attempts to the webhook.")
+}
+
+type delivery struct {
+	id        int64
+	webhookID string
+	payload   []byte
+	attempts  int
+}
+
+func run(ctx context.Context) error {
+	client := &http.Client{Timeout: config.Global.Webhooks.Timeout.Value()}
+	ticker := time.NewTicker(deliveryPollInterval)
+	defer ticker.Stop()
+
+	for {
+		newBlocks := false
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-*db.WebhooksNotify:
+			newBlocks = true
+		case <-ticker.C:
+		}
+
+		hooks, err := loadHooks(ctx)
+		if err != nil {
+			return stopped(ctx, err)
+		}
+		if newBlocks {
+			if err := queueNewBlocks(ctx, hooks); err != nil {
+				return stopped(ctx, err)
+			}
+		}
+		if err := deliverDue(ctx, client, hooks); err != nil {
+			return stopped(ctx, err)
+		}
+	}
+}
+
+// Errors caused by the shutdown are not reported.
+func stopped(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
+
+// Sign returns the hex HMAC-SHA256 of "<timestamp>.<body>", sent in the X-Midgard-Signature
+// header.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Returns the wait after the given number of failed attempts.
+func backoff(attempts int, min, max time.Duration) time.Duration {
+	d := min
+	for i := 1; i < attempts && d < max; i++ {
+		d *= 2
+	}
+	if max < d {
+		d = max
+	}
+	return d
+}
+
+// Delivers the due payloads, every webhook in its own goroutine.
+func deliverDue(ctx context.Context, client *http.Client, hooks []config.Webhook) error {
+	byID := map[string]config.Webhook{}
+	for _, hook := range hooks {
+		byID[hook.ID] = hook
+	}
+	heads, err := dueHeads(ctx)
+	if err != nil {
+		return fmt.Errorf("reading webhook deliveries: %w", err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, len(heads))
+	for _, head := range heads {
+		hook, ok := byID[head.webhookID]
+		if !ok {
+			// The webhook was removed from the config.
+			logger.WarnF("Dropping the queued payloads of the unknown webhook %s", head.webhookID)
+			_, err := db.TheDB.ExecContext(ctx,
+				"DELETE FROM webhook_deliveries WHERE webhook_id = $1", head.webhookID)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		wg.Add(1)
+		go func(head delivery) {
+			defer wg.Done()
+			errs <- deliverHook(ctx, client, hook, head)
+		}(head)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	counts, err := pendingCounts(ctx)
+	if err != nil {
+		return err
+	}
+	var pending int64
+	for _, n := range counts {
+		pending += n
+	}
+	pendingPayloads.Set(pending)
+	return nil
+}
+
+// Delivers the payloads of the hook in order until one fails or the queue is empty.
+// Returns only DB errors.
+func deliverHook(ctx context.Context, client *http.Client, hook config.Webhook, d delivery) error {
+	for ctx.Err() == nil {
+		start := time.Now()
+		err := post(ctx, client, hook, d)
+		deliveryDuration(hook.ID).AddSince(start)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			deliveryFailures(hook.ID).Add(1)
+			return failed(ctx, hook, d, err)
+		}
+		deliveries(hook.ID).Add(1)
+		_, err = db.TheDB.ExecContext(ctx, "DELETE FROM webhook_deliveries WHERE id = $1", d.id)
+		if err != nil {
+			return err
+		}
+
+		var due, found bool
+		d, due, found, err = nextDelivery(ctx, hook.ID)
+		if err != nil || !found || !due {
+			return err
+		}
+	}
+	return nil
+}
+
+func post(ctx context.Context, client *http.Client, hook config.Webhook, d delivery) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(d.payload))
+	if err != nil {
+		return err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Midgard-Webhooks")
+	req.Header.Set("X-Midgard-Webhook", hook.ID)
+	req.Header.Set("X-Midgard-Delivery", strconv.FormatInt(d.id, 10))
+	req.Header.Set("X-Midgard-Timestamp", timestamp)
+	if hook.Secret != "" {
+		req.Header.Set("X-Midgard-Signature", "sha256="+Sign(hook.Secret, timestamp, d.payload))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// Read some of the body, so the connection can be reused.
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode < 200 || 300 <= resp.StatusCode {
+		return fmt.Errorf("status %s", resp.Status)
+	}
+	return nil
+}
+
+// Schedules the retry or moves the payload to the dead letters.
+func failed(ctx context.Context, hook config.Webhook, d delivery, deliveryErr error) error {
+	c := config.Global.Webhooks
+	attempts := d.attempts + 1
+	if attempts < c.MaxAttempts {
+		wait := backoff(attempts, c.MinBackoff.Value(), c.MaxBackoff.Value())
+		logger.DebugF("Delivery %d to webhook %s failed (%v), retry in %s",
+			d.id, hook.ID, deliveryErr, wait)
+		_, err := db.TheDB.ExecContext(ctx, `
+			UPDATE webhook_deliveries
+			SET attempts = $2, next_attempt = now() + make_interval(secs => $3), last_error = $4
+			WHERE id = $1`,
+			d.id, attempts, wait.Seconds(), deliveryErr.Error())
+		return err
+	}
+
+	logger.WarnF("Delivery %d to webhook %s failed %d times, moving it to the dead letters: %v",
+		d.id, hook.ID, attempts, deliveryErr)
+	tx, err := db.TheDB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO webhook_dead_letters (id, webhook_id, url, height, payload, attempts, last_error)
+		SELECT id, webhook_id, $2, height, payload, $3, $4 FROM webhook_deliveries WHERE id = $1`,
+		d.id, hook.URL, attempts, deliveryErr.Error())
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, "DELETE FROM webhook_deliveries WHERE id = $1", d.id)
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	deadLetters(hook.ID).Add(1)
+	return nil
+}
+
+// Returns the first queued payload of every webhook if it is due.
+func dueHeads(ctx context.Context) ([]delivery, error) {
+	rows, err := db.Query(ctx, `
+		SELECT id, webhook_id, payload, attempts FROM (
+			SELECT DISTINCT ON (webhook_id) id, webhook_id, payload, attempts, next_attempt
+			FROM webhook_deliveries
+			ORDER BY webhook_id, id) heads
+		WHERE next_attempt <= now()`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ret []delivery
+	for rows.Next() {
+		var d delivery
+		if err := rows.Scan(&d.id, &d.webhookID, &d.payload, &d.attempts); err != nil {
+			return nil, err
+		}
+		ret = append(ret, d)
+	}
+	return ret, rows.Err()
+}
+
+func nextDelivery(ctx context.Context, webhookID string) (d delivery, due, found bool, err error) {
+	rows, err := db.Query(ctx, `
+		SELECT id, webhook_id, payload, attempts, next_attempt <= now()
+		FROM webhook_deliveries
+		WHERE webhook_id = $1
+		ORDER BY id
+		LIMIT 1`, webhookID)
+	if err != nil {
+		return delivery{}, false, false, err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return delivery{}, false, false, rows.Err()
+	}
+	err = rows.Scan(&d.id, &d.webhookID, &d.payload, &d.attempts, &due)
+	return d, due, err == nil, err
+}
+
+func pendingCounts(ctx context.Context) (map[string]int64, error) {
+	rows, err := db.Query(ctx,
+		"SELECT webhook_id, COUNT(*) FROM webhook_deliveries GROUP BY webhook_id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ret := map[string]int64{}
+	for rows.Next() {
+		var id string
+		var n int64
+		if err := rows.Scan(&id, &n); err != nil {
+			return nil, err
+		}
+		ret[id] = n
+	}
+	return ret, rows.Err()
+}
+
+// DeadLetter is a payload which failed max_attempts times, as shown by the admin API.
+type DeadLetter struct {
+	ID        int64     `json:"id"`
+	WebhookID string    `json:"webhookId"`
+	URL       string    `json:"url"`
+	Height    int64     `json:"height"`
+	Attempts  int       `json:"attempts"`
+	LastError string    `json:"lastError"`
+	FailedAt  time.Time `json:"failedAt"`
+}
+
+// DeadLetters returns the latest dead letters, of one webhook if webhookID is not empty.
+func DeadLetters(ctx context.Context, webhookID string, limit int) ([]DeadLetter, error) {
+	rows, err := db.Query(ctx, `
+		SELECT id, webhook_id, url, height, attempts, COALESCE(last_error, ''), failed_at
+		FROM webhook_dead_letters
+		WHERE $1 = '' OR webhook_id = $1
+		ORDER BY id DESC
+		LIMIT $2`, webhookID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ret := []DeadLetter{}
+	for rows.Next() {
+		var d DeadLetter
+		err := rows.Scan(&d.ID, &d.WebhookID, &d.URL, &d.Height, &d.Attempts, &d.LastError, &d.FailedAt)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, d)
+	}
+	return ret, rows.Err()
+}
+
+// RetryDeadLetters queues the dead letters of the webhook again, after its pending payloads.
+// Returns the number of payloads queued.
+func RetryDeadLetters(ctx context.Context, webhookID string) (int64, error) {
+	tx, err := db.TheDB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	res, err := tx.ExecContext(ctx, `
+		INSERT INTO webhook_deliveries (webhook_id, height, payload)
+		SELECT webhook_id, height, payload FROM webhook_dead_letters
+		WHERE webhook_id = $1
+		ORDER BY id`, webhookID)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	_, err = tx.ExecContext(ctx, "DELETE FROM webhook_dead_letters WHERE webhook_id = $1", webhookID)
+	if err != nil {
+		return 0, err
+	}
+	return n, tx.Commit()
+}
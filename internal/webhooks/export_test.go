@@ -0,0 +1,32 @@
+package webhooks
+
+import (
+	"context"
+	"net/http"
+
+	"gitlab.com/thorchain/midgard/internal/db"
+)
+
+// Access for the DB tests in package webhooks_test, which can't be in this package because
+// testdb imports the api.
+
+func QueueForTest(ctx context.Context, height int64, p *Payload, cursor db.Nano) error {
+	return queueStep(ctx, []queued{{height: height, payload: p}}, cursor)
+}
+
+func CursorForTest(ctx context.Context) (db.Nano, error) {
+	cursor, _, err := readCursor(ctx)
+	return cursor, err
+}
+
+func DeliverForTest(ctx context.Context, client *http.Client) error {
+	hooks, err := loadHooks(ctx)
+	if err != nil {
+		return err
+	}
+	return deliverDue(ctx, client, hooks)
+}
+
+func PendingForTest(ctx context.Context) (map[string]int64, error) {
+	return pendingCounts(ctx)
+}
@@ -0,0 +1,277 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"gitlab.com/thorchain/midgard/config"
+	"gitlab.com/thorchain/midgard/internal/db"
+	"gitlab.com/thorchain/midgard/internal/fetch/record"
+	"gitlab.com/thorchain/midgard/internal/timeseries"
+	"gitlab.com/thorchain/midgard/openapi/generated/oapigen"
+)
+
+// Blocks are queued in steps of at most a day, after a long catch up too.
+const maxQueueStep = db.Nano(24 * 60 * 60 * 1e9)
+
+// Payload is POSTed to a webhook for every block with events matching its filter.
+type Payload struct {
+	WebhookID string `json:"webhookId"`
+	// Int64, height of the block.
+	Height string `json:"height"`
+	// Int64, nano timestamp of the block.
+	Date              string             `json:"date"`
+	Actions           []oapigen.Action   `json:"actions"`
+	PoolStatusChanges []PoolStatusChange `json:"poolStatusChanges"`
+}
+
+type PoolStatusChange struct {
+	Pool string `json:"pool"`
+	// Lowercase, e.g. available, staged, suspended.
+	Status string `json:"status"`
+}
+
+type queued struct {
+	height  int64
+	payload *Payload
+}
+
+// Events of one block.
+type blockEvents struct {
+	height            int64
+	timestamp         db.Nano
+	actions           []timeseries.ActionWithAddresses
+	poolStatusChanges []timeseries.PoolStatusChange
+}
+
+// Returns the value of an amount of the asset in RUNE.
+type runeValueFunc func(asset string, amountE8 int64) int64
+
+func latestRuneValue(asset string, amountE8 int64) int64 {
+	if record.IsRune([]byte(asset)) {
+		return amountE8
+	}
+	pool := string(record.GetNativeAsset([]byte(asset)))
+	depths, ok := timeseries.Latest.GetState().Pools[pool]
+	if !ok {
+		return 0
+	}
+	return int64(float64(amountE8) * depths.AssetPrice())
+}
+
+func contains(list []string, s string) bool {
+	for _, x := range list {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
+
+// True if the lists have a common element or filter is empty.
+func matchesAny(filter []string, values []string) bool {
+	if len(filter) == 0 {
+		return true
+	}
+	for _, v := range values {
+		if contains(filter, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesAction(f config.WebhookFilter, a timeseries.ActionWithAddresses, runeValue runeValueFunc) bool {
+	if len(f.Events) != 0 && !contains(f.Events, EventAction) {
+		return false
+	}
+	actionType := string(a.Action.Type)
+	if len(f.ActionTypes) != 0 && !contains(f.ActionTypes, actionType) {
+		return false
+	}
+	if !matchesAny(f.Pools, a.Action.Pools) || !matchesAny(f.Addresses, a.Addresses) {
+		return false
+	}
+	if actionType == "swap" && 0 < f.MinSwapRuneE8 {
+		var value int64
+		for _, tx := range a.Action.In {
+			for _, coin := range tx.Coins {
+				amount, err := strconv.ParseInt(coin.Amount, 10, 64)
+				if err != nil {
+					continue
+				}
+				value += runeValue(coin.Asset, amount)
+			}
+		}
+		if value < f.MinSwapRuneE8 {
+			return false
+		}
+	}
+	return true
+}
+
+// Addresses and action types don't apply to pool status changes.
+func matchesPoolStatus(f config.WebhookFilter, c timeseries.PoolStatusChange) bool {
+	if len(f.Events) != 0 && !contains(f.Events, EventPoolStatus) {
+		return false
+	}
+	return len(f.Pools) == 0 || contains(f.Pools, c.Pool)
+}
+
+// Returns the payload of the block for the hook, nil if nothing matches.
+func hookPayload(hook config.Webhook, block blockEvents, runeValue runeValueFunc) *Payload {
+	p := Payload{
+		WebhookID:         hook.ID,
+		Height:            strconv.FormatInt(block.height, 10),
+		Date:              strconv.FormatInt(int64(block.timestamp), 10),
+		Actions:           []oapigen.Action{},
+		PoolStatusChanges: []PoolStatusChange{},
+	}
+	for _, a := range block.actions {
+		if matchesAction(hook.Filter, a, runeValue) {
+			p.Actions = append(p.Actions, a.Action)
+		}
+	}
+	for _, c := range block.poolStatusChanges {
+		if matchesPoolStatus(hook.Filter, c) {
+			p.PoolStatusChanges = append(p.PoolStatusChanges,
+				PoolStatusChange{Pool: c.Pool, Status: c.Status})
+		}
+	}
+	if len(p.Actions) == 0 && len(p.PoolStatusChanges) == 0 {
+		return nil
+	}
+	return &p
+}
+
+// Groups the events by block, oldest first.
+func groupByBlock(actions []timeseries.ActionWithAddresses,
+	changes []timeseries.PoolStatusChange) ([]blockEvents, error) {
+	var ret []blockEvents
+	indexes := map[db.Nano]int{}
+	block := func(height int64, timestamp db.Nano) *blockEvents {
+		i, ok := indexes[timestamp]
+		if !ok {
+			i = len(ret)
+			indexes[timestamp] = i
+			ret = append(ret, blockEvents{height: height, timestamp: timestamp})
+		}
+		return &ret[i]
+	}
+
+	for _, a := range actions {
+		height, err := strconv.ParseInt(a.Action.Height, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("action height %q: %w", a.Action.Height, err)
+		}
+		timestamp, err := strconv.ParseInt(a.Action.Date, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("action date %q: %w", a.Action.Date, err)
+		}
+		b := block(height, db.Nano(timestamp))
+		b.actions = append(b.actions, a)
+	}
+	for _, c := range changes {
+		b := block(c.Height, c.Timestamp)
+		b.poolStatusChanges = append(b.poolStatusChanges, c)
+	}
+
+	// Both lists are sorted, but a block may only have pool status changes.
+	sort.SliceStable(ret, func(i, j int) bool { return ret[i].timestamp < ret[j].timestamp })
+	return ret, nil
+}
+
+func readCursor(ctx context.Context) (cursor db.Nano, found bool, err error) {
+	rows, err := db.Query(ctx, "SELECT block_timestamp FROM webhook_cursor")
+	if err != nil {
+		return 0, false, err
+	}
+	defer rows.Close()
+	if rows.Next() {
+		err = rows.Scan(&cursor)
+		return cursor, true, err
+	}
+	return 0, false, rows.Err()
+}
+
+// Queues the payloads of the aggregated blocks after the cursor.
+func queueNewBlocks(ctx context.Context, hooks []config.Webhook) error {
+	aggregated := db.LastAggregatedBlock.Get().Timestamp
+	cursor, found, err := readCursor(ctx)
+	if err != nil {
+		return fmt.Errorf("reading webhook cursor: %w", err)
+	}
+	if !found {
+		// Past blocks are not sent when webhooks are enabled the first time.
+		logger.InfoF("Webhooks start after height %d", db.LastAggregatedBlock.Get().Height)
+		return queueStep(ctx, nil, aggregated)
+	}
+
+	for cursor < aggregated && ctx.Err() == nil {
+		to := aggregated
+		if cursor+maxQueueStep < to {
+			to = cursor + maxQueueStep
+		}
+		actions, err := timeseries.ActionsBetween(ctx, cursor, to)
+		if err != nil {
+			return err
+		}
+		changes, err := timeseries.PoolStatusChangesBetween(ctx, cursor, to)
+		if err != nil {
+			return fmt.Errorf("pool status changes query: %w", err)
+		}
+		blocks, err := groupByBlock(actions, changes)
+		if err != nil {
+			return err
+		}
+
+		var payloads []queued
+		for _, block := range blocks {
+			for _, hook := range hooks {
+				if p := hookPayload(hook, block, latestRuneValue); p != nil {
+					payloads = append(payloads, queued{height: block.height, payload: p})
+				}
+			}
+		}
+		if err := queueStep(ctx, payloads, to); err != nil {
+			return err
+		}
+		cursor = to
+	}
+	return nil
+}
+
+// Inserts the payloads and moves the cursor in one transaction.
+func queueStep(ctx context.Context, payloads []queued, cursor db.Nano) error {
+	tx, err := db.TheDB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, q := range payloads {
+		body, err := json.Marshal(q.payload)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx,
+			"INSERT INTO webhook_deliveries (webhook_id, height, payload) VALUES ($1, $2, $3)",
+			q.payload.WebhookID, q.height, body)
+		if err != nil {
+			return fmt.Errorf("queueing webhook payload: %w", err)
+		}
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO webhook_cursor (block_timestamp) VALUES ($1)
+		ON CONFLICT (id) DO UPDATE SET block_timestamp = $1`, cursor)
+	if err != nil {
+		return fmt.Errorf("moving webhook cursor: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	queuedPayloads.Add(uint64(len(payloads)))
+	return nil
+}
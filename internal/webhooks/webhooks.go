@@ -0,0 +1,239 @@
+// Package webhooks POSTs the actions and pool status changes of the new blocks to the
+// registered webhooks.
+//
+// When the aggregates caught up with the new blocks (where the websockets are notified too) the
+// events after the cursor are matched with the filters of the webhooks, and one payload per
+// block and webhook is queued in the webhook_deliveries table, in the transaction which moves the
+// cursor. The payloads of a webhook are delivered in order, a failed delivery is retried with
+// exponential backoff and blocks the later ones of the webhook. After max_attempts it's moved to
+// the webhook_dead_letters table.
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+
+	"gitlab.com/thorchain/midgard/config"
+	"gitlab.com/thorchain/midgard/internal/db"
+	"gitlab.com/thorchain/midgard/internal/util/jobs"
+	"gitlab.com/thorchain/midgard/internal/util/miderr"
+	"gitlab.com/thorchain/midgard/internal/util/midlog"
+)
+
+const (
+	EventAction     = "action"
+	EventPoolStatus = "pool_status"
+)
+
+var actionTypes = map[string]bool{
+	"swap":         true,
+	"addLiquidity": true,
+	"withdraw":     true,
+	"donate":       true,
+	"refund":       true,
+	"switch":       true,
+}
+
+// Ids are used as metric labels.
+var idRegex = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+var logger = midlog.LoggerForModule("webhooks")
+
+func Enabled() bool {
+	return config.Global.Webhooks.Enabled
+}
+
+// Setup checks the config and creates the channel notified after the aggregates refresh.
+// Called once at startup, also by the standby instances.
+func Setup() error {
+	if !Enabled() {
+		return nil
+	}
+	c := config.Global.Webhooks
+	if c.MaxAttempts < 1 {
+		return fmt.Errorf("webhooks max_attempts has to be at least 1")
+	}
+	if c.MinBackoff <= 0 || c.MaxBackoff < c.MinBackoff {
+		return fmt.Errorf("webhooks min_backoff has to be positive and at most max_backoff")
+	}
+	ids := map[string]bool{}
+	for _, hook := range c.Hooks {
+		if err := Validate(hook); err != nil {
+			return err
+		}
+		if ids[hook.ID] {
+			return fmt.Errorf("duplicate webhook id %q", hook.ID)
+		}
+		ids[hook.ID] = true
+	}
+	db.CreateWebhooksChannel()
+	return nil
+}
+
+// Init returns the job queueing and delivering the payloads. It runs with the block writer.
+func Init(ctx context.Context) jobs.NamedFunction {
+	if !Enabled() {
+		return jobs.EmptyJob()
+	}
+	// Queued payloads are kept in the DB, nothing is lost when the job is restarted.
+	return jobs.Supervised(ctx, "Webhooks", jobs.RestartOnFailure, func() error {
+		return run(ctx)
+	})
+}
+
+// Hook is a webhook as shown by the admin API, without the secret.
+type Hook struct {
+	ID     string               `json:"id"`
+	URL    string               `json:"url"`
+	Filter config.WebhookFilter `json:"filter"`
+	// "config" or "admin".
+	Source string `json:"source"`
+	// Queued payloads, including the one being retried.
+	Pending int64 `json:"pending"`
+}
+
+// Validate checks a webhook from the config or the admin API.
+func Validate(hook config.Webhook) error {
+	if !idRegex.MatchString(hook.ID) {
+		return fmt.Errorf("invalid webhook id %q, use letters, digits, _ and -", hook.ID)
+	}
+	u, err := url.Parse(hook.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid webhook url %q", hook.URL)
+	}
+	for _, e := range hook.Filter.Events {
+		if e != EventAction && e != EventPoolStatus {
+			return fmt.Errorf("unknown webhook event %q, use %s or %s", e, EventAction, EventPoolStatus)
+		}
+	}
+	for _, t := range hook.Filter.ActionTypes {
+		if !actionTypes[t] {
+			return fmt.Errorf("unknown action type %q", t)
+		}
+	}
+	if hook.Filter.MinSwapRuneE8 < 0 {
+		return fmt.Errorf("negative min_swap_rune_e8")
+	}
+	return nil
+}
+
+func configHook(id string) (config.Webhook, bool) {
+	for _, hook := range config.Global.Webhooks.Hooks {
+		if hook.ID == id {
+			return hook, true
+		}
+	}
+	return config.Webhook{}, false
+}
+
+// Returns the webhooks of the config followed by the ones registered with the admin API.
+func loadHooks(ctx context.Context) ([]config.Webhook, error) {
+	ret := append([]config.Webhook{}, config.Global.Webhooks.Hooks...)
+	rows, err := db.Query(ctx, "SELECT id, url, secret, filter FROM webhooks ORDER BY created_at, id")
+	if err != nil {
+		return nil, fmt.Errorf("reading webhooks: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var hook config.Webhook
+		var filter []byte
+		if err := rows.Scan(&hook.ID, &hook.URL, &hook.Secret, &filter); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(filter, &hook.Filter); err != nil {
+			return nil, fmt.Errorf("filter of webhook %s: %w", hook.ID, err)
+		}
+		// Registered before it was added to the config, the config wins.
+		if _, ok := configHook(hook.ID); ok {
+			continue
+		}
+		ret = append(ret, hook)
+	}
+	return ret, rows.Err()
+}
+
+// List returns the webhooks for the admin API.
+func List(ctx context.Context) ([]Hook, error) {
+	hooks, err := loadHooks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pending, err := pendingCounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]Hook, 0, len(hooks))
+	for _, hook := range hooks {
+		source := "admin"
+		if _, ok := configHook(hook.ID); ok {
+			source = "config"
+		}
+		ret = append(ret, Hook{
+			ID:      hook.ID,
+			URL:     hook.URL,
+			Filter:  hook.Filter,
+			Source:  source,
+			Pending: pending[hook.ID],
+		})
+	}
+	return ret, nil
+}
+
+// Register stores a webhook, an earlier one with the same id is replaced. The events of the
+// blocks after the next aggregates refresh are sent to it.
+func Register(ctx context.Context, hook config.Webhook) miderr.Err {
+	if err := Validate(hook); err != nil {
+		return miderr.BadRequest(err.Error())
+	}
+	if _, ok := configHook(hook.ID); ok {
+		return miderr.BadRequestF("Webhook %s is in the config", hook.ID)
+	}
+	filter, err := json.Marshal(hook.Filter)
+	if err != nil {
+		return miderr.InternalErrE(err)
+	}
+	_, err = db.TheDB.ExecContext(ctx, `
+		INSERT INTO webhooks (id, url, secret, filter) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (id) DO UPDATE SET url = $2, secret = $3, filter = $4`,
+		hook.ID, hook.URL, hook.Secret, filter)
+	if err != nil {
+		return miderr.InternalErrE(err)
+	}
+	logger.InfoF("Registered webhook %s to %s", hook.ID, hook.URL)
+	return nil
+}
+
+// Remove deletes a webhook registered with the admin API together with its queued payloads.
+// Returns false if there was no such webhook.
+func Remove(ctx context.Context, id string) (bool, miderr.Err) {
+	if _, ok := configHook(id); ok {
+		return false, miderr.BadRequestF("Webhook %s is in the config", id)
+	}
+	tx, err := db.TheDB.BeginTx(ctx, nil)
+	if err != nil {
+		return false, miderr.InternalErrE(err)
+	}
+	defer tx.Rollback()
+	res, err := tx.ExecContext(ctx, "DELETE FROM webhooks WHERE id = $1", id)
+	if err != nil {
+		return false, miderr.InternalErrE(err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, miderr.InternalErrE(err)
+	}
+	_, err = tx.ExecContext(ctx, "DELETE FROM webhook_deliveries WHERE webhook_id = $1", id)
+	if err != nil {
+		return false, miderr.InternalErrE(err)
+	}
+	if err := tx.Commit(); err != nil {
+		return false, miderr.InternalErrE(err)
+	}
+	if n != 0 {
+		logger.InfoF("Removed webhook %s", id)
+	}
+	return n != 0, nil
+}
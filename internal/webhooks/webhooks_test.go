@@ -0,0 +1,170 @@
+package webhooks
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gitlab.com/thorchain/midgard/config"
+	"gitlab.com/thorchain/midgard/internal/timeseries"
+	"gitlab.com/thorchain/midgard/openapi/generated/oapigen"
+)
+
+func swapAction(height, date, pool, asset, amount string, addresses ...string) timeseries.ActionWithAddresses {
+	return timeseries.ActionWithAddresses{
+		Action: oapigen.Action{
+			Height: height,
+			Date:   date,
+			Type:   "swap",
+			Pools:  []string{pool},
+			In: []oapigen.Transaction{{
+				Address: addresses[0],
+				Coins:   oapigen.Coins{{Asset: asset, Amount: amount}},
+			}},
+		},
+		Addresses: addresses,
+	}
+}
+
+// 1 BTC is 1000 RUNE.
+func testRuneValue(asset string, amountE8 int64) int64 {
+	if asset == "THOR.RUNE" {
+		return amountE8
+	}
+	return amountE8 * 1000
+}
+
+func TestMatchesAction(t *testing.T) {
+	swap := swapAction("1", "10", "BTC.BTC", "BTC.BTC", "100", "bc1addr", "thoraddr")
+	add := timeseries.ActionWithAddresses{
+		Action:    oapigen.Action{Type: "addLiquidity", Pools: []string{"ETH.ETH"}},
+		Addresses: []string{"thoraddr"},
+	}
+
+	match := func(f config.WebhookFilter, a timeseries.ActionWithAddresses) bool {
+		return matchesAction(f, a, testRuneValue)
+	}
+	require.True(t, match(config.WebhookFilter{}, swap))
+	require.True(t, match(config.WebhookFilter{}, add))
+
+	require.False(t, match(config.WebhookFilter{Events: []string{EventPoolStatus}}, swap))
+	require.True(t, match(config.WebhookFilter{Events: []string{EventPoolStatus, EventAction}}, swap))
+
+	byAddress := config.WebhookFilter{Addresses: []string{"bc1addr"}}
+	require.True(t, match(byAddress, swap))
+	require.False(t, match(byAddress, add))
+
+	byPool := config.WebhookFilter{Pools: []string{"ETH.ETH"}}
+	require.False(t, match(byPool, swap))
+	require.True(t, match(byPool, add))
+
+	byType := config.WebhookFilter{ActionTypes: []string{"swap"}}
+	require.True(t, match(byType, swap))
+	require.False(t, match(byType, add))
+
+	// The swap is 100 * 1000 RUNE, other actions are not affected.
+	require.True(t, match(config.WebhookFilter{MinSwapRuneE8: 100000}, swap))
+	require.False(t, match(config.WebhookFilter{MinSwapRuneE8: 100001}, swap))
+	require.True(t, match(config.WebhookFilter{MinSwapRuneE8: 100001}, add))
+}
+
+func TestMatchesPoolStatus(t *testing.T) {
+	change := timeseries.PoolStatusChange{Pool: "BTC.BTC", Status: "available"}
+
+	require.True(t, matchesPoolStatus(config.WebhookFilter{}, change))
+	require.False(t, matchesPoolStatus(config.WebhookFilter{Events: []string{EventAction}}, change))
+	require.True(t, matchesPoolStatus(config.WebhookFilter{Pools: []string{"BTC.BTC"}}, change))
+	require.False(t, matchesPoolStatus(config.WebhookFilter{Pools: []string{"ETH.ETH"}}, change))
+	// Address and action type filters are only for actions.
+	require.True(t, matchesPoolStatus(config.WebhookFilter{
+		Addresses:   []string{"thoraddr"},
+		ActionTypes: []string{"swap"},
+	}, change))
+}
+
+func TestPayloadsByBlock(t *testing.T) {
+	actions := []timeseries.ActionWithAddresses{
+		swapAction("1", "10", "BTC.BTC", "THOR.RUNE", "5", "thoraddr"),
+		swapAction("3", "30", "ETH.ETH", "THOR.RUNE", "6", "thoraddr"),
+		swapAction("3", "30", "BTC.BTC", "THOR.RUNE", "7", "thoraddr"),
+	}
+	changes := []timeseries.PoolStatusChange{
+		{Pool: "BTC.BTC", Status: "staged", Height: 2, Timestamp: 20},
+		{Pool: "BTC.BTC", Status: "available", Height: 3, Timestamp: 30},
+	}
+	blocks, err := groupByBlock(actions, changes)
+	require.NoError(t, err)
+	require.Len(t, blocks, 3)
+	require.Equal(t, []int64{1, 2, 3}, []int64{blocks[0].height, blocks[1].height, blocks[2].height})
+
+	hook := config.Webhook{ID: "btc", Filter: config.WebhookFilter{Pools: []string{"BTC.BTC"}}}
+	p := hookPayload(hook, blocks[2], testRuneValue)
+	require.NotNil(t, p)
+	require.Equal(t, "btc", p.WebhookID)
+	require.Equal(t, "3", p.Height)
+	require.Equal(t, "30", p.Date)
+	require.Len(t, p.Actions, 1)
+	require.Equal(t, "7", p.Actions[0].In[0].Coins[0].Amount)
+	require.Equal(t, []PoolStatusChange{{Pool: "BTC.BTC", Status: "available"}}, p.PoolStatusChanges)
+
+	hook.Filter = config.WebhookFilter{Events: []string{EventAction}, Pools: []string{"ETH.ETH"}}
+	require.Nil(t, hookPayload(hook, blocks[1], testRuneValue))
+}
+
+func TestValidate(t *testing.T) {
+	valid := config.Webhook{ID: "my-hook_1", URL: "https://example.com/hook"}
+	require.NoError(t, Validate(valid))
+
+	invalid := []config.Webhook{
+		{ID: "", URL: "https://example.com"},
+		{ID: "a b", URL: "https://example.com"},
+		{ID: "a", URL: "ftp://example.com"},
+		{ID: "a", URL: "example.com"},
+		{ID: "a", URL: "https://example.com", Filter: config.WebhookFilter{Events: []string{"block"}}},
+		{ID: "a", URL: "https://example.com", Filter: config.WebhookFilter{ActionTypes: []string{"stake"}}},
+		{ID: "a", URL: "https://example.com", Filter: config.WebhookFilter{MinSwapRuneE8: -1}},
+	}
+	for _, hook := range invalid {
+		require.Error(t, Validate(hook), "%+v", hook)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	min, max := 5*time.Second, time.Minute
+	require.Equal(t, 5*time.Second, backoff(1, min, max))
+	require.Equal(t, 10*time.Second, backoff(2, min, max))
+	require.Equal(t, 40*time.Second, backoff(4, min, max))
+	require.Equal(t, time.Minute, backoff(5, min, max))
+	require.Equal(t, time.Minute, backoff(1000, min, max))
+}
+
+func TestPostSigned(t *testing.T) {
+	body := []byte(`{"webhookId":"a"}`)
+	var headers http.Header
+	var received []byte
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header
+		var err error
+		received, err = io.ReadAll(r.Body)
+		require.NoError(t, err)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	hook := config.Webhook{ID: "a", URL: server.URL, Secret: "secret"}
+	err := post(context.Background(), server.Client(), hook, delivery{id: 7, payload: body})
+	require.NoError(t, err)
+	require.Equal(t, body, received)
+	require.Equal(t, "7", headers.Get("X-Midgard-Delivery"))
+	timestamp := headers.Get("X-Midgard-Timestamp")
+	require.Equal(t, "sha256="+Sign("secret", timestamp, body), headers.Get("X-Midgard-Signature"))
+
+	status = http.StatusInternalServerError
+	err = post(context.Background(), server.Client(), hook, delivery{id: 8, payload: body})
+	require.Error(t, err)
+}
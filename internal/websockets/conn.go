@@ -0,0 +1,185 @@
+package websockets
+
+import (
+	"encoding/json"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/gobwas/ws"
+	"github.com/gobwas/ws/wsutil"
+	"github.com/pascaldekloe/metrics"
+)
+
+var (
+	sentMessages = metrics.MustCounter("midgard_websocket_messages_sent_total",
+		"Number of messages written to websocket connections.")
+	slowDisconnects = metrics.MustCounter("midgard_websocket_slow_disconnects_total",
+		"Number of websocket connections closed because the client didn't read the messages.")
+	rejectedConnections = metrics.Must1LabelCounter("midgard_websocket_rejected_connections_total",
+		"reason")
+)
+
+func init() {
+	metrics.MustHelp("midgard_websocket_rejected_connections_total",
+		"Number of websocket connections rejected by the connection limits.")
+}
+
+// One entry of the send queue. The snapshot and the replay on subscribe are queued as a single
+// entry, so they don't overflow the queue however many pools and blocks they contain.
+type outMessage struct {
+	frames [][]byte
+	// The connection is closed after writing it.
+	last bool
+}
+
+// A websocket connection. Messages are written by its own goroutine from a bounded queue, a
+// client which doesn't read them fast enough is disconnected instead of holding up the others.
+type client struct {
+	fd      int
+	conn    net.Conn
+	ip      string
+	version int
+
+	send      chan outMessage
+	done      chan struct{}
+	closeOnce sync.Once
+
+	mu sync.Mutex
+	// Protocol v1: subscribed pools.
+	assets map[string]bool
+	// Protocol v2: subscribed pools by channel, nil for all pools.
+	channels map[string]map[string]bool
+	// Protocol v2: sequence number of the last message.
+	seq int64
+}
+
+func newClient(conn net.Conn, ip string, version int, queueSize int) *client {
+	if queueSize <= 0 {
+		queueSize = 1
+	}
+	return &client{
+		fd:       websocketFD(conn),
+		conn:     conn,
+		ip:       ip,
+		version:  version,
+		send:     make(chan outMessage, queueSize),
+		done:     make(chan struct{}),
+		assets:   map[string]bool{},
+		channels: map[string]map[string]bool{},
+	}
+}
+
+func (c *client) name() string {
+	return nameConn(c.conn)
+}
+
+// Queues the message without blocking. Returns false if the connection is closed, or it's
+// closed now because the queue is full.
+func (c *client) enqueue(data []byte) bool {
+	return c.enqueueFrames([][]byte{data})
+}
+
+// Queues the messages as one entry of the queue, see enqueue.
+func (c *client) enqueueFrames(frames [][]byte) bool {
+	select {
+	case <-c.done:
+		return false
+	default:
+	}
+	select {
+	case c.send <- outMessage{frames: frames}:
+		return true
+	default:
+		slowDisconnects.Add(1)
+		Logger.Infof("Disconnecting %s, it doesn't read the messages", c.name())
+		c.close()
+		return false
+	}
+}
+
+// Writes the message and closes the connection after the messages already queued.
+func (c *client) enqueueLast(data []byte) {
+	select {
+	case <-c.done:
+	case c.send <- outMessage{frames: [][]byte{data}, last: true}:
+	default:
+		c.close()
+	}
+}
+
+// Protocol v2: sets the sequence number and queues the message.
+func (c *client) sendMessage(m Message) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.sendMessageLocked(m)
+}
+
+func (c *client) sendMessageLocked(m Message) bool {
+	data, err := c.marshalLocked(m)
+	if err != nil {
+		return false
+	}
+	return c.enqueue(data)
+}
+
+// Protocol v2: sends the messages as one entry of the send queue.
+func (c *client) sendMessagesLocked(messages []Message) bool {
+	if len(messages) == 0 {
+		return true
+	}
+	frames := make([][]byte, 0, len(messages))
+	for _, m := range messages {
+		data, err := c.marshalLocked(m)
+		if err != nil {
+			return false
+		}
+		frames = append(frames, data)
+	}
+	return c.enqueueFrames(frames)
+}
+
+func (c *client) marshalLocked(m Message) ([]byte, error) {
+	c.seq++
+	m.V = ProtocolV2
+	m.Seq = c.seq
+	data, err := json.Marshal(m)
+	if err != nil {
+		Logger.Warnf("marshalling err on write %v", err)
+	}
+	return data, err
+}
+
+func (c *client) writeLoop(timeout time.Duration) {
+	for {
+		select {
+		case <-c.done:
+			return
+		case msg := <-c.send:
+			for _, data := range msg.frames {
+				if 0 < timeout {
+					_ = c.conn.SetWriteDeadline(time.Now().Add(timeout))
+				}
+				err := wsutil.WriteServerMessage(c.conn, ws.OpText, data)
+				if err != nil {
+					Logger.Infof("Failed to write to %s, disconnecting: %v", c.name(), err)
+					c.close()
+					return
+				}
+				sentMessages.Add(1)
+			}
+			if msg.last {
+				c.close()
+				return
+			}
+		}
+	}
+}
+
+// Closes the connection, queued messages are dropped.
+func (c *client) close() {
+	c.closeOnce.Do(func() {
+		close(c.done)
+		connManager.Remove(c)
+	})
+}
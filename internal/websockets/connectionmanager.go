@@ -1,32 +1,30 @@
 package websockets
 
 import (
+	"errors"
 	"net"
 	"reflect"
 	"sync"
 )
 
-const (
-	INIT_FLUSH_COUNT  = 1
-	MAX_FLUSH_ATTEMPT = 3
+var (
+	errConnectionLimit = errors.New("max websocket connections on this node, no room left")
+	errPerIPLimit      = errors.New("max websocket connections from this address")
 )
 
 type connectionManager struct {
-	fd        int
-	connLimit int
+	fd         int
+	connLimit  int
+	perIPLimit int
 
 	connMutex sync.RWMutex
-	// connections[FD] => net.Conn
-	connections map[int]net.Conn
-
-	// TODO(kano): let's consider moving the connection attempts out of this data structure and
-	//     adding it in the connections.
-	assetMutex sync.RWMutex
-	// assetFDs[BTC.BTC] => map[FD] => connection attempts
-	assetFDs map[string]map[int]int
+	// connections[FD] => client
+	connections map[int]*client
+	// perIP[remote address] => number of connections
+	perIP map[string]int
 }
 
-func ConnectionManagerInit(connLimit int) (*connectionManager, error) {
+func ConnectionManagerInit(connLimit, perIPLimit int) (*connectionManager, error) {
 	fd, err := epollCreate1(0)
 	if err != nil {
 		Logger.Warnf("mkepoll err %v", err)
@@ -34,55 +32,89 @@ func ConnectionManagerInit(connLimit int) (*connectionManager, error) {
 	}
 	return &connectionManager{
 		fd:          fd,
-		connections: make(map[int]net.Conn),
-		assetFDs:    make(map[string]map[int]int),
+		connections: make(map[int]*client),
+		perIP:       make(map[string]int),
 		connLimit:   connLimit,
+		perIPLimit:  perIPLimit,
 	}, nil
 }
 
-func (cm *connectionManager) GetConnection(fd int) *net.Conn {
+func (cm *connectionManager) GetConnection(fd int) *client {
 	cm.connMutex.RLock()
 	defer cm.connMutex.RUnlock()
-	ret, ok := cm.connections[fd]
-	if !ok {
-		return nil
+	return cm.connections[fd]
+}
+
+// Returns errConnectionLimit or errPerIPLimit if there is no room for a connection from ip.
+func (cm *connectionManager) CheckLimits(ip string) error {
+	cm.connMutex.RLock()
+	defer cm.connMutex.RUnlock()
+	return cm.checkLimits(ip)
+}
+
+func (cm *connectionManager) checkLimits(ip string) error {
+	if cm.connLimit <= len(cm.connections) {
+		return errConnectionLimit
 	}
-	return &ret
+	if 0 < cm.perIPLimit && cm.perIPLimit <= cm.perIP[ip] {
+		return errPerIPLimit
+	}
+	return nil
 }
 
-func (cm *connectionManager) Add(conn net.Conn) error {
-	// Extract file descriptor associated with the connection
-	fd := websocketFD(conn)
-	err := epollAdd(cm.fd, fd)
+func (cm *connectionManager) Add(c *client) error {
+	cm.connMutex.Lock()
+	defer cm.connMutex.Unlock()
+	// Checked again, other connections might have been upgraded in the meantime.
+	if err := cm.checkLimits(c.ip); err != nil {
+		return err
+	}
+	err := epollAdd(cm.fd, c.fd)
 	if err != nil {
 		Logger.Warnf("add epoll fail %v", err)
 		return err
 	}
-	cm.connMutex.Lock()
-	defer cm.connMutex.Unlock()
-	cm.connections[fd] = conn
-	// TODO(acsaba): add some metric for len(e.connections)
+	cm.connections[c.fd] = c
+	cm.perIP[c.ip]++
 	return nil
 }
 
-func (cm *connectionManager) Remove(conn net.Conn) {
-	fd := websocketFD(conn)
-	err := epollDel(cm.fd, fd)
-	if err != nil {
-		Logger.Warnf("epoll remove error %v", err)
-		return
-	}
+func (cm *connectionManager) Remove(c *client) {
 	cm.connMutex.Lock()
-	defer cm.connMutex.Unlock()
-	delete(cm.connections, fd)
-	// TODO(kano): Not sure if closing has to wait for a client turnaround.
-	//     Consider close before locking, if it's safe to do so.
-	conn.Close()
-	// TODO(acsaba): add some metric for len(e.connections)
+	if cm.connections[c.fd] == c {
+		err := epollDel(cm.fd, c.fd)
+		if err != nil {
+			Logger.Warnf("epoll remove error %v", err)
+		}
+		delete(cm.connections, c.fd)
+		cm.perIP[c.ip]--
+		if cm.perIP[c.ip] <= 0 {
+			delete(cm.perIP, c.ip)
+		}
+	}
+	cm.connMutex.Unlock()
+	c.conn.Close()
+}
+
+func (cm *connectionManager) Count() int {
+	cm.connMutex.RLock()
+	defer cm.connMutex.RUnlock()
+	return len(cm.connections)
+}
+
+// Returns the open connections, the caller doesn't need to hold the lock while writing to them.
+func (cm *connectionManager) Clients() []*client {
+	cm.connMutex.RLock()
+	defer cm.connMutex.RUnlock()
+	ret := make([]*client, 0, len(cm.connections))
+	for _, c := range cm.connections {
+		ret = append(ret, c)
+	}
+	return ret
 }
 
 // TODO(kano): document if this only works for existing connections, or it also accepts new ones.
-func (cm *connectionManager) WaitOnReceive() (map[int]net.Conn, error) {
+func (cm *connectionManager) WaitOnReceive() (map[int]*client, error) {
 	const maxEventNum = 100
 	const waitMSec = 100
 
@@ -96,11 +128,11 @@ func (cm *connectionManager) WaitOnReceive() (map[int]net.Conn, error) {
 	}
 	cm.connMutex.RLock()
 	defer cm.connMutex.RUnlock()
-	readableConnections := map[int]net.Conn{}
+	readableConnections := map[int]*client{}
 	for i := 0; i < n; i++ {
-		conn, found := cm.connections[int(events[i].Fd)]
+		c, found := cm.connections[int(events[i].Fd)]
 		if found {
-			readableConnections[int(events[i].Fd)] = conn
+			readableConnections[int(events[i].Fd)] = c
 		}
 		// TODO(kano): what handle or document !found.
 	}
@@ -0,0 +1,469 @@
+package websockets
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+
+	"gitlab.com/thorchain/midgard/internal/db"
+	"gitlab.com/thorchain/midgard/internal/timeseries"
+)
+
+// Protocol v2 is chosen with /v2/websocket?version=2. Clients send Requests and receive
+// Messages, both JSON. After subscribing to channels the client gets the updates of every new
+// block which changed them, each message with the height of its block. A client which
+// reconnects can subscribe with fromHeight to get the updates it missed replayed.
+const (
+	ProtocolV1 = 1
+	ProtocolV2 = 2
+)
+
+// Channels.
+const (
+	// Asset price in RUNE and USD per pool.
+	ChannelPrices = "prices"
+	// Depths and units per pool.
+	ChannelDepths = "depths"
+	// Height and date of the new blocks.
+	ChannelBlocks = "blocks"
+	// Pool status changes, there is no snapshot on subscribe.
+	ChannelPoolStatus = "pool_status"
+)
+
+var channels = map[string]bool{
+	ChannelPrices:     true,
+	ChannelDepths:     true,
+	ChannelBlocks:     true,
+	ChannelPoolStatus: true,
+}
+
+// Request ops.
+const (
+	OpSubscribe   = "subscribe"
+	OpUnsubscribe = "unsubscribe"
+	OpPing        = "ping"
+)
+
+// Message types besides the channels.
+const (
+	TypeWelcome    = "welcome"
+	TypeHeartbeat  = "heartbeat"
+	TypePong       = "pong"
+	TypeSubscribed = "subscribed"
+	TypeError      = "error"
+)
+
+// Error codes.
+const (
+	ErrorBadRequest        = "bad_request"
+	ErrorUnknownChannel    = "unknown_channel"
+	ErrorUnknownPool       = "unknown_pool"
+	ErrorResumeUnavailable = "resume_unavailable"
+)
+
+// Request is sent by v2 clients.
+type Request struct {
+	Op       string           `json:"op"`
+	Channels []ChannelRequest `json:"channels,omitempty"`
+	// Subscribe: the updates of the blocks after this height are replayed, if they are still
+	// in memory. Otherwise a resume_unavailable error is sent followed by the snapshot.
+	FromHeight int64 `json:"fromHeight,omitempty"`
+	// Ping: sent back in the pong.
+	ID string `json:"id,omitempty"`
+}
+
+type ChannelRequest struct {
+	Name string `json:"name"`
+	// All pools when empty. Not used by the blocks channel.
+	Pools []string `json:"pools,omitempty"`
+}
+
+// Message is sent to v2 clients.
+type Message struct {
+	V    int    `json:"v"`
+	Type string `json:"type"`
+	// Per connection, starts at 1 and has no gaps.
+	Seq int64 `json:"seq"`
+	// Height of the block of the update, for the other types the last block.
+	Height int64  `json:"height,omitempty"`
+	Pool   string `json:"pool,omitempty"`
+	// Sent again after a resume.
+	Replay bool        `json:"replay,omitempty"`
+	Data   interface{} `json:"data,omitempty"`
+}
+
+type PriceData struct {
+	AssetPrice    string `json:"assetPrice"`
+	AssetPriceUSD string `json:"assetPriceUSD"`
+}
+
+type DepthData struct {
+	AssetDepth     string `json:"assetDepth"`
+	RuneDepth      string `json:"runeDepth"`
+	SynthSupply    string `json:"synthSupply"`
+	LiquidityUnits string `json:"liquidityUnits"`
+}
+
+type BlockData struct {
+	// Int64, nano timestamp of the block.
+	Date string `json:"date"`
+}
+
+type PoolStatusData struct {
+	Status string `json:"status"`
+}
+
+type WelcomeData struct {
+	HeartbeatInterval string `json:"heartbeatInterval"`
+	ResumeBlocks      int    `json:"resumeBlocks"`
+}
+
+type SubscribedData struct {
+	Channels []ChannelRequest `json:"channels"`
+}
+
+type PongData struct {
+	ID string `json:"id,omitempty"`
+}
+
+type ErrorData struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type update struct {
+	channel string
+	pool    string
+	data    json.RawMessage
+}
+
+type blockUpdate struct {
+	height  int64
+	updates []update
+}
+
+// Keeps the updates of the last blocks for the resumes and the last value of every channel and
+// pool for the snapshots.
+type hub struct {
+	sync.Mutex
+	resumeBlocks  int
+	history       []blockUpdate
+	last          map[string]map[string]update
+	lastHeight    int64
+	lastTimestamp db.Nano
+}
+
+var theHub = newHub(0)
+
+func newHub(resumeBlocks int) *hub {
+	return &hub{resumeBlocks: resumeBlocks, last: map[string]map[string]update{}}
+}
+
+func mustMarshal(v interface{}) json.RawMessage {
+	ret, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return ret
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// Returns the updates of the channels which changed since the last block.
+func (h *hub) blockUpdates(state timeseries.BlockState, runePriceUSD float64,
+	statusChanges []timeseries.PoolStatusChange) blockUpdate {
+	ret := blockUpdate{height: state.Height}
+	add := func(channel, pool string, data json.RawMessage) {
+		if prev, ok := h.last[channel][pool]; ok && bytes.Equal(prev.data, data) {
+			return
+		}
+		ret.updates = append(ret.updates, update{channel: channel, pool: pool, data: data})
+	}
+
+	add(ChannelBlocks, "", mustMarshal(BlockData{Date: strconv.FormatInt(state.Timestamp.ToI(), 10)}))
+
+	pools := make([]string, 0, len(state.Pools))
+	for pool := range state.Pools {
+		pools = append(pools, pool)
+	}
+	sort.Strings(pools)
+	for _, pool := range pools {
+		depths := state.Pools[pool]
+		add(ChannelPrices, pool, mustMarshal(PriceData{
+			AssetPrice:    formatFloat(depths.AssetPrice()),
+			AssetPriceUSD: formatFloat(depths.AssetPrice() * runePriceUSD),
+		}))
+	}
+	for _, pool := range pools {
+		depths := state.Pools[pool]
+		add(ChannelDepths, pool, mustMarshal(DepthData{
+			AssetDepth:     strconv.FormatInt(depths.AssetDepth, 10),
+			RuneDepth:      strconv.FormatInt(depths.RuneDepth, 10),
+			SynthSupply:    strconv.FormatInt(depths.SynthDepth, 10),
+			LiquidityUnits: strconv.FormatInt(depths.PoolUnit, 10),
+		}))
+	}
+	for _, c := range statusChanges {
+		// Not deduplicated, every change is sent.
+		ret.updates = append(ret.updates, update{
+			channel: ChannelPoolStatus,
+			pool:    c.Pool,
+			data:    mustMarshal(PoolStatusData{Status: c.Status}),
+		})
+	}
+	return ret
+}
+
+// Records the updates of the block and sends them to the subscribed v2 clients.
+func (h *hub) publish(state timeseries.BlockState, runePriceUSD float64,
+	statusChanges []timeseries.PoolStatusChange, clients []*client) {
+	h.Lock()
+	defer h.Unlock()
+	if state.Height == h.lastHeight {
+		return
+	}
+
+	b := h.blockUpdates(state, runePriceUSD, statusChanges)
+	for _, u := range b.updates {
+		if u.channel == ChannelPoolStatus {
+			continue
+		}
+		if h.last[u.channel] == nil {
+			h.last[u.channel] = map[string]update{}
+		}
+		h.last[u.channel][u.pool] = u
+	}
+	h.lastHeight = state.Height
+	h.lastTimestamp = state.Timestamp
+	if 0 < h.resumeBlocks {
+		h.history = append(h.history, b)
+		if h.resumeBlocks < len(h.history) {
+			h.history = h.history[len(h.history)-h.resumeBlocks:]
+		}
+	}
+
+	for _, c := range clients {
+		if c.version != ProtocolV2 {
+			continue
+		}
+		c.mu.Lock()
+		for _, u := range b.updates {
+			if c.subscribedLocked(u.channel, u.pool) {
+				c.sendMessageLocked(Message{Type: u.channel, Height: b.height, Pool: u.pool, Data: u.data})
+			}
+		}
+		c.mu.Unlock()
+	}
+}
+
+func (h *hub) height() int64 {
+	h.Lock()
+	defer h.Unlock()
+	return h.lastHeight
+}
+
+func (h *hub) timestamp() db.Nano {
+	h.Lock()
+	defer h.Unlock()
+	return h.lastTimestamp
+}
+
+func (c *client) subscribedLocked(channel, pool string) bool {
+	pools, ok := c.channels[channel]
+	if !ok {
+		return false
+	}
+	return pools == nil || pools[pool]
+}
+
+// A subscription request with only the known channels and pools.
+type subscription struct {
+	channel string
+	// nil for all pools.
+	pools map[string]bool
+}
+
+func (s subscription) matches(u update) bool {
+	return s.channel == u.channel && (s.pools == nil || s.pools[u.pool])
+}
+
+// Checks the requested channels, the errors are sent to the client.
+func validChannels(c *client, requested []ChannelRequest, poolExists func(string) bool) []subscription {
+	var ret []subscription
+	for _, r := range requested {
+		if !channels[r.Name] {
+			c.sendMessageLocked(errorMessage(ErrorUnknownChannel, fmt.Sprintf("unknown channel %q", r.Name)))
+			continue
+		}
+		s := subscription{channel: r.Name}
+		if len(r.Pools) != 0 && r.Name != ChannelBlocks {
+			s.pools = map[string]bool{}
+			for _, pool := range r.Pools {
+				if !poolExists(pool) {
+					c.sendMessageLocked(errorMessage(ErrorUnknownPool, fmt.Sprintf("unknown pool %q", pool)))
+					continue
+				}
+				s.pools[pool] = true
+			}
+			if len(s.pools) == 0 {
+				continue
+			}
+		}
+		ret = append(ret, s)
+	}
+	return ret
+}
+
+func subscribedData(subs []subscription) SubscribedData {
+	ret := SubscribedData{Channels: []ChannelRequest{}}
+	for _, s := range subs {
+		r := ChannelRequest{Name: s.channel}
+		for pool := range s.pools {
+			r.Pools = append(r.Pools, pool)
+		}
+		sort.Strings(r.Pools)
+		ret.Channels = append(ret.Channels, r)
+	}
+	return ret
+}
+
+func errorMessage(code, message string) Message {
+	return Message{Type: TypeError, Data: ErrorData{Code: code, Message: message}}
+}
+
+// Subscribes the client and sends the snapshot or replays the missed blocks. The hub is locked
+// so no block is published in the meantime. The snapshot and the replay are queued as one entry,
+// a full history of resume_blocks doesn't overflow the send queue.
+func (h *hub) subscribe(c *client, req Request, poolExists func(string) bool) {
+	h.Lock()
+	defer h.Unlock()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	subs := validChannels(c, req.Channels, poolExists)
+	for _, s := range subs {
+		current, ok := c.channels[s.channel]
+		switch {
+		case !ok || s.pools == nil:
+			c.channels[s.channel] = s.pools
+		case current != nil:
+			for pool := range s.pools {
+				current[pool] = true
+			}
+		}
+	}
+	c.sendMessageLocked(Message{Type: TypeSubscribed, Height: h.lastHeight, Data: subscribedData(subs)})
+
+	var messages []Message
+	if 0 < req.FromHeight {
+		if h.canResume(req.FromHeight) {
+			for _, b := range h.history {
+				if b.height <= req.FromHeight {
+					continue
+				}
+				for _, u := range b.updates {
+					for _, s := range subs {
+						if s.matches(u) {
+							messages = append(messages, Message{
+								Type: u.channel, Height: b.height, Pool: u.pool, Data: u.data, Replay: true,
+							})
+							break
+						}
+					}
+				}
+			}
+			c.sendMessagesLocked(messages)
+			return
+		}
+		c.sendMessageLocked(errorMessage(ErrorResumeUnavailable, fmt.Sprintf(
+			"blocks after height %d are not kept anymore, sending the current state", req.FromHeight)))
+	}
+
+	for _, s := range subs {
+		last := h.last[s.channel]
+		pools := make([]string, 0, len(last))
+		for pool := range last {
+			pools = append(pools, pool)
+		}
+		sort.Strings(pools)
+		for _, pool := range pools {
+			u := last[pool]
+			if s.matches(u) {
+				messages = append(messages,
+					Message{Type: u.channel, Height: h.lastHeight, Pool: u.pool, Data: u.data})
+			}
+		}
+	}
+	c.sendMessagesLocked(messages)
+}
+
+// True if all the blocks after fromHeight are in the history.
+func (h *hub) canResume(fromHeight int64) bool {
+	if h.lastHeight <= fromHeight {
+		return true
+	}
+	// Not every block is published, the updates are complete only if a block at or before
+	// fromHeight is still kept.
+	return len(h.history) != 0 && h.history[0].height <= fromHeight
+}
+
+func unsubscribe(c *client, req Request) {
+	height := theHub.height()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, r := range req.Channels {
+		current, ok := c.channels[r.Name]
+		if !ok {
+			continue
+		}
+		if len(r.Pools) == 0 {
+			delete(c.channels, r.Name)
+			continue
+		}
+		if current == nil {
+			c.sendMessageLocked(errorMessage(ErrorBadRequest, fmt.Sprintf(
+				"channel %s is subscribed for all pools, unsubscribe it without pools", r.Name)))
+			continue
+		}
+		for _, pool := range r.Pools {
+			delete(current, pool)
+		}
+		if len(current) == 0 {
+			delete(c.channels, r.Name)
+		}
+	}
+	c.sendMessageLocked(Message{Type: TypeSubscribed, Height: height, Data: c.subscriptionsLocked()})
+}
+
+func (c *client) subscriptionsLocked() SubscribedData {
+	subs := make([]subscription, 0, len(c.channels))
+	for channel, pools := range c.channels {
+		subs = append(subs, subscription{channel: channel, pools: pools})
+	}
+	sort.Slice(subs, func(i, j int) bool { return subs[i].channel < subs[j].channel })
+	return subscribedData(subs)
+}
+
+// Handles a message of a v2 client.
+func handleRequest(c *client, msg []byte) {
+	var req Request
+	if err := json.Unmarshal(msg, &req); err != nil {
+		c.sendMessage(errorMessage(ErrorBadRequest, fmt.Sprintf("invalid request: %v", err)))
+		return
+	}
+	switch req.Op {
+	case OpSubscribe:
+		theHub.subscribe(c, req, timeseries.Latest.GetState().PoolExists)
+	case OpUnsubscribe:
+		unsubscribe(c, req)
+	case OpPing:
+		c.sendMessage(Message{Type: TypePong, Height: theHub.height(), Data: PongData{ID: req.ID}})
+	default:
+		c.sendMessage(errorMessage(ErrorBadRequest, fmt.Sprintf("unknown op %q", req.Op)))
+	}
+}
@@ -0,0 +1,207 @@
+package websockets
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gitlab.com/thorchain/midgard/internal/timeseries"
+)
+
+func testClient() *client {
+	return testClientQueue(100)
+}
+
+func testClientQueue(size int) *client {
+	return &client{
+		version:  ProtocolV2,
+		send:     make(chan outMessage, size),
+		done:     make(chan struct{}),
+		assets:   map[string]bool{},
+		channels: map[string]map[string]bool{},
+	}
+}
+
+type receivedMessage struct {
+	V      int             `json:"v"`
+	Type   string          `json:"type"`
+	Seq    int64           `json:"seq"`
+	Height int64           `json:"height"`
+	Pool   string          `json:"pool"`
+	Replay bool            `json:"replay"`
+	Data   json.RawMessage `json:"data"`
+}
+
+func received(t *testing.T, c *client) []receivedMessage {
+	var ret []receivedMessage
+	for {
+		select {
+		case msg := <-c.send:
+			for _, data := range msg.frames {
+				var m receivedMessage
+				require.NoError(t, json.Unmarshal(data, &m))
+				ret = append(ret, m)
+			}
+		default:
+			return ret
+		}
+	}
+}
+
+func testState(height int64, btcRune int64) timeseries.BlockState {
+	return timeseries.BlockState{
+		Height:    height,
+		Timestamp: 1000 * 1e9,
+		Pools: timeseries.DepthMap{
+			"BTC.BTC": {AssetDepth: 10, RuneDepth: btcRune, PoolUnit: 5},
+			"ETH.ETH": {AssetDepth: 10, RuneDepth: 100, PoolUnit: 7},
+		},
+	}
+}
+
+func poolExists(pool string) bool {
+	return pool == "BTC.BTC" || pool == "ETH.ETH"
+}
+
+func subscribeRequest(fromHeight int64, channels ...ChannelRequest) Request {
+	return Request{Op: OpSubscribe, Channels: channels, FromHeight: fromHeight}
+}
+
+func TestPublishSendsChanges(t *testing.T) {
+	h := newHub(10)
+	c := testClient()
+	h.subscribe(c, subscribeRequest(0, ChannelRequest{Name: ChannelPrices, Pools: []string{"BTC.BTC"}}), poolExists)
+	msgs := received(t, c)
+	require.Len(t, msgs, 1)
+	require.Equal(t, TypeSubscribed, msgs[0].Type)
+	require.Equal(t, int64(1), msgs[0].Seq)
+
+	h.publish(testState(1, 20), 2, nil, []*client{c})
+	msgs = received(t, c)
+	require.Len(t, msgs, 1)
+	require.Equal(t, ChannelPrices, msgs[0].Type)
+	require.Equal(t, ProtocolV2, msgs[0].V)
+	require.Equal(t, int64(2), msgs[0].Seq)
+	require.Equal(t, int64(1), msgs[0].Height)
+	require.Equal(t, "BTC.BTC", msgs[0].Pool)
+	require.JSONEq(t, `{"assetPrice": "2", "assetPriceUSD": "4"}`, string(msgs[0].Data))
+
+	// Only ETH changed.
+	h.publish(testState(2, 20), 2, nil, []*client{c})
+	require.Empty(t, received(t, c))
+
+	h.publish(testState(3, 30), 2, []timeseries.PoolStatusChange{{Pool: "BTC.BTC", Status: "staged"}},
+		[]*client{c})
+	msgs = received(t, c)
+	require.Len(t, msgs, 1)
+	require.Equal(t, int64(3), msgs[0].Height)
+	require.Equal(t, int64(3), msgs[0].Seq)
+}
+
+func TestSubscribeSnapshot(t *testing.T) {
+	h := newHub(10)
+	h.publish(testState(1, 20), 1, nil, nil)
+
+	c := testClient()
+	h.subscribe(c, subscribeRequest(0,
+		ChannelRequest{Name: ChannelDepths},
+		ChannelRequest{Name: ChannelBlocks},
+		ChannelRequest{Name: "trades"},
+		ChannelRequest{Name: ChannelPrices, Pools: []string{"DOGE.DOGE"}},
+	), poolExists)
+	msgs := received(t, c)
+
+	types := []string{}
+	for _, m := range msgs {
+		types = append(types, m.Type+" "+m.Pool)
+	}
+	require.Equal(t, []string{
+		"error ", "error ", "subscribed ", "depths BTC.BTC", "depths ETH.ETH", "blocks ",
+	}, types)
+	require.JSONEq(t,
+		`{"assetDepth": "10", "runeDepth": "20", "synthSupply": "0", "liquidityUnits": "5"}`,
+		string(msgs[3].Data))
+	require.JSONEq(t, `{"date": "1000000000000"}`, string(msgs[5].Data))
+}
+
+func TestSubscribeResume(t *testing.T) {
+	h := newHub(2)
+	h.publish(testState(1, 20), 1, nil, nil)
+	h.publish(testState(2, 30), 1, nil, nil)
+	h.publish(testState(3, 40), 1, nil, nil)
+
+	c := testClient()
+	h.subscribe(c, subscribeRequest(2, ChannelRequest{Name: ChannelPrices, Pools: []string{"BTC.BTC"}}),
+		poolExists)
+	msgs := received(t, c)
+	require.Len(t, msgs, 2)
+	require.Equal(t, TypeSubscribed, msgs[0].Type)
+	require.Equal(t, int64(3), msgs[1].Height)
+	require.True(t, msgs[1].Replay)
+	require.JSONEq(t, `{"assetPrice": "4", "assetPriceUSD": "4"}`, string(msgs[1].Data))
+
+	// Block 2 is not kept anymore.
+	c = testClient()
+	h.subscribe(c, subscribeRequest(1, ChannelRequest{Name: ChannelPrices, Pools: []string{"BTC.BTC"}}),
+		poolExists)
+	msgs = received(t, c)
+	require.Len(t, msgs, 3)
+	require.Equal(t, TypeError, msgs[1].Type)
+	require.Contains(t, string(msgs[1].Data), ErrorResumeUnavailable)
+	require.False(t, msgs[2].Replay)
+	require.Equal(t, int64(3), msgs[2].Height)
+}
+
+func TestSubscribeMoreThanQueue(t *testing.T) {
+	h := newHub(20)
+	for height := int64(1); height <= 10; height++ {
+		state := testState(height, 20+height)
+		state.Pools["ETH.ETH"] = timeseries.PoolDepths{AssetDepth: 10, RuneDepth: 100 + height, PoolUnit: 7}
+		h.publish(state, 1, nil, nil)
+	}
+
+	// The replay, 9 blocks of 2 pools, and the ack in a queue of 2.
+	c := testClientQueue(2)
+	h.subscribe(c, subscribeRequest(1, ChannelRequest{Name: ChannelDepths}), poolExists)
+	select {
+	case <-c.done:
+		t.Fatal("client disconnected")
+	default:
+	}
+	msgs := received(t, c)
+	require.Len(t, msgs, 1+9*2)
+	require.Equal(t, TypeSubscribed, msgs[0].Type)
+	for i, m := range msgs[1:] {
+		require.True(t, m.Replay)
+		require.Equal(t, int64(2+i/2), m.Height)
+		require.Equal(t, int64(i+2), m.Seq)
+	}
+}
+
+func TestRemoteIP(t *testing.T) {
+	r := &http.Request{RemoteAddr: "10.0.0.1:4000", Header: http.Header{}}
+	require.Equal(t, "10.0.0.1", remoteIP(r, ""))
+	require.Equal(t, "10.0.0.1", remoteIP(r, "X-Forwarded-For"))
+
+	r.Header.Add("X-Forwarded-For", "1.1.1.1, 2.2.2.2")
+	r.Header.Add("X-Forwarded-For", "3.3.3.3")
+	require.Equal(t, "10.0.0.1", remoteIP(r, ""))
+	require.Equal(t, "3.3.3.3", remoteIP(r, "X-Forwarded-For"))
+}
+
+func TestUnsubscribe(t *testing.T) {
+	c := testClient()
+	theHub.subscribe(c, subscribeRequest(0,
+		ChannelRequest{Name: ChannelPrices, Pools: []string{"BTC.BTC", "ETH.ETH"}},
+		ChannelRequest{Name: ChannelBlocks},
+	), poolExists)
+	unsubscribe(c, Request{Op: OpUnsubscribe, Channels: []ChannelRequest{
+		{Name: ChannelPrices, Pools: []string{"BTC.BTC"}},
+		{Name: ChannelBlocks},
+	}})
+	msgs := received(t, c)
+	last := msgs[len(msgs)-1]
+	require.Equal(t, TypeSubscribed, last.Type)
+	require.JSONEq(t, `{"channels": [{"name": "prices", "pools": ["ETH.ETH"]}]}`, string(last.Data))
+}
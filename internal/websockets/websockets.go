@@ -1,20 +1,21 @@
 package websockets
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"net"
 	"net/http"
 	"strconv"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/gobwas/ws"
 	"github.com/gobwas/ws/wsutil"
 	"github.com/julienschmidt/httprouter"
+	"gitlab.com/thorchain/midgard/config"
 	"gitlab.com/thorchain/midgard/internal/db"
 	"gitlab.com/thorchain/midgard/internal/timeseries"
 	"gitlab.com/thorchain/midgard/internal/util/jobs"
@@ -31,13 +32,13 @@ import (
 var (
 	connManager *connectionManager
 	// TODO(kano): - extend Logger to application name to prefix all output with service name => in this case websockets.
-	Logger                = NewLogger()
-	MAX_BYTE_LENGTH_FLUSH = 128
+	Logger = NewLogger()
 )
 
 // Setups websockets and return an error if setup fails.
 // If error is nil, websockets are started in the background.
 // Websockets can be stopped by canceling the context.
+// The other settings are read from config.Global.Websockets.
 func Init(ctx context.Context, connectionLimit int) (jobs.NamedFunction, error) {
 	Logger.Infof("Starting Websocket goroutine for pool prices with connection limit %d", connectionLimit)
 
@@ -53,51 +54,82 @@ func Init(ctx context.Context, connectionLimit int) (jobs.NamedFunction, error)
 
 	// Start connectionManger
 	var err error
-	connManager, err = ConnectionManagerInit(connectionLimit)
+	connManager, err = ConnectionManagerInit(connectionLimit, config.Global.Websockets.PerIPLimit)
 	if err != nil {
 		return jobs.EmptyJob(), fmt.Errorf("Can't create the connectionManager %v", err)
 	}
+	theHub = newHub(config.Global.Websockets.ResumeBlocks)
 
-	job := jobs.Later("websockets", func() {
-		serve(ctx, connectionLimit)
+	// Websockets are not essential, they are restarted instead of shutting down Midgard.
+	job := jobs.Supervised(ctx, "websockets", jobs.RestartOnFailure, func() error {
+		return serve(ctx)
 	})
 	return job, nil
 }
 
-func serve(ctx context.Context, connectionLimit int) {
+// Returns an error if the reading of the client messages fails, so the supervisor restarts it.
+func serve(ctx context.Context) error {
+	readCtx, stopRead := context.WithCancel(ctx)
+	readErr := make(chan error, 1)
 	readJob := jobs.Start("websocketsRead", func() {
-		readMessagesWaiting(ctx)
+		readErr <- jobs.Recovered(func() error {
+			return readMessagesWaiting(readCtx)
+		})
 	})
-
-	for waitForBlock(ctx) {
-		Logger.Info("Sending push price updates")
-
-		notifyClients()
+	defer func() {
+		stopRead()
+		readJob.MustWait()
+	}()
+
+	var heartbeats <-chan time.Time
+	if interval := config.Global.Websockets.HeartbeatInterval.Value(); 0 < interval {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		heartbeats = ticker.C
 	}
-	readJob.MustWait()
-}
 
-func waitForBlock(ctx context.Context) bool {
-	if ctx.Err() != nil {
-		// Done is already closed, don't even check WebsocketNotify
-		return false
-	}
-	select {
-	case <-*db.WebsocketNotify:
-	case <-ctx.Done():
-		return false
+	for {
+		if ctx.Err() != nil {
+			// Done is already closed, don't even check WebsocketNotify
+			return nil
+		}
+		select {
+		case <-*db.WebsocketNotify:
+			// If more notifications happened, eat all future ones.
+			drainNotifications()
+			Logger.Info("Sending push price updates")
+			notifyClients(ctx)
+		case <-heartbeats:
+			sendHeartbeats()
+		case err := <-readErr:
+			if ctx.Err() != nil {
+				return nil
+			}
+			if err == nil {
+				return errors.New("websocket read loop stopped")
+			}
+			return fmt.Errorf("websocket read loop: %w", err)
+		case <-ctx.Done():
+		}
 	}
+}
 
-	// If more notifications happened, eat all future ones.
-	hadMore := true
-	for hadMore {
+func drainNotifications() {
+	for {
 		select {
 		case <-*db.WebsocketNotify:
 		default:
-			hadMore = false
+			return
 		}
 	}
-	return true
+}
+
+// Number of open websocket connections, 0 if websockets are disabled.
+func ConnectionCount() int {
+	if connManager == nil {
+		return 0
+	}
+	return connManager.Count()
 }
 
 var TestChannel *chan Payload
@@ -112,10 +144,12 @@ func NotifyTest(p Payload) {
 	}
 }
 
-func notifyClients() {
+func notifyClients(ctx context.Context) {
 	// TODO(acsaba): refactor depthrecorder, updates prices only if there was a change.
 	//     Currently for testing purposes we update even if the price stayed the same.
+	// Protocol v2 only sends the changes.
 	state := timeseries.Latest.GetState()
+	clients := connManager.Clients()
 	for pool, info := range state.Pools {
 		payload := &Payload{
 			Price: strconv.FormatFloat(info.AssetPrice(), 'g', -1, 64),
@@ -125,7 +159,26 @@ func notifyClients() {
 		Logger.Info("send price info to websockets: ", payload)
 		NotifyTest(*payload)
 
-		write(payload)
+		write(payload, clients)
+	}
+
+	var statusChanges []timeseries.PoolStatusChange
+	if from := theHub.timestamp(); from != 0 && from < state.Timestamp {
+		var err error
+		statusChanges, err = timeseries.PoolStatusChangesBetween(ctx, from, state.Timestamp)
+		if err != nil {
+			Logger.Warnf("Failed to read the pool status changes %v", err)
+		}
+	}
+	theHub.publish(state, timeseries.RunePriceUSDForDepths(state.Pools), statusChanges, clients)
+}
+
+func sendHeartbeats() {
+	height := theHub.height()
+	for _, c := range connManager.Clients() {
+		if c.version == ProtocolV2 {
+			c.sendMessage(Message{Type: TypeHeartbeat, Height: height})
+		}
 	}
 }
 
@@ -135,10 +188,11 @@ var (
 )
 
 // Listens for connections subscribing/unsubscribing from pools.
-func readMessagesWaiting(ctx context.Context) {
+// Returns an error if waiting on the connections fails, nil on cancellation.
+func readMessagesWaiting(ctx context.Context) error {
 	for {
 		if ctx.Err() != nil {
-			return
+			return nil
 		}
 		waitTimer := recieveWaitTimer.One()
 		connections, err := connManager.WaitOnReceive()
@@ -150,29 +204,32 @@ func readMessagesWaiting(ctx context.Context) {
 			if err.Error() == "interrupted system call" {
 				continue
 			}
-			Logger.Warnf("Failed to epoll wait %v", err)
-			return
+			return fmt.Errorf("epoll wait: %w", err)
 		}
 
-		for fd, conn := range connections {
-			if conn == nil {
+		for fd, c := range connections {
+			if c == nil {
 				Logger.Warnf("Nil connections in the pool, this shouldn't happen.")
-				connManager.Remove(connManager.connections[fd])
 				continue
 			}
 
-			msg, _, err := wsutil.ReadClientData(conn)
+			msg, _, err := wsutil.ReadClientData(c.conn)
 			if err != nil {
 				Logger.Warnf("Error reading from socket %v", err)
-				clearConnEntirely(fd, "unable to read msg waiting from socket")
+				c.close()
 				continue
 			}
 
 			Logger.Infof("received msg \n %s", string(msg))
+			if c.version == ProtocolV2 {
+				handleRequest(c, msg)
+				continue
+			}
+
 			i := &Instruction{}
 
 			if err := json.Unmarshal(msg, i); err != nil {
-				Logger.Warnf("Error marshaling message from %s \n closing connection", nameConn(conn))
+				Logger.Warnf("Error marshaling message from %s \n closing connection", c.name())
 				clearConnEntirely(fd, "unable to unmarshall message from connection, check format of payload sent")
 				continue
 			}
@@ -200,9 +257,9 @@ func readMessagesWaiting(ctx context.Context) {
 			}
 
 			if i.Message == MessageConnect {
-				subscribeToPools(fd, validPools, conn)
+				subscribeToPools(c, validPools)
 			} else if i.Message == MessageDisconnect {
-				unsubscribeFromPools(fd, validPools)
+				unsubscribeFromPools(c, validPools)
 			} else {
 				// What the hell is this.
 				clearConnEntirely(fd, fmt.Sprintf("Message not recognized, %s", i.Message))
@@ -212,153 +269,60 @@ func readMessagesWaiting(ctx context.Context) {
 	}
 }
 
-func write(update *Payload) {
-	// Only write to connections concerned with update.Asset.
-	connManager.assetMutex.RLock()
-	defer connManager.assetMutex.RUnlock()
-	assetConns, ok := connManager.assetFDs[update.Asset]
-
-	if !ok {
-		Logger.Infof("no pool in memory for %s, ignoring ...", update.Asset)
-		return
-	}
-
+// Queues the price update for the v1 connections subscribed to update.Asset. Slow readers are
+// disconnected when their queue is full, the others are not held up.
+func write(update *Payload, clients []*client) {
 	payload, err := json.Marshal(update)
 	if err != nil {
 		Logger.Warnf("marshalling err on write %v", err)
 		return
 	}
 
-	for fd, connectionAttempts := range assetConns {
-		// TODO(kano): get the connections mutex.
-		// Note(acsaba):
-		//   I think we need the mutex of connections here.
-		//   Probably not the case programatically, but this might even modify connections.
-		writer := wsutil.NewWriterSize(connManager.connections[fd], ws.StateServerSide, ws.OpText, MAX_BYTE_LENGTH_FLUSH)
-		if _, err := io.Copy(writer, bytes.NewReader(payload)); err != nil {
-			Logger.Infof("Failed to copy message to buffer %v", err)
-			return
+	for _, c := range clients {
+		if c.version != ProtocolV1 {
+			continue
 		}
-		// TODO(kano): test that 2nd websocket get's notified if the first sleeps.
-		if err := writer.Flush(); err != nil {
-			if connectionAttempts >= MAX_FLUSH_ATTEMPT {
-				clearConnEntirely(fd, "3 attempted flushs to connection have failed, disconnecting")
-			} else {
-				connManager.assetMutex.Lock()
-				connManager.assetFDs[update.Asset][fd] = connectionAttempts + 1
-				connManager.assetMutex.Unlock()
-				continue
-			}
-			// reset connection attempts
-			connManager.assetMutex.Lock()
-			connManager.assetFDs[update.Asset][fd] = INIT_FLUSH_COUNT
-			connManager.assetMutex.Unlock()
+		c.mu.Lock()
+		subscribed := c.assets[update.Asset]
+		c.mu.Unlock()
+		if subscribed {
+			c.enqueue(payload)
 		}
-		// TODO(kano): remove once we are happy with performance.
-		Logger.Infof("Write succeeded for asset %s on connection %d", update.Asset, fd)
 	}
 }
 
-func subscribeToPools(fd int, assets []string, conn net.Conn) {
+func subscribeToPools(c *client, assets []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	for _, asset := range assets {
-		// TODO(acsaba): refactor to use less locking/unlicking,
-		//   maybe add one lock grab at the beginning.
-		// In order to keep it working at optimal performance, we need to use the
-		// right type of lock at the write point, reads or write locks
-
-		// Read lock
-		connManager.assetMutex.RLock()
-		assetConns, ok := connManager.assetFDs[asset]
-		connManager.assetMutex.RUnlock()
-
-		if !ok {
-			// Don't have a assetConns, create a new one, easy
-			Logger.Infof("no assetConns in memory for %s, create new assetConns entry", asset)
-			// Stop everything lock, we're writing.
-			connManager.assetMutex.Lock()
-			connManager.assetFDs[asset] = map[int]int{fd: INIT_FLUSH_COUNT}
-			connManager.assetMutex.Unlock()
-			continue
-		}
-
-		// we have assetConns, before we add conn, Double check we don't have it already, buggy clients will be foolish.
-		connManager.assetMutex.RLock()
-		_, okConn := assetConns[fd]
-		if okConn {
-			Logger.Infof("Connection %s already in %s assetConns, ignoring", nameConn(conn), asset)
-			connManager.assetMutex.RUnlock()
-			continue
-		}
-		connManager.assetMutex.RUnlock()
-
-		// Stop everything lock, we're writing, Add connection to existing pool
-		connManager.assetMutex.Lock()
-		connManager.assetFDs[asset][fd] = INIT_FLUSH_COUNT
-		connManager.assetMutex.Unlock()
-
-		Logger.Infof("successfully added connection %s to existing pool: %s, len(%d)", nameConn(conn), asset, len(assetConns))
+		c.assets[asset] = true
 	}
+	Logger.Infof("successfully added connection %s to pools: %s", c.name(), strings.Join(assets, ","))
 }
 
-func unsubscribeFromPools(fd int, assets []string) {
-	Logger.Infof("Unsubscribe connection %d from pools", fd)
+func unsubscribeFromPools(c *client, assets []string) {
+	Logger.Infof("Unsubscribe connection %d from pools", c.fd)
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	for _, asset := range assets {
-		connManager.assetMutex.RLock()
-		assetConns, ok := connManager.assetFDs[asset]
-		if !ok {
-
-			// Don't have a assetConns
-			Logger.Warnf("no assetConns in memory for %s, to unsubscribe conn from, weird", asset)
-			connManager.assetMutex.RUnlock()
-			continue
-		}
-
-		_, okConn := assetConns[fd]
-		if !okConn {
-			Logger.Infof("Connection %d not in assetConns, ignoring %v", fd, asset)
-			connManager.assetMutex.RUnlock()
-			continue
-		}
-
-		connManager.assetMutex.RUnlock()
-
-		// delete it
-		connManager.assetMutex.Lock()
-		delete(assetConns, fd)
-		connManager.assetFDs[asset] = assetConns
-		connManager.assetMutex.Unlock()
-
-		Logger.Infof("successfully removed connection from assetConns: %s, len(%d)", asset, len(assetConns))
+		delete(c.assets, asset)
 	}
 }
 
 func clearConnEntirely(fd int, disconnMsg string) {
-	// TODO(kano): Not locked. Do lock assetFDs
-	// Todo(kano): Consider: if we make a struct where we have the connection and the
-	//   connection attempts together, we might want to put the followed pools there too.
-	// We can redo the structure of how things are done once we have things working well with unit tests
-	// but that forloop below is not expensive but one whereby you have to continuously iterate over arrays of connections
-	// is not ideal nor duplicating any references anywhere, hence the 2 maps in connManager, one with fd -> conn the other with
-	// asset -> fd -> connectionAttempt ->-> quick look up.
-	assets := []string{}
-	connManager.assetMutex.RLock()
-	for asset := range connManager.assetFDs {
-		assets = append(assets, asset)
-	}
-	connManager.assetMutex.RUnlock()
-
-	unsubscribeFromPools(fd, assets)
-	messageAndDisconnect(fd, disconnMsg)
-}
-
-func messageAndDisconnect(fd int, message string) {
-	Logger.Infof("messageAndDisconnect %s for conn %d", message, fd)
-	con := connManager.GetConnection(fd)
-	if con == nil {
+	c := connManager.GetConnection(fd)
+	if c == nil {
 		Logger.Warn("Was not able to find connection:", fd)
 		return
 	}
-	writer := wsutil.NewWriterSize(*con, ws.StateServerSide, ws.OpText, MAX_BYTE_LENGTH_FLUSH)
+	c.mu.Lock()
+	c.assets = map[string]bool{}
+	c.mu.Unlock()
+	messageAndDisconnect(c, disconnMsg)
+}
+
+func messageAndDisconnect(c *client, message string) {
+	Logger.Infof("messageAndDisconnect %s for conn %d", message, c.fd)
 	i := &Instruction{
 		Message: message,
 	}
@@ -366,16 +330,10 @@ func messageAndDisconnect(fd int, message string) {
 	pi, err := json.Marshal(i)
 	if err != nil {
 		Logger.Warnf("marshalling err %v", err)
+		c.close()
 		return
 	}
-
-	if _, err := io.Copy(writer, bytes.NewReader(pi)); err != nil {
-		Logger.Warnf("Failed to copy message to buffer %v", err)
-	} else if err := writer.Flush(); err != nil {
-		Logger.Warnf("Failed to flush buffer %v", err)
-	}
-
-	connManager.Remove(connManager.connections[fd])
+	c.enqueueLast(pi)
 }
 
 // ------------------------------- //
@@ -385,14 +343,43 @@ func nameConn(conn net.Conn) string {
 	return conn.LocalAddr().String() + " > " + conn.RemoteAddr().String()
 }
 
+// The address per_ip_limit applies to, see config.Websockets.RemoteIPHeader.
+func remoteIP(r *http.Request, header string) string {
+	if header != "" {
+		values := r.Header.Values(header)
+		if 0 < len(values) {
+			addrs := strings.Split(values[len(values)-1], ",")
+			if ip := strings.TrimSpace(addrs[len(addrs)-1]); ip != "" {
+				return ip
+			}
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// The protocol is chosen with the version query parameter, v1 is the default.
 func WsHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
 	if connManager == nil {
 		miderr.InternalErr("Websockets are not active").ReportHTTP(w)
 		return
 	}
-	if len(connManager.connections) >= connManager.connLimit {
-		Logger.Info("reject incoming connection, we are maxed out")
-		fmt.Fprintf(w, "max websocket connections on this node, no room left ... ")
+	version := ProtocolV1
+	switch r.URL.Query().Get("version") {
+	case "", "1":
+	case "2":
+		version = ProtocolV2
+	default:
+		miderr.BadRequest("Unknown websocket protocol version, use 1 or 2").ReportHTTP(w)
+		return
+	}
+
+	ip := remoteIP(r, config.Global.Websockets.RemoteIPHeader)
+	if err := connManager.CheckLimits(ip); err != nil {
+		reject(w, err)
 		return
 	}
 
@@ -405,8 +392,35 @@ func WsHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
 		Logger.Warn("Failed to upgrade connection: ", err)
 		return
 	}
-	if err := connManager.Add(conn); err != nil {
-		Logger.Warnf("Failed to add connection to EPoll %v", err)
+
+	c := newClient(conn, ip, version, config.Global.Websockets.SendQueueSize)
+	if err := connManager.Add(c); err != nil {
+		Logger.Warnf("Failed to add connection %v", err)
 		conn.Close()
+		return
+	}
+	go c.writeLoop(config.Global.Websockets.WriteTimeout.Value())
+
+	if version == ProtocolV2 {
+		c.sendMessage(Message{
+			Type:   TypeWelcome,
+			Height: theHub.height(),
+			Data: WelcomeData{
+				HeartbeatInterval: config.Global.Websockets.HeartbeatInterval.Value().String(),
+				ResumeBlocks:      config.Global.Websockets.ResumeBlocks,
+			},
+		})
+	}
+}
+
+func reject(w http.ResponseWriter, err error) {
+	reason := "connection_limit"
+	status := http.StatusServiceUnavailable
+	if errors.Is(err, errPerIPLimit) {
+		reason = "per_ip_limit"
+		status = http.StatusTooManyRequests
 	}
+	rejectedConnections(reason).Add(1)
+	Logger.Info("reject incoming connection: ", err)
+	http.Error(w, err.Error(), status)
 }
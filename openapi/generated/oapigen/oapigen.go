@@ -37,6 +37,66 @@ const (
 	ActionTypeWithdraw ActionType = "withdraw"
 )
 
+// Defines values for DepthChangeReason.
+const (
+	DepthChangeReasonAdd DepthChangeReason = "add"
+
+	DepthChangeReasonDonate DepthChangeReason = "donate"
+
+	DepthChangeReasonErrata DepthChangeReason = "errata"
+
+	DepthChangeReasonFee DepthChangeReason = "fee"
+
+	DepthChangeReasonGas DepthChangeReason = "gas"
+
+	DepthChangeReasonPoolBalanceChange DepthChangeReason = "pool_balance_change"
+
+	DepthChangeReasonReward DepthChangeReason = "reward"
+
+	DepthChangeReasonSlash DepthChangeReason = "slash"
+
+	DepthChangeReasonSuspension DepthChangeReason = "suspension"
+
+	DepthChangeReasonSwap DepthChangeReason = "swap"
+
+	DepthChangeReasonWithdraw DepthChangeReason = "withdraw"
+)
+
+// Defines values for JobHealthRestartPolicy.
+const (
+	JobHealthRestartPolicyAlways JobHealthRestartPolicy = "always"
+
+	JobHealthRestartPolicyNever JobHealthRestartPolicy = "never"
+
+	JobHealthRestartPolicyOnFailure JobHealthRestartPolicy = "on-failure"
+)
+
+// Defines values for JobHealthState.
+const (
+	JobHealthStateRestarting JobHealthState = "restarting"
+
+	JobHealthStateRunning JobHealthState = "running"
+)
+
+// Defines values for NodeEventType.
+const (
+	NodeEventTypeBondCost NodeEventType = "bond_cost"
+
+	NodeEventTypeBondPaid NodeEventType = "bond_paid"
+
+	NodeEventTypeBondReturned NodeEventType = "bond_returned"
+
+	NodeEventTypeBondReward NodeEventType = "bond_reward"
+
+	NodeEventTypeIpAddress NodeEventType = "ip_address"
+
+	NodeEventTypeSlashPoints NodeEventType = "slash_points"
+
+	NodeEventTypeStatus NodeEventType = "status"
+
+	NodeEventTypeVersion NodeEventType = "version"
+)
+
 // action details among with related transactions
 type Action struct {
 	// Int64, nano timestamp of the block at which the action was registered
@@ -77,6 +137,59 @@ type AddLiquidityMetadata struct {
 	LiquidityUnits string `json:"liquidityUnits"`
 }
 
+// AffiliateHistory defines model for AffiliateHistory.
+type AffiliateHistory struct {
+	Intervals AffiliateHistoryIntervals `json:"intervals"`
+	Meta      AffiliateHistoryItem      `json:"meta"`
+}
+
+// AffiliateHistoryIntervals defines model for AffiliateHistoryIntervals.
+type AffiliateHistoryIntervals []AffiliateHistoryItem
+
+// AffiliateHistoryItem defines model for AffiliateHistoryItem.
+type AffiliateHistoryItem struct {
+	Affiliates Affiliates `json:"affiliates"`
+
+	// Int64, The end time of bucket in unix timestamp
+	EndTime string `json:"endTime"`
+
+	// Int64(e8), fees earned by the affiliates in rune
+	Fees string `json:"fees"`
+
+	// Int64, The beginning time of bucket in unix timestamp
+	StartTime string `json:"startTime"`
+
+	// Int64, number of swaps with an affiliate in the interval
+	SwapCount string `json:"swapCount"`
+
+	// Int64(e8), volume of the swaps with an affiliate in rune
+	Volume string `json:"volume"`
+
+	// Int64(e8), volume of the swaps with an affiliate in USD
+	VolumeUsd string `json:"volumeUsd"`
+}
+
+// AffiliateStats defines model for AffiliateStats.
+type AffiliateStats struct {
+	// Affiliate address or THORName as given in the swap memo
+	Affiliate string `json:"affiliate"`
+
+	// Int64(e8), fees earned by the affiliate in rune
+	Fees string `json:"fees"`
+
+	// Int64, number of swaps with the affiliate
+	SwapCount string `json:"swapCount"`
+
+	// Int64(e8), volume of the swaps with the affiliate in rune
+	Volume string `json:"volume"`
+
+	// Int64(e8), volume of the swaps with the affiliate in USD
+	VolumeUsd string `json:"volumeUsd"`
+}
+
+// Affiliates defines model for Affiliates.
+type Affiliates []AffiliateStats
+
 // Balance defines model for Balance.
 type Balance struct {
 	Coins Coins `json:"coins"`
@@ -133,6 +246,50 @@ type BoolConstants struct {
 	StrictBondLiquidityRatio bool `json:"StrictBondLiquidityRatio"`
 }
 
+// CacheHealth defines model for CacheHealth.
+type CacheHealth struct {
+	// Seconds since the last refresh, missing if the cache wasn't calculated yet
+	Age *int `json:"age,omitempty"`
+
+	// Error of the last refresh
+	Error *string `json:"error,omitempty"`
+	Name  string  `json:"name"`
+}
+
+// ChurnItem defines model for ChurnItem.
+type ChurnItem struct {
+	// Int64(e8), total bond of the nodes after the churn block
+	BondAfter string `json:"bondAfter"`
+
+	// Int64(e8), total bond of the nodes before the churn block
+	BondBefore string `json:"bondBefore"`
+
+	// Int64, nano timestamp of the churn block
+	Date string `json:"date"`
+
+	// Int64, seconds from the churn until the last vault was retired. Missing if no
+	// vault was retired yet.
+	Duration *string `json:"duration,omitempty"`
+
+	// Int64, height of the churn block
+	Height string `json:"height"`
+
+	// Pubkeys of the vaults activated in the churn
+	NewVaults []string `json:"newVaults"`
+
+	// Addresses of the nodes which became active
+	NodesJoined []string `json:"nodesJoined"`
+
+	// Addresses of the nodes which stopped being active
+	NodesLeft []string `json:"nodesLeft"`
+
+	// Pubkeys of the vaults retired after the churn, before the next one
+	RetiredVaults []string `json:"retiredVaults"`
+}
+
+// Churns defines model for Churns.
+type Churns []ChurnItem
+
 // Represents a digital currency amount
 type Coin struct {
 	// Int64(e8), asset Amount.
@@ -152,6 +309,66 @@ type Constants struct {
 	StringValues StringConstants `json:"string_values"`
 }
 
+// DatabaseHealth defines model for DatabaseHealth.
+type DatabaseHealth struct {
+	Idle            int  `json:"idle"`
+	InUse           int  `json:"inUse"`
+	OpenConnections int  `json:"openConnections"`
+	Reachable       bool `json:"reachable"`
+
+	// Int64, number of times a query waited for a connection
+	WaitCount string `json:"waitCount"`
+
+	// Int64, total time spent waiting for a connection
+	WaitDurationMs string `json:"waitDurationMs"`
+}
+
+// DepthChange defines model for DepthChange.
+type DepthChange struct {
+	// Int64(e8), asset depth change caused by these events
+	AssetE8 string `json:"assetE8"`
+
+	// Kind of the events which changed the depths
+	Reason DepthChangeReason `json:"reason"`
+
+	// Int64(e8), rune depth change caused by these events
+	RuneE8 string `json:"runeE8"`
+
+	// Int64(e8), synth supply change caused by these events
+	SynthE8 string `json:"synthE8"`
+}
+
+// Kind of the events which changed the depths
+type DepthChangeReason string
+
+// DepthChangesHistory defines model for DepthChangesHistory.
+type DepthChangesHistory struct {
+	Intervals DepthChangesHistoryIntervals `json:"intervals"`
+	Meta      DepthChangesHistoryItem      `json:"meta"`
+}
+
+// DepthChangesHistoryIntervals defines model for DepthChangesHistoryIntervals.
+type DepthChangesHistoryIntervals []DepthChangesHistoryItem
+
+// DepthChangesHistoryItem defines model for DepthChangesHistoryItem.
+type DepthChangesHistoryItem struct {
+	// Int64(e8), change of the asset depth during the interval
+	AssetDelta string        `json:"assetDelta"`
+	Changes    []DepthChange `json:"changes"`
+
+	// Int64, The end time of bucket in unix timestamp
+	EndTime string `json:"endTime"`
+
+	// Int64(e8), change of the rune depth during the interval
+	RuneDelta string `json:"runeDelta"`
+
+	// Int64, The beginning time of bucket in unix timestamp
+	StartTime string `json:"startTime"`
+
+	// Int64(e8), change of the synth supply during the interval
+	SynthDelta string `json:"synthDelta"`
+}
+
 // DepthHistory defines model for DepthHistory.
 type DepthHistory struct {
 	Intervals DepthHistoryIntervals `json:"intervals"`
@@ -356,16 +573,55 @@ type Health struct {
 	Database bool `json:"database"`
 
 	// True means healthy. False means Midgard is still catching up to the chain
-	InSync         bool     `json:"inSync"`
-	LastAggregated HeightTS `json:"lastAggregated"`
-	LastCommitted  HeightTS `json:"lastCommitted"`
-	LastFetched    HeightTS `json:"lastFetched"`
-	LastThorNode   HeightTS `json:"lastThorNode"`
+	InSync bool `json:"inSync"`
+
+	// Background jobs which are running
+	Jobs           []JobHealth `json:"jobs"`
+	LastAggregated HeightTS    `json:"lastAggregated"`
+	LastCommitted  HeightTS    `json:"lastCommitted"`
+	LastFetched    HeightTS    `json:"lastFetched"`
+	LastThorNode   HeightTS    `json:"lastThorNode"`
 
 	// Int64, the current block count
 	ScannerHeight string `json:"scannerHeight"`
 }
 
+// HealthCheck defines model for HealthCheck.
+type HealthCheck struct {
+	// The reason of the failure, missing if the check passed
+	Message *string `json:"message,omitempty"`
+	Name    string  `json:"name"`
+	Ok      bool    `json:"ok"`
+}
+
+// HealthReport defines model for HealthReport.
+type HealthReport struct {
+	// Seconds since the last aggregated block was produced
+	AggregatesLag int `json:"aggregatesLag"`
+
+	// Int64, the last block available in the local blockstore
+	BlockStoreHeight string         `json:"blockStoreHeight"`
+	Caches           []CacheHealth  `json:"caches"`
+	ChainTip         HeightTS       `json:"chainTip"`
+	Checks           []HealthCheck  `json:"checks"`
+	Database         DatabaseHealth `json:"database"`
+
+	// Number of blocks the fetching is behind the chain tip
+	FetchLag int `json:"fetchLag"`
+
+	// Seconds since the last block committed to the DB was produced
+	LastCommitAge int `json:"lastCommitAge"`
+
+	// True if all the checks passed
+	Ready bool `json:"ready"`
+
+	// False if all the configured ThorNode or Tendermint endpoints are failing
+	ThorNodeReachable bool `json:"thorNodeReachable"`
+
+	// Number of open websocket connections
+	WebsocketConnections int `json:"websocketConnections"`
+}
+
 // HeightTS defines model for HeightTS.
 type HeightTS struct {
 	// Block height
@@ -430,6 +686,30 @@ type Int64Constants struct {
 	YggFundLimit                  int64  `json:"YggFundLimit"`
 }
 
+// JobHealth defines model for JobHealth.
+type JobHealth struct {
+	// Number of times the job failed since Midgard started
+	Crashes int `json:"crashes"`
+
+	// The error of the last failure, missing if there was none
+	LastError *string `json:"lastError,omitempty"`
+	Name      string  `json:"name"`
+
+	// What happens when the job fails. With never Midgard shuts down, otherwise the job is
+	// restarted with exponential backoff. Missing for jobs without a supervisor.
+	RestartPolicy *JobHealthRestartPolicy `json:"restartPolicy,omitempty"`
+
+	// Restarting jobs failed and are waiting to be started again
+	State JobHealthState `json:"state"`
+}
+
+// What happens when the job fails. With never Midgard shuts down, otherwise the job is
+// restarted with exponential backoff. Missing for jobs without a supervisor.
+type JobHealthRestartPolicy string
+
+// Restarting jobs failed and are waiting to be started again
+type JobHealthState string
+
 // LPDetail defines model for LPDetail.
 type LPDetail struct {
 	// asset address
@@ -547,6 +827,11 @@ type LiquidityHistoryItem struct {
 	WithdrawVolume string `json:"withdrawVolume"`
 }
 
+// Liveness defines model for Liveness.
+type Liveness struct {
+	Alive bool `json:"alive"`
+}
+
 // MemberDetails defines model for MemberDetails.
 type MemberDetails struct {
 	// List details of all the liquidity providers identified with the given address
@@ -603,6 +888,24 @@ type Metadata struct {
 	Withdraw     *WithdrawMetadata     `json:"withdraw,omitempty"`
 }
 
+// Mimir defines model for Mimir.
+type Mimir []MimirItem
+
+// MimirItem defines model for MimirItem.
+type MimirItem struct {
+	// Int64, nano timestamp of the block where the value was set
+	Date string `json:"date"`
+
+	// Int64, height of the block where the value was set
+	Height string `json:"height"`
+
+	// Mimir key, upper case
+	Key string `json:"key"`
+
+	// Value set for the key
+	Value string `json:"value"`
+}
+
 // Network defines model for Network.
 type Network struct {
 	// Array of rune amounts (e8) bonded by each active node.
@@ -665,6 +968,100 @@ type Node struct {
 	Secp256k1 string `json:"secp256k1"`
 }
 
+// NodeEvent defines model for NodeEvent.
+type NodeEvent struct {
+	// Int64, for bond events the amount of RUNE in e8, for slash_points the number of points
+	Amount *string `json:"amount,omitempty"`
+
+	// Status after the change, for status events
+	CurrentStatus *string `json:"currentStatus,omitempty"`
+
+	// Int64, nano timestamp of the block containing the event
+	Date string `json:"date"`
+
+	// Status before the change, for status events
+	FormerStatus *string `json:"formerStatus,omitempty"`
+
+	// Int64, height of the block containing the event
+	Height string `json:"height"`
+
+	// Ip address set by the node, for ip_address events
+	IpAddress *string `json:"ipAddress,omitempty"`
+
+	// Reason of the slash points
+	Reason *string `json:"reason,omitempty"`
+
+	// Transaction id, for bond events
+	TxId *string `json:"txId,omitempty"`
+
+	// Type of the event
+	Type NodeEventType `json:"type"`
+
+	// Version set by the node, for version events
+	Version *string `json:"version,omitempty"`
+}
+
+// Type of the event
+type NodeEventType string
+
+// NodeHistory defines model for NodeHistory.
+type NodeHistory struct {
+	Intervals NodeHistoryIntervals `json:"intervals"`
+	Meta      NodeHistoryItem      `json:"meta"`
+}
+
+// NodeHistoryIntervals defines model for NodeHistoryIntervals.
+type NodeHistoryIntervals []NodeHistoryItem
+
+// NodeHistoryItem defines model for NodeHistoryItem.
+type NodeHistoryItem struct {
+	// Int64(e8), bond of the node at the end of the interval
+	Bond string `json:"bond"`
+
+	// Int64, The end time of bucket in unix timestamp
+	EndTime string `json:"endTime"`
+
+	// Int64(e8), bond rewards paid to the node in the interval
+	Rewards string `json:"rewards"`
+
+	// Int64, slash points received in the interval
+	SlashPoints string `json:"slashPoints"`
+
+	// Int64(e8), bond slashed from the node in the interval
+	Slashed string `json:"slashed"`
+
+	// Int64, The beginning time of bucket in unix timestamp
+	StartTime string `json:"startTime"`
+}
+
+// NodeMimir defines model for NodeMimir.
+type NodeMimir struct {
+	// Int64, number of active nodes, the votes are counted from these nodes
+	ActiveNodeCount string           `json:"activeNodeCount"`
+	Keys            []NodeMimirTally `json:"keys"`
+}
+
+// NodeMimirTally defines model for NodeMimirTally.
+type NodeMimirTally struct {
+	// Mimir key, upper case
+	Key string `json:"key"`
+
+	// Votes per value, the most voted first
+	Votes []NodeMimirVote `json:"votes"`
+}
+
+// NodeMimirVote defines model for NodeMimirVote.
+type NodeMimirVote struct {
+	// Value voted for
+	Value string `json:"value"`
+
+	// Int64, number of active nodes voting for the value
+	Votes string `json:"votes"`
+}
+
+// NodeTimeline defines model for NodeTimeline.
+type NodeTimeline []NodeEvent
+
 // Nodes defines model for Nodes.
 type Nodes []Node
 
@@ -944,6 +1341,40 @@ type RefundMetadata struct {
 // ReverseTHORNames defines model for ReverseTHORNames.
 type ReverseTHORNames []string
 
+// RunePriceHistory defines model for RunePriceHistory.
+type RunePriceHistory struct {
+	Intervals RunePriceHistoryIntervals `json:"intervals"`
+	Meta      RunePriceHistoryItem      `json:"meta"`
+}
+
+// RunePriceHistoryIntervals defines model for RunePriceHistoryIntervals.
+type RunePriceHistoryIntervals []RunePriceHistoryItem
+
+// RunePriceHistoryItem defines model for RunePriceHistoryItem.
+type RunePriceHistoryItem struct {
+	// Int64, The end time of bucket in unix timestamp
+	EndTime string `json:"endTime"`
+
+	// Float, the highest price of Rune in USD during the interval.
+	HighPriceUSD string `json:"highPriceUSD"`
+
+	// Float, the lowest price of Rune in USD during the interval.
+	LowPriceUSD string `json:"lowPriceUSD"`
+
+	// The aggregation method of the last price: deepest, median or mean.
+	Method string `json:"method"`
+
+	// The USD pools that contributed to the last price.
+	Pools []string `json:"pools"`
+
+	// Float, the price of Rune in USD at the end of the interval. NaN if there was no
+	// price recorded yet.
+	RunePriceUSD string `json:"runePriceUSD"`
+
+	// Int64, The beginning time of bucket in unix timestamp
+	StartTime string `json:"startTime"`
+}
+
 // StatsData defines model for StatsData.
 type StatsData struct {
 	// Int64, number of deposits since beginning.
@@ -1127,6 +1558,24 @@ type SwapMetadata struct {
 	SwapTarget string `json:"swapTarget"`
 }
 
+// SwapSizeBin defines model for SwapSizeBin.
+type SwapSizeBin struct {
+	// Int64(e8), the smallest swap size in rune which falls into the bin
+	LowerBound string `json:"lowerBound"`
+
+	// Int64, number of swaps in the bin
+	SwapCount string `json:"swapCount"`
+
+	// Int64(e8), the swaps in the bin are smaller than this. Missing for the last bin.
+	UpperBound *string `json:"upperBound,omitempty"`
+
+	// Int64(e8), volume of the swaps in the bin in rune
+	Volume string `json:"volume"`
+}
+
+// SwapSizeHistogram defines model for SwapSizeHistogram.
+type SwapSizeHistogram []SwapSizeBin
+
 // THORNameDetails defines model for THORNameDetails.
 type THORNameDetails struct {
 	// List details of all chains and their addresses for a given THORName
@@ -1183,6 +1632,24 @@ type TVLHistoryItem struct {
 	TotalValuePooled string `json:"totalValuePooled"`
 }
 
+// TraderStats defines model for TraderStats.
+type TraderStats struct {
+	// Address which initiated the swaps
+	Address string `json:"address"`
+
+	// Int64, number of swaps by the address
+	SwapCount string `json:"swapCount"`
+
+	// Int64(e8), volume of the swaps by the address in rune
+	Volume string `json:"volume"`
+
+	// Int64(e8), volume of the swaps by the address in USD
+	VolumeUsd string `json:"volumeUsd"`
+}
+
+// Traders defines model for Traders.
+type Traders []TraderStats
+
 // Transaction data
 type Transaction struct {
 	// Sender address
@@ -1194,6 +1661,40 @@ type Transaction struct {
 	TxID string `json:"txID"`
 }
 
+// UsersHistory defines model for UsersHistory.
+type UsersHistory struct {
+	Intervals UsersHistoryIntervals `json:"intervals"`
+	Meta      UsersHistoryItem      `json:"meta"`
+}
+
+// UsersHistoryIntervals defines model for UsersHistoryIntervals.
+type UsersHistoryIntervals []UsersHistoryItem
+
+// UsersHistoryItem defines model for UsersHistoryItem.
+type UsersHistoryItem struct {
+	// Int64, number of addresses which swapped in the interval
+	ActiveSwappers string `json:"activeSwappers"`
+
+	// Int64, The end time of bucket in unix timestamp
+	EndTime string `json:"endTime"`
+
+	// Int64, number of addresses which swapped the first time in the interval
+	NewSwappers string `json:"newSwappers"`
+
+	// Int64, number of addresses which swapped before the interval too
+	ReturningSwappers string `json:"returningSwappers"`
+
+	// Int64, The beginning time of bucket in unix timestamp
+	StartTime string `json:"startTime"`
+
+	// Int64, number of swaps in the interval
+	SwapCount string            `json:"swapCount"`
+	SwapSizes SwapSizeHistogram `json:"swapSizes"`
+
+	// Int64(e8), volume of the swaps in rune
+	Volume string `json:"volume"`
+}
+
 // WithdrawMetadata defines model for WithdrawMetadata.
 type WithdrawMetadata struct {
 	// Decimal (-1.0 <=> 1.0), indicates how assymetrical the withdrawal was. 0 means
@@ -1262,12 +1763,24 @@ type ActionsResponse struct {
 	Count string `json:"count"`
 }
 
+// AffiliateHistoryResponse defines model for AffiliateHistoryResponse.
+type AffiliateHistoryResponse AffiliateHistory
+
+// AffiliatesResponse defines model for AffiliatesResponse.
+type AffiliatesResponse Affiliates
+
 // BalanceResponse defines model for BalanceResponse.
 type BalanceResponse Balance
 
+// ChurnsResponse defines model for ChurnsResponse.
+type ChurnsResponse Churns
+
 // ConstantsResponse defines model for ConstantsResponse.
 type ConstantsResponse Constants
 
+// DepthChangesHistoryResponse defines model for DepthChangesHistoryResponse.
+type DepthChangesHistoryResponse DepthChangesHistory
+
 // DepthHistoryResponse defines model for DepthHistoryResponse.
 type DepthHistoryResponse DepthHistory
 
@@ -1277,6 +1790,9 @@ type EarningsHistoryResponse EarningsHistory
 // FullMembersResponse defines model for FullMembersResponse.
 type FullMembersResponse FullMemberDetails
 
+// HealthReportResponse defines model for HealthReportResponse.
+type HealthReportResponse HealthReport
+
 // HealthResponse defines model for HealthResponse.
 type HealthResponse Health
 
@@ -1292,15 +1808,33 @@ type LastblockResponse Lastblock
 // LiquidityHistoryResponse defines model for LiquidityHistoryResponse.
 type LiquidityHistoryResponse LiquidityHistory
 
+// LivenessResponse defines model for LivenessResponse.
+type LivenessResponse Liveness
+
 // MemberDetailsResponse defines model for MemberDetailsResponse.
 type MemberDetailsResponse MemberDetails
 
 // MembersResponse defines model for MembersResponse.
 type MembersResponse Members
 
+// MimirHistoryResponse defines model for MimirHistoryResponse.
+type MimirHistoryResponse Mimir
+
+// MimirResponse defines model for MimirResponse.
+type MimirResponse Mimir
+
 // NetworkResponse defines model for NetworkResponse.
 type NetworkResponse Network
 
+// NodeHistoryResponse defines model for NodeHistoryResponse.
+type NodeHistoryResponse NodeHistory
+
+// NodeMimirResponse defines model for NodeMimirResponse.
+type NodeMimirResponse NodeMimir
+
+// NodeTimelineResponse defines model for NodeTimelineResponse.
+type NodeTimelineResponse NodeTimeline
+
 // NodesResponse defines model for NodesResponse.
 type NodesResponse Nodes
 
@@ -1325,6 +1859,9 @@ type QueueResponse Queue
 // ReverseTHORNameResponse defines model for ReverseTHORNameResponse.
 type ReverseTHORNameResponse ReverseTHORNames
 
+// RunePriceHistoryResponse defines model for RunePriceHistoryResponse.
+type RunePriceHistoryResponse RunePriceHistory
+
 // StatsResponse defines model for StatsResponse.
 type StatsResponse StatsData
 
@@ -1337,6 +1874,12 @@ type THORNameDetailsResponse THORNameDetails
 // TVLHistoryResponse defines model for TVLHistoryResponse.
 type TVLHistoryResponse TVLHistory
 
+// TradersResponse defines model for TradersResponse.
+type TradersResponse Traders
+
+// UsersHistoryResponse defines model for UsersHistoryResponse.
+type UsersHistoryResponse UsersHistory
+
 // GetActionsParams defines parameters for GetActions.
 type GetActionsParams struct {
 	// Comma separated list. Address of sender or recipient of any in/out transaction related
@@ -1364,6 +1907,18 @@ type GetActionsParams struct {
 	Offset *int64 `json:"offset,omitempty"`
 }
 
+// GetAffiliatesParams defines parameters for GetAffiliates.
+type GetAffiliatesParams struct {
+	// Period of the leaderboard. Default is 30d.
+	Period *GetAffiliatesParamsPeriod `json:"period,omitempty"`
+
+	// Number of affiliates to return. Should be between [1..400], default is 50.
+	Limit *int `json:"limit,omitempty"`
+}
+
+// GetAffiliatesParamsPeriod defines parameters for GetAffiliates.
+type GetAffiliatesParamsPeriod string
+
 // GetBalanceParams defines parameters for GetBalance.
 type GetBalanceParams struct {
 	// Unix timestamp as seconds since 1970 (if provided, height must not be provided)
@@ -1373,12 +1928,60 @@ type GetBalanceParams struct {
 	Height *int64 `json:"height,omitempty"`
 }
 
+// GetChurnsParams defines parameters for GetChurns.
+type GetChurnsParams struct {
+	// Number of churns to return. Should be between [1..400], default is 50.
+	Limit *int `json:"limit,omitempty"`
+
+	// Pagination offset, number of churns to skip. Default is 0.
+	Offset *int `json:"offset,omitempty"`
+}
+
 // GetFullMembersAdressesParams defines parameters for GetFullMembersAdresses.
 type GetFullMembersAdressesParams struct {
 	// Return only members present in the pool.
 	Pool *string `json:"pool,omitempty"`
 }
 
+// GetAffiliateHistoryParams defines parameters for GetAffiliateHistory.
+type GetAffiliateHistoryParams struct {
+	// Affiliate address or THORName.
+	Affiliate *string `json:"affiliate,omitempty"`
+
+	// Interval of calculations
+	Interval *GetAffiliateHistoryParamsInterval `json:"interval,omitempty"`
+
+	// Number of intervals to return. Should be between [1..400].
+	Count *int `json:"count,omitempty"`
+
+	// End time of the query as unix timestamp. If only count is given, defaults to now.
+	To *int64 `json:"to,omitempty"`
+
+	// Start time of the query as unix timestamp
+	From *int64 `json:"from,omitempty"`
+}
+
+// GetAffiliateHistoryParamsInterval defines parameters for GetAffiliateHistory.
+type GetAffiliateHistoryParamsInterval string
+
+// GetDepthChangesHistoryParams defines parameters for GetDepthChangesHistory.
+type GetDepthChangesHistoryParams struct {
+	// Interval of calculations
+	Interval *GetDepthChangesHistoryParamsInterval `json:"interval,omitempty"`
+
+	// Number of intervals to return. Should be between [1..400].
+	Count *int `json:"count,omitempty"`
+
+	// End time of the query as unix timestamp. If only count is given, defaults to now.
+	To *int64 `json:"to,omitempty"`
+
+	// Start time of the query as unix timestamp
+	From *int64 `json:"from,omitempty"`
+}
+
+// GetDepthChangesHistoryParamsInterval defines parameters for GetDepthChangesHistory.
+type GetDepthChangesHistoryParamsInterval string
+
 // GetDepthHistoryParams defines parameters for GetDepthHistory.
 type GetDepthHistoryParams struct {
 	// Interval of calculations
@@ -1436,6 +2039,24 @@ type GetLiquidityHistoryParams struct {
 // GetLiquidityHistoryParamsInterval defines parameters for GetLiquidityHistory.
 type GetLiquidityHistoryParamsInterval string
 
+// GetNodeHistoryParams defines parameters for GetNodeHistory.
+type GetNodeHistoryParams struct {
+	// Interval of calculations
+	Interval *GetNodeHistoryParamsInterval `json:"interval,omitempty"`
+
+	// Number of intervals to return. Should be between [1..400].
+	Count *int `json:"count,omitempty"`
+
+	// End time of the query as unix timestamp. If only count is given, defaults to now.
+	To *int64 `json:"to,omitempty"`
+
+	// Start time of the query as unix timestamp
+	From *int64 `json:"from,omitempty"`
+}
+
+// GetNodeHistoryParamsInterval defines parameters for GetNodeHistory.
+type GetNodeHistoryParamsInterval string
+
 // GetOHLCVHistoryParams defines parameters for GetOHLCVHistory.
 type GetOHLCVHistoryParams struct {
 	// Interval of calculations
@@ -1454,6 +2075,24 @@ type GetOHLCVHistoryParams struct {
 // GetOHLCVHistoryParamsInterval defines parameters for GetOHLCVHistory.
 type GetOHLCVHistoryParamsInterval string
 
+// GetRunePriceHistoryParams defines parameters for GetRunePriceHistory.
+type GetRunePriceHistoryParams struct {
+	// Interval of calculations
+	Interval *GetRunePriceHistoryParamsInterval `json:"interval,omitempty"`
+
+	// Number of intervals to return. Should be between [1..400].
+	Count *int `json:"count,omitempty"`
+
+	// End time of the query as unix timestamp. If only count is given, defaults to now.
+	To *int64 `json:"to,omitempty"`
+
+	// Start time of the query as unix timestamp
+	From *int64 `json:"from,omitempty"`
+}
+
+// GetRunePriceHistoryParamsInterval defines parameters for GetRunePriceHistory.
+type GetRunePriceHistoryParamsInterval string
+
 // GetSwapHistoryParams defines parameters for GetSwapHistory.
 type GetSwapHistoryParams struct {
 	// Return history given pool. Returns sum of all pools if missing.
@@ -1514,6 +2153,24 @@ type GetTVLHistoryParams struct {
 // GetTVLHistoryParamsInterval defines parameters for GetTVLHistory.
 type GetTVLHistoryParamsInterval string
 
+// GetUsersHistoryParams defines parameters for GetUsersHistory.
+type GetUsersHistoryParams struct {
+	// Interval of calculations
+	Interval *GetUsersHistoryParamsInterval `json:"interval,omitempty"`
+
+	// Number of intervals to return. Should be between [1..400].
+	Count *int `json:"count,omitempty"`
+
+	// End time of the query as unix timestamp. If only count is given, defaults to now.
+	To *int64 `json:"to,omitempty"`
+
+	// Start time of the query as unix timestamp
+	From *int64 `json:"from,omitempty"`
+}
+
+// GetUsersHistoryParamsInterval defines parameters for GetUsersHistory.
+type GetUsersHistoryParamsInterval string
+
 // GetLPDetailParams defines parameters for GetLPDetail.
 type GetLPDetailParams struct {
 	// Return information for given pools
@@ -1526,6 +2183,21 @@ type GetMembersAdressesParams struct {
 	Pool *string `json:"pool,omitempty"`
 }
 
+// GetNodeTimelineParams defines parameters for GetNodeTimeline.
+type GetNodeTimelineParams struct {
+	// Number of events to return. Should be between [1..400], default is 50.
+	Limit *int `json:"limit,omitempty"`
+
+	// Pagination offset, number of events to skip. Default is 0.
+	Offset *int `json:"offset,omitempty"`
+}
+
+// GetNodeMimirParams defines parameters for GetNodeMimir.
+type GetNodeMimirParams struct {
+	// Mimir key, case insensitive. If missing all keys are returned.
+	Key *string `json:"key,omitempty"`
+}
+
 // GetPoolParams defines parameters for GetPool.
 type GetPoolParams struct {
 	// Specifies the base interval from which APY is extrapolated.
@@ -1562,217 +2234,308 @@ type GetPoolsParamsStatus string
 // GetPoolsParamsPeriod defines parameters for GetPools.
 type GetPoolsParamsPeriod string
 
+// GetTradersParams defines parameters for GetTraders.
+type GetTradersParams struct {
+	// Period of the leaderboard. Default is 30d.
+	Period *GetTradersParamsPeriod `json:"period,omitempty"`
+
+	// Number of addresses to return. Should be between [1..400], default is 50.
+	Limit *int `json:"limit,omitempty"`
+}
+
+// GetTradersParamsPeriod defines parameters for GetTraders.
+type GetTradersParamsPeriod string
+
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/+x97XLbuLLgq6C0e+rYMxxFkr8Sb6Xu2nFykr1O4mt75u7UaNYDkZCEhAQYApSsmcpr",
-	"7Qvsi23hiwRJgKJke+586Pw444hAo9HobjQaje7feiFNUkoQ4ax3+lsvQyylhCH5j7OQY0rYtf5N/BRS",
-	"whHh4k+YpjEOoWjy7BOjRPzGwjlKoPgrzWiKMo4VJKggiT8xR4n8479naNo77f23ZyUGz1R/9kyN3Psa",
-	"9PgqRb3THswyuBL/Dmmuho8QCzOcynanvXeEHx8GgOTJBGWATkGGWB5zBhLIwzkmM8DnCMzwAhEwxTFH",
-	"Gev3CuiMZ5jMel+/Br0MfclxhqLe6U96rKDA/ueiA518QiHvfRU9qohcI55nhAFIgMRZ4KL7gynNXGh8",
-	"DXrnMIYkRFtRuo2OGq4LUTUFIIaBmAgKTVRjECEOcazQhRpZGEUZYkzg+ooSxiHh7NGxLSC78L2d04zQ",
-	"CIGiFTDMKkl4gVI+f4sZp9nq0TGzgbuQk98BJBFIMxwiMDdNg95rmAnqsqdCrQbfhR3STWy03uRx/B4J",
-	"aXn8dSxhXyhW6sZ/Mf6S4wjzFUgzusARykAEOZR8qLgwUQgL/N8iGPP5o6OuwLYJ9ly2AIxDnisZeY+j",
-	"GcwigdU7MqE5ic6UtKDHJ219gFZJeUci2Rqc6dZVibm80svz6FgWkLdeeEymNEug2QUuIeOTmIafHx9V",
-	"A7mVkkWrGgUN4k8l3PUBXEj+J+bzKINLGDOpgSKUUoZ5Rdor0vjoWD6yrEPAUhTiKQ61wJczeCrcnViX",
-	"+3ccy33bUj8fEF/S7PHZUcNdY1k06an7SSJK/Gj0BNpHQt0cNyFBaT6JcQg+o1WB48e3l69+eCrJsYG7",
-	"MJbfbRG5ojR+dCwEUCUXThwapLJNL0oQSCmNDXI3HD6BzVVA9qMpP1dQ6huc2BNSzMloZ0YmBRqGXBKb",
-	"jN5jFD0N39vAW/cJ2aK6R/xHjvLHN+wl1FZUZIsqKtdogTKGbt9+vP4Ak8dHqga/41YAQYwZF2tqOgIi",
-	"etf3AusI8jSioMRAKqcueM9iOoExOH99dbOEaaHVxD+eSqlZsJ2CKvCQh9YALGicJygAU4SkVcBinNrq",
-	"ztD6qWyCGvxuJC0YwGULCKaQmP9w+VT0LUE7RYtyGBvFk/I5CwCXP00oiVggyax+WMA4R0DYiygqif41",
-	"0GhYzpWmJ0M5C4qdACaUzMAS8znIUAw5igDPIGHGJxHUPC0R5MjvHoGEAo4TxDhMUiFywqxRhi3kYDnH",
-	"4Vz+pJFYQqHMZphxlKGo6S8JenOEZ3O/P0Z9foyBMHENoo43NkFKKlFrgF7Qze90W0JyOZ8SxKGU87VG",
-	"pW73NejR3EGejzn/nVEXbMuaiMhNHGCyoPECRQATz8iN9ajDV+dh1xpFQhYRA3hqrzhmQEwiRmLCNBNf",
-	"zfSpkzgwQ4BxHMdjkiISYTLrjwWKiORJ7/SnHsvDUGwPQU9/ttx1dbTrSN6uUlQ66mygS5j2gh6MouIk",
-	"1gt6S33i6gW9iBIhcEEvQ9OcCM5lS8zDuWPwmnNRLYhuVdBPMrpimkDJciFjFvs1HZFB78zC8b3Fp1X1",
-	"UJy7vieYM6/cwkTsIoIk5UEtFz0AZAzPSMmk6kwEpPyyPC5kveg3JvpAKlernSY17FzTNB7NxsxCiglb",
-	"72EUjb4GHjU5zcVJr9CPe0JfMhQK9Q4YJiECwxcng/0WfZbJgxCKCmcqIzBlc8qlilvAGAseQfdQsH7v",
-	"tPfi8Pj4+eHzwXBg/reJll2rXjdFZ+TDobZQBUtqFlXEd66X8tosYRax5qJNyq9OLSN21pbPqTyzeT7X",
-	"ULaHqgCugHFOgZLoPeIZDh0zgAuUwRkSu/kCiZYemdpDz/cDcKZaS4vBKJwFAkQeLBwLr6HfcEiiyWpj",
-	"8Ez188NP4D1O8qQj9u/hPSZ50hl7Db0r9u9V8w2wRxGGpCvysnF33GXzzqhXga/HHJNN6C6ovgndFfTO",
-	"yNfAr8VeGrkdcb8tLOQumEvIXfGugl6DdU0b1KcQOATZwWCulXNJkWMmTmF28Zlz/ZzC5FZWNC7v0Rrq",
-	"6kaoMS56F8bCtTgpiW/FNjCFMUMF7AmlMYKkQUIvKBdaYuNtLuY1SjPExA4NIIjwDIsFDfMsQyRcaSOk",
-	"ccLRP7dxBmQMcXAmG/adelU0aII4k/0wAa/enr370L/58f35x0ugbiLWspSCGRj8fFTofgkuaeYwtVuW",
-	"V6zVnTx9roVeZRR5wuJ3x4cde0tiV7orqnTsfiMb2/e9VVpWcQkq86oP5SJ05b62QSVMOMoWMGabXPu+",
-	"Kzrpg+AmnYVB3pikBBJY2KybyTsb704sVOnNUeJip0abpo0jOFs2a5U6eborDg6FLImfpdMEcvk3Uvpa",
-	"/Gmm7hXRqwyHDjv9TUwhD/Rdu9hWzFhZTlAfvOujvvzToPNMtyg0Ssto399ceAeUU2kM+v3NBdibQCaO",
-	"rmq6EUIpYlx+EVPfdyohRKJbnPi9NbeaWOJAIgac5OFnNWJO8H15TnHB7njOKzQ3kO0esFxxvsBeut3a",
-	"B0J5kNQuMkwidN8HN18yvldyGfhGrp78e/9Z7QTqOkYGvaL9Bhx6LThk+xkzDjO+dgEnaIaJdG5us4xs",
-	"Rfj8Jk/TeOUd5Ua0AUw2esh0BJh2blEjPZRT8tYxlFGnxtgrcQLfgipL73fEoIPXoVzIUiYDW+nZ7FVR",
-	"THW90ZC7Clmry2kIoUVnne5/j1zuHESis6dWzvKT4F1NG7cAIhJdXj2FsqkP7xn8+mkVQGci3DyFGHWk",
-	"wdPtJvkCvyNhhiBDWyp5gHV/MEF8iRABhdjJi5NWuso992aOp/ySMtaKAU5SlCVQmEEgpowVwwl6TnHG",
-	"uBwuhoyrWxwgDCmvfn9c6ZIgfYs7JgVF3FSQnx9ByFqxAF2QeExhezg2Dxe5h+PwpHZAp/2qJiM1oW2y",
-	"c2MpawzWJG5Q225qereyB9R1oWt7q0eubn9Oq0Ha+KhW7y9PStuc1ryIdD2wOTFpntlczRyu6dkHGqFX",
-	"bnfJVOhMwZ3GaVyKqu2lA1GemfB5ybltpt2k5uxf57rTEZWyPUAJ5hxFmw1I5VWfIUfrmDdzmEnBK8Kh",
-	"mdgkON1ioqjTgCvGUSI2PpogMEMEZbBtgn3wjgPMlEbKE0CnY1JuqFOEVHxnhWpeO2QjW6BQeZudLR9I",
-	"dnkDuhHZi5HfINSFwarUC0BIyQJl+or9+vsPr8VPcYzCtmXxmCTu+3RDkjKCBcFwrjYb8/yjPs1tFcOV",
-	"Dg6sKwdxWNnMlSH35lYPRotx2n/cbXETXuy0MVaZpqakLFFuahMXqwdVtVqjtuGLLnuDWcEGeSS9UYWR",
-	"WoTE4aq77CwmNfVSCoNlJP2TAePa3k4PqpioYkKYSNEDe/L3Cq7gW6PX9n0y5wiYktZ29W2VbOlyE3XY",
-	"myRyejM0qmpPRjDgBdoHYhz4GREwzWgC9giaQfWhNCrZmEArHELdvYv1w/yfDDCjEFllf5B6QvKm38P1",
-	"OAsrJui9fKsMIfRCh6WtDaY8eWY9c4LEn/slDgFgc7okBpUOHhq9nA7mdtHFO5Ny/S3OdYlq8wFTw7jy",
-	"6P9LLM+VKnbPejrQfO/AAI4Q4XiKUaTi+0r2NaGuHfeGEl33luCMPGqft9FMDu1yFkUo6sAV2uUuWptg",
-	"ISkek5V51uHzw+vXTT5R19QBudivJisrDMnv2dchYesvDaXET3IOCDXIrxAPAOZgieMYTJD+dTnXTgV5",
-	"0ZBCnAGYZXiBPDaZBG/e6pDO9DOxZlrfdKNiBDl6gzPWulaBOA9b22uhQpWXRO4zOrpLR3BJ9WEtpW/o",
-	"S7jlyNIrs+XA7s1B8LFQxcXpXm6nGZoKAeTUB6jdm6DWR8XDabDeDScnqKvASCtsQ3nR8N3iYgBuIC3S",
-	"mukgLBL05rKieHq9sAg0NpEVic5WoiJ346j7evM55GCCYmrMsRZyuncxe8lqCs9mvipqNicFth6uLllN",
-	"3dUJ2dBCDVVRF2DXLqHfyDZ2ByFaE6eH+DbLBZVg8Xx2Jc9iRJkknIKiazPqJOhhcrMiYReoffAGxsz8",
-	"qN/liiO1DBwGockFkKdGwsI5xMQ5qtBFZ7NZJuw7Jb7t74bxbM5vb0zPVzRR7oxNO75BPJxv3u12TjNx",
-	"KtmkHwshISh72x7CL6kkQ3K4dj2E7kv0GrdbS1odqFjQGubV+dfJ2FgQN2fq2TV40xdBK8NTQRHOqiGK",
-	"49VMvTstT5+evla4cDVUGKU0nO87gPpiacuhXHNrvPzu6lKsd/T5FJ3tmiZgudmUYcOTMOPDL6Ojk9nx",
-	"gIf3i/wwWkzjlP06+7z8cnAYHS2Wx+nsZHQ8mx64lLCSwgrI89tXrpYzyO4yHbtdNj46Hh25A6dhzF17",
-	"L9avEsTOxOdIWB+YKWUA5pAB3S9YGxMX9NJ8cvcZraoIcT6nWZpPhjCKliRF6ZfoBfnyJZnB1XHyKR+s",
-	"vpyMUv4pD5PPLyCHS44Wh4tDcrz8jNDRanT85fkAheFscP/54MS5P9Kco6w65uD+RXT44vgCnTx/fnAy",
-	"PYKjydnx4avJ4eD18SgcvnhzHp4fn0yPjuD6xCdaK5q5Bb3yRKJJ42bRSlBYg3fOmNDHN/jX6vIdDIKe",
-	"jrGTUnJ86JTEcxj9AGMcQU6z6zoLHG8BA0UxJjVUnJ2EnLMrlP2IYJXmxwfD4cGLbkO/mucZMRcC2+Au",
-	"AVwjXl4rbEPFC8TEKhdUuFFxkAWY4agbGJpPYnSDZ+Q9vD+bVak4OuwE43WCGcOUvMqzRW01O/V/A3H8",
-	"72g1Q+Qmhmx+RbHmugLOyWiwCSSGZ15Q3cgiDtDvkvSSMnaVUY7keyLFP1UqHx4OBoOOyOUkeo9nmXzQ",
-	"+BAGekdCRDheoCbBhx1x+V8Qx7c4QYrsNRCbQhDk3gbEJQw/f5x+nDBBCUGUK0RgzFdbrFfhI7qk4efv",
-	"U8dKdUNJCMEC4hhOYnRlPEObzus9vL9ZwlRoGonIVjAwEWch9YpeX9BvAcOSgjc0s7XmtgAfYWI4yZNz",
-	"SqJ3xPglbf5V/9sElnysL6b35rYC7HAbID/OZlEGGY63UGQfpAfZesD5Brmn1w0aWorlf7UK4yqUo+Hz",
-	"w24gLNm6QDFcvYnRPZ7gGNeE7GgDaMinWYfdgMQP3/3Nq9sOlO4G0E3nw4NRx/5i/8RkZuFzhTJMo9rG",
-	"3g3YDzjjOYzf57EK2thm//pxNhPbzSVOMN94pWtGpGXtOYw3ty1WN7bqZpPTCnIbNQ4bpW5y+EwIr0HQ",
-	"vsH7turGztvYR5vbYssu59u1XLuQY1Nx7BHrVL5Dg7sUslOxtihKj+JrVz9OdeJQD15pt6W2RQJd8lST",
-	"D9cRyGQ+89+ddLjZ8F4fPDgQ0f8Y6jXMiOukbMIFrCsJpJpu92bDuJK0J1hGIOiHG2veZbBoU6A5i7rf",
-	"FEhXscwxssWdgCK+BGGiure6e+aNuKiNnf6Z5fR/qpcSfR/gTlwkMfQzURlQwdpvTcyqqwu4NYu+3s+v",
-	"gKrbee8iMg4/o1LEOzng7D4Ov1vOIh/ZGpzRaarmHuQx0Wy5kC8vLxw3G64HFO13HMXS+19alB9fGy6y",
-	"mM+mqH3jb8+vQaQ2Xd7d1Vpof8cyl4kmO0MzPXwe22qDZkYMh1f1w7kzrg4yfkfVvhrd1ToNjzp6uCQU",
-	"lR3kTme9sezqF51g8DnNmngPjw6fb26AGi9mY3ZNVO2BnaxQz8O5faxyHdTGwcoNAFtHK/tR6cyiTlwc",
-	"nOpq57pbOKuE+Pwgk5h1jZVgVriAN8Z2TC4QoSYSSqjTnJmWSrFCbuDIrp7wRjsl0KuOOcmLzKwt2K0b",
-	"qzNJ6FQ/gSWRtovM8A4a7K0hwr4fs2s7/qozdnLUTsv1e791sh4QVU95VxC3b9Gp9YjEbC9inoooOivP",
-	"EjKQwhWKwASFMGcq5rX2aGlM0mJYDwkIak+BQBC3gs7COSQzxMDe0koW/F3BEfsbBj//uSOMN+EGQy+p",
-	"lzZVR2VAysPUkYHTpo9Mm666qIC5oeg1GXvz8Jwth3yA6rPYvqP2q9Dcrf66PSP27Gle3elU9679xs2c",
-	"zhVap9UaNK7zk9I3NcF3mRR/oqjZR4mY3UXL7qJl/4zRsh3zg0ji1VNAbhIC+YhxubtQ2j90KO0Wwa6N",
-	"ZBl/nBhXUxbCPghXCfdeJz4tZmNFf03IZDj9NIq/fHoeLbKjNE+m4Tw8ITyefolGi+Nfo/svy09oOT3q",
-	"BetT6/qzuVby0q4rqeXKD/u1SFy7NrW7aGX3k6lxO+Qrt/sUiXPX9DOLV/b96lgjUyvDWW1MJeJzrFtR",
-	"QcBKEMWAkACZSlBJtnyMZT2DVvZf92zIqmvL62utXz8U9vi6zIj1p9Wtqd3stvqVtJXAtD0rXNm0fF99",
-	"dvWj95j3uj/rg0F/MAQvwfAfffCacZxAjoxil4PkirAKWvkUsHIg1O78McmQdNUBhn9FAUCJvrMVDRYo",
-	"ULcjDKQoAysEM1V1Sygn5bqfwpDTDLwck73/ROhzvFI3lCFNkJydygoIvgXD/f9zNALfgaFbbxbK6ZEm",
-	"7yp5UyXEmFQpAR6ZEIX4a2qoB5hiJ0byJPLNaH89WQi65/IO/m2nzO976i5LnTuLxMkCCAgFlL7PZJDJ",
-	"NOUVsJSgiHo3r0BnOk50EQFzzFdOhQIM2MNEk8z7uFS+VX8j6eZZcvDToN8f/qzHFIC1Xwdrs4BTwNIY",
-	"c2ttdQIax/pDEo2JFPn+mFxeqXUBL4uUCN+AGlbgf4xJyc/g9CWw2u4NwXf1Dvte14nJIvogFWknXd1U",
-	"R+q+myjJSo7XADCaGElLQCL5bYIkYbQ21S4li93c5KgJQlcrCRN5UpWnwz441/48fewikWqkcyDptA4w",
-	"Ke5VMRkTtEDZSnHq3mQFIjTFBIsx9RWsPtuoDqlEUIKqC27bvK6VFmmd1CutcYSmMUKq+60vk2lvL43n",
-	"9fZeXOO75jbpYIrm4tSm1VRJfgXSlPLKFldT+j/7bQ73++tLXceG6GJcKmUHYzTEpq4DJLrIQB98JMhu",
-	"CdQDhGyGoiJvxJi4aiGMO9eE8GWQNS+FasnkotHR0fBFc176g1XDq2ru1t86zO6X0fQgz9AgnR1NxW/5",
-	"/cEqeUEGx6Pjk/hzhtjR4a/LT/PD8Png8Dn6df7paDA6/LJynqOEsHvPZbLUUXF357bG5zQbDkarQXKQ",
-	"p3w2WCzyCK3mg0E2mpJfTwbLLyfR89VJko9mTlWJwnR0dPx52By8+PRfQpmaFNpksrEOinV18rO0N7te",
-	"90m+cfBTpdja9lejNpiNr0Xtztun23Wj0JU+ld6eq9BGm+a9fUwZWhNFJZq4U9E631eJ5k92YzbHs3k7",
-	"uqJFd2xF67XISpAPyoy7JoUeXwmN7EExpsv2Gcd02X3CMV2una8AuM10aYpIO6qiRXdcResnvnNbtN3z",
-	"BEBfp/pQrEl9ga8tBRaPldS3aRXYQmhzuLX0BaI2T63TKd40rk8mnX+I9IROsuiInFcm3Gd99JL7TWj5",
-	"treMdjdB/B1DhDQIF5pWCc+mo4mQHMZXKAsR4XCGrp1VhbScncWMghDGwoY/u7rugzPZW9foAUi7DiIQ",
-	"Y4JgFq/AHqHcciHsS2fsmEBx5MdUXZOvUixgrsobgYMBoBkYDgYggisG9kJKpniWZ3ASI+O11gBSmMEE",
-	"cZQFYxKhKcxjmYDuYLDfN66NoX5MPxz8Q3oZ4pVGuD8mF7k4oYB3VvTCJWUqS50KmVbvas3RDJhkSYF0",
-	"datbO6v3mMju5eWkNISHg8G3Yiqm/G4gyEA4IpGBzOZ0Cd6f/e+9s6vrAAy8wSqm6MPvHlz9JGns+3+U",
-	"PPYPzzXv9wK1+N7kgjsEUHDA0yWJ73t0rLP43a3KLVv6AwWEACAhWsVLjQDcCNSj/mMngO8/bpr3/u+U",
-	"zL3vtwlGh+uXTlfgVEYCnQK2hGkxD6kgR4dzdRSP1O2WjkWrxIT0x+SdEIowziPEgMQdJFjwRAYmPk+S",
-	"pyJMify2aeWdG00pIlb5wM0T0Ldvet0PP3at6+axp15n+lGCP5U7rah0X5aKuNMBb6f+8JsHRHR2GrYR",
-	"rfPQsM7NRm2d56Yxm52GdgczNYff2Uw7m6k1NuWvX/nHVIKLceoZ8fgQ7J1DhhlI5RPHAAy+k2+TA7Cc",
-	"owwB+Y+Xw8HgHw75lHveqTWKe3H/JKHOD7cv/zzm5ZNZl07AS5huttMpxpImlndbE2222FMs0P7N4yHm",
-	"8ONaw+7rNqnfzp5GusekTqr6aL5LQNluu4W2uvpBr81G3Ard5Oz1AH8AK1Xjoh0DCOn73VarNphvsUSz",
-	"7daq7OkFvPVKFZ29oB+wTpVIdfcl9taIm77us+uXHKk4vm4UjxHZMwNoy+rfhDJ4efXx4+W+f4ybJUxT",
-	"7yAXKM1QCGVmbJkhHMZLYYUOfq8T92M8fFl/RHC/VtjyIUv34bwSseFLlu4jtrPzBq9Zug/pG87jkCi8",
-	"Be1OB5+rYpsidw1tXJV6y2io7VhVnWgbLU7pcu7CLl1ftcKre1lFXdoaqIMn5q/y4KepHZ2OogxNY3Gg",
-	"vCmM1No1jg41sROqNZ99ZwgyWrvt+fjv7cZw2fIawWjV5V5KMb0eLFC4uWdF7zGK3GEyKmjpTsY53Tnu",
-	"noajg8OjY9csJ7qieYm5anvy/IWvuNGdM9+qTC8KJ6E7maqK5bqDMn2IYzhXpynNQhTdcXoXI6iCxRyJ",
-	"mFM3OsNBfzToHwz6h84krJ+c/j5CI9Q+u0PnkjYWSyLsWggnp60fdui89laXlXfywnCDgJDKHafDLZoa",
-	"+bkrGbvVyVqTtzIB7Z121HhjuhzZae9Xv47Wxjy5+w3Xy1vHECTRBzG+lvtkuobsTttdc2WubxBuGkM2",
-	"v0ub+ecORy4+cekZVeDfr5XuZOJnRz451wALk67qLqSE3fnTCMNJ6FykBcoYrivNQf/gqD/oFCx2VwbL",
-	"FarR5iUvilqRBU5NWFNbNdK4VtHJAU2NVBP0ijYqiVFbZ6186kqxIc8OQVyzL2xwL2JtJg6+/I8c5cgT",
-	"NEdqiV6djGRCRNe3NE922lrVhVh0scYISsRcBKo9E2oq/Wr4bGusodW0YiHUHubJ34s3kfo101r+t4AX",
-	"sN0TEpyFbt9+vP4AE9T2IMy0MUnESqFMVr68YnVmUDdka996bZxwRGWkL4J8+o+ZZcQ7iNvDEUEcr5Qm",
-	"/Z7pJ3bOaSgDFOSiEdiDJi29OLlijiHHZOa83AVzmmes/7gO70ZZ4dJrDVKII0Bz7plwQgmfP+WUD9St",
-	"VX97n7R5cFR922DcA37Ij50DpL+dl7pkeEWcPXVvL6klr9T2OwlAMdC6MIMO49XiDdrHOxhED5lblRei",
-	"/gOc8u7gCceYtXCJjrLPllhW91j7yKa8ICHy8lPd6ZnuKmwDZeFIXtuev766WcJUXQF6vfzneUY2YyM5",
-	"inL/c+qepne095jwLUaTg3Bq7jA6jNbJu986lrqT7TbWeue0cyg1xCZE7OI33Vhpdhl42zw6m0HfZH/1",
-	"j9AhBMl221Wkr1G4tOKBq6jCmqaquuDafXVVUWhIosMacO6XHk+f02/WcGyt84S1Odr8jq8bSe+WYicX",
-	"KjbExD3Vz5KvCZzEaL2l2gTjxGYJ04e/v7GgbPz8xu67dUJCJwJdz1kNDBzmda2Jo2T6414GyvgIeWBF",
-	"EdDA5UtPWUNrf0zoFDT91UL4a+7qABSy1Pz1GkUIJdbv/TE5x0brzeEClY9PFTYAMsASGMeqzX9Bmr2/",
-	"Two518I9GnMZnmIxVomB5HqOidx2M73DSwwQx6GKXNqUOzyk62bmhMaQ8yCjhmgdYO3dr0zDVC1zLOdv",
-	"WSC+0fd06Nj+mkl22LDrVnPruJb97Et97hbt34dz6uhyqqzrx+McNbGOvONBZw3vqCEeg3t843fhHoXF",
-	"tvzjG7kD/zxRNFJnrfOIuqbTIafklqb4eQvYd41g2kDF1BTLw0KbWpVKZ1XyNJFOaznhCXRHlyOoixEq",
-	"qDwgPqoDH3hUxIMip5xssIVGKOI2/aWOS0kD3wKL3OBbUN3yzQ+2Jt86fMuSw2JY/Y+KHVAd1B+RVkaR",
-	"tow6JhVBLAYu/lnb/quD69/2aoTfb0nForqsqWvx+2HVKYHtFgf8GkdUooXXhAHV7C3XHlpd3gZlWyN5",
-	"qjZl007oFOvTOcDIafl7Dbt6QNLaXLuV7HrNo+x0imMMuT93yplpIdPPmOSW+uAk9EwAUJLyFcBT2ULf",
-	"BTYvjgwcXUzNwdjubcXeVV4OB//Yb43+90PXeTS///DaOI5NIh2xpSxhauXemiLkSae+5cWkgO/eUltm",
-	"7gkdFqjKnTQteM7nzL8VE2xPAZ+owluA5jzNzRMYwFIUqvTJ5s7Uva6tN6aVRbFoUMGtxhlBkyVdXG2u",
-	"Ub05pBHhGUYds0ir+335uorPES7ydSImpw/1gyozaNfESqb9a8KzlcvThO5TnLV4Fd5+vJYxQbrWk86W",
-	"h4l+OlPcJSs4zk2OLglypKmTP/+TSRCPnw+pnl1D4lBMNygWp21lFdHaKovX8lgbzaSpVavZ7ygjXu3f",
-	"1smTDwK2MegPlw93tZZANva0Wl23drS6hu/qZ62P32T+WovfNd3J38evqOweGOcyu966tNj1ZIJiMEoi",
-	"BvYmlBe5boWe1Cn49quzHhN72m02rsDokoafOyXqVtkRY9ncXGSrVG6Wb+V2jhlAX3IYM/CLlQ5QDiXt",
-	"Yw5jRQT50y+B2PixfAc8wQSxajGBMZFRDXKyigYmLanOxNk+NzV2y9yMzW1TXeduVNPVJFdFQgQWcp4m",
-	"naT1mI8ByBXh3az3gXJ0qt4LYwb4UnC0PJXPZhmaCcvO1Nnhc7MjqoiOBxwHanToYKha1TUdYVPlR5kE",
-	"XsbiddoXbhCJKhm4mxsC1cG665IkSuXL799dtOOHIzCHbN4HNzRBdkpGBvZYHoo1Ba6MjQwkMDIxNWOi",
-	"YxokA+yDBK6UPwSCX1FGFY90WSCJb7lXmem6VqCRUNtRrGKVJEhvzPX3SSFOYAz2vhv2B2CcDwYH4Uv5",
-	"HwSG/cF+ADCJcAg5YmBOl2Jeq0TmA4Vx5YUwjMESsj4YqPfx4qDL5Tv8sr1b+CbChC5LRz/QzC6NaylS",
-	"hXwCac2wOU7HpEiAv+HLaa++h1EkE7ua+Cr1MJrmvO1xtBVl9oCH0aXGr1dyyFBCF8aFVVYzEGycIZbH",
-	"jTfevgXa9vhU4+jGw6KSK6tMENTOI/71cElDrYinq2pL0uIlswugrMkr8F+WU+pRy+t2K6tbro8XUFFU",
-	"qf18GzlTgpjQG0ioVQxFM+hEV6VuSQ7nhGYfv7Yp/GvsmDIgaE0R4JygDsxlpbD43Wv4rmWHR6q32ySd",
-	"UkrqbWPU7THjmaGRRVjNQMXae2rHmmePtaKz/uq03veQFuc31Y1AG5MpVY/hCIeheqWUSO3Ti9CC/c8i",
-	"gXGfZkoCGoke3uNoBrMIXKlMw2dX78CXHImztuVMkAXPyMp4PWJMhFm9wFCu+jmeZv/v/zKVmzzNUAoz",
-	"xIDALUtUinw4ERuSSpmuElNzCiYIZAhGOF4BaLJKSOeJTnosA+/68kAjsEphxoTFbZlNaCE2NFnhR+40",
-	"VYTFQVGZ6Ik0gaVwf8fU3EQncTYTiCTwsyoY912EUmH7EV7QAEG26hdEiihispLOnMYRCDPMpS1iTbUP",
-	"bqmypGGoykYVYaQCpzMm4KD7QIcVsjnN40iOtrLQj3CGQh6vpCxhLt0rzYWynqec9kb94bB/aDKpwhT3",
-	"TnsH/YF8s5NCPpfS8mwxeqYtR/FPp6NPeruMeQllMSZTDw1nIEOxSjluGaF9cGayj4vjwgwRlMlGkxWg",
-	"BAGagYRmaEwwcViwRbE1LPMIociAsnYse9ETlFDFFeYHuJK1PWTCJGKP6LaZ++BaNmYAZsJemmFisJUH",
-	"JzoFR4P+mLzBMRdrJOzoCQIwTWOskq2r5TLgpOUidnrJAO+i3mnvX4ifaTIL6uvETqx3+lOd2q9okkDA",
-	"hMzofFOM98FZ6TNn6jBCBelDnGKktK6QRkyeSbGyiKOXZ2zKdEEr/822dYSwwFNOWZhHMEGl60yoMml+",
-	"OV7KfQ0a2vmijvl9yU82vhVkR2+OR4fHBycXr4cnL46Pj87PDg5Go/Pnx4cX5y/eHAwGg+Gbi4OT88PX",
-	"g4vR6Gxwfvz61evjs6Pzwcnzi7PzQ88M+L0M0twA/TOyMlejcyhzc9nZivQC7L16e/buQ//mx/fnKnND",
-	"pUZ2//bj+4/n3w1fD310NQ/pu6P10eL3sMZNOoRZwGCmGBAlY7KnbmHsCNXSiApARIlMSKQePQU6Pn6/",
-	"xkUSRqVSlIfSYgabUbq4QKpdHhkii6Hr6GzmfnYS3wy7DttyUHXiDopomrZlvd2YDqRSxUkoS5XuDUUB",
-	"sFLEHQ08w8Y4wVVuapQ6T+A9TvKkd3o0CHr6Ysfzfq+OndacYkHodMoQryDlw0k1XYNUGx4/C2uNpZQw",
-	"da4aDQa+o2HR7pnWxtf6B2nzsTxJYLbS728FdcXWJz+JfXICY0hC9Ow3zYNfvTvmtVwUpu6FqDA1dGUb",
-	"zbWVKqLG+1lel1lnjmxMlG0ZCJHWLYWCZOKYREPRX50s8BQQhPkcZVIRqcI/UR/sfSSx3nWnY2KDNndB",
-	"ISRiOyvGD6RBM6F83t8fE+MUJZH0eAD5t4woB1RAxmpKiTZGpC8vT4HKaygoQJcMYO7ZFM8VTddtinZR",
-	"xP5DhVwYP66NqzT5eZajjeSyVjcTMsBQKB2+6iXF8MXJAOzhabEuRdmqJGeqFOMEFR8ru8TweHB88nzw",
-	"fDDwKFPLc98iQeul99y+H6ziWs5sLbpFPnQXrsUxaRNEtxJvzVce8TZFiLREqwvasqaLEfiIhl4Rv1nC",
-	"2Qxlzz6miIjDyEF/YCQoVCqwtHojGuaJQLDvEoELGirua86xVgTGM2R1JFab64UeHBrfHpwJCevd2Mj2",
-	"fjZznuZxrN/Mr1dvBMibOCBPm7om2hzVb771VYDJENofk7OigTC4pRphc7oUpnqI+uCdVCkZ+icD6v5C",
-	"R1wYfSnPnqBSdHhM1CW7GiNQMGVtKbunKXAqB/NopDd5HOuanGcay7XaSeWIlWPqSYpTr6y0WvODuKRC",
-	"J8byq5utJMCah0cKRAugm1R3ujmCsXL5rFt/5XuoM4DqDwwyZts7u3rXH5P3CMqGdKqVDTsVu0wMGb+d",
-	"0+wDjRD4DlyqPU7tbdJLnNJMH8pMs77p9wap15q1blNkPeJsdBIHLcx5s1soP5RHkIvzol6ukI6V2XhV",
-	"5dwC3pm5CmviUeslGbho7eHDt2oNtll61dWz6uojeEemtFxvnfZLXt+xZ78Jjlxv3ZS1gc3Norr/Y9rt",
-	"g0NBbHEulxdNZg2VsSOziVZvu2WdP+vOcUz03X5pfMwhA3xJQUIjJNjmG/CfWE5GdSpTJgPMtWnMgDj/",
-	"SNeNYEOcIH33zfrgjQxuJRG4pSCSt0pCQ4wJkK4oXU5Tc0ExhvQfQAGwbxAQR1cHDlDs/rMYyXH6/VsK",
-	"GIKZKuGYokxsfaa2PLqHIS/3WSanL8AbsKcgpYzhSWyoeQqOEkwCmYMgABEU5zWEPgdAvmoMwJccZlyo",
-	"whWCmcRURiufgp+G/f7hYPBzH1ygqbyzLk8VRSxHH1xQ8k9u9nhh5RUTlDmjGVPvQr+R0vWM01NAU33t",
-	"hAkPVFiBsoLkZK4M+jmDM6S9PNZqf6OelQ9VioFT8Mu/mY8vI7ga54PB6FjO4OVw8Eu9OZigqTjuCjTa",
-	"Oqp/cfpyeDx4/nx0dDxQsGTdRgMLTsXiiWl1ASbavSyNNAnuQqGkqnFODZNxqjlOH4qYfK7DtctAelID",
-	"8ZP9mdBlX/DjR7OfqSLwh4NBuVZyEzXnwFPR+pcm0jU03YQYk6vyCCfvOPWaycWS+c1LCphQNwJyZhS/",
-	"wQIkiMO+vssX6lveRsvJl+Jhl2iVW2KVT4rM6KVXUMiZmZqUfiU2WqqEBSDOOVLGp2IUsX6Vib944eOA",
-	"akeQE45jQX0HiF8M1+v4EbWEVg9iHIKl1eDW8dKtb0K8uhkZQnEyHVCJmVEwFfOier4x5dG3P9wUgi+4",
-	"FMZhHkPjySzPYRH0eXvMglVMHETEaf6nntBhvaAnlJi8TRH9hBozr7N7QU8rsl7QE5rMuvfwY/yhqdDk",
-	"6wWVGh/cKB/7BBUyWuhEe04HnlNMaCLQ6yRsOVy9toLMBNNrhzGrRV9J21fakUrEMFO+gsChFGqHRM3M",
-	"nkMifejp8EYyeYcpbHJ2FUL0e5wGbTnzmEWyiVRp8oYOGKmsm0im2PJa66ioyqxuo0wEdOHk2Rk6O0Nn",
-	"Z+jsDJ2/haHzWivDjrbOzuLYWRx/boujxvAeo8O08lobjTzsa82OMn5SuWGKnIlCaah7J0lSa0N6p0PJ",
-	"sIpkKU0UY1fYXly2M1d25srOXNmZK39lc6UIYNnWN6MUrfTKAKOZWZ5UXsYI2dXyuu3F0N/ZVfNnsZt2",
-	"RlNno6kudh6rqSy/90oZRV7zic7jcLG7ztqZTTuzaWc27cympzabPr69fPXD7jpr51zaOZee1E6y5cxj",
-	"I/0LcV3MT75Hkj3A3sdU0P4tns0DcEmXAXgVU4YCoHJa7cvjiarTWLekVP7LdSaUTCskl9okkgtkDrtA",
-	"5RoSysV1/7VzQO0sqZ0ltbOkdpaUsaTsLPvdDCmtqDf1PPV3rqedWbUzq6RZZQmdx6q6kSlxfd4mzr57",
-	"OjNJWB0m/P3tx2uByc5y2llOO8tpZzntLCfLcrq92dlOO9tpZzv94WwnY7WANUbUIl5/U1cm0lM3dCbB",
-	"k8yxGeidwZX00xN+fSsbqySfKqEoeAnUj+fyFfe3YAS+0b9ciXFluPjOoNqZUztzamdO/ZXNqTIF+S5m",
-	"e2fD/KVtmJLXfSZM00yomzFxeqfqQmyQJUh10FUiKvnDzeaWMQAZoyGW2QWkqjP1JPicZpXMGObuvBnU",
-	"eKUT0a4RZLucg0qGyMO5C6dmIp7R58kJmR5OTz7lh9n85GiUp8vj5fP7fJajT4cJWSwHx7+m8AkT8egT",
-	"nZ1qshqPyVoObezxs3AYorO1sWxXmqzAlCMxLKUyimySdcqkZaFTGV+BGcdh6QrckMPGxKRq1umX3BuF",
-	"yiPSjcPOujAXeK2SWUEViqcz9Ng5X0weRolmPaPhhEyGn+6n89Hs+dGXg8WAR1+OjqcELe6P78N7HpI5",
-	"Z0mYHx8mj8eNW3GITTgfl6g2Hs5gf888PX+FHD3t+XmcqXl0utztcvPojOim+kGDpvr7hfq8+Xx0f898",
-	"zOgSfDEfcUpePxuZBlUoNJkeSOcD/oxWmss0Ezj3vQ9yhK2mI3r6JiO+VZdG8Miz3yS7d930mV2D47Tw",
-	"Zshg4wCMDufF/dDZ1Y994JreleLMVuaXYk1U1ak16UdrWlBnH33AjqzzjOnSKDLDcnGnJY8oqg7V2dWP",
-	"MrXgPc9gSmOdGOmiTB95MIjqOn44GEQ+eUUZppHzMDEU8xsdiv8/ES0OJJAX8v81xOFz+Z+D4yOZwzuO",
-	"XeeJrSRerJY3A0FZT0w6mZyM9UxGTPrT0pX7fZliW3m0b2W9oaKx4bwpRnGk66RKB5a0BUzfMQlpptCP",
-	"hAYpPXUgpqFOcP0DZliNNK87w4S2jzIcxxFdejMji8neyGn9gfn4GolvIWdF6i5BRNHNkFA74aTXR/Gf",
-	"3G7WsrHixT8bF8sF87CydJGWrFhh5O3MFaMulfUgDApdAIH5GGotM6ls3tonE6/09Y5OPo6ZNJ1z33Gh",
-	"+NhcmSJ1fU/W/1AFBljOZCL5qJNnoqPOdFUNLc6AfyOF2saGtT26XXu2Gk+zmE5gbEWsl3eC0gYRRysr",
-	"qbwzrEsruS3uV1qk7V8KMQW8mKhKFtr/xFSpoNb5zvMEqhIJCQznmKg6DLL8gkk6qmsIVHOcekLXRIdO",
-	"GU23HrcRliPagjdYCt2aDKdFCYxnISWMQ9LCEK90C/PkK2dlCI4qzBAARktvl26mMyrTBcoyHKkuCU5w",
-	"5lRWGb3HKHpVILMNfxS9faKgBrEQL8drEkaXZbgrDoheAr3TBRzKo6Q8uykfqmW2m5NdIN3YKcqUZ1qX",
-	"bTDneZWOWj7qA1OxVyPCVYrpEu85ZCDJY47TGAEoq2e47X49ZY1hcZTdirx1IJ2pbOhTDt+ktjAYVFWh",
-	"FjnNMFoo26LMK4rJlJp6KmFGGbMK0baR5LIYcCuXlundmQjleM3Jdzv/qTKaCq7sYVmYqqIYzcTvLZPe",
-	"/hhoA+g8ZzVcc75fcpSjjeYre2w+3/+QA20zX9mz80TVOPZEhcXwLKb0c54++038YzOXZUsh5bqLssyl",
-	"b5VVbt5hVeo8rzMLoQ2r6t42NXsdBwx9KHliX2GtYnVLpIUs6Vz3FxaLI8sPbulRNtCZLGIotzbLl1hV",
-	"6DoslaAQMQYzWdiJjIm1jKalegFIGQIGSd9hsRj/o5jE+eqscNR28jkry1kcRkG50n+AkgJb8cM1WqCM",
-	"ITONdfygamg3uCEzsvpAfvBLZ0kQ/3JuvJLF7YGFwUyoDt7/c94EdFvNf+EFsh2i5ewnK5ApEECtaO/r",
-	"169f/38AAAD//zMM8QJ4PQEA",
+	"H4sIAAAAAAAC/+y965LbOLIw+CoI7Z4Ye0Ytq66+fNHxbZUvY5/jSx1XdZ+dmJr1QCQkwUUBNAFKpZ7o",
+	"19oX2BfbyARAgiRIUaqq7uluzY/psggkEonMRCKRyPzXIJKLVAomtBq8+NcgYyqVQjH8x1mkuRTqs/0N",
+	"foqk0Exo+JOmacIjCk2efFVSwG8qmrMFhb/STKYs09xAogYS/Mk1W+Af/2fGpoMXg//jSYnBE9NfPTEj",
+	"D34eDvQ6ZYMXA5pldA3/jmRuho+ZijKeYrsXg3dCnx4PicgXE5YROSUZU3miFVlQHc25mBE9Z2TGl0yQ",
+	"KU80y9RoUEBXOuNiNvj55+EgY99ynrF48OLvdqxhgf0/ig5y8pVFevAz9Kgi8pnpPBOKUEEQZ8DF9idT",
+	"mYXQ+Hk4OJtOecKpZm+50jJb70TyToLWBgihXrQhakVTgrMfkqVM8gUjVMRkypgicwfAw1o9HL6qE1NF",
+	"VlzPkahzPpszpQ2SjGaCxYDjOU2oiNi9I2jhhrAzzEFgGMoF8N7ENCYx05QnhhGoZQMaxxlTCnB9OQfW",
+	"uXdUDdgQpu+50sCgkW0xHLyUQmkq9ANg4SCHELmay0zImJGiFXGaCOXjFUv1/OWcihlTDyUigTFCqF5I",
+	"mZAY2pLINCaTTN4wQWK5EmSyJhmjyigvBPmg+HYgit9RbtOMR8wX3Nc0A758MFLW4IewY7aJj9abPEk+",
+	"MNDg989+JexXRgj7SW7Cv+U85npN0kwuecwyElNNUYKN/C4MwoD/W0YTPf/MUpnpe5+ADzy83DAtFpM5",
+	"NgSxBrVYQvIxfBDcurZDi5TSVOdG/33g8YxmqKPfiYnMRXxmNOED7Cb1ATpV0DsRY2tyZltXVdH7C8tA",
+	"945lAXln1uRiKrMFdbbTe6r0JJHRzf2j6iB3UrJoVaOgQ/yh1E99gBCS/8P1PM7oiiYKdWTMUqm4ruij",
+	"9yDgTD3ASlvAIcSsXBCuSJYLgXbpcFBRXfeOzz0rRkpUyiI+5ZHVjuUMHgr3INalAZ4kqAw9Xf2BL3j2",
+	"UAyIwEMY/UiTnClEiSygEblh6wKdXw6Pl3mWMaEtDkvECtD4yPRKZvevLyzcDQemJpfZfshaiJ+MH+yI",
+	"5MEO4XkuRTwkGVvRLDYqQyVUzZ2+MGsKas/h+TBLWkAO4Qgf3ZpKzYimScLtysqYXfEFS7hgD4KUAx7C",
+	"6/USmjdJpB4EE7U9mwHd0nyS8AgEsmC3T2/fv/zxofjNBx7CGL/72xGcPO4dCwBqFH8Qhwap/COsFIyk",
+	"UiYOuUtNH+DgWEBuRxM/V1AaOZzUA1Is7Jtwm05qDoq24XBwkclbzuKH4XsfeKdNhi2q9th/5yy/f52A",
+	"UDtRwRZVVD6zJcsUu3r76fNHurh/pGrwe9o6lCTWUeI6EgG968aO58r5nAt2Acfuh9Ie9QGCOu+Hj6+f",
+	"/HD5qukAeBhJNVKKurMPWWeJnNCEnL++uAR/o1O68I+HopoHO6hHGn7PIfgT7W7PU5+EjhUeyiavwe9H",
+	"0oI/Q7Y48Cxi/uP7h6JvCToo+VLTxOnFVM/VkGj8aSJFrIZIZvMDWqQEjo7g0PCIntH4IQ4RFm5Qnzun",
+	"QdPVjG5ywyeA3A+KZQ/mUvOBB9GMNF+yIRFshXTM0N4BngAsU5ZZmxVQVvwnTxv8PLQoeHc+zQsWc4dR",
+	"bP90IcXMUCRjCdUsJjqjQplmajCsXQDFVLP2WxsqJNF8wZSmi9R5rozngGqymvPIEN4isaKwg8240ixj",
+	"cfMaZziYMz6bt18Tmc/3MRAXoUGM/8gnSEkl6Q0wGPa7DrsqIYXuxBZMU9SeG4/Ktt3Pw4HMA+T5lOtf",
+	"GHVQBqqJCFpuhIulTJYsJly0jNxYjzp843AMrVEMcsgU4VN/xblCj2nCYMIyg69u+jJIHJoxojRPkmuR",
+	"MhFzMRtdA4pM5Au4Q1R5FDGFAmE+e7eIdbTrSF6tU1beH/pAVzQdDAc0jgtX12A4WFmX1mA4iKUAgRsO",
+	"MjbNBXCuWnEdzQf/2HTnaRbEtiroh4xumGZoZLmQMY/9mvejw8GZh+MHj0+r6qHwJv0guFatcksXsDcD",
+	"SYoOJIcehCrFZ6JkUuPpIVTZi2An60W/a2E9frha3TSpYRecZv1+tTFFLjTLljRR217Vvis6WlHfGoBm",
+	"i8acENDQw6rPrN75c+h3kR/EpSmnwXYNGlLXSm1zfwxyE4N7opWvruaMMJBsvkCBm+TRDdOgdnLBb8ut",
+	"KbQDTBlrY9hH7NnjoX8hDbeDqGwK7GCMLBcsBFlpmumNeE/YjKObeCfsQZO87BlSAW2tFURFOQennR0r",
+	"hYaxRlIXmUwTJ6cdY7WRywD4QcX3M8wPl682KoZyhUoe82laTNzHzrLM0OfmTuHDY1WHMDTnW3R1R1LY",
+	"yoqzAVX26tIuHNqEC7aQ987endy9G+dV4N83q/VG/o681hinD7P5s+7HYp1ctYMON4wY0N4uGKbBo5Hk",
+	"Qm2ODOEm+CR8SJjmSVIqMfJIUCEVi6SIFVFcRIwcPH86ftxhzZuzEPCnwZMoQVM1lxoN/CVNOJCM3VIw",
+	"/AYvBs+PT0+fHT8bH4zd/7Y5Y2w8XGyLzmEbDjUGKQwya6AZ4oeY4NxcCuNVRnPRJuXXoI0Np/WOzym6",
+	"qVs+11D2h6oAroAJTkGK+APTGY9CqnHJMjpj5kwMLTvF9My0Ri+EM7eXDC8qVGjhLfRLTUU8WW8NXpl+",
+	"7fAX9JYv8kVP7D/QWy7yRW/sLfS+2H8wzbfAnsWcir7IY+P+uGPz3qhXgW/GnItt6A5U34buBnpv5Gvg",
+	"N2KPjrOeuF8VXrc+mCPkvnhXQW/AuqYN6lMYBgQ5wGChlQtJUWAmQWEO8Vlw/YLCFFZWMinjHxvq6hLU",
+	"mIbexVH5M9VcwrdiG5jSRLEC9kTKhFHRIGErqBBaL2k0ZzZ2q6lDZ4G9+LKy7+KBmipNMjbNmJoPyYIr",
+	"BUcR61SJYADY1cSfNIloEuXGlbJmumQGLjSbmRgRlmUya476Gn4uTvDegCFuRS/3xo0HWwVpAqGw4dMn",
+	"MPXZVLOsUwB0RQAAY2R+QqGnoQoMYQyD0ASg6zmbyoztNM4Eu/YZaAef7CaIeUbD7mML1dlt00wuPIC5",
+	"0DwpV3dJ88QYQxnTsGIj8qFkLCGvRaMFsNToWmxjpwV9wRsmKNjqRxg65LLMJzdsrRwgxFAZ3Yo8z0U5",
+	"wFYuTFzW/5RcsIDmLW8nKkxgjM0Ji/C8hypw+zHfs6neckSlZZqCZcvw3nT7ge1ybkdkxwM1ERv6siDY",
+	"rSZSbIPNBvPaXxefYj6X1CdUke6hp1FaVVH/Y1qpuQJ0hTNWk5yfWZoxBVAIJTGfcVApEcaGRWvrbW1c",
+	"5difu3QTVYppcoYNR0ETGhoEeAv7cUFevj1793F0+bcP55/eExPTuvl8jDCHDr8gRSXfhqBAsyAtW3dy",
+	"2Ja/2HC6TQ9IKjYBXiXpL6fHPXsjsSvdDVV6dr/Exl7/Gi2ruAwr86oPFSI0BAFMqGo1Lnic+Ju0ZwJw",
+	"8YNq+SRTJl5KIVj5mKzZKGM0mtNJBXxhLA0HK8p1X68T7n6Ekm85y9YEerLY3qlHBRoh7oamr+xW+KH9",
+	"NsPs4DAKUSkTGocAvbl5jNpylZNuUsmRdGiI7pOggWlwKctnMQEjEWTu9bMeysB/MUMimqvCXagYYRgf",
+	"GCKlfUvTgP9fvLR7TG+7A5kRYvyAY6rgvVnrdZn1PsyoMl60wXCAcZ4DtE6pptY18cU6b76YAaFZDquo",
+	"AL/QFR+4EjdQCprsSii1Fnq+AT62ISpP02S97QhNjlPImI4DigmWqGzgJnX3q7IAtK1vy0Iwdr4w60So",
+	"777TilFzK2prGhbUVyzRtJNDLFNYwfIlN84z94q266LHANhprqH5PeTFHfDrthTxJLQnQX6RSzwQuG2n",
+	"UlEGvSbT6/LLYzSfxBUkSzZplaF70g13Ugq2M0Qt3EEb3E0N9JH/PoKf6nknb6C4F9EVhR0OP2O8HtX4",
+	"Nyt33S6mx0ExOrU56JtEUj20IangfXRjAbOMyLsRG+GfDp0ntkVxGukYDa7R2gbEqTQGhejYR2CpxkQK",
+	"azSwlCmNX2Dqj4MHmIdUTD2DYQoHH8F2d1iuJF/yVrpd+VEzgL220ZlcxOx2RC6/ZfpRyWXkz8RIfarn",
+	"j59UO6qwg6ZovwWHQvjzHWb8i2nlS9SvraNc+kr4DtMBMN3cYka6K6fknWMY378Z41GJE/kLqbL0454Y",
+	"9AjN6tyEgKV89qooprreaMhdhazV5XSEsKKzSfd/YKGYNybis4dWzvgJeNfSJiyATMTvLx5C2dSHbxn8",
+	"88MqgN5EuHwIMepJg4fbTfIlfyciOLCxHZU84bY/mTC9YkyQQuwwqLyTrrjnXs75VL+XSnViwBcpyxZU",
+	"MKFJIpUqhgN6TnmmNA6HdwTGCAdDqlW/3690Ici2xb0WBUXCVMDP9yBknViQPkjcp7DdHZu7i9zdcXhQ",
+	"O6DXflWTkZrQNtm5sZQ1BmsSd1jbbmp6t7IH1HVhaHurZ3jZ/ZxWg7T1Ua3ef2ffTSsifQ9sQUyaZ7ZQ",
+	"s0AE0wxeara4qqegM0+Py9iiUlT9YA7/WI+c22XaTWoxYZsiPGxeD2xP2IJrzeLtBpT4HsKRo3PMyznN",
+	"UPCKtEEKNgktd5go6zXgWmm2gI1PLhiZMcEy2jXBEXmnCVf4QeULIqfXotxQMUYWtq4K1VrtkK1sgULl",
+	"bXe2vCPZ8ZnIVmQvRn7DWB8Gq1JvSCIpliyz75DgZSv8lCQs6lqWFpMk/OjIkaR8PAnXKmazcan76tPc",
+	"VTFc2Gfzjftv96y3tysD9+ZOD0aHcTq6321xG17stTFWmaampDxRbmqTEKsPq2q1Rm3HF332BreCDfIg",
+	"vVmFkTqEJOCqe99bTGrqpRQGz0j6kyLuWnw3PWjuKIsJcYGiRx7h7xVcyV+cXnvcJnPNkRC5Wl5MbBm8",
+	"Cty8NyFydjN0quoRPvPiS/aYwDgUUvVh/NEjwWbUfCiNSnUtqPdmzNzywfpx/SdFlFOIqrI/oJ5A3mz3",
+	"cN3PwsIEW2M0K0OAXuixtLXBjCfPrWcuGPz5uMRhSNQc0hxaVHp4aOxyBpg7RJfWmZTr73FuSFSbif4a",
+	"xlWL/sdsmO6Bs5c1qpnqShEeM6H5lLO4fNFRzefZc28o0Q1vCcHnmd3zdpopoF3O4pjFPbjCutyhtXtR",
+	"ieIxWbuMXm1+eBse1ibqljrEu3TeBPHCvpvdHGOAEj/JNRHSIb9meki4JiueJGTC7K+ruXUq4EVDSnlG",
+	"aJbxJWuxyRC8yxgnetPPRRiIMt5xMxVjqtkbnqnOtRqSHyrba6FCjZcEfrdDuMR2qD68pWwb+j3dcWT0",
+	"yuw4cHhzAD4GVVyc7nE7zdgUBFDLNkDd3gSzPubRsAXbuuHkgvUVGLTCtpQXCz8sLg7gFtKC1kwPYUHQ",
+	"28uK4enNwgJobCMriM5OooK7cdx/vfWcajJhiXTmWAc5w7uYv2Q1heczXxU1n5OGvh6uLllN3dUJ2dBC",
+	"DVVRF+DQLtEWkBfbgL1AEoIsByrRIonreuhC0wyzF12HgXg7Li7XIuoDdUTe0ES5H70smJhdgUQuj3ue",
+	"OgmL5pSL4Khf5STAEec0upllmMIBGtiAMTDoXKLNnnv2f8qJpWPgBAd68Gw2y8C2ZPEmUG8xqPjq0vV8",
+	"KRfGlbJtxzdMR/Ptu0EyLjgRbdNPRVQIlr3tjqvHFbJpJo3bIwpf4NckzWOn6kAFM9Uwr86/TsbGglju",
+	"aBeOl3MW3TQlZMGUCr6JucInnVRJ4TaUKeVJnrHmcxiATFKQ43iLpyvDgbwJRbOGnrRg2/a52fTRgcc+",
+	"lj7qPZ31fvZT9HKOLXiQkWYyziN/gl50Lja71DJjPbgHxzCA6ZLyBAJc3eE2kZFzQiptgukbVMPXR1uE",
+	"e3uvoQJyjdrmiqfbSAoueH8MfP4LYODr6M7AoWrwNT7i19E8uLIfi2hnQ0vDv8zqWg5XcHMu4lLfEs3T",
+	"4NKWYne2xcsxpxmsuDrV/up8My9ljMbrlp2Fl6c3swYNqfN2C201yWc/dLzmeMOtyYcqxZTP8ozFxCki",
+	"zLHARMyyBReaMBGnkuPTisyoBLPDBGLS2URBpjNdC25vWymZMkGKTl6cuArQqRm3G68HBWd6bO1xSUBO",
+	"6+s7rKmMQtpapjP09XqT4GGNZQWpoa3aXnThs3b7oCvIMqU7sqWvl2agmmKApTKaP95M33LwYqjQ3BoJ",
+	"6fuqiHrHtkumYLum2i9PH2W6gUmU6YNvhydPZ6djHd0u8+N4OU1S9dPsZvXt6Dg+Wa5O09nTw9PZ9Kgl",
+	"BJiLKsjzq5ehljOqvmT2EWLZ+OT08CSccIEmOnQY4zaXFxxV9BxfgHFltdWcKmL7DTe+pR0O0nzyBTJy",
+	"VxDSwK9pPjmgcbwSKUu/xc/Ft2+LGV2fLr7m4/W3p4ep/ppHi5vnVNOVZsvj5bE4Xd0wdrI+PP32bMyi",
+	"aDa+vTl6Gjwwydw+Ki3HHN8+j4+fn75iT589O3o6PaGHk7PT45eT4/Hr08Po4Pmb8+j89On05IRurmJk",
+	"zWQ3t+GgdFFZ0oRZtPLCqME7ZwoM9Ev+U3X5jsbDgX2whVJyehyUxHMa/0gTHlMts891FjjdAQaLMfl0",
+	"BZVgJ9zmLlj2N0arND89Ojg4et5vaPPWzjnyd8AdAXxmOlsHofSk4iumzONCS4VLpitgDg77gZH5JGGX",
+	"fCY+0Fu7excwDo97wXiNtq4UL/NsWVvNXv3fUJ78F1vPmLiE5zYXuHVW4Dw9HG8DSfFZK6h+ZAGP6rtF",
+	"CiEZF5nUZisz/FOl8vEx5GfpCVPEH/jMvLi6CwO9ExETcHnRJPhBT1z+k/IE7tkM2WsgtoUA5N4FxHsa",
+	"3XyafpoooAQQ5YIJmuj1DutVXBq8l9HND2lgpfqhBELgzhsX7qpg23l9oLeQTRg0DSKyEwwuMM0zJhy3",
+	"EVs7wPCk4I3MfK25K8B7mBikzYA0Fe+Eu6jy+df8bxtYmNccpvfmqgLseBcgf5vN4owqnuygyD7ilaKX",
+	"9vQNC0+vHzS2guV/uY6SKpSTg2fH/UB4svWKJXT9JmG3fMITXhOyky2gsTbNetAPSHL33d/lqu1B6X4A",
+	"w3Q+Pjrs2R/2Ty5mHj4XLOMyrm3s/YD9yDOd0+RDnpgovl32r7/NZrDdvOcLrrde6ZoR6Vl7AeMtbIvV",
+	"ja262RS0gsJGTcBGqZscbSZEq0HQvcG3bdWNnbexjza3xY5drm3XCu1CgU0lsEdsUvkBDR5SyEHF2qEo",
+	"WxRft/oJqpOAemiVdl9qOyQwJE81+QgdgUo/fzOxYUbVnHW6aUz6AHAWfZUTdACx2HoT3O0GBj21uLfA",
+	"3/I6nA8JQwAbOZFavM4ZJmAiQoYzWbb6nDOG2F3IhEcBN9v/wF3anKYpE6q8I3QzVSMCF1VEQL2Ncrrz",
+	"XCsskzkkElBbccWKflxdCzuoi6pgt8b/wcHXS6MbOZ2WqYDg4tnc43A9l7kmFB5SsWzJlcyqSbgRjcFw",
+	"IMV3lkqD4YAmK7pWwbf6SgdTI3022MHgOLBdUypi9PG59A1awu2pmwidUe7jUt41ZQW4zWm5rXPfIDYs",
+	"2C/Eta6MYHsISI8AjdYoiDu/p2jPB/PaFO1tjXr0Iitsfd/dnp66WykDygRSduR69Z6JqXhboLmK+wc8",
+	"wK/ErvTWoQ2G+AjCPU7bKYRON8K7t45dyLzYhYd68DlqA9yLixDDdiYq40I3ZPh1q47wNi365nAFAxTb",
+	"tS+ighDGUsR7uY39PgFvca7iNrI1OKPXVF04x32i2RFXWMZgBAI0Qu9Au0M1iqVvfzBafnztuMhjPp+i",
+	"fuCiP78Gkbp0ef8LAtcjtMxl1dbe0FyPtnuGaoOmmRS4C/h4HnweQJX+Io01GH+pdTo46emXRSimEsQX",
+	"W+HEOw0+7wUDvP1NvA9Ojp9tf2xyvvfG7Jqo+gMHWaFe1Hb3J1d1UFu/uWoA2PnRVTsqvVk0iEuAU0Pt",
+	"QjdiZ5VI5R83p5P3DBPlRT22PhW6Fq+YkC6gG9RprlxLo1ipdnCwa8srDb/8S9+cZkWZ4w7sNo3VmyRy",
+	"ajN5iNiQpxg+QINHG4jwuB2zz34YeW/sPttcPpuX65d+su29g676Ji4o796iU+8trNteYJ6GKDbiDSMr",
+	"6BrTdGL6L+hRf3t9LdJi2BYSCNadBVIw7cXO25Q/5NHKq7z9XcERj7d8w/Xbfii1DTc4eqFe2lYdlXG1",
+	"d1NHDk6XPnJt+uqiAuaWotdk7O2jjHcc8g6qz2P7ntqvQvOw+uuXDaVlT2vVnUF1H9pvwswZXKFNWq1B",
+	"4zo/GX1TE/ywSWGL6zc394QvWY9IStMuBPs39LDoXh4V7R8U7R8U/RYfFPVMoYbEq5cS3OaVyD0+Xdq/",
+	"Nvq3fm20w3ugRj6xf59nQEavVw/ZVcKZFt6m48VDTsTkYPr1MPn29Vm8zE7SfDGN5tFToZPpt/hwefpT",
+	"fPtt9ZWtpieD4eaU/+1VQX2DY2ORslCd0Z+LAqgb68JDK78fporuUU3c7+PYalM/t3hl359Da8QXPOvt",
+	"BsHWbb6P8mPo4dduhZlXeLVoqjBApjA4VbZkGtihGvMW0G28bo19Ycbkhq2HJE9TlpGo8kKt7I3gm/1/",
+	"xFH95AQmdLZbK5g2BuSwVjAiJIYfmV7JLPDYiBZViwKieQbLWp4w8IpEEVByWA7GKG9MSeAlAzLHh/6F",
+	"OEzXjhxEdvHK++5NZaTqCYY6iyP4bW2uIK/aW2dXr2mZZejs4m+tXoLXo9mIjEfjA/I9OfiPEXmtNF9Q",
+	"XWRKxkFyQ1gDrUyIUfEn2NsgvMYGTy8WWh8StrCBKtAAarTbpzbAlmtGMzwk4v5jbn6mNNIyI99fi0f/",
+	"w9hNsjZhGZFcMJydqatB/kIOHv8/J4fkO3IQ3hqL/eeeJh84r9QIcS2qlCD3TIhCw1tqmDQkYGwxPMj+",
+	"+fDxZrJAJRgMPHrbSy09MgrJuC2KKpMAxFSZGbVZhWem9A8EKMF6QdhD53O3jC0or3iJjE+qAEMecVua",
+	"qD3FCmZseoN0a1ly8vfxaHTwDzsmALZuQW4tPy2JShOuvbW1aRgD609FfC1Q5EfX4v2FWRfyfZEY7M+k",
+	"hhX5X9ei5Gfy4nvitX10QL6rd3jc6nlzJdfupCL9CnXb6kjbdxsl6Q+nhkTJoj7sgiyQ3yYMCWO1KdV1",
+	"dguToyYIfQ1hLtAZASRXI3Ju3cH2ZC1i08jswC65GV04ghIurjGyZm049dFkTWI25YLDmPYG3wxmO6SI",
+	"IIKqC27XvD4bLdI5qZdW44CmcUJq+4027tv+9tJIMuXvxTW+a26TAaZoLk5tWk2V1K5AmlJe2eJqSr/D",
+	"5ghnIUJPlZwSYRrZxHVKyYhT+zyTCmJi60bkk2B+S2JeXWUzWyYH5OtaSBu5R3QZlleVs11qMLk367WU",
+	"yvHhycnB8+a87AeS5pOER9aW80409Qdes9tVPD3KMzZOZydT+C2/PVovnovx6eHp0+QmY+rk+KfV1/lx",
+	"9Gx8/Iz9NP96Mj48/rYOHpVB2FuP3vCRFFe/4QPXXGYH48P1eHGUp3o2Xi7zmK3n43F2OBU/PR2vvj2N",
+	"n62fLvLDWWh4xaL08OT05qA5ePHpV6FMTQp9MvlYD4t1DfKzjNnrJROhV+2LTq0MPAqy4woI1QKOIJUY",
+	"F4Q9My2xCtAX+6QXmpYXGebHsAKzlhCUzs4Dq29+r1Sug2syO6T52F70Z+fDWySFdsaGq6AUGgDiHVi2",
+	"AflKxcktsN/hWNgXb562ytu7tHB0wS5n/VzAewZtnn5x37cvS/W5kgcCWcZyRwiKvn0Xhx6wF3qS8LjB",
+	"pu0ulQagdcoqNbK8KFQA+CU1Fy/4tytKXv7bK8r9JZJKu0pYX4oJmRWGEy/LFIw5HJTUCwbWuobN87b5",
+	"EF4S26tvbap6oUZs3aY67h5N40HZOpDG77tzDE0Qgb5+owYG4b22M2hmsqkodL1K7ZZVJx60ClSPkFhE",
+	"3zYEl3Thdce5cLFxAqr6Wio4CV9ZkIxFjC9Z3Bs467EAtmHp8+6N/r9HlvSJqbnthVPamVcJ3CYihT81",
+	"5GrbfIYTQUeXOeQspWYmzwfmOPJorDocYlDJdis5xRlc0SRZb7zSbZ5LcLRO2hjIDQLd0b8KpAnoe6QY",
+	"dMTTpSHjQkLxZ4yLwGvHvoeEYgYAdSNprJMW8eqkB0JrkKPTYWyRl9kWpOjkL4DoHrsU3vCN8uNadU8S",
+	"xMolTehNZ2Nqt+wS27FzCMqnt+9f/nj3PdkHs/Wm7HfevfhbGIW+9Kn0btmXG20a9IoSqdiGxzDQJFwY",
+	"LXicgeYPthnP+WzejS606I8ttN6ILIK8U522DQVd9Jpw1YZiIlfdM07kqv+EE7naOF8AuMt0ZcpEN6rQ",
+	"oj+u0PqBQyeXXeF6Q2KjYttQrEl9ga8vBR6PldT3aTX0hdDncG/pC0R9ntqkU1qLij2YdP5bmIFBstiH",
+	"FS/dq43Nj1DCCalKj0T51N5lEOj50sOCCKFp3mi3PM0UIqfJBcsiJjSdsc9Ut8vZWaIkiWgCvvSzi88j",
+	"coa9iTnDE2av8GICuzvNkjV5JKT2rvIeo30Kme5TfCCNh7B1ygHmugy+OhoTmZGD8ZjEdK3II5f0DhMx",
+	"2lO6BZDSjC6YZtnwWsRsSiF9PlfkaPx45K4YD2xq14Pxf+BtX7K2CEM4bM7gTPXOC0KHGFG8gzDvtU1S",
+	"L3dFQlzq/iFGFZkASa/3tcDuZYwpGlAH4/FfYCo2yEcNgQxCMxE7yJDcnnw4+78fnV18HpJx65sDpUzs",
+	"+y/+RvZBiqqO/l2qqt698mn7bWzHHTgueEAAgQMermTpqEXHBl2tV6bSWXkvDxCGhIFoFWkihuQSUI9H",
+	"912OdHS/RUdHv1Bp0VG7TXB4vHnpzNWlaQ9kh5CwYh6oIA+P5+ZKzB747ZOiSmj/6FpAaSgRJTmc5xB3",
+	"ghlCwa/bdqNbP87bIi4l8rsWOQ1uNKWIeE7d7cuhdm96/Q8/ZZ/QsQe+ws2Dat1Ld3jDZ661FZkb46os",
+	"XPzFvlt60f6K4g4P83oN23h0cdfXeduN2jnPbZ/e9Ro6/CalOfzeZtrbTJ3PAH7/deipKQB5mfC0ZcTT",
+	"Y/LonCqu7N3CkIy/w8RoQxtki//4/mA8/o+AfOKe98IbJby4v5EXq3e3L3875uWDWZdBwCuabrfTGcZC",
+	"E6t1W4M2O+wpHuj2zeMu5vD9WsPhsDfUb2cPI93Xok6q+mhtwXjYbreF9rq2g95YG68Tuqsg1wL8DqxU",
+	"fd4aGACk7xdbrdpgbYsFzXZbq7JnK+CdV6ro3Ar6DuvkdW8LJt0Zcdc3fHb9ljPzZKofxRMmHrkBrGX1",
+	"v0EZfH/x6dP7x+1jwJOjtHWQVyzNWESxTiPWqzQ57sj4lzpx30f+gs1HhPCj8x3zEfQfrlUitkxI0H/E",
+	"bnbeIilB/yHbhmtxSBTegm6nQ5urYnsfQ12b15RGxWio7VhVnegbLUHpCu7CIV1ftcKre1lFXfoaqIcn",
+	"5veSt6GpHYOOooxNEzhQltGmtWscG/LtZ3MPVgWykZlFu8Gn/+o2hsuWn215nI33Uobp7WBDg1t4VvKW",
+	"szgcrm4CPb5gaOuXwN3TweHR8clpaJYu4q7E3LR9+ux5W6n9L8FiL1jbhE6io47Y5S8UQ0EDw7WEDEcs",
+	"/qLll4TRcLqNSphoFex4dDgeHY1Hx8EKMF+D/j4hY9Y9u+PgkjYWCxEOLUSQ0zYPexCag80C9wUvDLcI",
+	"CKnccQbcoqmTny8lY3c6WWvyVla/+WIdNa1vKwKlcW7XPx1ufHsQ7newWd56PgWAPkzpjdyHWfeyL9bu",
+	"mvO0sgqbn335UdCVXPGHIT4J6Rl82sPatdIXzBMdSGYfGmDpcmV/iaRQX9prGNFJFFwkLyq77DEeHZ2M",
+	"xr0ebXwpH60UqtHnpVYUi5jOkCasqa0aaUKrGOSApkaqCXpFG/mx7LVo968ma2dVKTbkOSCIG/aFLe5F",
+	"yk4hvvzvnOWsJWhO1KrMBBnJPdXa3NJlR+hqVRdi6OKNMSwRCxGolpGhqfSrz9g6Yw29ppvfbrhIS5s4",
+	"YiP/e8AL2OEJAWexq7efPn+kC9aVe8O1cbmgS6FcrNvSQ9eZ4bNLk3X3YMo6qK0DKhsAdn7q0I5KXxkK",
+	"47KZfuEAy4cMtypCxTbdJkBDpnQt06G9VPAS3RU57rriEDcNlsjVncdaMD2XcdgN7cpKcimIaVcpcoDj",
+	"vnCXI0OyYDGnYojXZHDxNqVJApUCWi4AWhK0wcDunsUmgIqk0Bmf5F5R0hKB0VbPxHdMVWmJ2pGTknyk",
+	"H+tVHq6FgZKxSGY2z9Kvlryy17OOCnmqjFgTgoJzhh0p60xowMZ8QlsnzDWVOwpqjO4zS27rIOGFiylP",
+	"1saE/EHZNE7BaZiTN8mhEXlEXTFQcNlxzSm+LAhFtZC5zDM1ut+bvvp9HSmv68yjKpm3cepCCj1/yCkf",
+	"mev60e6XcS7jSTW5gvOLtkO+7xy2o92u50qGN8R5ZAKWkFoYS/C4lwAUA22Kr+oxXi3Qqnu8o3F8l7lV",
+	"eSEe3eE2Mhw1FhizFifWU/bVimOh+41ZPsqbYYFRHyaYwXU38Wosiw4xXuX89QX4Pk3sQ+v15nmeie3Y",
+	"CEcx955ahqfZOtoHLvQOo+EgWtpR+4zW61qzc6wzky2011ibb+WCQ5khtiFinwujrZVmn4F3zQO9HfRt",
+	"9tf2EXrEXvr3FRXpa5gulauHiiqsaarq3UP3JUVVFBqSGLAGgvtlyxVH8MKg4dHfdAXQdcPQ7vG/RHp3",
+	"lJh+ZYLiXMBn3Yn2WkBYyuYjehNMEJsVTe9+VvagbH1M9vvufEIOItD3cNzAoHmYqTdpGtn3HAWBgWHo",
+	"qWMxscAx1RQ+tX58LeBU1LioGxIjRpWfCllq/vqZxYwtvN9H1+KcO603p0tWZr8y2MB1qlpA9ixs8yuU",
+	"ifjjlEAILdy9MZfjKZVwk3wa1/Na4Lab2R0eMWCaRyZkc1vuaCFdPzMncoZcCzJmiM4BNga9APqY7CuS",
+	"ScKiIoWBZ4G0jf7Ixsw+3jDJHht23WruHNezn9tK94VF+5fhnDq6Whrr+v44x0ysJ++0oLOBd8wQ98E9",
+	"beP34R6Dxa780zZyD/55oDDM3lrnHnVNr0NOyS1N8XMRPzuHbm6hYmqK5W4xnZ1KpbcqeZgQz42c8AC6",
+	"o88RNMQIFVTuEBjagw9aVMSdQkaDbLCDRigC1tto50sa+QvxyE3+QqpbvvvB1+Q7x616clgMa/9RsQOq",
+	"g7aH4pbh8x2jXouKIBYDF/+sbf/Vwe1vj2qEf9yRC9Z02VCX9ZfDqtcNxw4H/BpHVJ5JbIh/rE5tENpD",
+	"q8vboGxnCGPVpmzaCb2CHHtHVgYt/1bDrh6JubFWVKWCQ/MoO53yhFPdnrz1zLXA/Lcub6RL/7ii6ZCw",
+	"RarXcEs3Zean4MWRg/OGtYlbeFvxd5XvD8b/8bjz2VM7dFurBXKeWsexy+QLWwq4hgsgMJGWcoA7RmQA",
+	"/PCW2jHzljcTgCrupGnBc23O/CuYYHcJwwUXfJEv4Goqzd3bP6JSFpkSXS5YJLyunaEilUXxaFDBrcYZ",
+	"wyZLtnH1Jf+JnYeyocAFfnbuwnw6d2k0JZjSlqqQWdvqQvsuD27bVVmNacLF/dw+cdEFDxPO9ZxBDRzm",
+	"6DPTgoWjAp/IjsgHrpSfZg0vgia87R3hchtzI4hHq4lR4xlvtapOY4tC1/Kjm26W0cVW/j/HOAHfn4tL",
+	"aq1/x4TOOOtZAc8EzOFzZT1nvKg1xBQuA7UvlN2gfZMBuvavhc7WoVmw25RnHd6qt58+Y5CtTTls0xBz",
+	"YXnewScGTtB4kivBAvUX8Oc/KQRx/4m+a5xjcCimOywWJ8QyVaKFAidaavC5Hc9Sy2VBalCkyMFU7d/V",
+	"qSXBEu1QfFc/vr+7C78EsrUH3+u6swM/NHxf+a2PHxDhaotfNKDtj+OvNvY0TXIsG8Hinqlmyit7iHxW",
+	"5NFE6qKIE+hJW1vicXXW18Kf9rXoxui9jG56FRk0ZT8SbO62LhRC32d3NeeKsG85TRT5p1fnAofCc5em",
+	"iSEC/vTPIaGKcEysMeGCqWoh1GthQvBgsoYGXtZoNdo0NzN2x9zcvutT3RYlMdO1JDfFkwELnKerk+K9",
+	"jleE6mtRLkKd9T5KzV6YBBxcEb0CjtZefCMr6o/rudsREe7oDsfMGh16HICuMhqbBP9qC7VvjU9XOMgE",
+	"CbC4NHXuxwy06VLKnfK+DLEq4C5/z7KPu6HnGH2STJZzDRh7Pjrti7nFduEtfmivKAsRdFcpwNPzsC/z",
+	"XDIRVypLNo0FycVG5F9iI1NF4dWmKgpkTtV8RC7lgvl1aOBlcx6BvJNQmRpFFrTIkH4tbBwVKofHZEHX",
+	"xgdLyU8sk0Z/9BFexHforbSZbmhBMXTj7haND2Zrm6bSeWerJoxCX0Zt4tDk1kablueWNgCmVyLu4kBi",
+	"FJ3Crr2S8j+kHSXY6h4mUSvu3GNOJvMUF7N7GN2rGeOGJFrKXy3AYFcXRRe9lD1N9z52lwf2O/kYejkW",
+	"wiZETUSqvBbigJZ9qpx6SBk06t82RVWtFwtmz6L1HBcRX9CEPPruYDQm1/l4fBR9j/9h5GA0hkBzEfOI",
+	"aqYIZCajSq0XWNuNJpUsUzQhK6pGZGxyrF0LtJ+SNSnbh+3NCVVcdVbz2MZjWfopcSELk5TgAR7eNV4L",
+	"h7LYMvtWKzfTOMYifS5U3STXkrnuSrDlBezfIblWecipF17P2EIu/foktu47hU8qTxp5wtoWaFdPdN33",
+	"Vk9OUXJllQmGNddu+3qEpEFpesNac1niNUlXHTMb7d0nN92vlpfYYGeO9R3Gtpc2RMV9geUq7hbSVkCO",
+	"jTZcFexaZG2Hkme+x7Ht+Vq3dBn94ZemkwkpMqeEPDI9mMtLg/iLJkMuT68d7IDIbeIGrCccb0c6o5RM",
+	"fpy4X0KcM0cjj7BFGbJiWatsXkudU5mzv+LVSXTk1PE4v6luAG0uptIkVBGaRrj0bIHaZxCzpfq/imKU",
+	"I5kZCWg8lvzA4xnNYnJhqkaeXbwj33KWcaY8/zkVMaFi7Rz9CRfgSVpyiqt+zqfZ//f/KlNnNs1YSjOm",
+	"COCWLczzTzqBDUnPyyKjWpIJIxmjMU/WhLrMhHhfYAtY4huGEZqEgFVKM8WUf6gzVeTwzGp2mirCSsvM",
+	"eKUW6PVB4f5OmblBJ3BHAiILesPMx5ilTMQA1NGAUbUeFUSKJVNESE3mMolJlHGNtog31RG5ksaUpJFG",
+	"vVS8yAGczhTAYbdDMzui5jJPYhxt7aEf84xFOlmjLHGNNwrNhfJSHLwYHI4ODkbHrhoHTfngxeBoNMa8",
+	"DynVc5SWJ8vDJ/ZADP8M3pniBY9tRGgixcxMw9zsZCwx/qFyGRRk4CXugK7IjAmWYaPJmkjBiMzIQmbs",
+	"WnAROJg74Eg1LOhsQXk7lr/oC7aQhivcD3R9LaxZwYU/YtgVMIJqwnmiTZmvlM64cNiir1BOycl4dC3e",
+	"8ETDGoF7YMIITdOEmyfEZrkcOLRcYKdHBoA6kIO/Mn1mviL1bXJgNXjx9zq1X8rFghIFMmNzFis9Imdl",
+	"+IEyPhYJpI94ypnRuiCNXDxBsfKIY5fn2t3jUi+Hqnc5NRGTg+nXw+Tb12fxMjtJ88U0mkdPhU6m3+LD",
+	"5elP8e231Ve2mp6gO2DwYoBTBvOIwknGd26h+RV4Pf3zsKGdX9Uxvy35yce3guzhm9PD49Ojp69eHzx9",
+	"fnp6cn52dHR4eP7s9PjV+fM3R+Px+ODNq6On58evx68OD8/G56evX74+PTs5Hz999urs/LhlBvqWx9uh",
+	"fybWLspsTjG/s5/x1i7Ao5dvz959HF3+7cO5yf7nVSr5eD66+vTh0/l3B68P2ujqkrH1R+uTx+9RjZvs",
+	"azCAoVw9MimuxSMT0OI/9imNqCGJpcCktiZxxtA+NXxc4yKE4YNoozTMYDtKF7E4tTgcR2QYuo7Odjeu",
+	"QeK7YTdhWw5qHInDIjC5a1mvtqZDtZCcFIq42q5D4qUZPxm3DJvwBa9yk9mnTG6XUxCNBb2FsJjBi5Px",
+	"cGBjZFpywNSxs5qTY5XcqWK6glQbTqbpBqS68PgHWGsqlUKZc9XheNx2NCzaPbHa+LP9AaYzUPliQbO1",
+	"zeEE1IWtDz/hPum4oX2r/IyrYctNF83LHc2l88AYWUZh4dyOZiIiTPr4UXD/KIffsIVclEns4QKO0Zhl",
+	"E0mzeERe+bno4/o28DRuWSODVmWNXLXhA7BLzTtI7G7eQD7H/z8Ym/88w/8cnZ7Af2iSBOoHN7npY8nr",
+	"JSW1dEnyyaWxlCbgH9QrxgT5+8FodDwe/6MmCyN/jgfjvpJxT3xW4N7GauXk3pcrVTDdhCZUROzJv6zi",
+	"+3kj72H8jeTCnsgKVWkYzIJxt8xluJt30M2uhTnQDInMXEvYlTFHioygvznO8ikRjOs5y3D3y+SSxywe",
+	"kUefRGJNPbjC9kC7mJuICjLxxh+iFQ03tKPH18JdPosY3WwE/8YXoUQCZJMYhSysBYwOzzwlpiADUECu",
+	"FOG6xRI7NzTdJEZ4jrX0Gt11ZwGLO2QtledMneVsq83gh4rrG4M5WYQX6+Yl9MHzp2PyiE+LdSnqri9y",
+	"pQ29WfGxYpocnI5Pnz4bPxu3SIvvcO9Q25u3jHM/DquKazmzjegWhdxCuBZn820Q3UnWLV+1CPpLm0XE",
+	"SrQJhCsEshD4aA5S3GuHMU2HRDDM24Q3PiNyZn4HmaFWTI1rWrAVWdLinIP3AVQz2AXg8AQHWO9zxjTP",
+	"zPYEop8ZUHDXw8yGBZagabrgs8wkpjZVkeE3OLbAsaPY+jDs0mBWXhAtWiT0pSHCBgEtdwhDiV91d2iw",
+	"9kXTGhIBhNUNTyv7ch2jcW+z6X642NC+jYkN3hW7KJZRK7teruhsxrInn1ImwGdzNBo7nR8Z2pTOgVhG",
+	"+QKQCZo/r2Rk9GVzPtUhVcuQ1ZFUbWKv7ODUXYHQGbDc4NJHdvAPN+dpniQ2PeXmDVkQvMkm6JTjwuVR",
+	"q8XE2iAhV4xndC3OigYgUbjxQUEeQaSIoIyNzQ72J0VMZJON8Xc7PLro3O2B+fFa4FB2jKGBCbhUeq54",
+	"kuDmDIO1SOibPElM2md1ZrHcuJ8iPcyYdpIkzZhionEREbRBTQ769g1yJ2735tHC8tCC2CZVxp8zmuh5",
+	"n/U3Lto6A5j+xCHjDLWzi3eja/GBUWwop3Z7VC/ALgI1ejWXGSQKJd+R98YqM2oeL9NSmVnflWs2cv3e",
+	"MJMfqNZtyry0QY1O4I/iWje7Rfih9NS8OsfaU0JqAtKxdqZikafOwDtzQXJNPGq9kIGL1i18+NaswS5L",
+	"b7q2rLr5SN6Jqayt95OEL9nGRT8cjyEsIin96VyRLDdhDDAzxbIl/P326uqC2Ny2Yc1nUHlvkgpvP03o",
+	"KJhqY2/3uT7LDNOl9zFDKtWFShTgFzt3451VmKCFcEMcbuIi9dw6/QWqp2jOwMhIqVJDcjI+IhJU3Ior",
+	"ZmDoecYU+NqLY43tQTNmzf88Qz9XzDLyTzOXf3byjssLvzsDgciV1B0OTsZHu3auLM1nRxdkGHONzGJi",
+	"epbrZes+bOmewHc/+Ba4CDaBUXzPxGTtH70rfgoMxDFROMraj7T00YmYcK3Muzn0qGfKv/vHoY3X/ozE",
+	"Mp8k9jeuDEZ4zIvY8Bqv6Sx2XJU7lQno9YJjUL2881wF0NygWuxxCoNorEFKtY9wxgpHGu6rpsCKKLdN",
+	"6F+k9yUZm3GlWeaUH9XlrpsxVxAUPkpEzIaulefZOVVEryRZyJiBXv8zgbgZ4qLnyvKBhGuLmSKU2Ksq",
+	"Oa3Sn7wBygLVrySJrccpSa4FwSs1U+PQqeliDLwHoQBw5BCQuQ7hQAk8oEoYjjMaXUmiGM0gOliRlGVw",
+	"mmKxISy7pZEuj24Kp//nAugLkkqlOCw4LqF6QU4WXAwxKeWQxBS8zozdDAmmuRqSbznNNFgqa0YzxBMZ",
+	"5EVhzaPpDELiGddFuOKIvJLiT9odGkHnFNPD6on4MgzBAnM+0fIFkakNnuEgGRheZo7VOJULh36u6Mx5",
+	"9rzQ8D+bc86ByTn5gvzzf7uP38d0DaFMh6c4g+8Pxv+sNy8ORrK7o/mXlt8fnI6fPTs8OR0bWILdlrDo",
+	"FJYOptUHGLT7vjz1I7hXBiVzepo6FtPS8ps9RZlDjLYXH3gfPISf/M9CrkbAjZ+cKJrgxGPYBtxaVYTw",
+	"BbT+ZxPpGpphQlwL7+gFLOrWDBcLQHgUcG/UBMmVs8scFmTBNB3ZCDpClQkVxsmXwuEeMQDt0WKt8onb",
+	"k2h5twlS5qaGsm+ExsoUGOhSmOtnMoVRYP0qE3/+vI0Dqh1JLjRPgPoBEP90XG/WzC6h10O4a81irqrl",
+	"YtPpURfNvOEcELjMyQrlGnC03YpToWfP5mK6eB4f3XydLm+Wt89WaTrW3+a3327n08Wzm+ldr3AacSNG",
+	"TQBP0yTKE+pub0vsYtp2w+WFjjY95qDxIGZE5hlGkEA/UHouud9gOLBqbzAcgN7b0l1eilQvf0iF4kct",
+	"3obIJTDYwgHy2ouBBhGxl+SqFrOLGy5u0kYg3cY9DKiQmo/Ssn4YZS3v6py8RJHoMYVtXKcgcr+EM7Iu",
+	"lZuuH4ht17Ar8RmTqyX25F9wDN98CQHRuQt4fgFUQxeECzUTsa2TjnCV/+7KBWQRLgijUbmxDq/FJJM3",
+	"TJAY3B/2+c0NN6+zMOjHBaFjhGvsHrSJGXth7L6hF5JK41gNy8hbmih7ww0S7iL1MraiWayGZIbHar6Y",
+	"5Jli6J8almEkYC4PCVYTYQCTZRnVdGim4zy9lnY4d/ygcpUyoUwgxtW8bGFmk62LmQOuJE+d8Wbi2GKW",
+	"aBp4l/aHNzT3pube1Nybmr9jUxPjYF8aZdnT2rReZ6WpVjYHCFdOz1T8zdUrWutuvsP97N6G3NuQv20b",
+	"MiBuLWYktiS2abcp2duGLEzH4rG+sxlNWDkU6zHmk9ng7eWH55OuJpComJR7c2lvLu3Npb259Ps3l/Z2",
+	"0t5O2ttJD28n9TGQQKXhC8BWE4lRfIS/+Q7XNbSv3VyyyiKed2/o7A2dvaGzN3T+EIbOa6sMe9o6e4tj",
+	"b3H8ti2OGsO3GB2uVau1UdyJufu9jWaHd2mGeqsobwtKw4sN8zakdzZVBTcv5UsTxdkVfviz2psre3Nl",
+	"b67szZXfs7lSPJDf1TdjHwuDV4Y4zazyRSXZLMiuldddX1T8kV01vxW7aW80bfESoip2rS8ibLON91lC",
+	"xtu8zMb0YVLEw+KvMsoIfjFhRCY7ttOy5kebbRDlG/NlEy5MUHogHN/kzAQYNmTeRMs3s6mbDmZsfGeB",
+	"W4qBq/JFWdQC29m7tgqSdgCretysvkRSaRuWNSKXOAH3GN3sWZHMwMRKWYa66lo4LW9nZ0k6JAqMMGnf",
+	"lAqpCdU645Pce3ck3GulfZT93mbc24x7m/H3aTPCo8ye5uJHT4U+YP6JvRdt70X7bRuEnky12ILQotX8",
+	"k/MkWu6jmfZes70FtLeA9hbQQ1tAn96+f/njPpppbxXtraIHtYp8OWsxi/7KNDFWEKa7xh7kEWRcGpK3",
+	"fDYfkvdyNSQvE6nYkJjq04+N9wolrG5JZblgX1JXZ2CjHQU5IJ5gmUDoYjFZm/w31rU0WbvkI7BLzBjm",
+	"KCyTkUnMmEZFfC1cTs4iz3zFsir8cK4qoTL5HiIp6o6ohDooI5PBsMhdr7w8NmUaDC9dSQm8QNH7umB6",
+	"LuNr8chVSISmQ1tfboXJgbBVzKkgMmt+oOLxiFwg/AlL5Ko2wLVwtZAzr25dRrSUZEozv2gHDoE5LmZC",
+	"ZjZbhjCJHr36eVA0kE/XxpVYlHz0izyyW640bIRu6kAlfLr5yEwXCxBPaHTzeG/c7k3bvWm7N21/x6bt",
+	"Z1cZZB/Atjcy/xBGZp3j21Kg5YJtiJg3RWk3mY3NdGfDInkDTwkXXlzaPnZt74Xbmyp7U2VvqgRMFahc",
+	"tZ0Tzh3PtwxaG+2j1vbW0t5aQmvJE7oWQ+kSk5u22UhaffdwZhJYHS7l9NtPny9NOta95bS3nPaW095y",
+	"2ltOznK6utzbTnvbaW87/dvZTs5qIRuMqGWy+XayrPFvortc7WmIi1dDuzPADyYoH1KNmoSnLZkbrrDx",
+	"j9j4vWn8PTE/ngNI8hdySP5sf4ErPoKZJvYG1d6c2ptTe3Pq92xO/fh+f1u2t2H+EDZMyettJkzTTGgz",
+	"Y3KFUtIjzKpabXrJsNKkCV/HVqC4wGGUsswrZlc8XASLSvGf2LWIuTLBUqgaO+oIXYuzAiJXOBwXhNoW",
+	"sJFyY8UIie2UV0uyeM5YlIzCMj7XokB2U7khLFoEGG+sNXSFwVCauopuprkC5ac01VxpHqkiKddqLhOr",
+	"IDMqZow8yijgSPScCu8BpQmXKiR+SKgH1xLYFc41K4KGI1uyjCZlv33A1N742xt/e+Pv92z8/aBYts/2",
+	"tTf//hjmn8/tLQYgNmnYfEn6JcaykVtkqTAdzPWciWsv8mHYPS1ThColI47x7KjhqLPn5jKrVCB2b22a",
+	"OXAuTEHLTdILzrkCopZkQXU0D+HUrBx2eDN5KqbH06df8+Ns/vTkME9Xp6tnt/ksZ1+PF2K5Gp/+lNIH",
+	"fCJtaEq4MIwBm0M1fY/qcNSr+6927IiuNqY+ubBktWVHy/KwpnLzFvxUlL+W07px7ArAbsFh18Ig4Fii",
+	"pbSrqdfcj8PO+jAXec3RZKfmjYSt3e/X1iYLugadjGjW9NtgIiYHX2+n88PZs5NvR8uxjr+dnE4FW96e",
+	"3ka3OhJzrRZRfnq8uD9u3IlDfMK1cYlp08IZ6o9ZD/33UAu9uw56sAT6gi941suZgIcOc5aGBQC6m1Jn",
+	"CILcsPWQ0ESKWfkEytQ9J1yTFVXQq432iMROM4aebfNFvNCloqrzffKvG7b+2TlUNvO71XL2Xb+bPfVn",
+	"LpOYKW3OKKPWOfY0eD+UcCOqGOFCMaG4NhXES6X09uz91dXns1fvPv41rHVu2PoX0DjezDqXom7cCKZX",
+	"MrvZrf7+R9MZs6cH062Y76/M5x1SS5j+bWkl7OgIvphPJZtYb/YC1rIlEIv0YOipKxjqhbt9zIX9w889",
+	"hooxkkpD/UI/29jQVWy3uWGHZMkyxaXAHjwt9GOeogNodC165PoK5PRy9bL/lyuk6CcTuxbQzOlGqpoZ",
+	"xtrz5cAZOuGC/ZskzCmPdXa9ep3pirMT4YqcjCtm7kHLwSThC77tEc9zYMjpFCt2igDC6oan6ABzKNUx",
+	"akHJwOzGaeckLm6hu7K4uDYVefvSfwPTNEnWhesFIGJfspSaoU8SNO6o9DeZDU/q4sAZ5VnGhE7W18J6",
+	"bwGK8tzQHazs9rhdFT+aR9b5iDvSDVtX/V+jTXtDbUHN5nDPm0Ex1a6VtDsz0L2ymD1MT5JwhU5DBJTm",
+	"k4RHlhQiJtRab6O2VVCDXSelOiZUs6lASz75F9qpfU/rlTK6L4rQE3x5PSSHx/MimPfs4m8jEprehTEp",
+	"O/kLoBNcfp9Vzj+ej64+ffh0/t3B64MW9amM7N9BeV7a+BibRtOwt3XsoUvR1P89u/gbCBS71RlNZUKN",
+	"VHnq6mgc1w9nB+Nx3GZos4zLOOj6O4D5HR7D/z+FFkcI5Dn+v4V48Az/c3R6Av+hSRLy/u0kJ7BarZVm",
+	"Co6gGBEUZKwnSlPdLjCX5UGdTmSuyxMJZhf1GjvOm3KWxIrM6ZIZ+4FlXt9rEcnMoB+DBirvIyEEChlx",
+	"RH7kipuR5vXLKzCY44wnCRSAbtvyYbKXOK1/Yz7+zOBbpMucEEBE6OZIWEkngfyH58SNbGx48bfGxbhg",
+	"LawM30nJihVG3s3P4NSlOYAphnuBc7YFGWojM73hCd564R1Ksq7m8eDKGtEtK1N8bK4MXVKe0EkCTKo0",
+	"nTFcQKwcHrO41z1CT51JhchpcsGyiAkY6DPVrHDe/oEUahcb1vbobu3ZefKcJXJCEy81VRnAjTYIeAsy",
+	"KhSN3C1V8w2eVXI7BMN2SNtfDWIGeDHRFZ3NWDb6qqTYON95vqDmdLig0ZwLRjJGY2oDLgDOE5kyQVPu",
+	"wl2N6m95ZwgdWmZZHX/ncRtvqKAtecNR6DSdgbwPLitd/uEoo+cyw2vMJ5EUSlPRwRAvbQvnA8pV+V7q",
+	"JcAgcCwu76ZsMxvrIZcsy7jNarSwB4GmssrkLWfxywKZXfij6N0mCmYQD/FyvCZhuMCAjy+FZ7eVQO9M",
+	"S88HjE5XczbxzHb7nQzx2jllmblJNlaJ+7hAt6HxWpAp7NV47CJ86uE9p4os8kTzNGGEqhnN4rDdb6ds",
+	"MSx80DuRtw6kN5Udfcrhm9QGg2ECAeVdcppxtjS2BVOaYHO8nrK3UzTKpDIuSwTaSZL3xYA73UW53r2J",
+	"UI7XnHy/859JR2XgYg/PwjRJrmQGv3dMevdjoA+g95zNcM35fstZzraaL/bYfr7/jQPtMl/s2XuiZhx/",
+	"ooIu2JNEyps8ffIv+Md2d42Gf12sDa+EKNbuFst4RMDnI10ECeK+9btVpD6s6r00YhY+YNhDyQO73Ksz",
+	"UR3PYqBV46KvWBy5EjtfBTvoigAU3Nq8S8CqQrdviAWLmFI048maUHEtvGV0LU3IpVSMOCTbDovF+J9g",
+	"Eufrs8LD2+uy2FjOcBgl5Uo3QxAOxofr8eIoT/VsvFzmMVvPx+PscCp+ejpefXsaP1s/XeSHs1/50vcz",
+	"3MQp5qaxiR9w2ZvckDlZvSM/tEtnSZD25dx6JYtrfw+DGagOPfptXuH3W82/8iXzHaLl7CdrkhkQxKxo",
+	"udQZjfsGj/uWnLvPtZk2MfjaOCSr0eDmcBg0Oa7s0BsW9cL6S4wZnTDoNJE0i0ek+8j69Fc8sHZcFJVU",
+	"/DXvinbbZMyKtSkT85W8L5do8PPPP//8/w8AjDUfArXEAQA=",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file